@@ -0,0 +1,58 @@
+package geodecode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// readAdminCodes parses a GeoNames administrative code table such as
+// admin1CodesASCII.txt or admin2Codes.txt. Each row holds a dotted code
+// (e.g. "US.CA" or "US.CA.037"), the
+// name, the ASCII name and the geonameid, separated by tabs. The returned map
+// is keyed by the dotted code and holds the name.
+func readAdminCodes(r io.Reader) (map[string]string, error) {
+	names := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for row := 1; scanner.Scan(); row++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("geodecode: admin code row %d has %d columns, want at least 2", row, len(fields))
+		}
+		names[fields[0]] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("geodecode: reading admin codes: %w", err)
+	}
+	return names, nil
+}
+
+// resolveAdminNames replaces admin1 codes in locations with the names from
+// the tables configured in cfg and fills in Admin2Name. Admin1 codes without a
+// matching entry are left unchanged.
+func (cfg *loadConfig) resolveAdminNames(locations []Location) {
+	if cfg.admin1Names == nil && cfg.admin2Names == nil {
+		return
+	}
+	for i := range locations {
+		loc := &locations[i]
+		// Admin2 codes are keyed by the raw admin1 code, so resolve them first.
+		if cfg.admin2Names != nil {
+			loc.Admin2Name = cfg.admin2Names[loc.CC+"."+loc.Admin1+"."+loc.Admin2]
+		}
+		if cfg.admin1Names == nil {
+			continue
+		}
+		if cfg.keepAdmin1Code {
+			loc.Admin1Code = loc.Admin1
+		}
+		if name, ok := cfg.admin1Names[loc.CC+"."+loc.Admin1]; ok {
+			loc.Admin1 = name
+		}
+	}
+}
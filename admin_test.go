@@ -0,0 +1,94 @@
+package geodecode_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func openFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("opening fixture: %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestWithAdmin1Names(t *testing.T) {
+	losAngeles := [2]float64{34.0, -118.2}
+
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	err = geocoder.LoadFromFile(filepath.Join("testdata", "cities.txt"),
+		geodecode.WithFormat(geodecode.FormatGeoNames),
+		geodecode.WithAdmin1Names(openFixture(t, "admin1CodesASCII.txt")),
+	)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	got := geocoder.Query(losAngeles)[0]
+	if got.Admin1 != "California" || got.Admin1Code != "" {
+		t.Errorf("Expected Admin1 California without code, got %q / %q", got.Admin1, got.Admin1Code)
+	}
+
+	err = geocoder.LoadFromFile(filepath.Join("testdata", "cities.txt"),
+		geodecode.WithFormat(geodecode.FormatGeoNames),
+		geodecode.WithAdmin1Names(openFixture(t, "admin1CodesASCII.txt")),
+		geodecode.WithAdmin1Code(),
+	)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	got = geocoder.Query(losAngeles)[0]
+	if got.Admin1 != "California" || got.Admin1Code != "CA" {
+		t.Errorf("Expected Admin1 California with code CA, got %q / %q", got.Admin1, got.Admin1Code)
+	}
+
+	err = geocoder.LoadFromFile(filepath.Join("testdata", "cities.txt"),
+		geodecode.WithFormat(geodecode.FormatGeoNames),
+		geodecode.WithAdmin1Names(strings.NewReader("US.CA\n")),
+	)
+	if err == nil {
+		t.Errorf("Expected an error for a malformed admin1 table")
+	}
+}
+
+func TestWithAdmin2Names(t *testing.T) {
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	err = geocoder.LoadFromFile(filepath.Join("testdata", "cities.txt"),
+		geodecode.WithFormat(geodecode.FormatGeoNames),
+		geodecode.WithAdmin1Names(openFixture(t, "admin1CodesASCII.txt")),
+		geodecode.WithAdmin2Names(openFixture(t, "admin2Codes.txt")),
+	)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+
+	tests := []struct {
+		coord      [2]float64
+		admin1     string
+		admin2     string
+		admin2Name string
+	}{
+		{[2]float64{34.0, -118.2}, "California", "037", "Los Angeles County"},
+		{[2]float64{48.1, 11.6}, "Bavaria", "091", "Upper Bavaria"},
+		{[2]float64{48.9, 2.3}, "Île-de-France", "75", ""},
+	}
+	for _, tt := range tests {
+		got := geocoder.Query(tt.coord)[0]
+		if got.Admin1 != tt.admin1 || got.Admin2 != tt.admin2 || got.Admin2Name != tt.admin2Name {
+			t.Errorf("Query(%v) = %q/%q/%q, want %q/%q/%q", tt.coord,
+				got.Admin1, got.Admin2, got.Admin2Name, tt.admin1, tt.admin2, tt.admin2Name)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package geodecode
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LatLonner is implemented by point types that can be queried with
+// QuerySlice. Point types of geometry libraries such as orb.Point already
+// implement it.
+type LatLonner interface {
+	Lat() float64
+	Lon() float64
+}
+
+// QuerySlice queries rg for the location nearest to each item and returns
+// the results in the same order, as Query does for coordinate pairs. It saves
+// converting slices of point types to [][2]float64 first.
+//
+// Example usage:
+//
+//	var route []orb.Point // From a GeoJSON track
+//	locations := geodecode.QuerySlice(geocoder, route)
+func QuerySlice[T LatLonner](rg *RGeocoder, items []T) []Location {
+	return QueryFunc(rg, items, func(item T) (lat, lon float64) {
+		return item.Lat(), item.Lon()
+	})
+}
+
+// QueryFunc is like QuerySlice for types that do not implement LatLonner,
+// such as plain structs; latLon extracts the coordinate of an item.
+//
+// Example usage:
+//
+//	type stop struct{ Lat, Lon float64 }
+//	locations := geodecode.QueryFunc(geocoder, stops, func(s stop) (float64, float64) {
+//	    return s.Lat, s.Lon
+//	})
+func QueryFunc[T any](rg *RGeocoder, items []T, latLon func(T) (lat, lon float64)) []Location {
+	buf := coordinatePool.Get().(*[][2]float64)
+	coordinates := slices.Grow((*buf)[:0], len(items))[:len(items)]
+	for i, item := range items {
+		coordinates[i][0], coordinates[i][1] = latLon(item)
+	}
+	locations := rg.Query(coordinates...)
+
+	if cap(coordinates) <= maxPooledCoordinates {
+		*buf = coordinates
+		coordinatePool.Put(buf)
+	}
+	return locations
+}
+
+// maxPooledCoordinates is the capacity of the largest buffer kept in
+// coordinatePool, so that one huge batch does not pin its buffer.
+const maxPooledCoordinates = 1 << 16
+
+// coordinatePool holds the buffers QueryFunc converts items into, so that
+// services issuing many batches do not allocate one per batch.
+var coordinatePool = sync.Pool{
+	New: func() any { return new([][2]float64) },
+}
+
+// resolveAll resolves each of coordinates in ds with resolve, after
+// normalizing it, and appends the results to dst in order. The errors of
+// coordinates are joined, each annotated with its position. Large batches
+// are split across goroutines if enabled with WithAdaptiveParallelism.
+//
+// resolve is a method expression rather than a closure so that the
+// sequential path does not allocate.
+func resolveAll[T any](rg *RGeocoder, ds *dataset, dst []T, coordinates [][2]float64, resolve func(*RGeocoder, *dataset, [2]float64) (T, error)) ([]T, error) {
+	if workers := rg.tuner.workers(len(coordinates)); workers > 1 {
+		return resolveParallel(rg, ds, dst, slices.Clone(coordinates), resolve, workers)
+	}
+
+	start := rg.tuner.start()
+	var errs []error
+	for i, coord := range coordinates {
+		coord, _ = rg.normalize(coord)
+		v, err := resolve(rg, ds, coord)
+		if err != nil {
+			errs = append(errs, coordinateError(i, coord, err))
+		}
+		dst = append(dst, v)
+	}
+	rg.tuner.observe(len(coordinates), start)
+	return dst, errors.Join(errs...)
+}
+
+// resolveParallel is resolveAll for a batch split into parts for workers
+// goroutines, one of them the caller's. The goroutines only share
+// coordinates, which the caller must not modify, and write to separate parts
+// of the results.
+func resolveParallel[T any](rg *RGeocoder, ds *dataset, dst []T, coordinates [][2]float64, resolve func(*RGeocoder, *dataset, [2]float64) (T, error), workers int) ([]T, error) {
+	results := make([]T, len(coordinates))
+	errs := make([]error, len(coordinates))
+	part := func(lo, hi int) {
+		start := time.Now()
+		for i := lo; i < hi; i++ {
+			coord, _ := rg.normalize(coordinates[i])
+			v, err := resolve(rg, ds, coord)
+			if err != nil {
+				errs[i] = coordinateError(i, coord, err)
+			}
+			results[i] = v
+		}
+		rg.tuner.observe(hi-lo, start)
+	}
+
+	size := (len(coordinates) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := size; lo < len(coordinates); lo += size {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			part(lo, min(lo+size, len(coordinates)))
+		}()
+	}
+	part(0, size)
+	wg.Wait()
+	return append(dst, results...), errors.Join(errs...)
+}
+
+// coordinateError annotates err with the position i of the coordinate coord
+// in a batch.
+func coordinateError(i int, coord [2]float64, err error) error {
+	return fmt.Errorf("geodecode: coordinate %d %v: %w", i, coord, err)
+}
+
+// minParallelPart is the least time a part of a batch is expected to take
+// when it is split across goroutines; shorter parts are not worth starting
+// a goroutine for.
+const minParallelPart = 50 * time.Microsecond
+
+// batchTuner tracks how long lookups take, to decide how many goroutines a
+// batch is worth. A nil *batchTuner resolves every batch sequentially.
+type batchTuner struct {
+	nsPerLookup atomic.Int64 // Moving average; 0 until the first batch is measured
+}
+
+// WithAdaptiveParallelism lets Query, Lookup, Resolve, QueryInto and the
+// functions built on them resolve large batches on several goroutines. The
+// geocoder measures how long its lookups take, which depends on the dataset,
+// index and options as well as the machine, and splits a batch only if each
+// goroutine gets enough work to make up for starting it, using up to
+// GOMAXPROCS goroutines. Small batches, and the first batch, which is used
+// to measure, run sequentially on the caller's goroutine. Results are the
+// same either way, but query hooks may be called concurrently.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithAdaptiveParallelism())
+func WithAdaptiveParallelism() Option {
+	return func(rg *RGeocoder) error {
+		rg.tuner = &batchTuner{}
+		return nil
+	}
+}
+
+// workers returns the number of goroutines a batch of n coordinates is
+// worth.
+func (t *batchTuner) workers(n int) int {
+	if t == nil || n < 2 {
+		return 1
+	}
+	total := time.Duration(int64(n) * t.nsPerLookup.Load())
+	return max(1, min(int(total/minParallelPart), runtime.GOMAXPROCS(0), n))
+}
+
+// start returns the time a batch starts, if it is measured.
+func (t *batchTuner) start() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// observe records that n lookups took the time since start.
+func (t *batchTuner) observe(n int, start time.Time) {
+	if t == nil || n == 0 {
+		return
+	}
+	sample := time.Since(start).Nanoseconds() / int64(n)
+	for {
+		old := t.nsPerLookup.Load()
+		avg := sample
+		if old != 0 {
+			avg = old + (sample-old)/8
+		}
+		if t.nsPerLookup.CompareAndSwap(old, max(avg, 1)) {
+			return
+		}
+	}
+}
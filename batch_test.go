@@ -0,0 +1,117 @@
+package geodecode_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// lonLat mimics orb.Point, which stores longitude first.
+type lonLat [2]float64
+
+func (p lonLat) Lon() float64 { return p[0] }
+func (p lonLat) Lat() float64 { return p[1] }
+
+func TestQuerySlice(t *testing.T) {
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+
+	got := geodecode.QuerySlice(geocoder, []lonLat{{0, 60}, {178, 0}})
+	if len(got) != 2 || got[0].City != "North" || got[1].City != "West" {
+		t.Errorf("QuerySlice: Expected North and West, got %+v", got)
+	}
+
+	type stop struct{ Lat, Lon float64 }
+	got = geodecode.QueryFunc(geocoder, []stop{{Lat: 0, Lon: 178}}, func(s stop) (float64, float64) {
+		return s.Lat, s.Lon
+	})
+	if len(got) != 1 || got[0].City != "West" {
+		t.Errorf("QueryFunc: Expected West, got %+v", got)
+	}
+}
+
+func TestBatchAllocations(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithCoordinatePolicy(geodecode.WrapLongitude))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	coords := make([][2]float64, 100)
+	for i := range coords {
+		coords[i] = [2]float64{float64(i%90) - 45, float64(i*7) - 180}
+	}
+	geocoder.Query(coords...)
+
+	// Only the returned slice is allocated.
+	if allocs := testing.AllocsPerRun(10, func() { geocoder.Query(coords...) }); allocs != 1 {
+		t.Errorf("Expected 1 allocation per batch, got %v", allocs)
+	}
+}
+
+func TestWithAdaptiveParallelism(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	plain, err := geodecode.New(geodecode.WithMaxDistance(50))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	adaptive, err := geodecode.New(geodecode.WithMaxDistance(50), geodecode.WithAdaptiveParallelism())
+	if err != nil {
+		t.Fatalf("New with WithAdaptiveParallelism: %v", err)
+	}
+
+	// The first batch is measured, the others may be split. Coordinates far
+	// out at sea are not found within the maximum distance.
+	coords := randomCoords(5000)
+	for range 3 {
+		want, wantErr := plain.Lookup(coords...)
+		got, gotErr := adaptive.Lookup(coords...)
+		if len(got) != len(want) {
+			t.Fatalf("Expected %d results, got %d", len(want), len(got))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("Result %d: Expected %+v, got %+v", i, want[i], got[i])
+			}
+		}
+		if wantErr == nil || gotErr == nil || gotErr.Error() != wantErr.Error() {
+			t.Fatalf("Expected the error %v, got %v", wantErr, gotErr)
+		}
+	}
+
+	buf := make([]geodecode.Result, 0, 1)
+	buf, err = adaptive.QueryInto(buf, [2]float64{52.52, 13.405})
+	if err != nil || len(buf) != 1 || buf[0].Location.City != "Mitte" {
+		t.Errorf("Expected Mitte, got %+v (%v)", buf, err)
+	}
+}
+
+func BenchmarkBatch(b *testing.B) {
+	geocoder, _ := geodecode.New(geodecode.WithCoordinatePolicy(geodecode.WrapLongitude))
+	type stop struct{ Lat, Lon float64 }
+	stops := make([]stop, 100)
+	coords := make([][2]float64, len(stops))
+	for i := range stops {
+		stops[i] = stop{Lat: float64(i%90) - 45, Lon: float64(i*7) - 180}
+		coords[i] = [2]float64{stops[i].Lat, stops[i].Lon}
+	}
+	geocoder.Query(coords...)
+
+	b.Run("Query", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			geocoder.Query(coords...)
+		}
+	})
+	b.Run("QueryFunc", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			geodecode.QueryFunc(geocoder, stops, func(s stop) (float64, float64) { return s.Lat, s.Lon })
+		}
+	})
+}
@@ -0,0 +1,244 @@
+// Package bench compares the spatial indexes of geodecode on datasets of
+// different sizes and on different distributions of queries. The results are
+// reproducible for a given Config, so runs on different machines or versions
+// can be compared. The geodecode command prints them with "geodecode bench
+// -indexes". MeasureCapacity measures a single configured geocoder instead.
+//
+// Every geodecode.Index is measured, the KD-Tree, the grid and the VP-Tree,
+// along with the S2 cell index of this package, S2Index, which geodecode
+// itself does not offer. Backends added to geodecode later only need to be
+// listed in Config.Indexes.
+package bench
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"text/tabwriter"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// Distribution describes where benchmark queries are placed.
+type Distribution int
+
+const (
+	// NearCities places queries within half a degree of random locations of
+	// the dataset, as in typical use.
+	NearCities Distribution = iota
+	// Globe places queries uniformly in latitude and longitude, so most of
+	// them are far out at sea.
+	Globe
+)
+
+// String returns the name of d.
+func (d Distribution) String() string {
+	switch d {
+	case NearCities:
+		return "cities"
+	case Globe:
+		return "globe"
+	default:
+		return fmt.Sprintf("Distribution(%d)", int(d))
+	}
+}
+
+// Config selects what Run measures. Zero fields take the defaults of
+// DefaultConfig.
+type Config struct {
+	Indexes       []geodecode.Index // geodecode indexes, or S2Index
+	Metrics       []geodecode.Metric
+	Sizes         []int // Numbers of locations, sampled from the embedded dataset
+	Distributions []Distribution
+	Queries       int    // Number of queries per measurement
+	Seed          uint64 // Seed of the sampled locations and queries
+}
+
+// DefaultConfig returns the configuration used by "geodecode bench -indexes".
+func DefaultConfig() Config {
+	return Config{
+		Indexes:       []geodecode.Index{geodecode.KDTreeIndex, geodecode.GridIndex, geodecode.VPTreeIndex, S2Index},
+		Metrics:       []geodecode.Metric{geodecode.MetricEuclidean, geodecode.MetricHaversine},
+		Sizes:         []int{1000, 10000, 100000},
+		Distributions: []Distribution{NearCities, Globe},
+		Queries:       10000,
+		Seed:          1,
+	}
+}
+
+// Measurement is the outcome of benchmarking one index on one dataset size,
+// metric and query distribution.
+type Measurement struct {
+	Index        geodecode.Index
+	Metric       geodecode.Metric
+	Size         int // Number of locations
+	Distribution Distribution
+	Load         time.Duration // Time to load the dataset and build the index; only to build it for S2Index
+	Query        time.Duration // Mean time per query
+	IndexBytes   int64         // Memory held by the index
+}
+
+// Run performs the measurements selected by cfg, in the order of its Indexes,
+// Metrics, Sizes and Distributions. Sizes larger than the embedded dataset
+// are capped to it. S2Index is skipped for metrics other than
+// MetricHaversine.
+func Run(cfg Config) ([]Measurement, error) {
+	cfg = cfg.withDefaults()
+	if cfg.Queries <= 0 {
+		return nil, fmt.Errorf("bench: invalid number of queries %d", cfg.Queries)
+	}
+
+	all := make([]geodecode.Location, 0, 200000)
+	embedded, err := geodecode.New()
+	if err != nil {
+		return nil, err
+	}
+	for loc := range embedded.Locations() {
+		all = append(all, loc)
+	}
+	if len(all) == 0 {
+		return nil, errors.New("bench: the embedded dataset is empty")
+	}
+
+	var ms []Measurement
+	for _, index := range cfg.Indexes {
+		for _, metric := range cfg.Metrics {
+			if index == S2Index && metric != geodecode.MetricHaversine {
+				continue
+			}
+			for _, size := range cfg.Sizes {
+				r := rand.New(rand.NewPCG(cfg.Seed, uint64(size)))
+				locs := sample(r, all, size)
+				load, indexBytes, query, err := build(index, metric, locs)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, dist := range cfg.Distributions {
+					queries := Queries(rand.New(rand.NewPCG(cfg.Seed, uint64(dist))), locs, dist, cfg.Queries)
+					ms = append(ms, Measurement{
+						Index:        index,
+						Metric:       metric,
+						Size:         len(locs),
+						Distribution: dist,
+						Load:         load,
+						Query:        timeQueries(query, queries),
+						IndexBytes:   indexBytes,
+					})
+				}
+			}
+		}
+	}
+	return ms, nil
+}
+
+// build builds index over locs for metric and returns the time it took, the
+// memory held by the index and a function resolving a coordinate with it.
+func build(index geodecode.Index, metric geodecode.Metric, locs []geodecode.Location) (time.Duration, int64, func([2]float64), error) {
+	if index == S2Index {
+		start := time.Now()
+		x := newS2Index(locs)
+		return time.Since(start), x.bytes(), func(coord [2]float64) { x.nearest(coord) }, nil
+	}
+
+	var data bytes.Buffer
+	if err := geodecode.WriteBinary(&data, locs); err != nil {
+		return 0, 0, nil, err
+	}
+	geocoder, err := geodecode.New(geodecode.WithIndex(index), geodecode.WithMetric(metric))
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	start := time.Now()
+	if err := geocoder.LoadFromReader(&data, geodecode.WithFormat(geodecode.FormatBinary)); err != nil {
+		return 0, 0, nil, err
+	}
+	load := time.Since(start)
+	buf := make([]geodecode.Result, 0, 1)
+	return load, geocoder.MemoryFootprint().Tree, func(coord [2]float64) { buf, _ = geocoder.QueryInto(buf, coord) }, nil
+}
+
+// withDefaults returns cfg with its zero fields set from DefaultConfig.
+func (cfg Config) withDefaults() Config {
+	def := DefaultConfig()
+	if cfg.Indexes == nil {
+		cfg.Indexes = def.Indexes
+	}
+	if cfg.Metrics == nil {
+		cfg.Metrics = def.Metrics
+	}
+	if cfg.Sizes == nil {
+		cfg.Sizes = def.Sizes
+	}
+	if cfg.Distributions == nil {
+		cfg.Distributions = def.Distributions
+	}
+	if cfg.Queries == 0 {
+		cfg.Queries = def.Queries
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = def.Seed
+	}
+	return cfg
+}
+
+// sample returns n locations drawn from all without repetition, or all of
+// them if n is not smaller.
+func sample(r *rand.Rand, all []geodecode.Location, n int) []geodecode.Location {
+	if n >= len(all) {
+		return all
+	}
+	locs := make([]geodecode.Location, n)
+	for i, j := range r.Perm(len(all))[:n] {
+		locs[i] = all[j]
+	}
+	return locs
+}
+
+// Queries returns n query coordinates placed according to dist around locs.
+func Queries(r *rand.Rand, locs []geodecode.Location, dist Distribution, n int) [][2]float64 {
+	coords := make([][2]float64, n)
+	for i := range coords {
+		switch dist {
+		case NearCities:
+			loc := locs[r.IntN(len(locs))]
+			lat := min(max(loc.Lat+r.Float64()-0.5, -90), 90)
+			lon := min(max(loc.Lon+r.Float64()-0.5, -180), 180)
+			coords[i] = [2]float64{lat, lon}
+		default:
+			coords[i] = [2]float64{r.Float64()*180 - 90, r.Float64()*360 - 180}
+		}
+	}
+	return coords
+}
+
+// timeQueries returns the mean time query takes to resolve one of queries.
+func timeQueries(query func([2]float64), queries [][2]float64) time.Duration {
+	start := time.Now()
+	for _, coord := range queries {
+		query(coord)
+	}
+	return time.Since(start) / time.Duration(len(queries))
+}
+
+// indexName returns the name of index in tables.
+func indexName(index geodecode.Index) string {
+	if index == S2Index {
+		return "s2"
+	}
+	return index.String()
+}
+
+// WriteTable writes ms to w as an aligned table.
+func WriteTable(w io.Writer, ms []Measurement) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "index\tmetric\tlocations\tqueries\tload\tper query\tindex size\t")
+	for _, m := range ms {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%.1f MB\t\n", indexName(m.Index), m.Metric, m.Size, m.Distribution,
+			m.Load.Round(time.Millisecond), m.Query, float64(m.IndexBytes)/(1<<20))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,90 @@
+package bench_test
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/bench"
+)
+
+func TestRun(t *testing.T) {
+	cfg := bench.Config{Sizes: []int{100, 1000}, Queries: 50, Metrics: []geodecode.Metric{geodecode.MetricHaversine}}
+	ms, err := bench.Run(cfg)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// 4 indexes x 1 metric x 2 sizes x 2 distributions
+	if len(ms) != 16 {
+		t.Fatalf("Expected 16 measurements, got %d", len(ms))
+	}
+	for _, m := range ms {
+		if m.Metric != geodecode.MetricHaversine || m.Query <= 0 || m.IndexBytes <= 0 {
+			t.Errorf("Unexpected measurement %+v", m)
+		}
+	}
+	if ms[0].Index != geodecode.KDTreeIndex || ms[0].Size != 100 || ms[0].Distribution != bench.NearCities {
+		t.Errorf("Expected the first measurement for the KD-Tree on 100 locations near cities, got %+v", ms[0])
+	}
+	if last := ms[len(ms)-1]; last.Index != bench.S2Index || last.Size != 1000 {
+		t.Errorf("Expected the last measurement for S2 on 1000 locations, got %+v", last)
+	}
+
+	var out bytes.Buffer
+	if err := bench.WriteTable(&out, ms); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != len(ms)+1 {
+		t.Errorf("Expected a header and %d rows, got:\n%s", len(ms), out.String())
+	}
+	if !strings.Contains(out.String(), "s2") {
+		t.Errorf("Expected rows for the S2 index, got:\n%s", out.String())
+	}
+
+	// S2 only compares great-circle distances.
+	ms, err = bench.Run(bench.Config{Indexes: []geodecode.Index{bench.S2Index}, Metrics: []geodecode.Metric{geodecode.MetricEuclidean}, Sizes: []int{100}, Queries: 10})
+	if err != nil || len(ms) != 0 {
+		t.Errorf("Expected no measurements of S2 with the Euclidean metric, got %+v, %v", ms, err)
+	}
+
+	if _, err := bench.Run(bench.Config{Queries: -1}); err == nil {
+		t.Errorf("Expected an error for a negative number of queries")
+	}
+}
+
+func TestQueries(t *testing.T) {
+	locs := []geodecode.Location{{Lat: 89.9, Lon: 179.9}}
+	a := bench.Queries(rand.New(rand.NewPCG(1, 1)), locs, bench.NearCities, 100)
+	b := bench.Queries(rand.New(rand.NewPCG(1, 1)), locs, bench.NearCities, 100)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("Expected the same queries for the same seed, got %v and %v", a[i], b[i])
+		}
+		if a[i][0] > 90 || a[i][1] > 180 || a[i][0] < 89.3 || a[i][1] < 179.3 {
+			t.Errorf("Expected a valid query near the location, got %v", a[i])
+		}
+	}
+}
+
+func TestMeasureCapacity(t *testing.T) {
+	c, err := bench.MeasureCapacity(func() (*geodecode.RGeocoder, error) { return geodecode.New() }, 200, 1)
+	if err != nil {
+		t.Fatalf("MeasureCapacity: %v", err)
+	}
+	if c.Locations == 0 || c.Batch != 200 || c.P50 <= 0 || c.P50 > c.P90 || c.P90 > c.P99 || c.P99 > c.Max || c.Throughput <= 0 {
+		t.Errorf("Unexpected capacity %+v", c)
+	}
+
+	var out bytes.Buffer
+	if err := bench.WriteCapacity(&out, c); err != nil {
+		t.Fatalf("WriteCapacity: %v", err)
+	}
+	if !strings.Contains(out.String(), "latency p99") || !strings.Contains(out.String(), "queries/s") {
+		t.Errorf("Expected latency percentiles and throughput, got:\n%s", out.String())
+	}
+	if _, err := bench.MeasureCapacity(func() (*geodecode.RGeocoder, error) { return geodecode.New() }, 0, 1); err == nil {
+		t.Errorf("Expected an error for 0 queries")
+	}
+}
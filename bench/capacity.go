@@ -0,0 +1,100 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// batchRuns is the number of times MeasureCapacity resolves its queries as
+// a batch; the fastest run counts.
+const batchRuns = 3
+
+// Capacity is the outcome of MeasureCapacity: how fast a geocoder, as
+// configured for production, starts and answers queries on the local
+// machine.
+type Capacity struct {
+	Locations  int
+	ColdStart  time.Duration // Time to create the geocoder and load its dataset
+	FirstQuery time.Duration // Time of the first query after loading
+
+	// Latency percentiles of single queries.
+	P50, P90, P99, Max time.Duration
+
+	Batch      int     // Number of queries per batch
+	Throughput float64 // Queries per second when resolving batches
+	CPUs       int     // GOMAXPROCS during the measurement
+}
+
+// MeasureCapacity creates a geocoder with newGeocoder, which must load its
+// dataset, and measures its cold start, the latency of queries one at a
+// time and the throughput of resolving them as a batch. The queries are
+// placed near random locations of the dataset, as with NearCities.
+// newGeocoder decides whether batches use several CPUs, for example with
+// geodecode.WithAdaptiveParallelism.
+func MeasureCapacity(newGeocoder func() (*geodecode.RGeocoder, error), queries int, seed uint64) (Capacity, error) {
+	if queries <= 0 {
+		return Capacity{}, fmt.Errorf("bench: invalid number of queries %d", queries)
+	}
+	start := time.Now()
+	geocoder, err := newGeocoder()
+	if err != nil {
+		return Capacity{}, err
+	}
+	c := Capacity{ColdStart: time.Since(start), Batch: queries, CPUs: runtime.GOMAXPROCS(0)}
+
+	var locs []geodecode.Location
+	for loc := range geocoder.Locations() {
+		locs = append(locs, loc)
+	}
+	if len(locs) == 0 {
+		return Capacity{}, errors.New("bench: the dataset is empty")
+	}
+	c.Locations = len(locs)
+	coords := Queries(rand.New(rand.NewPCG(seed, 0)), locs, NearCities, queries)
+
+	buf := make([]geodecode.Result, 0, queries)
+	latencies := make([]time.Duration, len(coords))
+	for i, coord := range coords {
+		start := time.Now()
+		buf, _ = geocoder.QueryInto(buf, coord)
+		latencies[i] = time.Since(start)
+	}
+	c.FirstQuery = latencies[0]
+	slices.Sort(latencies)
+	c.P50, c.P90, c.P99 = percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 99)
+	c.Max = latencies[len(latencies)-1]
+
+	for range batchRuns {
+		start := time.Now()
+		buf, _ = geocoder.QueryInto(buf, coords...)
+		c.Throughput = max(c.Throughput, float64(len(coords))/time.Since(start).Seconds())
+	}
+	return c, nil
+}
+
+// percentile returns the p-th percentile of the sorted durations ds.
+func percentile(ds []time.Duration, p int) time.Duration {
+	return ds[min((len(ds)*p+99)/100, len(ds))-1]
+}
+
+// WriteCapacity writes c to w as an aligned table.
+func WriteCapacity(w io.Writer, c Capacity) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "locations\t%d\n", c.Locations)
+	fmt.Fprintf(tw, "cold start\t%s\n", c.ColdStart.Round(time.Millisecond))
+	fmt.Fprintf(tw, "first query\t%s\n", c.FirstQuery)
+	fmt.Fprintf(tw, "latency p50\t%s\n", c.P50)
+	fmt.Fprintf(tw, "latency p90\t%s\n", c.P90)
+	fmt.Fprintf(tw, "latency p99\t%s\n", c.P99)
+	fmt.Fprintf(tw, "latency max\t%s\n", c.Max)
+	fmt.Fprintf(tw, "throughput\t%.0f queries/s (batches of %d, %d CPUs)\n", c.Throughput, c.Batch, c.CPUs)
+	return tw.Flush()
+}
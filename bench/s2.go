@@ -0,0 +1,117 @@
+package bench
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"unsafe"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// S2Index selects, in Config.Indexes, the S2 cell index of this package
+// rather than one of geodecode. It sorts the locations by their S2 leaf cell,
+// so the locations of any cell of the S2 hierarchy are contiguous, and
+// searches the cells covering a growing cap around the query. Distances are
+// great-circle distances, so it is only measured with MetricHaversine.
+const S2Index geodecode.Index = -1
+
+// Parameters of the search of an s2Index.
+const (
+	s2CoverCells = 8 // Cells of a covering computed by the region coverer
+	s2Growth     = 4 // Factor by which the searched cap grows
+	// s2CoverRadius is the radius from which caps are covered with the
+	// region coverer. Smaller caps are covered with at most four cells of
+	// the level of their size, which is looser but much quicker to compute.
+	s2CoverRadius s1.Angle = 0.1 // About 640 km
+)
+
+// s2Index is the S2 cell index selected with S2Index.
+type s2Index struct {
+	cells   []s2.CellID // Leaf cells of the locations, in ascending order
+	points  []s2.Point  // Location of each cell
+	index   []int32     // Location index of each cell
+	radius  s1.Angle    // Radius of the first cap searched
+	coverer s2.RegionCoverer
+}
+
+// newS2Index builds an S2 cell index over locs.
+func newS2Index(locs []geodecode.Location) *s2Index {
+	x := &s2Index{
+		cells:   make([]s2.CellID, len(locs)),
+		points:  make([]s2.Point, len(locs)),
+		index:   make([]int32, len(locs)),
+		coverer: s2.RegionCoverer{MaxLevel: s2.MaxLevel, LevelMod: 1, MaxCells: s2CoverCells},
+	}
+	for i, loc := range locs {
+		ll := s2.LatLngFromDegrees(loc.Lat, loc.Lon)
+		x.cells[i], x.points[i] = s2.CellIDFromLatLng(ll), s2.PointFromLatLng(ll)
+		x.index[i] = int32(i)
+	}
+	sort.Sort(byCell{x})
+	// Start with the radius of a cap holding one location on average if they
+	// were spread evenly over the globe.
+	x.radius = s1.Angle(math.Sqrt(4 / float64(max(len(locs), 1))))
+	return x
+}
+
+// byCell sorts the entries of an s2Index by cell, then location index.
+type byCell struct{ *s2Index }
+
+func (b byCell) Len() int { return len(b.cells) }
+func (b byCell) Less(i, j int) bool {
+	return b.cells[i] < b.cells[j] || b.cells[i] == b.cells[j] && b.index[i] < b.index[j]
+}
+func (b byCell) Swap(i, j int) {
+	b.cells[i], b.cells[j] = b.cells[j], b.cells[i]
+	b.points[i], b.points[j] = b.points[j], b.points[i]
+	b.index[i], b.index[j] = b.index[j], b.index[i]
+}
+
+// nearest returns the index of the location nearest to coord by great-circle
+// distance, or -1 if the index is empty. Of several locations at the same
+// distance, the one with the lowest index is returned.
+func (x *s2Index) nearest(coord [2]float64) int {
+	if len(x.cells) == 0 {
+		return -1
+	}
+	q := s2.PointFromLatLng(s2.LatLngFromDegrees(coord[0], coord[1]))
+	best, bestDist := -1, s1.StraightChordAngle
+	// Grow the cap until it holds a location, then search the cap reaching
+	// the nearest one found: the covering contains the whole cap, so no
+	// nearer location is missed.
+	for radius := x.radius; ; radius = min(radius*s2Growth, math.Pi) {
+		found := best >= 0
+		c := s2.CapFromCenterAngle(q, radius)
+		if found {
+			c = s2.CapFromCenterChordAngle(q, bestDist)
+		}
+		for _, cell := range x.cover(c) {
+			i, _ := slices.BinarySearch(x.cells, cell.RangeMin())
+			for ; i < len(x.cells) && x.cells[i] <= cell.RangeMax(); i++ {
+				d := s2.ChordAngleBetweenPoints(q, x.points[i])
+				if best < 0 || d < bestDist || d == bestDist && int(x.index[i]) < best {
+					best, bestDist = int(x.index[i]), d
+				}
+			}
+		}
+		if found || best >= 0 && bestDist.Angle() <= radius {
+			return best
+		}
+	}
+}
+
+// cover returns the cells covering c.
+func (x *s2Index) cover(c s2.Cap) []s2.CellID {
+	if c.Radius() < s2CoverRadius {
+		return c.CellUnionBound()
+	}
+	return x.coverer.Covering(c)
+}
+
+// bytes returns the memory held by the index.
+func (x *s2Index) bytes() int64 {
+	return int64(len(x.cells))*int64(unsafe.Sizeof(s2.CellID(0))+unsafe.Sizeof(s2.Point{})) + int64(len(x.index))*4
+}
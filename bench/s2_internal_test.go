@@ -0,0 +1,65 @@
+package bench
+
+import (
+	"bytes"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestS2IndexNearest(t *testing.T) {
+	embedded, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var all []geodecode.Location
+	for loc := range embedded.Locations() {
+		all = append(all, loc)
+	}
+	locs := sample(rand.New(rand.NewPCG(1, 1)), all, 5000)
+	x := newS2Index(locs)
+
+	geocoder, err := geodecode.New(geodecode.WithMetric(geodecode.MetricHaversine))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var data bytes.Buffer
+	if err := geodecode.WriteBinary(&data, locs); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	if err := geocoder.LoadFromReader(&data, geodecode.WithFormat(geodecode.FormatBinary)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	for _, dist := range []Distribution{NearCities, Globe} {
+		for _, coord := range Queries(rand.New(rand.NewPCG(2, 2)), locs, dist, 500) {
+			results, err := geocoder.Resolve(coord)
+			if err != nil {
+				t.Fatalf("Resolve(%v): %v", coord, err)
+			}
+			i := x.nearest(coord)
+			if i < 0 {
+				t.Fatalf("Expected a location near %v, got none", coord)
+			}
+			// Leaf cell centres are within a centimetre of the locations.
+			got := haversineKM(coord, locs[i])
+			if want := results[0].DistanceKM; math.Abs(got-want) > 1e-3 {
+				t.Errorf("Expected the nearest location to %v at %.4f km (%s), got %s at %.4f km", coord, want, results[0].Location.City, locs[i].City, got)
+			}
+		}
+	}
+
+	if i := newS2Index(nil).nearest([2]float64{0, 0}); i != -1 {
+		t.Errorf("Expected -1 for an empty index, got %d", i)
+	}
+}
+
+// haversineKM returns the great-circle distance from coord to loc.
+func haversineKM(coord [2]float64, loc geodecode.Location) float64 {
+	const earthRadiusKM = 6371.0088
+	lat1, lat2 := coord[0]*math.Pi/180, loc.Lat*math.Pi/180
+	dLat, dLon := lat2-lat1, (loc.Lon-coord[1])*math.Pi/180
+	a := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * earthRadiusKM * math.Asin(math.Sqrt(a))
+}
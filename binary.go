@@ -0,0 +1,383 @@
+package geodecode
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"unsafe"
+)
+
+// The binary dataset format stores locations as fixed-width records followed
+// by a table of deduplicated strings, so it can be decoded without parsing
+// text and without copying strings. All integers are little-endian.
+//
+//	header   magic "GDBN", version uint16, fields uint16, count uint32, table size uint32
+//	records  count records: lat, lon float64; city, admin1, admin2, cc string refs;
+//	         then one uint32 per optional field present in fields, in bit order
+//	table    strings, each a uvarint length followed by the bytes; offset 0 is ""
+//
+// A string ref is the offset of the string in the table.
+const (
+	binaryMagic      = "GDBN"
+	binaryVersion    = 1
+	binaryHeaderSize = 16
+	binaryBaseSize   = 32 // Size of a record without optional fields
+)
+
+// Optional fields of a binary record, as bits of the header's fields value.
+const (
+	binGeonameID = 1 << iota
+	binElevation
+	binPopulation
+	binAdmin1Code
+	binAdmin2Name
+	binCountry
+	binTimezone
+
+	binAllFields = 1<<iota - 1
+)
+
+// WriteBinary writes locations to w in the compact binary format read with
+// FormatBinary. Binary datasets load much faster than CSV files, because no
+// text has to be parsed and strings are not copied. Optional fields are only
+// written if at least one location has a value for them.
+func WriteBinary(w io.Writer, locations []Location) error {
+	if len(locations) > math.MaxUint32 {
+		return errors.New("geodecode: too many locations for the binary format")
+	}
+
+	var fields uint16
+	for _, loc := range locations {
+		if loc.GeonameID != 0 {
+			fields |= binGeonameID
+		}
+		if loc.Elevation != 0 {
+			fields |= binElevation
+		}
+		if loc.Population != 0 {
+			fields |= binPopulation
+		}
+		if loc.Admin1Code != "" {
+			fields |= binAdmin1Code
+		}
+		if loc.Admin2Name != "" {
+			fields |= binAdmin2Name
+		}
+		if loc.Country != "" {
+			fields |= binCountry
+		}
+		if loc.Timezone != "" {
+			fields |= binTimezone
+		}
+	}
+
+	table := []byte{0} // The empty string
+	refs := map[string]uint32{"": 0}
+	ref := func(s string) (uint32, error) {
+		if r, ok := refs[s]; ok {
+			return r, nil
+		}
+		if uint64(len(table))+binary.MaxVarintLen64+uint64(len(s)) > math.MaxUint32 {
+			return 0, errors.New("geodecode: too much text for the binary format")
+		}
+		r := uint32(len(table))
+		table = binary.AppendUvarint(table, uint64(len(s)))
+		table = append(table, s...)
+		refs[s] = r
+		return r, nil
+	}
+
+	size := binaryRecordSize(fields)
+	records := make([]byte, 0, len(locations)*size)
+	for i, loc := range locations {
+		records = binary.LittleEndian.AppendUint64(records, math.Float64bits(loc.Lat))
+		records = binary.LittleEndian.AppendUint64(records, math.Float64bits(loc.Lon))
+
+		for _, s := range [...]string{loc.City, loc.Admin1, loc.Admin2, loc.CC} {
+			r, err := ref(s)
+			if err != nil {
+				return err
+			}
+			records = binary.LittleEndian.AppendUint32(records, r)
+		}
+
+		for bit := uint16(1); bit <= binAllFields; bit <<= 1 {
+			if fields&bit == 0 {
+				continue
+			}
+			var (
+				v   uint32
+				err error
+			)
+			switch bit {
+			case binGeonameID:
+				v, err = binaryUint(loc.GeonameID)
+			case binElevation:
+				if loc.Elevation < math.MinInt32 || loc.Elevation > math.MaxInt32 {
+					err = fmt.Errorf("elevation %d out of range", loc.Elevation)
+				}
+				v = uint32(int32(loc.Elevation))
+			case binPopulation:
+				v, err = binaryUint(loc.Population)
+			case binAdmin1Code:
+				v, err = ref(loc.Admin1Code)
+			case binAdmin2Name:
+				v, err = ref(loc.Admin2Name)
+			case binCountry:
+				v, err = ref(loc.Country)
+			case binTimezone:
+				v, err = ref(loc.Timezone)
+			}
+			if err != nil {
+				return fmt.Errorf("geodecode: location %d (%s): %w", i, loc.City, err)
+			}
+			records = binary.LittleEndian.AppendUint32(records, v)
+		}
+	}
+
+	header := make([]byte, 0, binaryHeaderSize)
+	header = append(header, binaryMagic...)
+	header = binary.LittleEndian.AppendUint16(header, binaryVersion)
+	header = binary.LittleEndian.AppendUint16(header, fields)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(locations)))
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(table)))
+
+	bw := bufio.NewWriter(w)
+	for _, part := range [][]byte{header, records, table} {
+		if _, err := bw.Write(part); err != nil {
+			return fmt.Errorf("geodecode: writing binary dataset: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("geodecode: writing binary dataset: %w", err)
+	}
+	return nil
+}
+
+// binaryUint converts n to a uint32 field value.
+func binaryUint(n int) (uint32, error) {
+	if n < 0 || n > math.MaxUint32 {
+		return 0, fmt.Errorf("value %d out of range", n)
+	}
+	return uint32(n), nil
+}
+
+// binaryRecordSize returns the size of a record with the optional fields.
+func binaryRecordSize(fields uint16) int {
+	return binaryBaseSize + 4*bits.OnesCount16(fields)
+}
+
+// binaryView gives access to the records of a binary dataset without
+// decoding all of them.
+type binaryView struct {
+	records []byte
+	table   []byte
+	size    int    // Size of a record
+	fields  uint16 // Optional fields present in the records
+	count   int
+}
+
+// parseBinary checks the header of a binary dataset and returns a view of its
+// records.
+func parseBinary(data []byte) (*binaryView, error) {
+	if len(data) < binaryHeaderSize || string(data[:4]) != binaryMagic {
+		return nil, errors.New("geodecode: not a binary dataset")
+	}
+	if v := binary.LittleEndian.Uint16(data[4:]); v != binaryVersion {
+		return nil, fmt.Errorf("geodecode: unsupported binary dataset version %d", v)
+	}
+	fields := binary.LittleEndian.Uint16(data[6:])
+	if fields&^binAllFields != 0 {
+		return nil, fmt.Errorf("geodecode: unknown fields %#x in binary dataset", fields)
+	}
+	count := uint64(binary.LittleEndian.Uint32(data[8:]))
+	tableSize := uint64(binary.LittleEndian.Uint32(data[12:]))
+	size := uint64(binaryRecordSize(fields))
+	if binaryHeaderSize+count*size+tableSize != uint64(len(data)) {
+		return nil, errors.New("geodecode: binary dataset is truncated or corrupt")
+	}
+	return &binaryView{
+		records: data[binaryHeaderSize : binaryHeaderSize+count*size],
+		table:   data[binaryHeaderSize+count*size:],
+		size:    int(size),
+		fields:  fields,
+		count:   int(count),
+	}, nil
+}
+
+// str returns the string at ref in the table. It points into the table
+// instead of being copied.
+func (v *binaryView) str(ref uint32) (string, error) {
+	var err error
+	str := v.strInto(ref, &err)
+	return str, err
+}
+
+// coord returns the coordinates of record i.
+func (v *binaryView) coord(i int) (lat, lon float64) {
+	rec := v.records[i*v.size:]
+	return math.Float64frombits(binary.LittleEndian.Uint64(rec)), math.Float64frombits(binary.LittleEndian.Uint64(rec[8:]))
+}
+
+// ccRef returns the string ref of the country code of record i.
+func (v *binaryView) ccRef(i int) uint32 {
+	return binary.LittleEndian.Uint32(v.records[i*v.size+28:])
+}
+
+// location decodes record i.
+func (v *binaryView) location(i int) (Location, error) {
+	var loc Location
+	err := v.decode(i, &loc)
+	return loc, err
+}
+
+// decode decodes record i into loc.
+func (v *binaryView) decode(i int, loc *Location) error {
+	rec := v.records[i*v.size : (i+1)*v.size]
+	var err error
+	loc.Lat = math.Float64frombits(binary.LittleEndian.Uint64(rec))
+	loc.Lon = math.Float64frombits(binary.LittleEndian.Uint64(rec[8:]))
+	loc.City = v.strInto(binary.LittleEndian.Uint32(rec[16:]), &err)
+	loc.Admin1 = v.strInto(binary.LittleEndian.Uint32(rec[20:]), &err)
+	loc.Admin2 = v.strInto(binary.LittleEndian.Uint32(rec[24:]), &err)
+	loc.CC = v.strInto(binary.LittleEndian.Uint32(rec[28:]), &err)
+
+	if v.fields != 0 {
+		off := binaryBaseSize
+		for bit := uint16(1); bit <= binAllFields; bit <<= 1 {
+			if v.fields&bit == 0 {
+				continue
+			}
+			f := binary.LittleEndian.Uint32(rec[off:])
+			off += 4
+			switch bit {
+			case binGeonameID:
+				loc.GeonameID = int(f)
+			case binElevation:
+				loc.Elevation = int(int32(f))
+			case binPopulation:
+				loc.Population = int(f)
+			case binAdmin1Code:
+				loc.Admin1Code = v.strInto(f, &err)
+			case binAdmin2Name:
+				loc.Admin2Name = v.strInto(f, &err)
+			case binCountry:
+				loc.Country = v.strInto(f, &err)
+			case binTimezone:
+				loc.Timezone = v.strInto(f, &err)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !validCoordinate(loc.Lat, loc.Lon) {
+		return fmt.Errorf("geodecode: location %d has invalid coordinates in binary dataset: lat=%v, lon=%v", i, loc.Lat, loc.Lon)
+	}
+	return nil
+}
+
+// strInto is like str, but records the first error in *err and returns ""
+// once one occurred, so that the strings of a record can be decoded in a
+// row.
+func (v *binaryView) strInto(ref uint32, err *error) string {
+	if ref == 0 || *err != nil {
+		return ""
+	}
+	if uint64(ref) >= uint64(len(v.table)) {
+		*err = fmt.Errorf("geodecode: string ref %d out of range in binary dataset", ref)
+		return ""
+	}
+	n, k := binary.Uvarint(v.table[ref:])
+	start := uint64(ref) + uint64(k)
+	if k <= 0 || n > uint64(len(v.table))-start {
+		*err = fmt.Errorf("geodecode: invalid string at %d in binary dataset", ref)
+		return ""
+	}
+	if n == 0 {
+		return ""
+	}
+	return unsafe.String(&v.table[start], n)
+}
+
+// decodeBinary decodes a binary dataset. The strings of the returned
+// locations point into data, which must therefore not be modified afterwards.
+func decodeBinary(data []byte) ([]Location, error) {
+	v, err := parseBinary(data)
+	if err != nil {
+		return nil, err
+	}
+	if v.count == 0 {
+		return nil, errors.New("geodecode: no valid coordinates loaded")
+	}
+	locations := make([]Location, v.count)
+	for i := range locations {
+		if err := v.decode(i, &locations[i]); err != nil {
+			return nil, err
+		}
+	}
+	return locations, nil
+}
+
+// readBinary reads a binary dataset from r.
+func readBinary(r io.Reader) ([]Location, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: reading binary dataset: %w", err)
+	}
+	return decodeBinary(data)
+}
+
+// readBlob decodes a binary dataset held in memory, such as the dataset
+// compiled into the package or a memory-mapped file, and applies cfg. The
+// strings of the locations point into data instead of being copied to the
+// heap.
+func readBlob(data []byte, cfg *loadConfig) ([]Location, error) {
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+	locations, err := decodeBinary(data)
+	if err != nil {
+		return nil, err
+	}
+	cfg.resolveAdminNames(locations)
+	if cfg.progress != nil {
+		cfg.progress(len(data), len(data))
+	}
+	return cfg.filter(locations)
+}
+
+// embeddedBinary returns the dataset compiled into the package in the binary
+// format, or nil if none is. embeddedData holds it gzip-compressed, which
+// halves the size of binaries. Every call decompresses it into a new buffer:
+// the locations decoded from it point into the buffer, which is thus
+// released with the dataset, for example by Close, instead of being held
+// for the life of the process.
+func embeddedBinary() ([]byte, error) {
+	if len(embeddedData) == 0 {
+		return nil, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(embeddedData))
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: decompressing embedded dataset: %w", err)
+	}
+	// The gzip trailer ends with the size of the decompressed data.
+	data := make([]byte, binary.LittleEndian.Uint32(embeddedData[len(embeddedData)-4:]))
+	if _, err := io.ReadFull(gz, data); err != nil {
+		return nil, fmt.Errorf("geodecode: decompressing embedded dataset: %w", err)
+	}
+	// Reading up to the end checks the checksum in the trailer.
+	if n, err := gz.Read(make([]byte, 1)); n > 0 || err != io.EOF {
+		if err == nil || err == io.EOF {
+			err = errors.New("longer than its recorded size")
+		}
+		return nil, fmt.Errorf("geodecode: decompressing embedded dataset: %w", err)
+	}
+	return data, nil
+}
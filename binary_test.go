@@ -0,0 +1,87 @@
+package geodecode_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestWriteBinaryRoundTrip(t *testing.T) {
+	want, err := geodecode.ReadLocations(openFixture(t, "cities.txt"), geodecode.WithFormat(geodecode.FormatGeoNames))
+	if err != nil {
+		t.Fatalf("ReadLocations: %v", err)
+	}
+	want[0].Elevation = -28 // Negative elevations must survive the round trip
+
+	var buf bytes.Buffer
+	if err := geodecode.WriteBinary(&buf, want); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	data := buf.Bytes()
+	got, err := geodecode.ReadLocations(bytes.NewReader(data), geodecode.WithFormat(geodecode.FormatBinary))
+	if err != nil {
+		t.Fatalf("ReadLocations of written binary dataset: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d locations after round trip, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Location %d after round trip = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	for _, corrupt := range [][]byte{
+		nil,
+		[]byte("lat,lon,city,admin1,admin2,cc\n"),
+		data[:len(data)-1],
+		append(bytes.Clone(data), 0),
+	} {
+		if _, err := geodecode.ReadLocations(bytes.NewReader(corrupt), geodecode.WithFormat(geodecode.FormatBinary)); err == nil {
+			t.Errorf("Expected an error for a corrupt binary dataset of %d bytes", len(corrupt))
+		}
+	}
+}
+
+func TestWithMemoryMap(t *testing.T) {
+	locs, err := geodecode.ReadLocations(openFixture(t, "cities.txt"), geodecode.WithFormat(geodecode.FormatGeoNames))
+	if err != nil {
+		t.Fatalf("ReadLocations: %v", err)
+	}
+	var plain, compressed bytes.Buffer
+	if err := geodecode.WriteBinary(&plain, locs); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(plain.Bytes())
+	gz.Close()
+
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{"cities.bin": plain.Bytes(), "cities.bin.gz": compressed.Bytes()} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		geocoder, err := geodecode.New(geodecode.WithDataset(path,
+			geodecode.WithFormat(geodecode.FormatBinary), geodecode.WithMemoryMap()))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		// Reloading the unchanged file reuses its mapping.
+		for range 2 {
+			results := geocoder.Query([2]float64{34.1, -118.2})
+			if len(results) != 1 || results[0].City != "Los Angeles" {
+				t.Errorf("Expected Los Angeles from %s, got %+v", name, results)
+			}
+			if err := geocoder.Reload(); err != nil {
+				t.Fatalf("Reload: %v", err)
+			}
+		}
+	}
+}
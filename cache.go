@@ -0,0 +1,201 @@
+package geodecode
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultCachePrecision is the number of decimal places coordinates are
+// rounded to for the cache unless set with WithCachePrecision; 4 places are
+// about 11 meters.
+const defaultCachePrecision = 4
+
+// CacheStats reports the activity of the cache enabled with WithCache.
+type CacheStats struct {
+	Hits    uint64 // Lookups answered from the cache.
+	Misses  uint64 // Lookups that searched the dataset.
+	Entries int    // Coordinates currently cached.
+}
+
+// resultCache is a least recently used cache of query results, keyed by the
+// rounded coordinate. Entries remember the dataset they were computed from,
+// so results from a replaced dataset are never returned.
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	scale   float64 // Factor applied to coordinates before rounding them to keys
+	entries map[cacheKey]*list.Element
+	order   list.List // Most recently used first; values are *cacheEntry
+
+	hits, misses atomic.Uint64
+}
+
+// cacheKey is a coordinate rounded to the cache's precision.
+type cacheKey struct {
+	lat, lon int64
+}
+
+// cacheEntry is a cached result.
+type cacheEntry struct {
+	key cacheKey
+	ds  *dataset
+	loc Location
+	err error
+}
+
+// defaultApproximateCacheSize is the number of cells whose results are
+// memoized with WithApproximate unless set with WithCache.
+const defaultApproximateCacheSize = 1 << 16
+
+// newResultCache returns a cache holding up to size results, whose keys are
+// coordinates multiplied by scale and rounded.
+func newResultCache(size int, scale float64) *resultCache {
+	return &resultCache{
+		size:    size,
+		scale:   scale,
+		entries: make(map[cacheKey]*list.Element, size),
+	}
+}
+
+// key returns the cache key of coord.
+func (c *resultCache) key(coord [2]float64) cacheKey {
+	return cacheKey{lat: int64(math.Round(coord[0] * c.scale)), lon: int64(math.Round(coord[1] * c.scale))}
+}
+
+// get returns the result cached for coord in ds, and reports whether there is
+// one.
+func (c *resultCache) get(ds *dataset, coord [2]float64) (Location, error, bool) {
+	key := c.key(coord)
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if !ok || elem.Value.(*cacheEntry).ds != ds {
+		c.mu.Unlock()
+		c.misses.Add(1)
+		return Location{}, nil, false
+	}
+	c.order.MoveToFront(elem)
+	entry := elem.Value.(*cacheEntry)
+	c.mu.Unlock()
+	c.hits.Add(1)
+	return entry.loc, entry.err, true
+}
+
+// put caches the result of coord in ds, evicting the least recently used
+// result if the cache is full.
+func (c *resultCache) put(ds *dataset, coord [2]float64, loc Location, err error) {
+	entry := &cacheEntry{key: c.key(coord), ds: ds, loc: loc, err: err}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[entry.key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+}
+
+// WithCache enables a cache of up to size query results, so that repeated
+// lookups of the same coordinates skip the search. Coordinates are rounded to
+// 4 decimal places, about 11 meters, unless set otherwise with
+// WithCachePrecision, and coordinates that round to the same key share one
+// result: the match for the first of them. Replacing the dataset, for
+// example with Reload, invalidates the cached results. Hit and miss counts
+// are reported by CacheStats.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithCache(10000))
+func WithCache(size int) Option {
+	return func(rg *RGeocoder) error {
+		if size <= 0 {
+			return fmt.Errorf("geodecode: invalid cache size %d", size)
+		}
+		rg.cacheSize = size
+		return nil
+	}
+}
+
+// WithCachePrecision sets the number of decimal places, between 0 and 8,
+// that coordinates are rounded to for the cache enabled with WithCache. The
+// order of options does not matter.
+func WithCachePrecision(decimals int) Option {
+	return func(rg *RGeocoder) error {
+		if decimals < 0 || decimals > 8 {
+			return fmt.Errorf("geodecode: invalid cache precision %d", decimals)
+		}
+		rg.cachePrecision = &decimals
+		return nil
+	}
+}
+
+// CacheStats returns the hit and miss counts and the size of the cache
+// enabled with WithCache. All fields are zero without a cache.
+func (rg *RGeocoder) CacheStats() CacheStats {
+	c := rg.cache
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	entries := c.order.Len()
+	c.mu.Unlock()
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Entries: entries}
+}
+
+// WithApproximate trades accuracy for throughput on dense streams of queries,
+// such as vehicle telemetry. Queries are snapped to the center of a cell of
+// cell degrees of latitude and longitude, for example 0.01, and the answer is
+// memoized per cell, so all queries within a cell return the same location
+// and most of them skip the search. The returned location is at most one
+// cell diagonal farther from the query than the true nearest location. Up to
+// 65536 cells are memoized unless a different size is set with WithCache.
+// Distances and WithMaxDistance still refer to the queried coordinate.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithApproximate(0.01))
+func WithApproximate(cell float64) Option {
+	return func(rg *RGeocoder) error {
+		if !(cell > 0 && cell <= 1) {
+			return fmt.Errorf("geodecode: invalid approximation cell size %v", cell)
+		}
+		rg.snap = cell
+		return nil
+	}
+}
+
+// newCache returns the cache configured with WithCache, WithCachePrecision
+// and WithApproximate, or nil if none is enabled.
+func (c *config) newCache() *resultCache {
+	size := c.cacheSize
+	if c.snap > 0 {
+		if size == 0 {
+			size = defaultApproximateCacheSize
+		}
+		// Snapped coordinates are odd multiples of half a cell; this makes
+		// them integers that never round to the key of another cell.
+		return newResultCache(size, 2/c.snap)
+	}
+	if size == 0 {
+		return nil
+	}
+	precision := defaultCachePrecision
+	if c.cachePrecision != nil {
+		precision = *c.cachePrecision
+	}
+	return newResultCache(size, math.Pow10(precision))
+}
+
+// snapToCell returns the center of the cell of cell degrees holding coord.
+func snapToCell(coord [2]float64, cell float64) [2]float64 {
+	lat := (math.Floor(coord[0]/cell) + 0.5) * cell
+	lon := (math.Floor(coord[1]/cell) + 0.5) * cell
+	return [2]float64{min(max(lat, -90), 90), min(max(lon, -180), 180)}
+}
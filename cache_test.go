@@ -0,0 +1,90 @@
+package geodecode_test
+
+import (
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestWithCache(t *testing.T) {
+	for _, opt := range []geodecode.Option{geodecode.WithCache(0), geodecode.WithCachePrecision(9)} {
+		if _, err := geodecode.New(opt); err == nil {
+			t.Errorf("Expected an error for an invalid cache setting")
+		}
+	}
+
+	geocoder, err := geodecode.New(geodecode.WithCache(2), geodecode.WithCachePrecision(2))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	data := "lat,lon,city,admin1,admin2,cc\n52.52,13.405,Berlin,,,DE\n48.8566,2.3522,Paris,,,FR\n"
+	if err := geocoder.LoadFromReader(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+
+	berlin := [2]float64{52.52, 13.4}
+	for _, coord := range [][2]float64{berlin, berlin, {52.521, 13.401}} {
+		if got := geocoder.Query(coord); len(got) != 1 || got[0].City != "Berlin" {
+			t.Errorf("Expected Berlin for %v, got %+v", coord, got)
+		}
+	}
+	if stats := geocoder.CacheStats(); stats != (geodecode.CacheStats{Hits: 2, Misses: 1, Entries: 1}) {
+		t.Errorf("Expected 2 hits and 1 miss on 1 entry, got %+v", stats)
+	}
+
+	// Paris and Madrid evict Berlin.
+	geocoder.Query([2]float64{48.85, 2.35}, [2]float64{40.4, -3.7}, berlin)
+	if stats := geocoder.CacheStats(); stats.Misses != 4 || stats.Entries != 2 {
+		t.Errorf("Expected 4 misses on 2 entries, got %+v", stats)
+	}
+
+	// Results of a replaced dataset are not returned.
+	data = "lat,lon,city,admin1,admin2,cc\n52.39886,13.06566,Potsdam,Brandenburg,,DE\n"
+	if err := geocoder.LoadFromReader(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	if got := geocoder.Query(berlin); len(got) != 1 || got[0].City != "Potsdam" {
+		t.Errorf("Expected Potsdam after replacing the dataset, got %+v", got)
+	}
+
+	uncached, _ := geodecode.New()
+	if stats := uncached.CacheStats(); stats != (geodecode.CacheStats{}) {
+		t.Errorf("Expected empty stats without a cache, got %+v", stats)
+	}
+}
+
+func TestWithApproximate(t *testing.T) {
+	for _, cell := range []float64{0, -0.1, 2} {
+		if _, err := geodecode.New(geodecode.WithApproximate(cell)); err == nil {
+			t.Errorf("Expected an error for cell size %v", cell)
+		}
+	}
+
+	geocoder, err := geodecode.New(geodecode.WithApproximate(0.1), geodecode.WithMaxDistance(20))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	data := "lat,lon,city,admin1,admin2,cc\n10.04,10.04,West,,,XX\n10.099,10.099,East,,,XX\n"
+	if err := geocoder.LoadFromReader(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+
+	// Both queries lie in the cell centered on (10.05, 10.05), which is
+	// closest to West, although the second one is right next to East.
+	results, err := geocoder.Resolve([2]float64{10.02, 10.02}, [2]float64{10.098, 10.098})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	for _, r := range results {
+		if r.Location.City != "West" {
+			t.Errorf("Expected West for every query in the cell, got %+v", r)
+		}
+	}
+	if results[1].DistanceKM < 8.5 || results[1].DistanceKM > 9.5 {
+		t.Errorf("Expected the distance from the queried coordinate, got %v km", results[1].DistanceKM)
+	}
+	if stats := geocoder.CacheStats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected the cell's answer to be memoized, got %+v", stats)
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sdwillbrand/GeoDecode/internal/prom"
+)
+
+// apiKeyPoll is how often the API key file is checked for changes.
+const apiKeyPoll = 5 * time.Second
+
+// apiKey is a key clients of the serve command authenticate with.
+type apiKey struct {
+	name  string  // Identifies the key in metrics and rate limits; not secret
+	rate  float64 // Coordinates per second; 0 for the -rate-limit of the server
+	burst int     // Coordinates at once beyond rate; 0 for one second's worth
+}
+
+// apiKeys are the API keys accepted by the serve command: the static ones
+// given with -api-keys and those of the key file, which is reloaded when it
+// changes. Keys are looked up by the SHA-256 hash of their secret. A nil
+// *apiKeys accepts every request.
+type apiKeys struct {
+	static map[[sha256.Size]byte]*apiKey
+	path   string // Key file; empty for none
+	keys   atomic.Pointer[map[[sha256.Size]byte]*apiKey]
+
+	rejected atomic.Uint64 // Requests without a known key
+	mu       sync.Mutex
+	usage    map[string]*apiKeyUsage // By key name
+}
+
+// apiKeyUsage counts what was done with a key.
+type apiKeyUsage struct {
+	requests    uint64
+	coordinates uint64
+}
+
+// apiKeyContext is the context key of the API key of a request.
+type apiKeyContext struct{}
+
+// newAPIKeys returns the keys of static, a comma-separated list of
+// name=secret pairs, and of the file at path, or nil if there are none.
+func newAPIKeys(static, path string) (*apiKeys, error) {
+	if static == "" && path == "" {
+		return nil, nil
+	}
+	k := &apiKeys{
+		static: make(map[[sha256.Size]byte]*apiKey),
+		path:   path,
+		usage:  make(map[string]*apiKeyUsage),
+	}
+	names := make(map[string]bool)
+	for entry := range strings.SplitSeq(static, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, secret, ok := strings.Cut(entry, "=")
+		if !ok || name == "" || secret == "" {
+			return nil, errors.New("api-keys: want name=key pairs separated by commas")
+		}
+		if err := addAPIKey(k.static, names, &apiKey{name: name}, secret); err != nil {
+			return nil, fmt.Errorf("api-keys: %w", err)
+		}
+	}
+	if err := k.reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// addAPIKey adds key with secret to keys, unless its name or secret is
+// taken.
+func addAPIKey(keys map[[sha256.Size]byte]*apiKey, names map[string]bool, key *apiKey, secret string) error {
+	sum := sha256.Sum256([]byte(secret))
+	if names[key.name] {
+		return fmt.Errorf("duplicate key name %q", key.name)
+	}
+	if _, ok := keys[sum]; ok {
+		return fmt.Errorf("key %q has the secret of another key", key.name)
+	}
+	keys[sum], names[key.name] = key, true
+	return nil
+}
+
+// reload reads the key file again and accepts its keys, with the static
+// ones, from then on. If the file cannot be read, the keys are left as they
+// were.
+func (k *apiKeys) reload() error {
+	keys := make(map[[sha256.Size]byte]*apiKey, len(k.static))
+	names := make(map[string]bool)
+	for sum, key := range k.static {
+		keys[sum], names[key.name] = key, true
+	}
+	if k.path != "" {
+		if err := readAPIKeyFile(k.path, keys, names); err != nil {
+			return err
+		}
+	}
+	k.keys.Store(&keys)
+	return nil
+}
+
+// readAPIKeyFile adds the keys of the file at path to keys. Each line of the
+// file holds the name and secret of a key, optionally followed by its rate
+// and burst limits, separated by spaces. Empty lines and lines starting with
+// # are skipped.
+func readAPIKeyFile(path string, keys map[[sha256.Size]byte]*apiKey, names map[string]bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 2 || len(fields) > 4 {
+			return fmt.Errorf("%s:%d: want name, key and optionally rate and burst", path, line)
+		}
+		key := &apiKey{name: fields[0]}
+		if len(fields) > 2 {
+			if key.rate, err = strconv.ParseFloat(fields[2], 64); err != nil || key.rate < 0 {
+				return fmt.Errorf("%s:%d: invalid rate %q", path, line, fields[2])
+			}
+		}
+		if len(fields) > 3 {
+			if key.burst, err = strconv.Atoi(fields[3]); err != nil || key.burst < 0 {
+				return fmt.Errorf("%s:%d: invalid burst %q", path, line, fields[3])
+			}
+		}
+		if err := addAPIKey(keys, names, key, fields[1]); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// watch polls the key file every interval and reloads it when its size or
+// modification time changes, reporting failures on standard error. It
+// returns a function that stops watching.
+func (k *apiKeys) watch(interval time.Duration) (stop func()) {
+	if k == nil || k.path == "" {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		last, _ := os.Stat(k.path)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(k.path)
+			if err != nil || last != nil && info.Size() == last.Size() && info.ModTime().Equal(last.ModTime()) {
+				continue
+			}
+			last = info
+			if err := k.reload(); err != nil {
+				fmt.Fprintf(os.Stderr, "geodecode: reloading API keys failed, keeping the previous ones: %v\n", err)
+			}
+		}
+	}()
+	return cancel
+}
+
+// requestSecret returns the API key secret sent with r: the token of an
+// Authorization header with the Bearer scheme, or the X-API-Key header.
+// WebSocket requests, for which browsers cannot set headers, may send it in
+// the query parameter api_key instead.
+func requestSecret(r *http.Request) string {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	if secret := r.Header.Get("X-API-Key"); secret != "" {
+		return secret
+	}
+	if headerHasToken(r.Header, "Upgrade", "websocket") {
+		return r.URL.Query().Get("api_key")
+	}
+	return ""
+}
+
+// authenticate returns r with the API key it was sent with in its context,
+// or false if it has no known key. A nil *apiKeys returns r as is.
+func (k *apiKeys) authenticate(r *http.Request) (*http.Request, bool) {
+	if k == nil {
+		return r, true
+	}
+	var key *apiKey
+	if secret := requestSecret(r); secret != "" {
+		key = (*k.keys.Load())[sha256.Sum256([]byte(secret))]
+	}
+	if key == nil {
+		k.rejected.Add(1)
+		return r, false
+	}
+	k.mu.Lock()
+	k.usageOf(key.name).requests++
+	k.mu.Unlock()
+	return r.WithContext(context.WithValue(r.Context(), apiKeyContext{}, key)), true
+}
+
+// require returns h answering requests without a known API key with status
+// 401.
+func (k *apiKeys) require(h http.Handler) http.Handler {
+	if k == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, ok := k.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="geodecode"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or unknown API key"))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// requestAPIKey returns the API key a request with ctx was authenticated
+// with, or nil.
+func requestAPIKey(ctx context.Context) *apiKey {
+	key, _ := ctx.Value(apiKeyContext{}).(*apiKey)
+	return key
+}
+
+// used records that n coordinates were resolved for the request of r.
+func (k *apiKeys) used(r *http.Request, n int) {
+	key := requestAPIKey(r.Context())
+	if k == nil || key == nil {
+		return
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.usageOf(key.name).coordinates += uint64(n)
+}
+
+// usageOf returns the usage of the key name. k.mu must be held.
+func (k *apiKeys) usageOf(name string) *apiKeyUsage {
+	u := k.usage[name]
+	if u == nil {
+		u = new(apiKeyUsage)
+		k.usage[name] = u
+	}
+	return u
+}
+
+// writeMetrics writes the usage of the keys with pw.
+func (k *apiKeys) writeMetrics(pw *prom.Writer) {
+	if k == nil {
+		return
+	}
+	pw.Header("geodecode_api_key_rejected_total", "Requests without a known API key.", prom.Counter)
+	pw.Sample("geodecode_api_key_rejected_total", float64(k.rejected.Load()))
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	names := make([]string, 0, len(k.usage))
+	for name := range k.usage {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	pw.Header("geodecode_api_key_requests_total", "Requests authenticated, by API key.", prom.Counter)
+	for _, name := range names {
+		pw.Sample("geodecode_api_key_requests_total", float64(k.usage[name].requests), "key", name)
+	}
+	pw.Header("geodecode_api_key_coordinates_total", "Coordinates resolved, by API key.", prom.Counter)
+	for _, name := range names {
+		pw.Sample("geodecode_api_key_coordinates_total", float64(k.usage[name].coordinates), "key", name)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/geodecodepb"
+)
+
+func TestAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	os.WriteFile(path, []byte("# name key rate burst\nmobile s3cret-mobile 5 20\n\nbatch s3cret-batch\n"), 0o600)
+	keys, err := newAPIKeys("web=s3cret-web", path)
+	if err != nil {
+		t.Fatalf("newAPIKeys: %v", err)
+	}
+	lookup := func(secret string) *apiKey {
+		req := httptest.NewRequest(http.MethodGet, "/reverse", nil)
+		req.Header.Set("X-API-Key", secret)
+		req, _ = keys.authenticate(req)
+		return requestAPIKey(req.Context())
+	}
+	if key := lookup("s3cret-mobile"); key == nil || *key != (apiKey{"mobile", 5, 20}) {
+		t.Errorf("Expected the key mobile with its limits, got %+v", key)
+	}
+	if key := lookup("s3cret-web"); key == nil || key.name != "web" {
+		t.Errorf("Expected the static key web, got %+v", key)
+	}
+
+	// A reload replaces the keys of the file, but keeps the static ones.
+	os.WriteFile(path, []byte("mobile n3w-secret\n"), 0o600)
+	if err := keys.reload(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if lookup("s3cret-mobile") != nil || lookup("s3cret-batch") != nil || lookup("n3w-secret") == nil || lookup("s3cret-web") == nil {
+		t.Errorf("Expected the keys of the new file and the static key after a reload")
+	}
+	os.WriteFile(path, []byte("mobile\n"), 0o600)
+	if err := keys.reload(); err == nil {
+		t.Errorf("Expected an error reloading an invalid file")
+	}
+	if lookup("n3w-secret") == nil {
+		t.Errorf("Expected the previous keys to be kept after a failed reload")
+	}
+
+	for _, tt := range []struct{ static, file string }{
+		{"web", ""},
+		{"web=a,web=b", ""},
+		{"web=a,app=a", ""},
+		{"web=a", "web b\n"},
+		{"", "app a fast\n"},
+		{"", "app a 1 -1\n"},
+		{"", "app a 1 1 1\n"},
+	} {
+		path := filepath.Join(t.TempDir(), "keys")
+		os.WriteFile(path, []byte(tt.file), 0o600)
+		if _, err := newAPIKeys(tt.static, path); err == nil {
+			t.Errorf("Expected an error for %q and the file %q", tt.static, tt.file)
+		}
+	}
+	if keys, err := newAPIKeys("", ""); keys != nil || err != nil {
+		t.Errorf("Expected no keys, got %v, %v", keys, err)
+	}
+}
+
+func TestAPIKeyHandler(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	keys, err := newAPIKeys("web=s3cret", "")
+	if err != nil {
+		t.Fatalf("newAPIKeys: %v", err)
+	}
+	handler := newHandler(geocoder, serveOptions{maxBatch: 10, keys: keys})
+
+	tests := []struct {
+		method, target, header, value string
+		code                          int
+	}{
+		{http.MethodGet, "/reverse?lat=48.9&lon=2.3", "", "", http.StatusUnauthorized},
+		{http.MethodGet, "/reverse?lat=48.9&lon=2.3", "X-API-Key", "wrong", http.StatusUnauthorized},
+		{http.MethodGet, "/reverse?lat=48.9&lon=2.3&api_key=s3cret", "", "", http.StatusUnauthorized},
+		{http.MethodGet, "/reverse?lat=48.9&lon=2.3", "X-API-Key", "s3cret", http.StatusOK},
+		{http.MethodGet, "/reverse?lat=48.9&lon=2.3", "Authorization", "Bearer s3cret", http.StatusOK},
+		{http.MethodPost, "/reverse/batch", "Authorization", "bearer s3cret", http.StatusOK},
+		{http.MethodGet, "/healthz", "", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader("[[48.9, 2.3], [52.5, 13.4]]"))
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("%s %s with %s %q: Expected %d, got %d", tt.method, tt.target, tt.header, tt.value, tt.code, rec.Code)
+		}
+		if rec.Code == http.StatusUnauthorized && !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Bearer") {
+			t.Errorf("Expected a WWW-Authenticate header with the status 401")
+		}
+	}
+
+	// WebSocket requests may send the key as a query parameter.
+	req := httptest.NewRequest(http.MethodGet, "/stream?api_key=s3cret", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	if got := requestSecret(req); got != "s3cret" {
+		t.Errorf("Expected the key of the query of a WebSocket request, got %q", got)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, want := range []string{
+		"geodecode_api_key_rejected_total 3\n",
+		`geodecode_api_key_requests_total{key="web"} 3` + "\n",
+		`geodecode_api_key_coordinates_total{key="web"} 4` + "\n",
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("Expected %q in\n%s", want, rec.Body.String())
+		}
+	}
+}
+
+func TestAPIKeyGRPC(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	keys, err := newAPIKeys("web=s3cret", "")
+	if err != nil {
+		t.Fatalf("newAPIKeys: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, geodecodepb.ReverseGeocodeMethod, nil)
+	req.ProtoMajor = 2
+	req.Header.Set("Content-Type", "application/grpc")
+	rec := httptest.NewRecorder()
+	grpcHandler(geocoder, keys, nil).ServeHTTP(rec, req)
+	if got := rec.Result().Trailer.Get("Grpc-Status"); got != "16" {
+		t.Errorf("Expected grpc-status 16 without a key, got %q", got)
+	}
+}
@@ -0,0 +1,520 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// batchSize is the number of rows the batch command resolves at once.
+const batchSize = 4096
+
+// batchOptions configures the batch command.
+type batchOptions struct {
+	outputOptions
+	inputFormat    string    // csv, kml or kmz
+	latCol, lonCol string    // Names of the coordinate columns in the header
+	delimiter      rune      // Field delimiter of the input, and of csv output
+	workers        int       // Number of batches resolved concurrently
+	errors         *errorLog // Rows that fail are written here and skipped, if set
+	progress       *progress
+}
+
+// runBatch implements the batch command: it streams a CSV file, or standard
+// input, and writes each row with the nearest location appended. Unless
+// -quiet is given, it draws its progress on standard error if that is a
+// terminal.
+func runBatch(args []string) error {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	newGeocoder := geocoderFlags(fs)
+	input := fs.String("input", "-", "CSV, KML or KMZ file to read, or - for standard input")
+	inputFormat := fs.String("input-format", "", "format of -input: csv, kml or kmz (default from the file extension)")
+	output := fs.String("output", "-", "file to write the enriched CSV to, - for standard output, or sqlite://results.db for a SQLite database")
+	latCol := fs.String("lat-col", "lat", "name of the latitude column")
+	lonCol := fs.String("lon-col", "lon", "name of the longitude column")
+	delimiter := fs.String("delimiter", ",", "CSV field delimiter")
+	workers := fs.Int("workers", 1, "number of batches of rows to resolve concurrently, 0 for one per CPU")
+	errorsPath := fs.String("errors", "", "write the rows that fail as JSON lines to this file, or - for standard error, and go on with the others")
+	quiet := fs.Bool("quiet", false, "do not draw a progress bar on the terminal")
+	outputOpts := outputFlags(fs, append(slices.Clip(outputFormats), "parquet", "sqlite"))
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	shape, err := outputOpts()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("geodecode batch [flags]")
+	}
+	if path, ok := strings.CutPrefix(*output, "sqlite://"); ok {
+		*output, shape.format = path, "sqlite"
+	}
+	if shape.format == "sqlite" && (*output == "-" || *output == "") {
+		return badInputf("sqlite output needs a file, such as -output sqlite://results.db")
+	}
+	if utf8.RuneCountInString(*delimiter) != 1 {
+		return badInputf("delimiter must be a single character, got %q", *delimiter)
+	}
+	delim, _ := utf8.DecodeRuneInString(*delimiter)
+	if *inputFormat == "" {
+		*inputFormat = "csv"
+		if ext := strings.ToLower(filepath.Ext(*input)); ext == ".kml" || ext == ".kmz" {
+			*inputFormat = ext[1:]
+		}
+	}
+	if !slices.Contains([]string{"csv", "kml", "kmz"}, *inputFormat) {
+		return badInputf("unknown input format %q", *inputFormat)
+	}
+	if *workers < 0 {
+		return badInputf("workers must not be negative, got %d", *workers)
+	}
+	if *workers == 0 {
+		*workers = runtime.GOMAXPROCS(0)
+	}
+
+	in, size := io.Reader(os.Stdin), int64(-1)
+	if *input != "-" {
+		file, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+			size = info.Size()
+		}
+		in = file
+	}
+	// A single worker splits large batches itself; several workers already
+	// keep the CPUs busy.
+	var geocoderOpts []geodecode.Option
+	if *workers == 1 {
+		geocoderOpts = append(geocoderOpts, geodecode.WithAdaptiveParallelism())
+	}
+	geocoder, err := newGeocoder(geocoderOpts...)
+	if err != nil {
+		return err
+	}
+
+	opts := batchOptions{inputFormat: *inputFormat, latCol: *latCol, lonCol: *lonCol, delimiter: delim, workers: *workers, outputOptions: shape}
+	var errFile *os.File
+	var errWriter *bufio.Writer
+	if *errorsPath == "-" {
+		opts.errors = &errorLog{w: os.Stderr}
+	} else if *errorsPath != "" {
+		if errFile, err = os.Create(*errorsPath); err != nil {
+			return err
+		}
+		errWriter = bufio.NewWriter(errFile)
+		opts.errors = &errorLog{w: errWriter}
+	}
+	// Draw progress only for a person watching, and not over the output.
+	if !*quiet && isTerminal(os.Stderr) && (*output != "-" || !isTerminal(os.Stdout)) {
+		opts.progress = startProgress(os.Stderr, size)
+		defer opts.progress.stop()
+		in = opts.progress.reader(in)
+	}
+	err = batchTo(*output, in, geocoder, opts)
+	if errFile != nil {
+		// Keep the rows that failed so far even if the batch did not finish.
+		// Both run, as arguments are evaluated before cmp.Or picks one.
+		err = cmp.Or(err, errWriter.Flush(), errFile.Close())
+	}
+	if err != nil {
+		return err
+	}
+	if opts.errors != nil && opts.errors.n > 0 {
+		where := *errorsPath
+		if where == "-" {
+			where = "standard error"
+		}
+		return withExitCode(exitFailure, fmt.Errorf("%d rows failed, listed in %s", opts.errors.n, where))
+	}
+	return nil
+}
+
+// batchTo runs batch with the output written to the file at path, or to
+// standard output if path is "-".
+func batchTo(path string, in io.Reader, geocoder *geodecode.RGeocoder, opts batchOptions) error {
+	if path == "-" {
+		w := bufio.NewWriter(os.Stdout)
+		if err := batch(w, in, geocoder, opts); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if opts.format == "sqlite" && opts.tmpl == nil {
+		// Written a page at a time, with the first page last.
+		if err := batch(out, in, geocoder, opts); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+	w := bufio.NewWriter(out)
+	if err := batch(w, in, geocoder, opts); err != nil {
+		out.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// batch reads CSV rows from r, or the records of kmlReader if
+// opts.inputFormat is kml or kmz, resolves the coordinate in the columns
+// named by opts with geocoder, and writes each row to w with the fields of the
+// nearest location in opts appended, or in the other formats of
+// resultWriter. With opts.k above 1, each row is written once for each of
+// its k nearest locations, nearest first. The fields are empty for rows
+// without a match. Rows are resolved batchSize at a time, so inputs of any
+// size are streamed, and with opts.workers above 1 that many batches are
+// resolved concurrently; rows are written in the order they were read
+// either way.
+func batch(w io.Writer, r io.Reader, geocoder *geodecode.RGeocoder, opts batchOptions) error {
+	var reader recordReader
+	switch opts.inputFormat {
+	case "kml":
+		reader = newKMLReader(r)
+	case "kmz":
+		kmz, err := newKMZReader(r)
+		if err != nil {
+			return err
+		}
+		reader = kmz
+	default:
+		csvReader := csv.NewReader(r)
+		csvReader.FieldsPerRecord = -1
+		if opts.delimiter != 0 {
+			csvReader.Comma = opts.delimiter
+		}
+		reader = csvReader
+	}
+	if opts.inputFormat == "kml" || opts.inputFormat == "kmz" {
+		opts.latCol, opts.lonCol = "lat", "lon"
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return errors.New("empty input")
+	}
+	if err != nil {
+		return err
+	}
+	latIdx, lonIdx := columnIndex(header, opts.latCol), columnIndex(header, opts.lonCol)
+	if latIdx < 0 || lonIdx < 0 {
+		return fmt.Errorf("columns %q and %q not found in header %q", opts.latCol, opts.lonCol, header)
+	}
+	rw, err := newResultWriter(w, opts.outputOptions, header, opts.delimiter)
+	if err != nil {
+		return err
+	}
+
+	// next fills c with the next batchSize rows, and returns false once the
+	// input is exhausted.
+	// Rows that fail are recorded in c if opts.errors is set, and else end
+	// the batch.
+	next := func(c *chunk) (bool, error) {
+		c.rows, c.coords, c.lines, c.failures = c.rows[:0], c.coords[:0], c.lines[:0], c.failures[:0]
+		for len(c.rows) < batchSize {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && opts.errors != nil {
+				c.failures = append(c.failures, rowFailure{line: parseErr.Line, err: err})
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+			c.line, _ = reader.FieldPos(0)
+			coord, err := rowCoordinate(record, latIdx, lonIdx)
+			if err != nil {
+				if opts.errors != nil {
+					c.failures = append(c.failures, rowFailure{line: c.line, record: record, err: err})
+					continue
+				}
+				return false, fmt.Errorf("line %d: %w", c.line, err)
+			}
+			c.rows, c.coords, c.lines = append(c.rows, record), append(c.coords, coord), append(c.lines, c.line)
+		}
+		return len(c.rows) > 0 || len(c.failures) > 0, nil
+	}
+
+	if opts.workers > 1 {
+		err = batchParallel(rw, geocoder, opts, next)
+	} else {
+		err = batchSequential(rw, geocoder, opts, next)
+	}
+	if err != nil {
+		return err
+	}
+	return rw.close()
+}
+
+// rowCoordinate returns the coordinate in the fields latIdx and lonIdx of
+// record.
+func rowCoordinate(record []string, latIdx, lonIdx int) ([2]float64, error) {
+	if latIdx >= len(record) || lonIdx >= len(record) {
+		return [2]float64{}, badInputf("want at least %d fields, got %d", max(latIdx, lonIdx)+1, len(record))
+	}
+	return parseLatLon(record[latIdx], record[lonIdx])
+}
+
+// recordReader reads the records of the batch command's input, the header
+// first. It is implemented by csv.Reader and kmlReader.
+type recordReader interface {
+	Read() ([]string, error)
+	FieldPos(field int) (line, column int)
+}
+
+// chunk is a batch of rows of the batch command's input.
+type chunk struct {
+	seq      int // Position of the chunk in the input
+	line     int // Line of the last row
+	rows     [][]string
+	coords   [][2]float64
+	lines    []int                // Line of each row
+	valid    [][2]float64         // Coordinates of the valid rows, reused across chunks
+	validIdx []int                // Index of each of valid in coords
+	found    []geodecode.Result   // Results of QueryInto, reused across chunks
+	results  [][]geodecode.Result // Results of each row; nil for rows that failed
+	failures []rowFailure
+	err      error
+}
+
+// rowFailure is a row of the batch command's input that could not be
+// resolved.
+type rowFailure struct {
+	line   int
+	record []string // nil if the row could not be parsed
+	err    error
+}
+
+// resolve resolves the coordinates of c with geocoder, k nearest locations
+// each. With tolerant set, rows that fail are recorded in c.failures
+// instead of failing the chunk. Rows with invalid coordinates are set aside
+// before the others are resolved, so each row is queried at most once.
+func (c *chunk) resolve(geocoder *geodecode.RGeocoder, k int, tolerant bool) error {
+	c.results = c.results[:0]
+	c.valid, c.validIdx = c.valid[:0], c.validIdx[:0]
+	for i, coord := range c.coords {
+		c.results = append(c.results, nil)
+		if err := geocoder.Validate(coord); err != nil {
+			if !tolerant {
+				return wrapf(err, "line %d", c.lines[i])
+			}
+			c.failures = append(c.failures, rowFailure{line: c.lines[i], record: c.rows[i], err: err})
+			continue
+		}
+		c.valid, c.validIdx = append(c.valid, coord), append(c.validIdx, i)
+	}
+	if len(c.valid) == 0 {
+		return nil
+	}
+	if k <= 1 {
+		var err error
+		if c.found, err = geocoder.QueryInto(c.found[:0], c.valid...); err != nil {
+			if !tolerant {
+				return wrapf(err, "rows ending at line %d", c.line)
+			}
+			// Such as a dataset that could not be loaded, which fails them all.
+			for _, i := range c.validIdx {
+				c.failures = append(c.failures, rowFailure{line: c.lines[i], record: c.rows[i], err: err})
+			}
+			return nil
+		}
+		for j, i := range c.validIdx {
+			c.results[i] = c.found[j : j+1 : j+1]
+		}
+		return nil
+	}
+	for j, i := range c.validIdx {
+		results, err := resolveK(geocoder, c.valid[j], k)
+		if err != nil {
+			if !tolerant {
+				return wrapf(err, "line %d", c.lines[i])
+			}
+			c.failures = append(c.failures, rowFailure{line: c.lines[i], record: c.rows[i], err: err})
+		}
+		c.results[i] = results
+	}
+	return nil
+}
+
+// write writes the rows of c with their results to rw, the rows that
+// failed to errs if it is not nil, and records them in p.
+func (c *chunk) write(rw *resultWriter, errs *errorLog, p *progress) error {
+	for i, results := range c.results {
+		for _, res := range results {
+			if err := rw.write(c.coords[i], c.rows[i], res); err != nil {
+				return err
+			}
+		}
+	}
+	if errs != nil {
+		slices.SortStableFunc(c.failures, func(a, b rowFailure) int { return cmp.Compare(a.line, b.line) })
+		for _, f := range c.failures {
+			if err := errs.write(f); err != nil {
+				return err
+			}
+		}
+	}
+	p.add(len(c.rows))
+	return nil
+}
+
+// errorLog writes the rows the batch command failed to resolve as JSON
+// lines: objects with the row's line, its fields as record (null if it
+// could not be parsed) and the error.
+type errorLog struct {
+	w io.Writer
+	n int // Number of rows written
+}
+
+// write writes the failed row f.
+func (l *errorLog) write(f rowFailure) error {
+	data, err := json.Marshal(struct {
+		Line   int      `json:"line"`
+		Record []string `json:"record"`
+		Error  string   `json:"error"`
+	}{f.line, f.record, errorMessage(f.err)})
+	if err != nil {
+		return err
+	}
+	l.n++
+	_, err = l.w.Write(append(data, '\n'))
+	return err
+}
+
+// batchSequential resolves and writes the chunks returned by next one after
+// the other.
+func batchSequential(rw *resultWriter, geocoder *geodecode.RGeocoder, opts batchOptions, next func(*chunk) (bool, error)) error {
+	var c chunk
+	for {
+		ok, err := next(&c)
+		if err != nil || !ok {
+			return err
+		}
+		if err := c.resolve(geocoder, opts.k, opts.errors != nil); err != nil {
+			return err
+		}
+		if err := c.write(rw, opts.errors, opts.progress); err != nil {
+			return err
+		}
+	}
+}
+
+// batchParallel resolves the chunks returned by next on opts.workers
+// goroutines and writes them in order. Chunks resolved ahead of an earlier
+// one wait in a reorder buffer, which together with the chunks being read
+// and resolved is bounded to twice as many chunks as there are workers, so
+// memory stays bounded when one chunk is slow.
+func batchParallel(rw *resultWriter, geocoder *geodecode.RGeocoder, opts batchOptions, next func(*chunk) (bool, error)) error {
+	free := make(chan *chunk, 2*opts.workers)
+	for range cap(free) {
+		free <- &chunk{}
+	}
+	jobs := make(chan *chunk)
+	resolved := make(chan *chunk)
+	stop := make(chan struct{})
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(jobs)
+		for seq := 0; ; seq++ {
+			var c *chunk
+			select {
+			case <-stop: // Checked first, as free may hold chunks too
+				readErr <- nil
+				return
+			default:
+			}
+			select {
+			case c = <-free:
+			case <-stop:
+				readErr <- nil
+				return
+			}
+			ok, err := next(c)
+			if err != nil || !ok {
+				readErr <- err
+				return
+			}
+			c.seq = seq
+			jobs <- c
+		}
+	}()
+	var wg sync.WaitGroup
+	for range opts.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				c.err = c.resolve(geocoder, opts.k, opts.errors != nil)
+				resolved <- c
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(resolved)
+	}()
+
+	pending := make(map[int]*chunk)
+	seq := 0
+	var err error
+	for c := range resolved {
+		pending[c.seq] = c
+		for c := pending[seq]; c != nil; c = pending[seq] {
+			delete(pending, seq)
+			seq++
+			if err == nil {
+				if err = c.err; err == nil {
+					err = c.write(rw, opts.errors, opts.progress)
+				}
+				if err != nil {
+					close(stop)
+				}
+			}
+			free <- c // Never blocks, as there are only cap(free) chunks
+		}
+	}
+	if rerr := <-readErr; err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// columnIndex returns the index of the column named name in header,
+// ignoring case and surrounding space, or -1.
+func columnIndex(header []string, name string) int {
+	for i, col := range header {
+		if strings.EqualFold(strings.TrimSpace(col), name) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/internal/parquet"
+)
+
+func TestBatch(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var out bytes.Buffer
+	input := "id;lng;Lat\n1;13.4;52.5\n2;0;0\n3;2.3;48.9\n"
+	if err := batch(&out, strings.NewReader(input), geocoder, batchOptions{latCol: "lat", lonCol: "lng", delimiter: ';', outputOptions: outputOptions{format: "csv", fields: defaultFields}}); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	want := "id;lng;Lat;city;admin1;admin2;cc;country;distance\n" +
+		"1;13.4;52.5;Berlin;Berlin;;DE;Germany;2.325\n" +
+		"2;0;0;;;;;;\n" +
+		"3;2.3;48.9;Paris;Ile-de-France;;FR;France;5.758\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	input = "lat,lon\n52.5,13.4\n0,0\n"
+	if err := batch(&out, strings.NewReader(input), geocoder, batchOptions{latCol: "lat", lonCol: "lon", outputOptions: outputOptions{format: "csv", fields: []string{"city"}, k: 2}}); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	want = "lat,lon,city\n52.5,13.4,Berlin\n0,0,\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	opts := batchOptions{latCol: "lat", lonCol: "lon", outputOptions: outputOptions{format: "csv", fields: defaultFields}}
+	for _, input := range []string{"", "latitude,lon\n", "lat,lon\n52.5\n", "lat,lon\n52.5,east\n", "lat,lon\n52.5,13.4\n91,0\n"} {
+		if err := batch(&out, strings.NewReader(input), geocoder, opts); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestBatchQueriesOnce(t *testing.T) {
+	var queries int
+	hook := func([2]float64, geodecode.Location, error, time.Duration) { queries++ }
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithQueryHook(hook))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	input := "lat,lon\n52.5,13.4\n91,0\n48.9,2.3\n"
+	var out, errs bytes.Buffer
+	opts := batchOptions{latCol: "lat", lonCol: "lon", errors: &errorLog{w: &errs}, outputOptions: outputOptions{format: "csv", fields: []string{"city"}}}
+	if err := batch(&out, strings.NewReader(input), geocoder, opts); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("Expected the 2 valid rows to be queried once each, got %d queries", queries)
+	}
+	if want := "lat,lon,city\n52.5,13.4,Berlin\n48.9,2.3,Paris\n"; out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	if want := `{"line":3,"record":["91","0"],"error":"invalid coordinate: lat=91, lon=0"}` + "\n"; errs.String() != want {
+		t.Errorf("Expected %q, got %q", want, errs.String())
+	}
+
+	// Rows that cannot be resolved at all are each reported with the reason.
+	broken, err := geodecode.New(geodecode.WithDataset(t.TempDir()+"/missing.csv"), geodecode.WithLogger(nil))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	out.Reset()
+	errs.Reset()
+	opts.errors = &errorLog{w: &errs}
+	if err := batch(&out, strings.NewReader(input), broken, opts); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if opts.errors.n != 3 || strings.Count(errs.String(), "no data loaded") != 2 {
+		t.Errorf("Expected 3 failed rows, 2 of them for the missing dataset, got:\n%s", errs.String())
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	dir := t.TempDir()
+	input, output := filepath.Join(dir, "coords.csv"), filepath.Join(dir, "enriched.csv")
+	if err := os.WriteFile(input, []byte("lat,lng\n52.5,13.4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-dataset", writeTestDataset(t), "-input", input, "-lon-col", "lng", "-output", output}
+	if err := runBatch(args); err != nil {
+		t.Fatalf("runBatch: %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(got), "lat,lng,city,") || !strings.Contains(string(got), "\n52.5,13.4,Berlin,") {
+		t.Errorf("Expected the enriched row for Berlin, got %q", got)
+	}
+	if err := runBatch([]string{"extra"}); err == nil {
+		t.Errorf("Expected a usage error for an argument")
+	}
+}
+
+func TestBatchWorkers(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var input strings.Builder
+	input.WriteString("id,lat,lon\n")
+	coords := []string{"52.5,13.4", "48.9,2.3", "0,0"}
+	for i := range 3*batchSize + 5 {
+		fmt.Fprintf(&input, "%d,%s\n", i, coords[i%len(coords)])
+	}
+
+	opts := batchOptions{latCol: "lat", lonCol: "lon", outputOptions: outputOptions{format: "csv", fields: []string{"city"}}}
+	var want bytes.Buffer
+	if err := batch(&want, strings.NewReader(input.String()), geocoder, opts); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	for _, workers := range []int{2, 3, 8} {
+		opts.workers = workers
+		var out bytes.Buffer
+		if err := batch(&out, strings.NewReader(input.String()), geocoder, opts); err != nil {
+			t.Fatalf("batch with %d workers: %v", workers, err)
+		}
+		if out.String() != want.String() {
+			t.Errorf("Expected the output of a single worker with %d workers, got %d bytes instead of %d", workers, out.Len(), want.Len())
+		}
+	}
+
+	invalid := input.String() + "x,91,0\n" + input.String()[len("id,lat,lon\n"):]
+	var out bytes.Buffer
+	if err := batch(&out, strings.NewReader(invalid), geocoder, opts); err == nil {
+		t.Errorf("Expected an error for an invalid coordinate")
+	}
+	if !strings.HasPrefix(want.String(), out.String()) {
+		t.Errorf("Expected the rows before the invalid one to be written in order")
+	}
+}
+
+func TestRunBatchErrors(t *testing.T) {
+	dir := t.TempDir()
+	input, output, errorsPath := filepath.Join(dir, "coords.csv"), filepath.Join(dir, "enriched.csv"), filepath.Join(dir, "errors.jsonl")
+	data := "id,lat,lon\n1,52.5,13.4\n2,north,13.4\n3,91,0\n4\n5,48.9,2.3\n"
+	if err := os.WriteFile(input, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-dataset", writeTestDataset(t), "-input", input, "-output", output, "-fields", "city"}
+	if err := runBatch(args); exitCode(err) != exitBadInput {
+		t.Errorf("Expected exit code %d without -errors, got %d (%v)", exitBadInput, exitCode(err), err)
+	} else if msg := errorMessage(err); strings.Contains(msg, "geodecode:") {
+		t.Errorf("Expected no geodecode prefix in the message, got %q", msg)
+	}
+
+	err := runBatch(append(args, "-errors", errorsPath))
+	if exitCode(err) != exitFailure || err == nil || !strings.Contains(err.Error(), "3 rows failed") {
+		t.Errorf("Expected exit code %d for 3 failed rows, got %d (%v)", exitFailure, exitCode(err), err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "id,lat,lon,city\n1,52.5,13.4,Berlin\n5,48.9,2.3,Paris\n"; string(got) != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	got, err = os.ReadFile(errorsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(got)), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], `{"line":3,"record":["2","north","13.4"],"error":"invalid latitude`) ||
+		!strings.HasPrefix(lines[1], `{"line":4,"record":["3","91","0"],"error":"invalid coordinate`) || !strings.HasPrefix(lines[2], `{"line":5,"record":["4"],"error":"want at least 3 fields`) {
+		t.Errorf("Expected the rows of lines 3, 4 and 5, got:\n%s", got)
+	}
+
+	if err := os.WriteFile(input, []byte("id,lat,lon\n1,52.5,13.4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runBatch(append(args, "-errors", errorsPath)); err != nil {
+		t.Errorf("Expected no error without failed rows, got %v", err)
+	}
+}
+
+func TestBatchSQLite(t *testing.T) {
+	dir := t.TempDir()
+	input, output := filepath.Join(dir, "coords.csv"), filepath.Join(dir, "results.db")
+	if err := os.WriteFile(input, []byte("id,lat,lon\n1,52.5,13.4\n2,0,0\n3,48.9,2.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-dataset", writeTestDataset(t), "-max-distance", "100", "-input", input}
+	if err := runBatch(append(args, "-format", "sqlite")); exitCode(err) != exitBadInput {
+		t.Errorf("Expected exit code %d for sqlite to standard output, got %d (%v)", exitBadInput, exitCode(err), err)
+	}
+	if err := runBatch(append(args, "-output", "sqlite://"+output)); err != nil {
+		t.Fatalf("runBatch: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("SQLite format 3\x00")) {
+		t.Fatalf("Expected a SQLite database, got %q", data[:min(len(data), 16)])
+	}
+
+	sqlite3, err := exec.LookPath("sqlite3")
+	if err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	out, err := exec.Command(sqlite3, output, "PRAGMA integrity_check; SELECT id, city, cc, distance FROM results ORDER BY cc, id;").CombinedOutput()
+	if err != nil {
+		t.Fatalf("sqlite3: %v\n%s", err, out)
+	}
+	if want := "ok\n2|||\n1|Berlin|DE|2.325\n3|Paris|FR|5.758\n"; string(out) != want {
+		t.Errorf("Expected %q, got %q", want, out)
+	}
+}
+
+func TestBatchParquet(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var out bytes.Buffer
+	input := "id,lat,lon\n1,52.5,13.4\n2,0,0\n3,48.9,2.3\n"
+	opts := batchOptions{latCol: "lat", lonCol: "lon", outputOptions: outputOptions{format: "parquet", fields: []string{"city", "distance", "geonameid"}}}
+	if err := batch(&out, strings.NewReader(input), geocoder, opts); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	f, err := parquet.ReadAll(out.Bytes())
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if want := []string{"id", "lat", "lon", "city", "distance", "geonameid"}; !slices.Equal(f.Columns(), want) {
+		t.Errorf("Expected columns %v, got %v", want, f.Columns())
+	}
+	var got []string
+	for _, name := range []string{"id", "city", "distance", "geonameid"} {
+		col, err := f.ReadColumn(name)
+		if err != nil {
+			t.Fatalf("ReadColumn(%q): %v", name, err)
+		}
+		for i := range col.Len() {
+			switch {
+			case col.IsNull(i):
+				got = append(got, "null")
+			case name == "distance":
+				d, _ := col.Float(i)
+				got = append(got, fmt.Sprint(d))
+			case name == "geonameid":
+				n, _ := col.Int(i)
+				got = append(got, fmt.Sprint(n))
+			default:
+				got = append(got, col.String(i))
+			}
+		}
+	}
+	if want := "1 2 3 Berlin null Paris 2.325 null 5.758 0 null 0"; strings.Join(got, " ") != want {
+		t.Errorf("Expected %q, got %q", want, strings.Join(got, " "))
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/bench"
+)
+
+// runBench implements the bench command: it measures the cold start,
+// query latency and batch throughput of the geocoder configured by the
+// flags and prints a summary. With -indexes, it compares the spatial indexes
+// on samples of the embedded dataset instead and prints a table of the
+// results.
+func runBench(args []string) error {
+	def := bench.DefaultConfig()
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	newGeocoder := geocoderFlags(fs)
+	indexes := fs.Bool("indexes", false, "compare the spatial indexes instead")
+	sizes := fs.String("sizes", joinInts(def.Sizes), "comma-separated numbers of locations to sample with -indexes")
+	queries := fs.Int("queries", def.Queries, "number of queries per measurement")
+	seed := fs.Uint64("seed", def.Seed, "seed of the sampled locations and queries")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("geodecode bench [flags]")
+	}
+	if !*indexes {
+		c, err := bench.MeasureCapacity(func() (*geodecode.RGeocoder, error) {
+			return newGeocoder(geodecode.WithAdaptiveParallelism())
+		}, *queries, *seed)
+		if err != nil {
+			return err
+		}
+		return bench.WriteCapacity(os.Stdout, c)
+	}
+
+	cfg := def
+	cfg.Queries, cfg.Seed = *queries, *seed
+	cfg.Sizes = nil
+	for _, field := range strings.Split(*sizes, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid size %q", field)
+		}
+		cfg.Sizes = append(cfg.Sizes, size)
+	}
+	// All metrics are compared unless one is chosen.
+	metricSet := false
+	fs.Visit(func(f *flag.Flag) { metricSet = metricSet || f.Name == "metric" })
+	if metricSet {
+		m, err := parseMetric(fs.Lookup("metric").Value.String())
+		if err != nil {
+			return err
+		}
+		cfg.Metrics = []geodecode.Metric{m}
+	}
+
+	ms, err := bench.Run(cfg)
+	if err != nil {
+		return err
+	}
+	return bench.WriteTable(os.Stdout, ms)
+}
+
+// joinInts formats ns as a comma-separated list.
+func joinInts(ns []int) string {
+	fields := make([]string, len(ns))
+	for i, n := range ns {
+		fields[i] = strconv.Itoa(n)
+	}
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,17 @@
+package main
+
+import "testing"
+
+func TestRunBench(t *testing.T) {
+	if err := runBench([]string{"-indexes", "-sizes", "100", "-queries", "10", "-metric", "euclidean"}); err != nil {
+		t.Errorf("Expected the benchmark to run, got %v", err)
+	}
+	if err := runBench([]string{"-dataset", writeTestDataset(t), "-queries", "10"}); err != nil {
+		t.Errorf("Expected the capacity benchmark to run, got %v", err)
+	}
+	for _, args := range [][]string{{"-indexes", "-sizes", "0"}, {"-indexes", "-sizes", "ten"}, {"-indexes", "-metric", "manhattan"}, {"-queries", "0"}, {"extra"}} {
+		if err := runBench(args); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// config holds the settings of a config file by section, which is "" for
+// the top level and else the name of a command. Settings are named after
+// the flags they set.
+type config map[string]map[string]string
+
+// flagEnv maps the flags that default to an environment variable to its
+// name. A set variable takes precedence over the config file.
+var flagEnv = map[string]string{"dataset": dataEnv, "data": dataEnv}
+
+// defaultConfigPath returns the path of the config file read when -config
+// is not given, or "" if there is no user config directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "geodecode", "config.yaml")
+}
+
+// parseFlags parses args with fs, after registering the flag -config on it,
+// and then sets the flags that args do not set from the config file. Flags
+// thus take precedence over environment variables, which take precedence
+// over the config file, which takes precedence over the defaults. A missing
+// config file is only an error if it was named with -config.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	path := fs.String("config", "", "config file with defaults for the flags (default "+cmp.Or(defaultConfigPath(), "none")+")")
+	if err := fs.Parse(args); err != nil {
+		return withExitCode(exitBadInput, err)
+	}
+	named := *path != ""
+	if !named {
+		if *path = defaultConfigPath(); *path == "" {
+			return nil
+		}
+	}
+	cfg, err := readConfig(*path)
+	if err != nil {
+		if !named && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return withExitCode(exitBadInput, err)
+	}
+	if err := cfg.apply(fs); err != nil {
+		return withExitCode(exitBadInput, fmt.Errorf("%s: %w", *path, err))
+	}
+	return nil
+}
+
+// apply sets the flags of fs from the top level of cfg and then from the
+// section named after fs, skipping flags that were set on the command line
+// or by an environment variable. Top-level settings without a flag in fs
+// are ignored, as they may be meant for other commands.
+func (cfg config) apply(fs *flag.FlagSet) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if set["data"] {
+		set["dataset"] = true // Aliases
+	}
+
+	for _, section := range []string{"", fs.Name()} {
+		keys := make([]string, 0, len(cfg[section]))
+		for key := range cfg[section] {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if fs.Lookup(key) == nil {
+				if section == "" {
+					continue
+				}
+				return fmt.Errorf("unknown setting %q for the %s command", key, section)
+			}
+			if env := flagEnv[key]; set[key] || key == "config" || env != "" && os.Getenv(env) != "" {
+				continue
+			}
+			if err := fs.Set(key, cfg[section][key]); err != nil {
+				return fmt.Errorf("setting %s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readConfig reads the config file at path, which is written in a subset
+// of YAML: "key: value" lines at the top level, and sections named after a
+// command whose indented "key: value" lines apply to that command only.
+// Values may be quoted, and # starts a comment.
+//
+//	dataset: /srv/data/gazetteer.csv.gz
+//	units: mi
+//	serve:
+//	  addr: ":8080"
+func readConfig(path string) (config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := config{"": {}}
+	section := ""
+	for i, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == '#' || trimmed == "---" {
+			continue
+		}
+		key, rest, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: want key: value, got %q", path, i+1, trimmed)
+		}
+		key = strings.TrimSpace(key)
+		value, err := parseConfigValue(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, i+1, err)
+		}
+
+		indented := line[0] == ' ' || line[0] == '\t'
+		rest = strings.TrimSpace(rest)
+		switch {
+		case indented && section == "":
+			return nil, fmt.Errorf("%s:%d: indented %q outside a command section", path, i+1, key)
+		case indented:
+			cfg[section][key] = value
+		case rest == "" || rest[0] == '#':
+			if !slices.Contains(commandOrder, key) {
+				return nil, fmt.Errorf("%s:%d: unknown command section %q", path, i+1, key)
+			}
+			section = key
+			if cfg[section] == nil {
+				cfg[section] = make(map[string]string)
+			}
+		default:
+			section = ""
+			cfg[""][key] = value
+		}
+	}
+	return cfg, nil
+}
+
+// parseConfigValue parses the value after the colon of a config line: text
+// up to a comment, or a string in double or single quotes.
+func parseConfigValue(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	var value, rest string
+	switch {
+	case strings.HasPrefix(s, `"`):
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s", s)
+		}
+		value, _ = strconv.Unquote(quoted)
+		rest = s[len(quoted):]
+	case strings.HasPrefix(s, "'"):
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("invalid quoted value %s", s)
+		}
+		value, rest = s[1:end+1], s[end+2:]
+	default:
+		if i := strings.Index(s, " #"); i >= 0 {
+			s = s[:i]
+		}
+		return strings.TrimSpace(s), nil
+	}
+	if rest = strings.TrimSpace(rest); rest != "" && rest[0] != '#' {
+		return "", fmt.Errorf("unexpected %q after quoted value", rest)
+	}
+	return value, nil
+}
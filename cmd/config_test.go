@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeConfig writes data to a config file in a temporary directory and
+// returns its path.
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseFlags(t *testing.T) {
+	path := writeConfig(t, `# Defaults for every command
+dataset: config.csv
+units: mi
+max-distance: 50 # km
+format: json
+addr: ":9090"
+
+lookup:
+  units: 'nmi'
+  fields: "city, cc"
+`)
+	t.Setenv(dataEnv, "env.csv")
+	fs := flag.NewFlagSet("lookup", flag.ContinueOnError)
+	geocoderFlags(fs)
+	outputFlags(fs, append([]string{"text"}, outputFormats...))
+	if err := parseFlags(fs, []string{"-config", path, "-format", "csv", "52.5,13.4"}); err != nil {
+		t.Fatalf("parseFlags: %v", err)
+	}
+	want := map[string]string{"dataset": "env.csv", "units": "nmi", "max-distance": "50", "format": "csv", "fields": "city, cc"}
+	for name, value := range want {
+		if got := fs.Lookup(name).Value.String(); got != value {
+			t.Errorf("-%s: Expected %q, got %q", name, value, got)
+		}
+	}
+	if fs.NArg() != 1 {
+		t.Errorf("Expected 1 argument, got %d", fs.NArg())
+	}
+
+	for _, data := range []string{"units mi\n", "  units: mi\n", "deploy:\n  units: mi\n", "lookup:\n  colour: red\n", "k: many\n", "units: \"mi\n", "units: 'mi' km\n"} {
+		fs := flag.NewFlagSet("lookup", flag.ContinueOnError)
+		outputFlags(fs, outputFormats)
+		fs.String("units", "km", "")
+		if err := parseFlags(fs, []string{"-config", writeConfig(t, data)}); err == nil {
+			t.Errorf("Expected an error for %q", data)
+		}
+	}
+	fs = flag.NewFlagSet("lookup", flag.ContinueOnError)
+	if err := parseFlags(fs, []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}); err == nil {
+		t.Errorf("Expected an error for a missing config file")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// corsMaxAge is how long, in seconds, browsers may cache the answer to a
+// preflight request.
+const corsMaxAge = "3600"
+
+// corsPolicy lets the browsers of pages from other origins call the serve
+// command, with the headers of Cross-Origin Resource Sharing (CORS). A nil
+// *corsPolicy sends no CORS headers, so browsers keep such pages from
+// reading the responses.
+type corsPolicy struct {
+	origins []string // Allowed origins, or "*" for any
+	methods []string // Allowed methods
+}
+
+// newCORSPolicy returns the policy allowing origins and methods, both
+// comma-separated lists, or nil if origins is empty.
+func newCORSPolicy(origins, methods string) (*corsPolicy, error) {
+	c := new(corsPolicy)
+	for origin := range strings.SplitSeq(origins, ",") {
+		if origin = strings.TrimSuffix(strings.TrimSpace(origin), "/"); origin == "" {
+			continue
+		}
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			return nil, fmt.Errorf("cors-origins: want origins such as https://example.com, or *, got %q", origin)
+		}
+		c.origins = append(c.origins, strings.ToLower(origin))
+	}
+	if len(c.origins) == 0 {
+		return nil, nil
+	}
+	for method := range strings.SplitSeq(methods, ",") {
+		if method = strings.ToUpper(strings.TrimSpace(method)); method != "" {
+			c.methods = append(c.methods, method)
+		}
+	}
+	if len(c.methods) == 0 {
+		return nil, errors.New("cors-methods: no methods given")
+	}
+	return c, nil
+}
+
+// allows tells whether requests from origin are allowed. A nil *corsPolicy
+// allows none.
+func (c *corsPolicy) allows(origin string) bool {
+	return c != nil && origin != "" && (slices.Contains(c.origins, "*") || slices.Contains(c.origins, strings.ToLower(origin)))
+}
+
+// handle returns h with the CORS headers added to the responses to allowed
+// origins. It answers their preflight requests itself, for the methods of
+// c and the headers clients send credentials, bodies and ETags with.
+func (c *corsPolicy) handle(h http.Handler) http.Handler {
+	if c == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if !c.allows(origin) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		method := r.Header.Get("Access-Control-Request-Method")
+		if r.Method != http.MethodOptions || method == "" {
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, Retry-After")
+			h.ServeHTTP(w, r)
+			return
+		}
+		// A preflight request, asking whether method may be used.
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		if slices.Contains(c.methods, method) {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(c.methods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, If-None-Match, X-API-Key")
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestCORS(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	cors, err := newCORSPolicy("https://app.example.com/, https://maps.example.com", "get")
+	if err != nil {
+		t.Fatalf("newCORSPolicy: %v", err)
+	}
+	handler := newHandler(geocoder, serveOptions{maxBatch: 10, cors: cors})
+
+	tests := []struct {
+		method, origin, requestMethod string
+		code                          int
+		allowOrigin, allowMethods     string
+	}{
+		{http.MethodGet, "https://app.example.com", "", http.StatusOK, "https://app.example.com", ""},
+		{http.MethodGet, "https://evil.example.com", "", http.StatusOK, "", ""},
+		{http.MethodGet, "", "", http.StatusOK, "", ""},
+		{http.MethodOptions, "https://maps.example.com", "GET", http.StatusNoContent, "https://maps.example.com", "GET"},
+		{http.MethodOptions, "https://maps.example.com", "POST", http.StatusNoContent, "https://maps.example.com", ""},
+		{http.MethodOptions, "https://evil.example.com", "GET", http.StatusMethodNotAllowed, "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/reverse?lat=48.9&lon=2.3", nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if tt.requestMethod != "" {
+			req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("%s from %q: Expected %d, got %d", tt.method, tt.origin, tt.code, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+			t.Errorf("%s from %q: Expected Access-Control-Allow-Origin %q, got %q", tt.method, tt.origin, tt.allowOrigin, got)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Methods"); got != tt.allowMethods {
+			t.Errorf("%s from %q: Expected Access-Control-Allow-Methods %q, got %q", tt.method, tt.origin, tt.allowMethods, got)
+		}
+		if rec.Header().Get("Vary") == "" {
+			t.Errorf("%s from %q: Expected a Vary header", tt.method, tt.origin)
+		}
+	}
+
+	open, err := newCORSPolicy("*", "GET,POST")
+	if err != nil || !open.allows("http://localhost:3000") || open.allows("") {
+		t.Errorf("Expected * to allow every origin, got %v", err)
+	}
+	for _, origins := range []string{"app.example.com", "https://app.example.com"} {
+		if _, err := newCORSPolicy(origins, " , "); err == nil {
+			t.Errorf("Expected an error for the origins %q without methods", origins)
+		}
+	}
+	if cors, err := newCORSPolicy("", "GET"); cors != nil || err != nil {
+		t.Errorf("Expected no policy without origins, got %v, %v", cors, err)
+	}
+}
@@ -0,0 +1,188 @@
+// Command countrynames-gen generates the localized country name table used by
+// geodecode.WithCountryLocale from the translations of the iso-codes project
+// (https://salsa.debian.org/iso-codes-team/iso-codes), as installed on most
+// Linux distributions:
+//
+//	go run ./cmd/countrynames-gen -out zz_country_names.go
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultLocales are the locales included in the generated table.
+const defaultLocales = "ar,de,es,fr,hi,it,ja,ko,nl,pl,pt,pt_BR,ru,sv,tr,zh_CN,zh_TW"
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "countrynames-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the command line and generates the output file.
+func run(args []string) error {
+	fs := flag.NewFlagSet("countrynames-gen", flag.ContinueOnError)
+	codes := fs.String("codes", "/usr/share/iso-codes/json/iso_3166-1.json", "iso-codes country list")
+	localeDir := fs.String("locales", "/usr/share/locale", "directory holding <locale>/LC_MESSAGES/iso_3166-1.mo")
+	locales := fs.String("lang", defaultLocales, "comma-separated locales to include")
+	out := fs.String("out", "zz_country_names.go", "output Go file")
+	pkg := fs.String("pkg", "geodecode", "package name of the generated file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	countries, err := readCountries(*codes)
+	if err != nil {
+		return err
+	}
+
+	table := make(map[string]map[string]string)
+	for _, locale := range strings.Split(*locales, ",") {
+		data, err := os.ReadFile(filepath.Join(*localeDir, locale, "LC_MESSAGES", "iso_3166-1.mo"))
+		if err != nil {
+			return err
+		}
+		messages, err := parseMO(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", locale, err)
+		}
+		names := make(map[string]string)
+		for _, c := range countries {
+			if name, ok := messages[c.msgid()]; ok && name != "" {
+				names[c.Alpha2] = name
+			} else if name, ok := messages[c.Name]; ok && name != "" {
+				names[c.Alpha2] = name
+			}
+		}
+		table[localeKey(locale)] = names
+	}
+
+	src, err := generate(*pkg, table)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+// country is an entry of the iso-codes country list.
+type country struct {
+	Alpha2     string `json:"alpha_2"`
+	Name       string `json:"name"`
+	CommonName string `json:"common_name"`
+}
+
+// msgid returns the English name that translations are looked up by,
+// preferring the common name (e.g. "Bolivia" over "Bolivia, Plurinational
+// State of").
+func (c country) msgid() string {
+	if c.CommonName != "" {
+		return c.CommonName
+	}
+	return c.Name
+}
+
+// readCountries reads the iso-codes country list at path.
+func readCountries(path string) ([]country, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Countries []country `json:"3166-1"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return list.Countries, nil
+}
+
+// localeKey normalizes a gettext locale name such as pt_BR to the form used
+// in the generated table (pt-br).
+func localeKey(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// parseMO returns the translations in a compiled gettext catalog, keyed by
+// their original string.
+func parseMO(data []byte) (map[string]string, error) {
+	if len(data) < 20 {
+		return nil, errors.New("catalog too short")
+	}
+	var order binary.ByteOrder
+	switch {
+	case binary.LittleEndian.Uint32(data) == 0x950412de:
+		order = binary.LittleEndian
+	case binary.BigEndian.Uint32(data) == 0x950412de:
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("not a gettext catalog")
+	}
+	n := order.Uint32(data[8:])
+	origTable, transTable := order.Uint32(data[12:]), order.Uint32(data[16:])
+
+	str := func(table, i uint32) (string, error) {
+		entry := uint64(table) + 8*uint64(i)
+		if entry+8 > uint64(len(data)) {
+			return "", errors.New("string table out of range")
+		}
+		length, offset := uint64(order.Uint32(data[entry:])), uint64(order.Uint32(data[entry+4:]))
+		if offset+length > uint64(len(data)) {
+			return "", errors.New("string out of range")
+		}
+		return string(data[offset : offset+length]), nil
+	}
+
+	messages := make(map[string]string, n)
+	for i := uint32(0); i < n; i++ {
+		orig, err := str(origTable, i)
+		if err != nil {
+			return nil, err
+		}
+		trans, err := str(transTable, i)
+		if err != nil {
+			return nil, err
+		}
+		messages[orig] = trans
+	}
+	return messages, nil
+}
+
+// generate returns the formatted Go source declaring table.
+func generate(pkg string, table map[string]map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by countrynames-gen from the iso-codes translations; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintln(&buf, "// countryNames maps a locale and an ISO country code to the country's name.")
+	fmt.Fprintln(&buf, "var countryNames = map[string]map[string]string{")
+	for _, locale := range sortedKeys(table) {
+		fmt.Fprintf(&buf, "\t%q: {\n", locale)
+		for _, cc := range sortedKeys(table[locale]) {
+			fmt.Fprintf(&buf, "\t\t%q: %q,\n", cc, table[locale][cc])
+		}
+		fmt.Fprintln(&buf, "\t},")
+	}
+	fmt.Fprintln(&buf, "}")
+	return format.Source(buf.Bytes())
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// buildMO returns a little-endian gettext catalog holding messages.
+func buildMO(messages [][2]string) []byte {
+	n := uint32(len(messages))
+	origTable := uint32(28)
+	transTable := origTable + 8*n
+	offset := transTable + 8*n
+
+	data := make([]byte, offset)
+	binary.LittleEndian.PutUint32(data[0:], 0x950412de)
+	binary.LittleEndian.PutUint32(data[8:], n)
+	binary.LittleEndian.PutUint32(data[12:], origTable)
+	binary.LittleEndian.PutUint32(data[16:], transTable)
+	for i, m := range messages {
+		for j, table := range []uint32{origTable, transTable} {
+			entry := table + 8*uint32(i)
+			binary.LittleEndian.PutUint32(data[entry:], uint32(len(m[j])))
+			binary.LittleEndian.PutUint32(data[entry+4:], uint32(len(data)))
+			data = append(data, m[j]...)
+		}
+	}
+	return data
+}
+
+func TestParseMO(t *testing.T) {
+	data := buildMO([][2]string{{"Germany", "Allemagne"}, {"Bolivia", "Bolivie"}})
+	messages, err := parseMO(data)
+	if err != nil {
+		t.Fatalf("parseMO: %v", err)
+	}
+	if messages["Germany"] != "Allemagne" || messages["Bolivia"] != "Bolivie" {
+		t.Errorf("Expected both translations, got %v", messages)
+	}
+
+	if _, err := parseMO(data[:40]); err == nil {
+		t.Errorf("Expected an error for a truncated catalog")
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strconv"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// debugReport is what GET /debug/geodecode reports.
+type debugReport struct {
+	Dataset  datasetReport         `json:"dataset"`
+	LoadedAt time.Time             `json:"loadedAt"`
+	Cache    geodecode.CacheStats  `json:"cache"`
+	Runtime  runtimeReport         `json:"runtime"`
+	Query    *geodecode.QueryTrace `json:"query,omitempty"`
+}
+
+// runtimeReport describes the Go runtime of the process.
+type runtimeReport struct {
+	GoVersion  string `json:"goVersion"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	Goroutines int    `json:"goroutines"`
+	HeapAlloc  uint64 `json:"heapAllocBytes"`
+	HeapSys    uint64 `json:"heapSysBytes"`
+	NumGC      uint32 `json:"numGC"`
+	PauseTotal uint64 `json:"gcPauseTotalNs"`
+}
+
+// newDebugHandler returns the handler of the debug endpoints of the serve
+// command, which -debug-addr serves apart from the API: the profiles of
+// net/http/pprof under /debug/pprof/, and GET /debug/geodecode, which
+// reports the dataset and index of geocoder as the stats command does, its
+// cache and the Go runtime as JSON. With the query parameters lat and lon,
+// it also reports the search for that coordinate.
+func newDebugHandler(geocoder *geodecode.RGeocoder) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("GET /debug/geodecode", func(w http.ResponseWriter, r *http.Request) {
+		if err := geocoder.Load(); err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		report := debugReport{
+			Dataset:  newDatasetReport(geocoder, false),
+			LoadedAt: geocoder.DatasetInfo().LoadedAt,
+			Cache:    geocoder.CacheStats(),
+			Runtime:  newRuntimeReport(),
+		}
+		if query := r.URL.Query(); query.Has("lat") || query.Has("lon") {
+			lat, latErr := strconv.ParseFloat(query.Get("lat"), 64)
+			lon, lonErr := strconv.ParseFloat(query.Get("lon"), 64)
+			if latErr != nil || lonErr != nil {
+				writeError(w, http.StatusBadRequest, errors.New("lat and lon must be numbers"))
+				return
+			}
+			_, trace := geocoder.QueryDebug([2]float64{lat, lon})
+			report.Query = &trace
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	})
+	return mux
+}
+
+// newRuntimeReport describes the Go runtime now.
+func newRuntimeReport() runtimeReport {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return runtimeReport{
+		GoVersion:  runtime.Version(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  mem.HeapAlloc,
+		HeapSys:    mem.HeapSys,
+		NumGC:      mem.NumGC,
+		PauseTotal: mem.PauseTotalNs,
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestDebugHandler(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithCache(10))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	handler := newDebugHandler(geocoder)
+	serve := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	geocoder.Resolve([2]float64{48.9, 2.3})
+	rec := serve("/debug/geodecode?lat=52.5&lon=13.4")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var report debugReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if report.Dataset.Records == 0 || report.Dataset.Hash == "" || report.LoadedAt.IsZero() {
+		t.Errorf("Expected the dataset to be reported, got %+v", report.Dataset)
+	}
+	if report.Cache.Misses != 1 {
+		t.Errorf("Expected 1 cache miss, got %+v", report.Cache)
+	}
+	if report.Runtime.Goroutines == 0 || report.Runtime.GoVersion == "" {
+		t.Errorf("Expected the runtime to be reported, got %+v", report.Runtime)
+	}
+	if report.Query == nil || report.Query.NodesVisited == 0 {
+		t.Errorf("Expected the trace of the query, got %+v", report.Query)
+	}
+
+	var plain debugReport
+	if rec := serve("/debug/geodecode"); rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &plain) != nil || plain.Query != nil {
+		t.Errorf("Expected no query trace without a coordinate, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve("/debug/geodecode?lat=north&lon=1"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid coordinate, got %d", rec.Code)
+	}
+	for _, target := range []string{"/debug/pprof/", "/debug/pprof/goroutine?debug=1", "/debug/pprof/cmdline"} {
+		if rec := serve(target); rec.Code != http.StatusOK {
+			t.Errorf("Expected GET %s to return 200, got %d", target, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// runDownload implements the download command: it downloads a GeoNames
+// dump and unpacks it into the data directory as it is, verifying its
+// checksums, for loading with -dataset.
+func runDownload(args []string) error {
+	fs := flag.NewFlagSet("download", flag.ContinueOnError)
+	dataset := fs.String("dataset", "cities1000", "GeoNames dump to download: "+strings.Join(geoNamesDatasets, ", "))
+	dir := fs.String("dir", defaultDataDir(), "directory the dump is unpacked into")
+	baseURL := fs.String("base-url", geoNamesBaseURL, "base URL of the GeoNames dumps")
+	sum := fs.String("sha256", "", "expected SHA-256 of the downloaded zip archive, in hex")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("geodecode download [flags]")
+	}
+	if !slices.Contains(geoNamesDatasets, *dataset) {
+		return fmt.Errorf("unknown dataset %q, want one of %s", *dataset, strings.Join(geoNamesDatasets, ", "))
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(*baseURL, "/") + "/" + *dataset + ".zip"
+	fmt.Printf("Downloading %s...\n", url)
+	zipFile, err := os.CreateTemp(*dir, ".download-*.zip")
+	if err != nil {
+		return err
+	}
+	zipFile.Close()
+	defer os.Remove(zipFile.Name())
+	if err := download(url, zipFile.Name()); err != nil {
+		return err
+	}
+	zipSum, err := fileSHA256(zipFile.Name())
+	if err != nil {
+		return err
+	}
+	if *sum != "" && !strings.EqualFold(*sum, zipSum) {
+		return fmt.Errorf("checksum mismatch for %s: want SHA-256 %s, got %s", url, *sum, zipSum)
+	}
+
+	outPath := filepath.Join(*dir, *dataset+".txt")
+	dumpSum, err := unpack(zipFile.Name(), *dataset+".txt", outPath)
+	if err != nil {
+		return err
+	}
+	line := dumpSum + "  " + filepath.Base(outPath) + "\n"
+	if err := os.WriteFile(outPath+".sha256", []byte(line), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s (SHA-256 %s)\n", outPath, dumpSum)
+	fmt.Printf("Load it with: geodecode lookup -dataset %s <lat,lon>\n", outPath)
+	return nil
+}
+
+// unpack extracts the file named member from the zip archive at zipPath to
+// path and returns its hex-encoded SHA-256. The member's CRC-32 is verified
+// before path is replaced, so a corrupt archive leaves path as it was.
+func unpack(zipPath, member, path string) (string, error) {
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", fmt.Errorf("opening %s: %w", zipPath, err)
+	}
+	defer archive.Close()
+	src, err := archive.Open(member)
+	if err != nil {
+		return "", fmt.Errorf("opening %s in archive: %w", member, err)
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".unpack-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name()) // Fails once renamed
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), src); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("unpacking %s: %w", member, err) // Wraps zip.ErrChecksum for corrupt data
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 of the file at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDownload(t *testing.T) {
+	server := newGeoNamesServer(t)
+	resp, err := http.Get(server.URL + "/cities1000.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zipSum := sha256.Sum256(archive)
+
+	dataDir := t.TempDir()
+	if err := runDownload([]string{"-base-url", server.URL, "-dir", dataDir, "-sha256", hex.EncodeToString(zipSum[:])}); err != nil {
+		t.Fatalf("runDownload: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dataDir, "cities1000.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("..", "testdata", "cities.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Expected the unpacked dump to match testdata/cities.txt")
+	}
+	dumpSum := sha256.Sum256(want)
+	sums, err := os.ReadFile(filepath.Join(dataDir, "cities1000.txt.sha256"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line := hex.EncodeToString(dumpSum[:]) + "  cities1000.txt\n"; string(sums) != line {
+		t.Errorf("Expected %q, got %q", line, sums)
+	}
+	if entries, _ := os.ReadDir(dataDir); len(entries) != 2 {
+		t.Errorf("Expected the dump and its checksum only, got %d files", len(entries))
+	}
+
+	otherDir := t.TempDir()
+	err = runDownload([]string{"-base-url", server.URL, "-dir", otherDir, "-sha256", strings.Repeat("0", 64)})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+	if entries, _ := os.ReadDir(otherDir); len(entries) != 0 {
+		t.Errorf("Expected no files after a checksum mismatch, got %d", len(entries))
+	}
+	if err := runDownload([]string{"-dataset", "cities42"}); err == nil {
+		t.Errorf("Expected an error for an unknown dataset")
+	}
+	if err := runDownload([]string{"-base-url", server.URL, "-dataset", "cities15000", "-dir", otherDir}); err == nil {
+		t.Errorf("Expected an error for a dataset missing on the server")
+	}
+}
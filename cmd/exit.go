@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// Exit codes of the CLI.
+const (
+	exitOK          = 0
+	exitFailure     = 1 // Some records failed, or the command failed otherwise
+	exitBadInput    = 2 // Invalid flags, arguments or input records
+	exitLoadFailure = 3 // The dataset could not be loaded
+)
+
+// exitError is an error that ends the CLI with a particular exit code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode returns err annotated with the exit code it ends the CLI
+// with, or nil if err is nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// errorMessage returns the message of err without the "geodecode: " prefix
+// the errors of the geodecode package start with, since the CLI adds it to
+// its own messages.
+func errorMessage(err error) string {
+	return strings.TrimPrefix(err.Error(), "geodecode: ")
+}
+
+// contextError is an error with context, such as the line of the input it
+// occurred at, in front of the message of err.
+type contextError struct {
+	context string
+	err     error
+}
+
+func (e *contextError) Error() string { return e.context + ": " + errorMessage(e.err) }
+func (e *contextError) Unwrap() error { return e.err }
+
+// wrapf returns err with the context formatted like fmt.Sprintf in front of
+// its message, instead of fmt.Errorf with %w, so that the messages of errors
+// of the geodecode package do not repeat the prefix of the CLI.
+func wrapf(err error, format string, args ...any) error {
+	return &contextError{context: fmt.Sprintf(format, args...), err: err}
+}
+
+// badInputf returns a bad input error formatted like fmt.Errorf.
+func badInputf(format string, args ...any) error {
+	return withExitCode(exitBadInput, fmt.Errorf(format, args...))
+}
+
+// usageError returns a bad input error showing the usage of a command.
+func usageError(usage string) error {
+	return withExitCode(exitBadInput, errors.New("usage: "+strings.TrimSpace(usage)))
+}
+
+// exitCode returns the exit code err ends the CLI with: the code of an
+// exitError in its chain, exitBadInput for malformed input and invalid
+// coordinates, exitLoadFailure if no data could be loaded, and else
+// exitFailure.
+func exitCode(err error) int {
+	var (
+		ee        *exitError
+		csvErr    *csv.ParseError
+		xmlErr    *xml.SyntaxError
+		syntaxErr *json.SyntaxError
+	)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &ee):
+		return ee.code
+	case errors.As(err, &csvErr), errors.As(err, &xmlErr), errors.As(err, &syntaxErr), errors.Is(err, geodecode.ErrInvalidCoordinate):
+		return exitBadInput
+	case errors.Is(err, geodecode.ErrDataNotLoaded):
+		return exitLoadFailure
+	default:
+		return exitFailure
+	}
+}
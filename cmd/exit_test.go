@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, exitOK},
+		{errors.New("disk full"), exitFailure},
+		{usageError("geodecode batch [flags]"), exitBadInput},
+		{fmt.Errorf("line 3: %w", badInputf("invalid latitude %q", "x")), exitBadInput},
+		{&csv.ParseError{Line: 2, Err: csv.ErrQuote}, exitBadInput},
+		{fmt.Errorf("rows ending at line 9: %w", geodecode.ErrInvalidCoordinate), exitBadInput},
+		{wrapf(geodecode.ErrInvalidCoordinate, "line %d", 9), exitBadInput},
+		{geodecode.ErrDataNotLoaded, exitLoadFailure},
+		{withExitCode(exitLoadFailure, errors.New("open cities.csv: no such file")), exitLoadFailure},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%v): Expected %d, got %d", tt.err, tt.want, got)
+		}
+	}
+	if withExitCode(exitFailure, nil) != nil {
+		t.Errorf("Expected no error for nil")
+	}
+
+	for _, args := range [][]string{{"-k", "0", "1,2"}, {"-units", "furlongs", "1,2"}, {"1;2"}, {"-nope"}} {
+		if got := exitCode(runLookup(args)); got != exitBadInput {
+			t.Errorf("%q: Expected exit code %d, got %d", args, exitBadInput, got)
+		}
+	}
+	if got := exitCode(runLookup([]string{"-dataset", "missing.csv", "1,2"})); got != exitLoadFailure {
+		t.Errorf("Expected exit code %d for a missing dataset, got %d", exitLoadFailure, got)
+	}
+}
+
+func TestWrapf(t *testing.T) {
+	err := wrapf(fmt.Errorf("%w: coordinate 0: lat=95, lon=1", geodecode.ErrInvalidCoordinate), "rows ending at line %d", 3)
+	if want := "rows ending at line 3: invalid coordinate: coordinate 0: lat=95, lon=1"; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+	if !errors.Is(err, geodecode.ErrInvalidCoordinate) {
+		t.Errorf("Expected the error to wrap ErrInvalidCoordinate")
+	}
+	if got := errorMessage(geodecode.ErrDataNotLoaded); strings.HasPrefix(got, "geodecode:") {
+		t.Errorf("Expected no geodecode prefix, got %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// dataEnv is the environment variable naming the dataset file to load when
+// -dataset is not given.
+const dataEnv = "GEODECODE_DATA"
+
+// geocoderFlags registers the flags configuring the geocoder of the lookup,
+// batch and serve commands on fs. The returned function creates the geocoder
+// once fs is parsed, with opts in addition to those set by the flags.
+func geocoderFlags(fs *flag.FlagSet) func(opts ...geodecode.Option) (*geodecode.RGeocoder, error) {
+	dataset := fs.String("dataset", os.Getenv(dataEnv), "dataset file to load instead of the embedded one; $"+dataEnv+" by default")
+	fs.StringVar(dataset, "data", *dataset, "shorthand for -dataset")
+	format := fs.String("dataset-format", "", "format of -dataset: csv, geonames, parquet or binary (default from the file extension)")
+	maxDistance := fs.Float64("max-distance", 0, "report no match for coordinates farther than this from any location (0 for no limit)")
+	units := fs.String("units", geodecode.Kilometers.String(), "unit of distances: km, mi or nmi")
+	metric := fs.String("metric", geodecode.MetricEuclidean.String(), "metric to find the nearest location: euclidean, haversine or equirectangular")
+
+	return func(opts ...geodecode.Option) (*geodecode.RGeocoder, error) {
+		unit, err := parseUnit(*units)
+		if err != nil {
+			return nil, withExitCode(exitBadInput, err)
+		}
+		m, err := parseMetric(*metric)
+		if err != nil {
+			return nil, withExitCode(exitBadInput, err)
+		}
+		opts = append(opts, geodecode.WithUnits(unit), geodecode.WithMetric(m))
+		if *maxDistance > 0 {
+			opts = append(opts, geodecode.WithMaxDistance(*maxDistance))
+		}
+		if *dataset != "" {
+			f, err := parseDatasetFormat(*format, *dataset)
+			if err != nil {
+				return nil, withExitCode(exitBadInput, err)
+			}
+			opts = append(opts, geodecode.WithDataset(*dataset, geodecode.WithFormat(f)))
+		}
+		geocoder, err := geodecode.New(opts...)
+		if err != nil {
+			return nil, withExitCode(exitLoadFailure, err)
+		}
+		// Report a dataset that fails to load now rather than with every
+		// coordinate.
+		if err := geocoder.Load(); err != nil {
+			return nil, withExitCode(exitLoadFailure, err)
+		}
+		return geocoder, nil
+	}
+}
+
+// parseUnit returns the unit with the symbol s.
+func parseUnit(s string) (geodecode.Unit, error) {
+	for _, unit := range []geodecode.Unit{geodecode.Kilometers, geodecode.Miles, geodecode.NauticalMiles} {
+		if s == unit.String() {
+			return unit, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown unit %q", s)
+}
+
+// parseMetric returns the metric named s.
+func parseMetric(s string) (geodecode.Metric, error) {
+	for _, metric := range []geodecode.Metric{geodecode.MetricEuclidean, geodecode.MetricHaversine, geodecode.MetricEquirectangular} {
+		if s == metric.String() {
+			return metric, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown metric %q", s)
+}
+
+// parseDatasetFormat returns the dataset format named s, or if s is empty the format
+// suggested by the extension of path.
+func parseDatasetFormat(s, path string) (geodecode.Format, error) {
+	if s == "" {
+		switch strings.ToLower(filepath.Ext(strings.TrimSuffix(path, ".gz"))) {
+		case ".txt", ".tsv":
+			return geodecode.FormatGeoNames, nil
+		case ".parquet":
+			return geodecode.FormatParquet, nil
+		case ".bin":
+			return geodecode.FormatBinary, nil
+		default:
+			return geodecode.FormatCSV, nil
+		}
+	}
+	switch s {
+	case "csv":
+		return geodecode.FormatCSV, nil
+	case "geonames":
+		return geodecode.FormatGeoNames, nil
+	case "parquet":
+		return geodecode.FormatParquet, nil
+	case "binary":
+		return geodecode.FormatBinary, nil
+	default:
+		return 0, fmt.Errorf("unknown format %q", s)
+	}
+}
+
+// parseCoordinate parses a coordinate written as "lat,lon".
+func parseCoordinate(s string) ([2]float64, error) {
+	lat, lon, ok := strings.Cut(s, ",")
+	if !ok {
+		return [2]float64{}, withExitCode(exitBadInput, fmt.Errorf("invalid coordinate %q, want lat,lon", s))
+	}
+	return parseLatLon(lat, lon)
+}
+
+// parseLatLon parses a coordinate from its latitude and longitude.
+func parseLatLon(latField, lonField string) ([2]float64, error) {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latField), 64)
+	if err != nil {
+		return [2]float64{}, withExitCode(exitBadInput, fmt.Errorf("invalid latitude %q", latField))
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(lonField), 64)
+	if err != nil {
+		return [2]float64{}, withExitCode(exitBadInput, fmt.Errorf("invalid longitude %q", lonField))
+	}
+	return [2]float64{lat, lon}, nil
+}
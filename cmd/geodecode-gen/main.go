@@ -0,0 +1,213 @@
+// Command geodecode-gen compiles a city dataset into Go source code for the
+// geodecode package, so that no CSV parsing happens at runtime.
+//
+// It reads a dataset in any format supported by geodecode.ReadLocations and
+// writes a Go file that declares the locations as a statically initialized
+// slice. The file is guarded by the geodecode_generated build tag:
+//
+//	go run ./cmd/geodecode-gen -in rg_cities1000.csv.gz -out zz_generated_dataset.go
+//	go build -tags geodecode_generated ./...
+//
+// With -binary, it writes the dataset in the package's binary format instead,
+// gzip-compressed if the output file name ends in .gz, which is how the
+// embedded dataset is produced:
+//
+//	go run -tags geodecode_noembed ./cmd/geodecode-gen -in rg_cities1000.csv.gz -binary -out rg_cities1000.bin.gz
+//
+// GeoNames dumps can be converted directly:
+//
+//	go run ./cmd/geodecode-gen -in cities15000.txt -format geonames \
+//	    -admin1 admin1CodesASCII.txt -out zz_generated_dataset.go
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "geodecode-gen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the command line and generates the output file.
+func run(args []string) error {
+	fs := flag.NewFlagSet("geodecode-gen", flag.ContinueOnError)
+	in := fs.String("in", "", "input dataset (CSV or GeoNames dump, optionally gzip-compressed)")
+	out := fs.String("out", "zz_generated_dataset.go", "output Go file")
+	format := fs.String("format", "csv", "input format: csv, geonames or parquet")
+	admin1 := fs.String("admin1", "", "optional GeoNames admin1CodesASCII.txt to resolve admin1 names")
+	admin2 := fs.String("admin2", "", "optional GeoNames admin2Codes.txt to resolve admin2 names")
+	pkg := fs.String("pkg", "geodecode", "package name of the generated file")
+	binary := fs.Bool("binary", false, "write a binary dataset for embedding instead of Go source")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return fmt.Errorf("missing -in")
+	}
+
+	opts, closeAll, err := loadOptions(*format, *admin1, *admin2)
+	defer closeAll()
+	if err != nil {
+		return err
+	}
+
+	input, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	locations, err := geodecode.ReadLocations(input, opts...)
+	if err != nil {
+		return err
+	}
+
+	output, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	write := func(w io.Writer) error { return generate(w, *pkg, *in, locations) }
+	if *binary {
+		write = func(w io.Writer) error { return writeBinary(w, locations, strings.HasSuffix(*out, ".gz")) }
+	}
+	if err := write(output); err != nil {
+		output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+// writeBinary writes locations in the binary format to w, gzip-compressed if
+// compress is set, as the embedded dataset is.
+func writeBinary(w io.Writer, locations []geodecode.Location, compress bool) error {
+	if !compress {
+		return geodecode.WriteBinary(w, locations)
+	}
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if err := geodecode.WriteBinary(gz, locations); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// loadOptions translates the command line flags into load options. The
+// returned function closes any files opened for the options.
+func loadOptions(format, admin1, admin2 string) ([]geodecode.LoadOption, func(), error) {
+	var (
+		opts  []geodecode.LoadOption
+		files []*os.File
+	)
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+
+	switch format {
+	case "csv":
+		opts = append(opts, geodecode.WithFormat(geodecode.FormatCSV))
+	case "geonames":
+		opts = append(opts, geodecode.WithFormat(geodecode.FormatGeoNames))
+	case "parquet":
+		opts = append(opts, geodecode.WithFormat(geodecode.FormatParquet))
+	default:
+		return nil, closeAll, fmt.Errorf("unknown format %q", format)
+	}
+
+	for _, table := range []struct {
+		path string
+		opt  func(io.Reader) geodecode.LoadOption
+	}{
+		{admin1, geodecode.WithAdmin1Names},
+		{admin2, geodecode.WithAdmin2Names},
+	} {
+		if table.path == "" {
+			continue
+		}
+		f, err := os.Open(table.path)
+		if err != nil {
+			return nil, closeAll, err
+		}
+		files = append(files, f)
+		opts = append(opts, table.opt(f))
+	}
+	return opts, closeAll, nil
+}
+
+// generate writes a Go file declaring locations as the package's generated
+// dataset.
+func generate(w io.Writer, pkg, source string, locations []geodecode.Location) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "// Code generated by geodecode-gen from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(bw, "//go:build geodecode_generated\n\n")
+	fmt.Fprintf(bw, "package %s\n\n", pkg)
+	fmt.Fprintf(bw, "// embeddedData is empty because the dataset is compiled in as Go code.\n")
+	fmt.Fprintf(bw, "var embeddedData []byte\n\n")
+	fmt.Fprintf(bw, "// embeddedDataset names the dataset compiled into the package.\n")
+	fmt.Fprintf(bw, "const embeddedDataset = %q\n\n", "generated")
+	fmt.Fprintf(bw, "// generatedLocations holds the %d locations of %s.\n", len(locations), source)
+	fmt.Fprintf(bw, "var generatedLocations = []Location{\n")
+	for _, loc := range locations {
+		bw.WriteString("\t{")
+		writeLocation(bw, loc)
+		bw.WriteString("},\n")
+	}
+	bw.WriteString("}\n")
+
+	return bw.Flush()
+}
+
+// writeLocation writes the non-zero fields of loc as a keyed composite
+// literal body.
+func writeLocation(w *bufio.Writer, loc geodecode.Location) {
+	first := true
+	field := func(name, value string) {
+		if !first {
+			w.WriteString(", ")
+		}
+		first = false
+		w.WriteString(name)
+		w.WriteString(": ")
+		w.WriteString(value)
+	}
+	str := func(name, value string) {
+		if value != "" {
+			field(name, strconv.Quote(value))
+		}
+	}
+	num := func(name string, value int) {
+		if value != 0 {
+			field(name, strconv.Itoa(value))
+		}
+	}
+
+	num("GeonameID", loc.GeonameID)
+	field("Lat", strconv.FormatFloat(loc.Lat, 'g', -1, 64))
+	field("Lon", strconv.FormatFloat(loc.Lon, 'g', -1, 64))
+	str("City", loc.City)
+	str("Admin1", loc.Admin1)
+	str("Admin1Code", loc.Admin1Code)
+	str("Admin2", loc.Admin2)
+	str("Admin2Name", loc.Admin2Name)
+	str("CC", loc.CC)
+	str("Country", loc.Country)
+	str("Timezone", loc.Timezone)
+	num("Elevation", loc.Elevation)
+	num("Population", loc.Population)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "zz_generated_dataset.go")
+	err := run([]string{
+		"-in", filepath.Join("..", "..", "testdata", "cities.txt"),
+		"-format", "geonames",
+		"-admin1", filepath.Join("..", "..", "testdata", "admin1CodesASCII.txt"),
+		"-out", out,
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	src, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), out, src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+	if file.Name.Name != "geodecode" {
+		t.Errorf("Expected package geodecode, got %s", file.Name.Name)
+	}
+
+	var elements int
+	ast.Inspect(file, func(n ast.Node) bool {
+		if vs, ok := n.(*ast.ValueSpec); ok && vs.Names[0].Name == "generatedLocations" {
+			elements = len(vs.Values[0].(*ast.CompositeLit).Elts)
+		}
+		return true
+	})
+	if elements != 6 {
+		t.Errorf("Expected 6 generated locations, got %d", elements)
+	}
+
+	for _, want := range []string{
+		"//go:build geodecode_generated",
+		`City: "Los Angeles", Admin1: "California"`,
+		`Timezone: "America/Los_Angeles", Elevation: 89`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Generated code is missing %q", want)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if err := run(nil); err == nil {
+		t.Errorf("Expected an error without -in")
+	}
+	if err := run([]string{"-in", "x", "-format", "xml"}); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+// TestBuildTags builds the geodecode package with each of its dataset build
+// tags, the geodecode_generated one with a file from run in an overlay.
+func TestBuildTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the package with the go command")
+	}
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "zz_generated_dataset.go")
+	if err := run([]string{"-in", filepath.Join("..", "..", "testdata", "cities.txt"), "-format", "geonames", "-out", out}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The blobs of the other population thresholds are not checked in; the
+	// default one stands in for them.
+	replace := map[string]string{filepath.Join(root, "zz_generated_dataset.go"): out}
+	thresholds := []string{"500", "5000", "15000"}
+	for _, n := range thresholds {
+		replace[filepath.Join(root, "rg_cities"+n+".bin.gz")] = filepath.Join(root, "rg_cities1000.bin.gz")
+	}
+	overlayFile := writeOverlay(t, filepath.Join(dir, "overlay.json"), replace)
+	vet := func(tags, overlay string) ([]byte, error) {
+		cmd := exec.Command(goCmd, "vet", "-tags", tags, "-overlay", overlay, ".")
+		cmd.Dir = root
+		return cmd.CombinedOutput()
+	}
+
+	tags := []string{"", "geodecode_noembed", "geodecode_generated"}
+	for _, n := range thresholds {
+		tags = append(tags, "geodecode_cities"+n)
+	}
+	for _, tags := range tags {
+		if output, err := vet(tags, overlayFile); err != nil {
+			t.Errorf("Expected the package to build with tags %q, got %v:\n%s", tags, err, output)
+		}
+	}
+
+	// Without its blob, a threshold's tag fails the build naming the file.
+	emptyOverlay := writeOverlay(t, filepath.Join(dir, "empty.json"), map[string]string{})
+	for _, n := range thresholds {
+		blob := "rg_cities" + n + ".bin.gz"
+		if _, err := os.Stat(filepath.Join(root, blob)); err == nil {
+			continue // Generated by a maintainer
+		}
+		output, err := vet("geodecode_cities"+n, emptyOverlay)
+		if err == nil || !strings.Contains(string(output), blob) {
+			t.Errorf("Expected the build with geodecode_cities%s to fail naming %s, got %v:\n%s", n, blob, err, output)
+		}
+	}
+}
+
+// writeOverlay writes the go command overlay file replacing the files of
+// replace and returns its path.
+func writeOverlay(t *testing.T, path string, replace map[string]string) string {
+	t.Helper()
+	overlay, err := json.Marshal(map[string]map[string]string{"Replace": replace})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, overlay, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunBinaryGzip(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "cities.bin.gz")
+	if err := run([]string{"-in", filepath.Join("..", "..", "testdata", "cities.txt"), "-format", "geonames", "-binary", "-out", out}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("Expected gzip-compressed output, got %v", err)
+	}
+	locations, err := geodecode.ReadLocations(gz, geodecode.WithFormat(geodecode.FormatBinary))
+	if err != nil || len(locations) != 6 {
+		t.Errorf("Expected 6 locations, got %d, %v", len(locations), err)
+	}
+}
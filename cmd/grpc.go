@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/geodecodepb"
+)
+
+// gRPC status codes, from the gRPC specification.
+const (
+	grpcOK               = 0
+	grpcCancelled        = 1
+	grpcInvalidArgument  = 3
+	grpcDeadlineExceeded = 4
+	grpcExhausted        = 8
+	grpcUnimplemented    = 12
+	grpcUnavailable      = 14
+	grpcUnauthenticated  = 16
+)
+
+// isGRPC tells whether r is a gRPC request.
+func isGRPC(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
+
+// withGRPC returns a handler passing gRPC requests to grpc and the others to
+// h.
+func withGRPC(h, grpc http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGRPC(r) {
+			grpc.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// grpcHandler returns the handler of the gRPC service Geocoder of
+// geodecode.proto, implemented on net/http: it serves HTTP/2 requests with
+// the messages of geodecodepb, uncompressed, and reports the outcome in
+// the grpc-status and grpc-message trailers. Calls need one of keys, if
+// any, sent as for HTTP requests. limiter fails unary calls of clients over
+// their limit, and holds back their streams.
+func grpcHandler(geocoder *geodecode.RGeocoder, keys *apiKeys, limiter *rateLimiter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isGRPC(r) {
+			http.Error(w, "gRPC requests need HTTP/2 and the content type application/grpc", http.StatusUnsupportedMediaType)
+			return
+		}
+		w.Header().Set("Content-Type", "application/grpc")
+		if enc := r.Header.Get("Grpc-Encoding"); enc != "" && enc != "identity" {
+			grpcStatus(w, grpcUnimplemented, fmt.Sprintf("compression %q is not supported", enc))
+			return
+		}
+		r, ok := keys.authenticate(r)
+		if !ok {
+			grpcStatus(w, grpcUnauthenticated, "missing or unknown API key")
+			return
+		}
+		client := limiter.client(r)
+		switch r.URL.Path {
+		case geodecodepb.ReverseGeocodeMethod:
+			if ok, delay := limiter.take(client, 1); !ok {
+				grpcStatus(w, grpcExhausted, fmt.Sprintf("rate limit exceeded, retry in %v", delay.Round(time.Millisecond)))
+				return
+			}
+			var req geodecodepb.ReverseGeocodeRequest
+			if err := geodecodepb.ReadMessage(r.Body, &req); err != nil {
+				grpcRequestError(w, r, err)
+				return
+			}
+			keys.used(r, 1)
+			res, err := grpcResolve(geocoder, req)
+			if err != nil {
+				grpcError(w, err)
+				return
+			}
+			if err := geodecodepb.WriteMessage(w, res); err != nil {
+				return
+			}
+			grpcStatus(w, grpcOK, "")
+
+		case geodecodepb.ReverseGeocodeStreamMethod:
+			// Answer each request as it arrives: HTTP/2 streams are full
+			// duplex, so the client may wait for a response before sending
+			// the next request.
+			rc := http.NewResponseController(w)
+			w.WriteHeader(http.StatusOK)
+			rc.Flush()
+			for n := 0; ; n++ {
+				var req geodecodepb.ReverseGeocodeRequest
+				err := geodecodepb.ReadMessage(r.Body, &req)
+				if err == io.EOF {
+					grpcStatus(w, grpcOK, "")
+					return
+				}
+				if err == nil {
+					err = limiter.wait(r.Context(), client, 1)
+				}
+				if err != nil {
+					grpcRequestError(w, r, fmt.Errorf("message %d: %w", n, err))
+					return
+				}
+				keys.used(r, 1)
+				res, err := grpcResolve(geocoder, req)
+				if err != nil {
+					grpcError(w, fmt.Errorf("message %d: %w", n, err))
+					return
+				}
+				if err := geodecodepb.WriteMessage(w, res); err != nil {
+					return
+				}
+				rc.Flush()
+			}
+
+		default:
+			grpcStatus(w, grpcUnimplemented, fmt.Sprintf("unknown method %s", r.URL.Path))
+		}
+	})
+}
+
+// grpcResolve returns the response to req.
+func grpcResolve(geocoder *geodecode.RGeocoder, req geodecodepb.ReverseGeocodeRequest) (*geodecodepb.ReverseGeocodeResponse, error) {
+	results, err := geocoder.Resolve([2]float64{req.Lat, req.Lon})
+	if err != nil {
+		return nil, err
+	}
+	return geodecodepb.FromResult(results[0]), nil
+}
+
+// grpcError ends the response with the status of the failed query err, as
+// errorStatus does for HTTP.
+func grpcError(w http.ResponseWriter, err error) {
+	code := grpcUnavailable
+	if errors.Is(err, geodecode.ErrInvalidCoordinate) {
+		code = grpcInvalidArgument
+	}
+	grpcStatus(w, code, err.Error())
+}
+
+// grpcRequestError ends the response with the status of a call whose request
+// message could not be read, or waited for, because of err: CANCELLED or
+// DEADLINE_EXCEEDED if the call ended, and INVALID_ARGUMENT otherwise, for
+// messages that are missing or malformed.
+func grpcRequestError(w http.ResponseWriter, r *http.Request, err error) {
+	if ctxErr := r.Context().Err(); ctxErr != nil {
+		err = ctxErr
+	}
+	code := grpcInvalidArgument
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = grpcCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = grpcDeadlineExceeded
+	}
+	grpcStatus(w, code, err.Error())
+}
+
+// grpcStatus ends the response with the status code and message, which are
+// sent as trailers.
+func grpcStatus(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(code))
+	if msg != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", grpcPercentEncode(msg))
+	}
+}
+
+// grpcPercentEncode encodes msg for the grpc-message trailer: bytes other
+// than printable ASCII, and the percent sign, are percent-encoded.
+func grpcPercentEncode(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		if c := msg[i]; c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/geodecodepb"
+)
+
+// startGRPCServer starts a server of the serve command with gRPC enabled and
+// the rate limit limiter, and returns its URL and a client speaking
+// unencrypted HTTP/2 to it.
+func startGRPCServer(t *testing.T, limiter *rateLimiter) (string, *http.Client) {
+	t.Helper()
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	server := httptest.NewUnstartedServer(withGRPC(newHandler(geocoder, serveOptions{maxBatch: 10}), grpcHandler(geocoder, nil, limiter)))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+
+	transport := &http.Transport{Protocols: new(http.Protocols)}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	t.Cleanup(transport.CloseIdleConnections)
+	return server.URL, &http.Client{Transport: transport}
+}
+
+func grpcRequest(t *testing.T, client *http.Client, url string, body io.Reader) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	return resp
+}
+
+func TestGRPCUnary(t *testing.T) {
+	url, client := startGRPCServer(t, nil)
+
+	tests := []struct {
+		lat, lon float64
+		status   string
+		city     string
+	}{
+		{48.9, 2.3, "0", "Paris"},
+		{91, 0, "3", ""},
+	}
+	for _, tt := range tests {
+		var body bytes.Buffer
+		geodecodepb.WriteMessage(&body, &geodecodepb.ReverseGeocodeRequest{Lat: tt.lat, Lon: tt.lon})
+		resp := grpcRequest(t, client, url+geodecodepb.ReverseGeocodeMethod, &body)
+		var res geodecodepb.ReverseGeocodeResponse
+		err := geodecodepb.ReadMessage(resp.Body, &res)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if got := resp.Trailer.Get("Grpc-Status"); got != tt.status {
+			t.Errorf("lat=%g, lon=%g: Expected grpc-status %s, got %q (%s)", tt.lat, tt.lon, tt.status, got, resp.Trailer.Get("Grpc-Message"))
+		}
+		if tt.city == "" {
+			if err != io.EOF {
+				t.Errorf("lat=%g, lon=%g: Expected no message, got %v", tt.lat, tt.lon, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ReadMessage: %v", err)
+		}
+		if !res.Found || res.Location.City != tt.city || res.Location.CC != "FR" || res.Unit != "km" {
+			t.Errorf("lat=%g, lon=%g: Expected %s, got %+v", tt.lat, tt.lon, tt.city, res)
+		}
+	}
+}
+
+func TestGRPCStream(t *testing.T) {
+	url, client := startGRPCServer(t, nil)
+
+	// Send each request only after the response to the previous one, so the
+	// test fails if the server does not answer as requests arrive.
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go geodecodepb.WriteMessage(pw, &geodecodepb.ReverseGeocodeRequest{Lat: 48.9, Lon: 2.3})
+	resp := grpcRequest(t, client, url+geodecodepb.ReverseGeocodeStreamMethod, pr)
+	defer resp.Body.Close()
+
+	var res geodecodepb.ReverseGeocodeResponse
+	for _, city := range []string{"Paris", "Berlin"} {
+		if err := geodecodepb.ReadMessage(resp.Body, &res); err != nil {
+			t.Fatalf("ReadMessage: %v", err)
+		}
+		if res.Location == nil || res.Location.City != city {
+			t.Errorf("Expected %s, got %+v", city, res)
+		}
+		if city == "Paris" {
+			go geodecodepb.WriteMessage(pw, &geodecodepb.ReverseGeocodeRequest{Lat: 52.5, Lon: 13.4})
+		}
+	}
+	go geodecodepb.WriteMessage(pw, &geodecodepb.ReverseGeocodeRequest{Lat: 0, Lon: 0})
+	if err := geodecodepb.ReadMessage(resp.Body, &res); err != nil || res.Found {
+		t.Errorf("Expected a result not found, got %+v, %v", res, err)
+	}
+	go pw.Close()
+	if err := geodecodepb.ReadMessage(resp.Body, &res); err != io.EOF {
+		t.Errorf("Expected the end of the stream, got %v", err)
+	}
+	if got := resp.Trailer.Get("Grpc-Status"); got != "0" {
+		t.Errorf("Expected grpc-status 0, got %q (%s)", got, resp.Trailer.Get("Grpc-Message"))
+	}
+}
+
+func TestGRPCErrors(t *testing.T) {
+	url, client := startGRPCServer(t, nil)
+
+	var body bytes.Buffer
+	geodecodepb.WriteMessage(&body, &geodecodepb.ReverseGeocodeRequest{Lat: 48.9, Lon: 2.3})
+	geodecodepb.WriteMessage(&body, &geodecodepb.ReverseGeocodeRequest{Lat: 0, Lon: 181})
+	resp := grpcRequest(t, client, url+geodecodepb.ReverseGeocodeStreamMethod, bytes.NewReader(body.Bytes()))
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if got, msg := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message"); got != "3" || msg == "" {
+		t.Errorf("Expected grpc-status 3 with a message, got %q (%s)", got, msg)
+	}
+
+	resp = grpcRequest(t, client, url+"/geodecode.v1.Geocoder/Forward", bytes.NewReader(body.Bytes()))
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if got := resp.Trailer.Get("Grpc-Status"); got != "12" {
+		t.Errorf("Expected grpc-status 12, got %q", got)
+	}
+
+	// Other requests still reach the HTTP API.
+	resp, err := client.Get(url + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected GET /healthz to return 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestGRPCStatusCodes(t *testing.T) {
+	url, client := startGRPCServer(t, newRateLimiter(0.001, 1, ""))
+	call := func(method string, body []byte) (string, string) {
+		resp := grpcRequest(t, client, url+method, bytes.NewReader(body))
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	}
+
+	var valid bytes.Buffer
+	geodecodepb.WriteMessage(&valid, &geodecodepb.ReverseGeocodeRequest{Lat: 48.9, Lon: 2.3})
+	// A message with the field number 0, which is invalid.
+	malformed := []byte{0, 0, 0, 0, 2, 0x00, 0x01}
+	tests := []struct {
+		method string
+		body   []byte
+		status string
+	}{
+		{geodecodepb.ReverseGeocodeMethod, valid.Bytes(), "0"},
+		{geodecodepb.ReverseGeocodeMethod, valid.Bytes(), "8"}, // Over the limit
+		{geodecodepb.ReverseGeocodeStreamMethod, malformed, "3"},
+		{geodecodepb.ReverseGeocodeStreamMethod, valid.Bytes()[:4], "3"},
+	}
+	for _, tt := range tests {
+		if got, msg := call(tt.method, tt.body); got != tt.status || (tt.status != "0" && msg == "") {
+			t.Errorf("%s with %x: Expected grpc-status %s with a message, got %q (%s)", tt.method, tt.body, tt.status, got, msg)
+		}
+	}
+}
+
+func TestGRPCRequestError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	deadline, cancelDeadline := context.WithDeadline(context.Background(), time.Now())
+	defer cancelDeadline()
+	tests := []struct {
+		ctx    context.Context
+		err    error
+		status string
+	}{
+		{context.Background(), errors.New("geodecodepb: truncated message"), "3"},
+		{context.Background(), io.EOF, "3"},
+		{ctx, errors.New("http2: stream closed"), "1"},
+		{deadline, errors.New("http2: stream closed"), "4"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		grpcRequestError(rec, httptest.NewRequest(http.MethodPost, "/", nil).WithContext(tt.ctx), tt.err)
+		if got := rec.Header().Get(http.TrailerPrefix + "Grpc-Status"); got != tt.status {
+			t.Errorf("%v: Expected grpc-status %s, got %q", tt.err, tt.status, got)
+		}
+	}
+}
+
+func TestGRPCPercentEncode(t *testing.T) {
+	if got, want := grpcPercentEncode("lat=91 % ok\nÜ"), "lat=91 %25 ok%0A%C3%9C"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// etagPrecision is the number of decimal places coordinates are rounded to
+// in ETags, about a centimeter: coordinates this close have the same result.
+const etagPrecision = 7
+
+// resultETag returns the ETag of the result for lat and lon from the dataset
+// with the hash datasetHash, encoded with the settings variant.
+func resultETag(datasetHash, variant string, lat, lon float64) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%.*f,%.*f", datasetHash, variant, etagPrecision, lat, etagPrecision, lon)
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches tells whether the If-None-Match header ifNoneMatch lists etag,
+// comparing weakly as RFC 9110 requires.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for tag := range strings.SplitSeq(ifNoneMatch, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControl returns the Cache-Control header of results: shared caches
+// may store them for maxAge, or must revalidate them if maxAge is 0, unless
+// private is set, for results only the client that asked may see.
+func cacheControl(maxAge time.Duration, private bool) string {
+	scope := "public"
+	if private {
+		scope = "private"
+	}
+	if maxAge <= 0 {
+		return scope + ", no-cache"
+	}
+	return scope + ", max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+}
+
+// writeCacheable writes the result body with the ETag etag and the
+// Cache-Control header cc, or status 304 without the body if the client has
+// it already.
+func writeCacheable(w http.ResponseWriter, r *http.Request, etag, cc string, body []byte) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cc)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestReverseCaching(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	handler := newHandler(geocoder, serveOptions{maxBatch: 10, maxAge: time.Hour})
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/reverse?lat=48.9&lon=2.3", "")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || len(etag) != 34 || rec.Header().Get("Cache-Control") != "public, max-age=3600" {
+		t.Fatalf("Expected 200 with an ETag and max-age, got %d with %q, %q", rec.Code, etag, rec.Header().Get("Cache-Control"))
+	}
+	if got := get("/reverse?latitude=48.90000001&lng=2.300", "").Header().Get("ETag"); got != etag {
+		t.Errorf("Expected the same ETag for the same rounded coordinate, got %s and %s", etag, got)
+	}
+	if got := get("/reverse?lat=48.9&lon=2.4", "").Header().Get("ETag"); got == etag {
+		t.Errorf("Expected another ETag for another coordinate")
+	}
+	for _, ifNoneMatch := range []string{etag, `"other", W/` + etag} {
+		if rec := get("/reverse?lat=48.9&lon=2.3", ifNoneMatch); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("ETag") != etag {
+			t.Errorf("Expected 304 without a body for If-None-Match %s, got %d: %s", ifNoneMatch, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := get("/reverse?lat=91&lon=0", "*"); rec.Code != http.StatusBadRequest || rec.Header().Get("ETag") != "" {
+		t.Errorf("Expected errors not to be cached, got %d with ETag %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	// Changing the dataset changes the ETags.
+	if err := geocoder.AddLocations(geodecode.Location{Lat: 48.9, Lon: 2.3, City: "Saint-Denis", CC: "FR"}); err != nil {
+		t.Fatalf("AddLocations: %v", err)
+	}
+	if rec := get("/reverse?lat=48.9&lon=2.3", etag); rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("Expected 200 with a new ETag after the dataset changed, got %d with %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	tests := []struct {
+		maxAge  time.Duration
+		private bool
+		want    string
+	}{
+		{time.Hour, false, "public, max-age=3600"},
+		{90 * time.Second, true, "private, max-age=90"},
+		{0, false, "public, no-cache"},
+		{0, true, "private, no-cache"},
+	}
+	for _, tt := range tests {
+		if got := cacheControl(tt.maxAge, tt.private); got != tt.want {
+			t.Errorf("Expected %q for %v, %t, got %q", tt.want, tt.maxAge, tt.private, got)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// kmlColumns are the columns of the records read from KML files.
+var kmlColumns = []string{"name", "point", "lat", "lon"}
+
+// kmlReader reads the coordinates of the placemarks of a KML file as
+// records of kmlColumns: the placemark's name, the number of the point
+// within it, starting at 1, and the coordinate. Points, paths and polygons
+// are read at any depth of folders and multi-geometries, as well as gx:Track
+// coordinates, each point and vertex as a record of its own. The first
+// record is the header.
+type kmlReader struct {
+	dec     *xml.Decoder
+	header  bool       // Whether the header was read
+	pending [][]string // Records of the current placemark not read yet
+	line    int        // Line of the current placemark
+}
+
+// newKMLReader returns a kmlReader reading the KML file r.
+func newKMLReader(r io.Reader) *kmlReader {
+	return &kmlReader{dec: xml.NewDecoder(r)}
+}
+
+// newKMZReader returns a kmlReader reading the KMZ file r: a zip archive
+// holding a KML file, doc.kml or else the first KML file at its root.
+func newKMZReader(r io.Reader) (*kmlReader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading KMZ: %w", err)
+	}
+	var doc *zip.File
+	for _, f := range archive.File {
+		if f.Name == "doc.kml" || doc == nil && path.Dir(f.Name) == "." && strings.EqualFold(path.Ext(f.Name), ".kml") {
+			doc = f
+		}
+	}
+	if doc == nil {
+		return nil, errors.New("reading KMZ: no KML file in the archive")
+	}
+	rc, err := doc.Open()
+	if err != nil {
+		return nil, err
+	}
+	kml, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+	return newKMLReader(bytes.NewReader(kml)), nil
+}
+
+// Read returns the next record, or io.EOF after the last one.
+func (k *kmlReader) Read() ([]string, error) {
+	if !k.header {
+		k.header = true
+		return kmlColumns, nil
+	}
+	for len(k.pending) == 0 {
+		tok, err := k.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "Placemark" {
+			k.line, _ = k.dec.InputPos()
+			if err := k.placemark(); err != nil {
+				return nil, fmt.Errorf("line %d: %w", k.line, err)
+			}
+		}
+	}
+	record := k.pending[0]
+	k.pending = k.pending[1:]
+	return record, nil
+}
+
+// FieldPos returns the line the current record's placemark starts on, so
+// kmlReader reports positions like a csv.Reader.
+func (k *kmlReader) FieldPos(int) (line, column int) {
+	return k.line, 1
+}
+
+// placemark reads the rest of a Placemark element into k.pending.
+func (k *kmlReader) placemark() error {
+	var name string
+	var coords [][2]float64
+	for depth := 0; ; {
+		tok, err := k.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			var text string
+			switch tok.Name.Local {
+			case "name":
+				if err := k.dec.DecodeElement(&text, &tok); err != nil {
+					return err
+				}
+				if depth == 0 {
+					name = strings.TrimSpace(text)
+				}
+			case "coordinates", "coord":
+				if err := k.dec.DecodeElement(&text, &tok); err != nil {
+					return err
+				}
+				if coords, err = appendKMLCoordinates(coords, text, tok.Name.Local == "coord"); err != nil {
+					return err
+				}
+			default:
+				depth++
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				for i, c := range coords {
+					k.pending = append(k.pending, []string{name, strconv.Itoa(i + 1),
+						strconv.FormatFloat(c[0], 'f', -1, 64), strconv.FormatFloat(c[1], 'f', -1, 64)})
+				}
+				return nil
+			}
+			depth--
+		}
+	}
+}
+
+// appendKMLCoordinates appends the coordinates of the text of a KML
+// coordinates element, tuples of "lon,lat[,alt]" separated by white space,
+// to coords. With track set, text is a gx:coord element instead, a single
+// "lon lat alt" tuple.
+func appendKMLCoordinates(coords [][2]float64, text string, track bool) ([][2]float64, error) {
+	tuples := strings.Fields(text)
+	if track && len(tuples) > 0 {
+		tuples = []string{strings.Join(tuples, ",")}
+	}
+	for _, tuple := range tuples {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid coordinate %q, want lon,lat", tuple)
+		}
+		coord, err := parseLatLon(parts[1], parts[0])
+		if err != nil {
+			return nil, err
+		}
+		coords = append(coords, coord)
+	}
+	return coords, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+const testKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">
+<Document><name>Export</name><Folder><name>Sites</name>
+  <Placemark><name>Office</name><Point><coordinates>13.4,52.5,0</coordinates></Point></Placemark>
+  <Placemark>
+    <name>Trip</name>
+    <ExtendedData><Data name="x"><value>1</value></Data></ExtendedData>
+    <MultiGeometry><LineString><coordinates>
+      13.4,52.5 2.3,48.9
+    </coordinates></LineString></MultiGeometry>
+  </Placemark>
+  <Placemark><name>Ride</name><gx:Track><when>2024-05-01T08:00:00Z</when><gx:coord>0 0 10</gx:coord></gx:Track></Placemark>
+</Folder></Document></kml>`
+
+func TestBatchKML(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	want := "name,point,lat,lon,city\n" +
+		"Office,1,52.5,13.4,Berlin\n" +
+		"Trip,1,52.5,13.4,Berlin\n" +
+		"Trip,2,48.9,2.3,Paris\n" +
+		"Ride,1,0,0,\n"
+	opts := batchOptions{inputFormat: "kml", latCol: "latitude", outputOptions: outputOptions{format: "csv", fields: []string{"city"}}}
+	var out bytes.Buffer
+	if err := batch(&out, strings.NewReader(testKML), geocoder, opts); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	var kmz bytes.Buffer
+	archive := zip.NewWriter(&kmz)
+	for name, data := range map[string]string{"files/other.kml": "<kml/>", "doc.kml": testKML} {
+		f, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(data))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	opts.inputFormat = "kmz"
+	out.Reset()
+	if err := batch(&out, &kmz, geocoder, opts); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	for _, input := range []string{
+		"<kml><Placemark><Point><coordinates>13.4</coordinates></Point></Placemark></kml>",
+		"<kml><Placemark><Point><coordinates>13.4,north</coordinates></Point></Placemark></kml>",
+		"<kml><Placemark>",
+	} {
+		opts.inputFormat = "kml"
+		if err := batch(&out, strings.NewReader(input), geocoder, opts); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+	opts.inputFormat = "kmz"
+	if err := batch(&out, strings.NewReader(testKML), geocoder, opts); err == nil {
+		t.Errorf("Expected an error for KML given as KMZ")
+	}
+}
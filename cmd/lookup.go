@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// runLookup implements the lookup command: it resolves the coordinates given
+// as arguments and prints the nearest locations. With the single argument
+// "-", it enriches the records read from standard input instead.
+func runLookup(args []string) error {
+	fs := flag.NewFlagSet("lookup", flag.ContinueOnError)
+	newGeocoder := geocoderFlags(fs)
+	outputOpts := outputFlags(fs, append([]string{"text"}, outputFormats...))
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	opts, err := outputOpts()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usageError("geodecode lookup [flags] <lat,lon>... | -")
+	}
+	if fs.NArg() == 1 && fs.Arg(0) == "-" {
+		geocoder, err := newGeocoder()
+		if err != nil {
+			return err
+		}
+		return lookupStream(os.Stdout, os.Stdin, geocoder, opts)
+	}
+
+	coords := make([][2]float64, fs.NArg())
+	for i, arg := range fs.Args() {
+		coord, err := parseCoordinate(arg)
+		if err != nil {
+			return err
+		}
+		coords[i] = coord
+	}
+	geocoder, err := newGeocoder()
+	if err != nil {
+		return err
+	}
+	return lookup(os.Stdout, geocoder, coords, opts)
+}
+
+// lookup resolves coords with geocoder and writes the results to w as
+// selected by opts: one line per result for the format "text", or else with
+// a resultWriter. With opts.k above 1, each coordinate has up to k results,
+// nearest first.
+func lookup(w io.Writer, geocoder *geodecode.RGeocoder, coords [][2]float64, opts outputOptions) error {
+	var results []geodecode.Result
+	queries := coords // The coordinate of each result
+	if opts.k > 1 {
+		queries = nil
+		for _, coord := range coords {
+			nearest, err := resolveK(geocoder, coord, opts.k)
+			if err != nil {
+				return err
+			}
+			results = append(results, nearest...)
+			for range nearest {
+				queries = append(queries, coord)
+			}
+		}
+	} else {
+		var err error
+		if results, err = geocoder.Resolve(coords...); err != nil {
+			return err
+		}
+	}
+	if opts.format != "text" || opts.tmpl != nil {
+		rw, err := newResultWriter(w, opts, nil, 0)
+		if err != nil {
+			return err
+		}
+		for i, r := range results {
+			if err := rw.write(queries[i], nil, r); err != nil {
+				return err
+			}
+		}
+		return rw.close()
+	}
+	for i, r := range results {
+		if !r.Found {
+			fmt.Fprintf(w, "%v,%v\tnot found\n", queries[i][0], queries[i][1])
+			continue
+		}
+		fmt.Fprintf(w, "%v,%v\t%s\t%.1f %s\n", queries[i][0], queries[i][1], placeName(r.Location), r.Distance, r.Unit)
+	}
+	return nil
+}
+
+// placeName joins the non-empty names of loc's city, regions and country code.
+func placeName(loc geodecode.Location) string {
+	var parts []string
+	for _, s := range []string{loc.City, loc.Admin1, loc.Admin2, loc.CC} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
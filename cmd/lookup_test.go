@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// writeTestDataset writes a small CSV dataset to a temporary file and returns its
+// path.
+func writeTestDataset(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	data := "lat,lon,city,admin1,admin2,cc\n52.52,13.41,Berlin,Berlin,,DE\n48.86,2.35,Paris,Ile-de-France,,FR\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLookup(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var out bytes.Buffer
+	if err := lookup(&out, geocoder, [][2]float64{{52.5, 13.4}, {0, 0}}, outputOptions{format: "text"}); err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	want := "52.5,13.4\tBerlin, Berlin, DE\t2.3 km\n0,0\tnot found\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	if err := lookup(&out, geocoder, [][2]float64{{0, 0}, {52.5, 13.4}}, outputOptions{format: "text", k: 2}); err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	want = "0,0\tnot found\n52.5,13.4\tBerlin, Berlin, DE\t2.3 km\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	unlimited, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	out.Reset()
+	if err := lookup(&out, unlimited, [][2]float64{{52.5, 13.4}}, outputOptions{format: "csv", fields: []string{"city"}, k: 3}); err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	want = "lat,lon,city\n52.5,13.4,Berlin\n52.5,13.4,Paris\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	if err := lookup(&out, geocoder, [][2]float64{{91, 0}}, outputOptions{format: "text"}); err == nil {
+		t.Errorf("Expected an error for an invalid coordinate")
+	}
+	if err := lookup(&out, geocoder, [][2]float64{{52.5, 13.4}}, outputOptions{format: "xml"}); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+	if err := runLookup(nil); err == nil {
+		t.Errorf("Expected a usage error without coordinates")
+	}
+	if err := runLookup([]string{"52.5;13.4"}); err == nil {
+		t.Errorf("Expected an error for a malformed coordinate")
+	}
+	if err := runLookup([]string{"-k", "0", "52.5,13.4"}); err == nil {
+		t.Errorf("Expected an error for k 0")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name, path string
+		want       geodecode.Format
+	}{
+		{"", "cities.csv.gz", geodecode.FormatCSV},
+		{"", "cities1000.txt", geodecode.FormatGeoNames},
+		{"", "cities.parquet", geodecode.FormatParquet},
+		{"", "cities.bin", geodecode.FormatBinary},
+		{"geonames", "cities.csv", geodecode.FormatGeoNames},
+	}
+	for _, tt := range tests {
+		if got, err := parseDatasetFormat(tt.name, tt.path); err != nil || got != tt.want {
+			t.Errorf("parseDatasetFormat(%q, %q): Expected %v, got %v (%v)", tt.name, tt.path, tt.want, got, err)
+		}
+	}
+	if _, err := parseDatasetFormat("xml", "cities.xml"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+func TestDataEnv(t *testing.T) {
+	path := writeTestDataset(t)
+	t.Setenv(dataEnv, path)
+	for _, args := range [][]string{nil, {"-data", path}, {"-dataset", path}} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		newGeocoder := geocoderFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		geocoder, err := newGeocoder()
+		if err != nil {
+			t.Fatalf("newGeocoder: %v", err)
+		}
+		if info := geocoder.DatasetInfo(); info.Location != path || info.Records != 2 {
+			t.Errorf("%q: Expected the 2 locations of %s, got %d of %s", args, path, info.Records, info.Location)
+		}
+	}
+
+	t.Setenv(dataEnv, filepath.Join(t.TempDir(), "missing.csv"))
+	if err := runLookup([]string{"52.5,13.4"}); err == nil {
+		t.Errorf("Expected an error for a missing dataset in $%s", dataEnv)
+	}
+}
@@ -1,59 +1,65 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-
-	geodecode "github.com/sdwillbrand/GeoDecode"
+	"os"
 )
 
-func main() {
-	fmt.Println("Geocoder instantiated, but data not loaded yet.")
+// command is a subcommand of the CLI.
+type command struct {
+	run     func(args []string) error
+	summary string // One line shown in the usage message
+}
 
-	fmt.Println("\nTesting single coordinate through FindLocation()...")
-	city1 := [2]float64{37.78674, -122.39222} // Near San Francisco
-	result1 := geodecode.FindLocation(city1, true)
-	if result1 != nil {
-		fmt.Printf("Result for %v: %+v\n", city1, *result1)
-	} else {
-		fmt.Printf("Result for %v: Not found\n", city1)
-	}
+// commands maps subcommand names to their implementations.
+var commands = map[string]command{
+	"lookup":   {runLookup, "print the nearest locations of coordinates given as arguments"},
+	"batch":    {runBatch, "resolve the coordinates of a CSV file or standard input"},
+	"watch":    {runWatch, "resolve the records appended to a JSON lines file as it grows"},
+	"repl":     {runRepl, "answer coordinates typed interactively"},
+	"track":    {runTrack, "list the places a GPX track passes through"},
+	"serve":    {runServe, "answer reverse geocoding requests over HTTP"},
+	"download": {runDownload, "download and unpack a GeoNames dump"},
+	"update":   {runUpdate, "download and convert a GeoNames dataset"},
+	"stats":    {runStats, "describe the size and coverage of a dataset"},
+	"validate": {runValidate, "check a CSV dataset for problems"},
+	"bench":    {runBench, "measure cold start, query latency and throughput"},
+}
 
-	fmt.Println("\nTesting another single coordinate (data already loaded)...")
-	city2 := [2]float64{48.8566, 2.3522}            // Paris
-	result2 := geodecode.FindLocation(city2, false) // Verbose=False as data is loaded
-	if result2 != nil {
-		fmt.Printf("Result for %v: %+v\n", city2, *result2)
-	} else {
-		fmt.Printf("Result for %v: Not found\n", city2)
-	}
+// commandOrder is the order commands are listed in the usage message.
+var commandOrder = []string{"lookup", "batch", "watch", "repl", "track", "serve", "download", "update", "stats", "validate", "bench"}
 
-	fmt.Println("\nTesting multiple coordinates through RGeocoder.Query()...")
-	coordsList := [][2]float64{
-		{52.5200, 13.4050},   // Berlin
-		{40.7128, -74.0060},  // New York City
-		{-33.8688, 151.2093}, // Sydney
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		usage()
+		if len(os.Args) < 2 {
+			os.Exit(exitBadInput)
+		}
+		return
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "geodecode: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(exitBadInput)
 	}
-	geocoderInstance := geodecode.GetRGeocoder(false) // Gets the existing singleton instance
-	resultsList := geocoderInstance.Query(coordsList...)
-	fmt.Println("Results for multiple coordinates:")
-	for i, coord := range coordsList {
-		if i < len(resultsList) {
-			fmt.Printf("  %v: %+v\n", coord, resultsList[i])
-		} else {
-			fmt.Printf("  %v: Not found\n", coord)
+	if err := cmd.run(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
 		}
+		fmt.Fprintln(os.Stderr, "geodecode:", errorMessage(err))
+		os.Exit(exitCode(err))
 	}
+}
 
-	fmt.Println("\nTesting edge case: Coordinate in ocean...")
-	oceanCoord := [2]float64{0.0, 0.0} // Middle of the ocean
-	resultOcean := geodecode.FindLocation(oceanCoord, false)
-	if resultOcean != nil {
-		fmt.Printf("Result for %v: %+v\n", oceanCoord, *resultOcean)
-	} else {
-		fmt.Printf("Result for %v: Not found\n", oceanCoord)
+// usage writes the list of commands to stderr.
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: geodecode <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, name := range commandOrder {
+		fmt.Fprintf(os.Stderr, "  %-9s %s\n", name, commands[name].summary)
 	}
-
-	fmt.Println("\nTesting edge case: Empty list input to query...")
-	emptyResults := geocoderInstance.Query()
-	fmt.Printf("Result for empty list query: %+v\n", emptyResults)
+	fmt.Fprintln(os.Stderr, "\nRun geodecode <command> -h for the flags of a command.")
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/internal/prom"
+)
+
+// requestLatencyBuckets are the upper bounds, in seconds, of the buckets of
+// the request latency histogram.
+var requestLatencyBuckets = []float64{1e-4, 2.5e-4, 5e-4, 1e-3, 2.5e-3, 5e-3, 1e-2, 2.5e-2, 5e-2, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// httpMetrics records the requests of the serve command, and serves them
+// with the metrics of the geocoder and the usage of the API keys at GET
+// /metrics.
+type httpMetrics struct {
+	collector *geodecode.Collector // Metrics of the geocoder; nil if not collected
+	keys      *apiKeys             // API keys whose usage is served; may be nil
+	inFlight  atomic.Int64
+
+	mu       sync.Mutex
+	requests map[requestKey]uint64
+	latency  map[string]*prom.Hist // By route
+}
+
+// requestKey identifies a series of geodecode_http_requests_total.
+type requestKey struct {
+	route string
+	code  int
+}
+
+func newHTTPMetrics(collector *geodecode.Collector, keys *apiKeys) *httpMetrics {
+	return &httpMetrics{
+		collector: collector,
+		keys:      keys,
+		requests:  make(map[requestKey]uint64),
+		latency:   make(map[string]*prom.Hist),
+	}
+}
+
+// instrument returns h, a ServeMux or a handler passing requests on to one,
+// recording its requests by route: the path of the pattern they matched, or
+// "unmatched". WebSocket connections taken over by
+// a handler are counted with the status 101, but not timed.
+func (m *httpMetrics) instrument(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.inFlight.Add(1)
+		defer m.inFlight.Add(-1)
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		h.ServeHTTP(rec, r)
+
+		route := "unmatched"
+		if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+			route = path
+		} else if r.Pattern != "" {
+			route = r.Pattern
+		}
+		code := cmp.Or(rec.code, http.StatusOK) // Handlers writing nothing send 200
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.requests[requestKey{route, code}]++
+		if code == http.StatusSwitchingProtocols {
+			return
+		}
+		h := m.latency[route]
+		if h == nil {
+			h = prom.NewHist(requestLatencyBuckets...)
+			m.latency[route] = h
+		}
+		h.Observe(time.Since(start).Seconds())
+	})
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *httpMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", prom.ContentType)
+	if m.collector != nil {
+		if _, err := m.collector.WriteTo(w); err != nil {
+			return
+		}
+	}
+	pw := prom.NewWriter(w)
+	pw.Header("geodecode_http_requests_in_flight", "HTTP requests being served.", prom.Gauge)
+	pw.Sample("geodecode_http_requests_in_flight", float64(m.inFlight.Load()))
+
+	m.mu.Lock()
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b requestKey) int {
+		return cmp.Or(strings.Compare(a.route, b.route), cmp.Compare(a.code, b.code))
+	})
+	pw.Header("geodecode_http_requests_total", "HTTP requests served, by route and status code.", prom.Counter)
+	for _, key := range keys {
+		pw.Sample("geodecode_http_requests_total", float64(m.requests[key]), "route", key.route, "code", strconv.Itoa(key.code))
+	}
+	routes := make([]string, 0, len(m.latency))
+	for route := range m.latency {
+		routes = append(routes, route)
+	}
+	slices.Sort(routes)
+	pw.Header("geodecode_http_request_duration_seconds", "Time taken to serve HTTP requests, by route.", prom.Histogram)
+	for _, route := range routes {
+		m.latency[route].Write(pw, "geodecode_http_request_duration_seconds", "route", route)
+	}
+	m.mu.Unlock()
+	m.keys.writeMetrics(pw)
+	pw.Flush()
+}
+
+// statusRecorder records the status code written to a ResponseWriter.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Hijack takes over the connection, as for WebSocket connections, which are
+// recorded as switching protocols.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil && r.code == 0 {
+		r.code = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec is the OpenAPI 3 specification of the HTTP API of the serve
+// command, for generating clients. The tests check the handler against it,
+// so keep both in step.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// handleOpenAPI serves openAPISpec.
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
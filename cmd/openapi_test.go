@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// openAPI is the part of an OpenAPI document the tests check against.
+type openAPI struct {
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components struct {
+		Responses map[string]openAPIResponse `json:"responses"`
+		Schemas   map[string]*schema         `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	Responses map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIResponse struct {
+	Ref     string `json:"$ref"`
+	Content map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"content"`
+}
+
+// schema is the subset of the schema objects of OpenAPI 3.0 used in the
+// specification.
+type schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Properties map[string]*schema `json:"properties"`
+	Required   []string           `json:"required"`
+	Items      *schema            `json:"items"`
+	MinItems   *int               `json:"minItems"`
+	MaxItems   *int               `json:"maxItems"`
+	Minimum    *float64           `json:"minimum"`
+	Maximum    *float64           `json:"maximum"`
+	Enum       []any              `json:"enum"`
+	OneOf      []*schema          `json:"oneOf"`
+	AllOf      []*schema          `json:"allOf"`
+}
+
+func loadOpenAPI(t *testing.T) *openAPI {
+	t.Helper()
+	var spec openAPI
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("Unmarshal openapi.json: %v", err)
+	}
+	return &spec
+}
+
+// response returns the response of the operation for status, resolving
+// references.
+func (spec *openAPI) response(op openAPIOperation, status int) (openAPIResponse, bool) {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if name, found := strings.CutPrefix(resp.Ref, "#/components/responses/"); found {
+		resp, ok = spec.Components.Responses[name]
+	}
+	return resp, ok
+}
+
+// validate checks the JSON value v against s.
+func (spec *openAPI) validate(s *schema, v any, path string) error {
+	if s.Ref != "" {
+		ref, ok := spec.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return fmt.Errorf("%s: unknown reference %s", path, s.Ref)
+		}
+		return spec.validate(ref, v, path)
+	}
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, alt := range s.OneOf {
+			if spec.validate(alt, v, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s: %v matches %d schemas of oneOf, want 1", path, v, matches)
+		}
+	}
+	for _, part := range s.AllOf {
+		if err := spec.validate(part, v, path); err != nil {
+			return err
+		}
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+		return fmt.Errorf("%s: %v is not one of %v", path, v, s.Enum)
+	}
+	switch s.Type {
+	case "":
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: %v is not an object", path, v)
+		}
+		for _, key := range s.Required {
+			if _, ok := obj[key]; !ok {
+				return fmt.Errorf("%s: missing key %s", path, key)
+			}
+		}
+		for key, value := range obj {
+			if prop, ok := s.Properties[key]; ok {
+				if err := spec.validate(prop, value, path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", path, v)
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems || s.MaxItems != nil && len(arr) > *s.MaxItems {
+			return fmt.Errorf("%s: array of %d items", path, len(arr))
+		}
+		for i, item := range arr {
+			if err := spec.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "number", "integer":
+		n, ok := v.(float64)
+		if !ok || s.Type == "integer" && n != float64(int64(n)) {
+			return fmt.Errorf("%s: %v is not an %s", path, v, s.Type)
+		}
+		if s.Minimum != nil && n < *s.Minimum || s.Maximum != nil && n > *s.Maximum {
+			return fmt.Errorf("%s: %v is out of range", path, n)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s: %v is not a string", path, v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: %v is not a boolean", path, v)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported type %s", path, s.Type)
+	}
+	return nil
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	spec := loadOpenAPI(t)
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	handler := newHandler(geocoder, serveOptions{maxBatch: 10})
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			// The handlers themselves never answer 404 or 405.
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(strings.ToUpper(method), path, nil))
+			if rec.Code == http.StatusNotFound || rec.Code == http.StatusMethodNotAllowed {
+				t.Errorf("Expected %s %s to be routed, got %d", strings.ToUpper(method), path, rec.Code)
+			}
+		}
+	}
+}
+
+func TestOpenAPIResponses(t *testing.T) {
+	spec := loadOpenAPI(t)
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	handler := newHandler(geocoder, serveOptions{maxBatch: 3})
+	broken, err := geodecode.New(geodecode.WithDataset(t.TempDir() + "/missing.csv"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	brokenHandler := newHandler(broken, serveOptions{maxBatch: 3})
+	limitedHandler := newHandler(geocoder, serveOptions{maxBatch: 3, limiter: newRateLimiter(0.001, 1, "")})
+	keys, err := newAPIKeys("web=s3cret", "")
+	if err != nil {
+		t.Fatalf("newAPIKeys: %v", err)
+	}
+	authHandler := newHandler(geocoder, serveOptions{maxBatch: 3, keys: keys})
+
+	tests := []struct {
+		handler                    http.Handler
+		method, path, target       string
+		contentType, body, upgrade string
+	}{
+		{handler, "get", "/reverse", "/reverse?lat=48.9&lon=2.3", "", "", ""},
+		{handler, "get", "/reverse", "/reverse?lat=0&lon=0", "", "", ""},
+		{handler, "get", "/reverse", "/reverse?lat=91&lon=0", "", "", ""},
+		{handler, "get", "/reverse", "/reverse?lat=1", "", "", ""},
+		{brokenHandler, "get", "/reverse", "/reverse?lat=1&lon=1", "", "", ""},
+		{handler, "post", "/reverse/batch", "/reverse/batch", "application/json", `[[48.9, 2.3], {"lat": 91, "lon": 0}, [0, 0]]`, ""},
+		{handler, "post", "/reverse/batch", "/reverse/batch", "application/x-ndjson", "[48.9, 2.3]\n[91, 0]\n", ""},
+		{handler, "post", "/reverse/batch", "/reverse/batch", "text/csv", "48.9,2.3\n", ""},
+		{handler, "post", "/reverse/batch", "/reverse/batch", "application/json", `[[1,2],[3,4],[5,6],[7,8]]`, ""},
+		{handler, "post", "/reverse/batch", "/reverse/batch", "application/json", `{}`, ""},
+		{handler, "post", "/reverse/batch", "/reverse/batch", "application/xml", `<coords/>`, ""},
+		{brokenHandler, "post", "/reverse/batch", "/reverse/batch", "application/json", `[[1,2]]`, ""},
+		{handler, "get", "/stream", "/stream", "", "", ""},
+		{handler, "get", "/stream", "/stream", "", "", "8"},
+		{brokenHandler, "get", "/stream", "/stream", "", "", ""},
+		{handler, "get", "/healthz", "/healthz", "", "", ""},
+		{brokenHandler, "get", "/healthz", "/healthz", "", "", ""},
+		{handler, "get", "/openapi.json", "/openapi.json", "", "", ""},
+		{handler, "get", "/metrics", "/metrics", "", "", ""},
+		{limitedHandler, "get", "/reverse", "/reverse?lat=48.9&lon=2.3", "", "", ""},
+		{limitedHandler, "get", "/reverse", "/reverse?lat=48.9&lon=2.3", "", "", ""},
+		{authHandler, "get", "/reverse", "/reverse?lat=48.9&lon=2.3", "", "", ""},
+		{authHandler, "post", "/reverse/batch", "/reverse/batch", "application/json", `[[1,2]]`, ""},
+		{authHandler, "get", "/stream", "/stream?api_key=wrong", "", "", "13"},
+		{authHandler, "get", "/healthz", "/healthz", "", "", ""},
+	}
+	for _, tt := range tests {
+		name := strings.ToUpper(tt.method) + " " + tt.target
+		req := httptest.NewRequest(strings.ToUpper(tt.method), tt.target, strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		if tt.upgrade != "" {
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Sec-WebSocket-Version", tt.upgrade)
+		}
+		rec := httptest.NewRecorder()
+		tt.handler.ServeHTTP(rec, req)
+
+		resp, ok := spec.response(spec.Paths[tt.path][tt.method], rec.Code)
+		if !ok {
+			t.Errorf("%s: status %d is not documented", name, rec.Code)
+			continue
+		}
+		mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+		content, ok := resp.Content[mediaType]
+		if !ok {
+			t.Errorf("%s: content type %q of status %d is not documented", name, mediaType, rec.Code)
+			continue
+		}
+		var values []any
+		switch mediaType {
+		case "application/json":
+			var v any
+			if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+				t.Errorf("%s: %v", name, err)
+			}
+			values = append(values, v)
+		case "application/x-ndjson":
+			// Each line is a BatchItem.
+			content.Schema = &schema{Ref: "#/components/schemas/BatchItem"}
+			scanner := bufio.NewScanner(rec.Body)
+			for scanner.Scan() {
+				var v any
+				if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+					t.Errorf("%s: %v", name, err)
+				}
+				values = append(values, v)
+			}
+		default:
+			values = append(values, rec.Body.String())
+		}
+		for _, v := range values {
+			if err := spec.validate(content.Schema, v, "body"); err != nil {
+				t.Errorf("%s: status %d: %v", name, rec.Code, err)
+			}
+		}
+	}
+}
+
+func TestOpenAPINotModified(t *testing.T) {
+	spec := loadOpenAPI(t)
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/reverse?lat=48.9&lon=2.3", nil)
+	req.Header.Set("If-None-Match", "*")
+	rec := httptest.NewRecorder()
+	newHandler(geocoder, serveOptions{maxBatch: 3}).ServeHTTP(rec, req)
+	resp, ok := spec.response(spec.Paths["/reverse"]["get"], rec.Code)
+	if rec.Code != http.StatusNotModified || !ok || len(resp.Content) != 0 || rec.Body.Len() != 0 {
+		t.Errorf("Expected a documented 304 without a body, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestOpenAPIValidate(t *testing.T) {
+	spec := loadOpenAPI(t)
+	result := &schema{Ref: "#/components/schemas/Result"}
+	tests := []struct {
+		body string
+		ok   bool
+	}{
+		{`{"found":false,"distance":0,"unit":"km","confidence":0,"location":{"geonameId":0,"lat":0,"lon":0,"city":"","admin1":"","admin1Code":"","admin2":"","admin2Name":"","cc":"","country":"","timezone":"","elevation":0,"population":0,"countryInfo":{"iso3":"","continent":"","currency":"","callingCode":""}}}`, true},
+		{`{"found":true}`, false},
+		{`{"found":"yes","distance":0,"unit":"km","confidence":0,"location":{}}`, false},
+		{`{"found":true,"distance":0,"unit":"furlong","confidence":0,"location":{}}`, false},
+	}
+	for _, tt := range tests {
+		var v any
+		json.Unmarshal([]byte(tt.body), &v)
+		if err := spec.validate(result, v, "body"); (err == nil) != tt.ok {
+			t.Errorf("%s: Expected valid=%t, got %v", tt.body, tt.ok, err)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/internal/parquet"
+	"github.com/sdwillbrand/GeoDecode/internal/sqlite"
+)
+
+// defaultFields are the fields of the nearest location written when -fields
+// is not given.
+var defaultFields = []string{"city", "admin1", "admin2", "cc", "country", "distance"}
+
+// fieldNames lists the fields that can be selected with -fields.
+var fieldNames = []string{"city", "admin1", "admin2", "cc", "country", "distance", "unit", "confidence", "timezone", "population", "geonameid"}
+
+// fieldValue returns the value of the field name for res, which was found:
+// a string, a float64 or an int.
+func fieldValue(name string, res geodecode.Result) any {
+	loc := res.Location
+	switch name {
+	case "city":
+		return loc.City
+	case "admin1":
+		return loc.Admin1
+	case "admin2":
+		return loc.Admin2
+	case "cc":
+		return loc.CC
+	case "country":
+		return loc.Country
+	case "distance":
+		return math.Round(res.Distance*1000) / 1000
+	case "unit":
+		return res.Unit.String()
+	case "confidence":
+		return math.Round(res.Confidence*1000) / 1000
+	case "timezone":
+		return loc.Timezone
+	case "population":
+		return loc.Population
+	default: // geonameid
+		return loc.GeonameID
+	}
+}
+
+// fieldType returns the SQL type of the field name.
+func fieldType(name string) string {
+	switch name {
+	case "distance", "confidence":
+		return "REAL"
+	case "population", "geonameid":
+		return "INTEGER"
+	default:
+		return "TEXT"
+	}
+}
+
+// parseFields parses a comma-separated list of field names.
+func parseFields(s string) ([]string, error) {
+	var fields []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(fieldNames, name) {
+			return nil, fmt.Errorf("unknown field %q, want one of %s", name, strings.Join(fieldNames, ", "))
+		}
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// fieldText returns the value of the field name for res as text, which is
+// empty if res was not found.
+func fieldText(name string, res geodecode.Result) string {
+	if !res.Found {
+		return ""
+	}
+	switch v := fieldValue(name, res).(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// appendFieldsJSON appends the key found and, if res was found, fields as
+// keys to buf, which holds a JSON object without its closing brace. more
+// tells whether the object already has keys.
+func appendFieldsJSON(buf []byte, more bool, fields []string, res geodecode.Result) []byte {
+	if more {
+		buf = append(buf, ',')
+	}
+	buf = strconv.AppendBool(append(buf, `"found":`...), res.Found)
+	if !res.Found {
+		return buf
+	}
+	for _, name := range fields {
+		buf = append(appendJSONString(append(buf, ','), name), ':')
+		data, _ := json.Marshal(fieldValue(name, res)) // Cannot fail for these types
+		buf = append(buf, data...)
+	}
+	return buf
+}
+
+// appendJSONString appends s to buf as a JSON string.
+func appendJSONString(buf []byte, s string) []byte {
+	data, _ := json.Marshal(s) // Cannot fail for a string
+	return append(buf, data...)
+}
+
+// outputFormats lists the formats of resultWriter.
+var outputFormats = []string{"csv", "tsv", "json", "jsonl", "geojson"}
+
+// outputOptions selects how the lookup and batch commands write results.
+type outputOptions struct {
+	format string             // One of outputFormats, or "text" for lookup
+	fields []string           // Fields of the nearest location to write
+	tmpl   *template.Template // Executed for each result instead, if set
+	k      int                // Number of nearest locations to write for each coordinate
+}
+
+// outputFlags registers the flags selecting the output of the lookup and
+// batch commands on fs: -format, one of formats and by default the first,
+// -fields, -template and -k. The returned function returns the options once
+// fs is parsed.
+func outputFlags(fs *flag.FlagSet, formats []string) func() (outputOptions, error) {
+	format := fs.String("format", formats[0], "output format: "+strings.Join(formats, ", "))
+	fieldList := fs.String("fields", strings.Join(defaultFields, ","), "comma-separated fields of the nearest location to write: "+strings.Join(fieldNames, ", "))
+	tmplText := fs.String("template", "", "Go template to execute for each result instead, e.g. '{{.City}}, {{.Country}} ({{printf \"%.1f\" .DistanceKM}} km)'")
+	k := fs.Int("k", 1, "number of nearest locations to write for each coordinate, nearest first")
+
+	return func() (outputOptions, error) {
+		if !slices.Contains(formats, *format) {
+			return outputOptions{}, badInputf("unknown output format %q", *format)
+		}
+		if *k < 1 {
+			return outputOptions{}, badInputf("k must be at least 1, got %d", *k)
+		}
+		fields, err := parseFields(*fieldList)
+		if err != nil {
+			return outputOptions{}, withExitCode(exitBadInput, err)
+		}
+		opts := outputOptions{format: *format, fields: fields, k: *k}
+		if *tmplText != "" {
+			if opts.tmpl, err = template.New("template").Parse(*tmplText); err != nil {
+				return outputOptions{}, withExitCode(exitBadInput, err)
+			}
+		}
+		return opts, nil
+	}
+}
+
+// templateData is the value the template of outputOptions is executed with
+// for a result. The fields of the nearest location, such as City and
+// Country, are promoted; they are empty if Found is false.
+type templateData struct {
+	geodecode.Location
+	Found      bool
+	Distance   float64        // In Unit
+	Unit       geodecode.Unit // Prints as its symbol, such as km
+	DistanceKM float64
+	Confidence float64
+	QueryLat   float64           // Latitude of the queried coordinate
+	QueryLon   float64           // Longitude of the queried coordinate
+	Record     map[string]string // Fields of the input record by column name; nil without records
+}
+
+// sqliteTable is the table the sqlite format writes results to.
+const sqliteTable = "results"
+
+// parquetRowGroup is the number of results per row group of the parquet
+// format, which are held in memory until written.
+const parquetRowGroup = 64 * 1024
+
+// resultWriter writes the results of the lookup and batch commands in one
+// of the formats csv, tsv, json (an array of objects), jsonl (an object per
+// line), geojson, and the tables sqlite, a database file, and parquet. Each
+// result is written with the fields of the input record it was read from,
+// or else the queried coordinate, followed by the selected fields of the
+// nearest location. A geojson FeatureCollection has a Point feature at each
+// queried coordinate, with the other values as its properties, for viewing
+// results in tools like geojson.io or QGIS. With a template, each result is
+// written as the template's output on a line of its own instead.
+type resultWriter struct {
+	w       io.Writer
+	format  string
+	fields  []string
+	tmpl    *template.Template
+	columns []string    // Names of the input records' fields; nil without records
+	csv     *csv.Writer // Writes csv and tsv
+	table   *sqlite.Writer
+	parquet *parquet.Writer
+	group   [][]any // Values of each column of the parquet row group not written yet
+	row     []any   // Values of the table's row being written
+	buf     []byte
+	text    bytes.Buffer // Output of tmpl
+	n       int          // Number of results written
+}
+
+// newResultWriter returns a resultWriter writing to w as selected by opts.
+// columns names the fields of the input records, if there are any. delimiter
+// separates the fields of csv output; 0 means comma. close must be called
+// to complete the output.
+func newResultWriter(w io.Writer, opts outputOptions, columns []string, delimiter rune) (*resultWriter, error) {
+	format, fields := opts.format, opts.fields
+	rw := &resultWriter{w: w, format: format, fields: fields, tmpl: opts.tmpl, columns: columns}
+	if rw.tmpl != nil {
+		return rw, nil
+	}
+	switch format {
+	case "csv", "tsv":
+		rw.csv = csv.NewWriter(w)
+		if format == "tsv" {
+			rw.csv.Comma = '\t'
+		} else if delimiter != 0 {
+			rw.csv.Comma = delimiter
+		}
+		header := columns
+		if header == nil {
+			header = []string{"lat", "lon"}
+		}
+		return rw, rw.csv.Write(append(slices.Clip(header), fields...))
+	case "json", "jsonl", "geojson":
+		return rw, nil
+	case "sqlite":
+		file, ok := w.(io.WriterAt)
+		if !ok {
+			return nil, errors.New("sqlite output needs a file")
+		}
+		table, indexes := tableSchema(columns, fields)
+		var err error
+		rw.table, err = sqlite.NewWriter(file, sqliteTable, table, indexes)
+		return rw, err
+	case "parquet":
+		table, _ := tableSchema(columns, fields)
+		schema := make([]parquet.WriteColumn, len(table))
+		for i, c := range table {
+			schema[i] = parquet.WriteColumn{Name: c.Name, Type: parquet.ByteArray, Optional: true}
+			switch c.Type {
+			case "REAL":
+				schema[i].Type = parquet.Double
+			case "INTEGER":
+				schema[i].Type = parquet.Int64
+			default:
+				schema[i].Dictionary = i >= len(table)-len(fields) // Place names repeat
+			}
+		}
+		rw.parquet = parquet.NewWriter(w, schema, parquet.WriteOptions{Codec: parquet.Gzip})
+		rw.group = make([][]any, len(schema))
+		return rw, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// tableSchema returns the columns of the table holding results with the
+// given input columns, or the queried lat and lon if there are none,
+// followed by fields. Input columns are text, and a name taken already gets
+// a number appended. The table is indexed by place, on those of cc, admin1,
+// admin2 and city selected, and by geonameid if selected.
+func tableSchema(columns, fields []string) ([]sqlite.Column, [][]string) {
+	var table []sqlite.Column
+	taken := make(map[string]bool)
+	add := func(name, typ string) string {
+		unique := name
+		for n := 2; taken[strings.ToLower(unique)]; n++ {
+			unique = fmt.Sprintf("%s_%d", name, n)
+		}
+		taken[strings.ToLower(unique)] = true
+		table = append(table, sqlite.Column{Name: unique, Type: typ})
+		return unique
+	}
+	if columns == nil {
+		add("lat", "REAL")
+		add("lon", "REAL")
+	}
+	for _, name := range columns {
+		add(name, "TEXT")
+	}
+	names := make(map[string]string)
+	for _, name := range fields {
+		names[name] = add(name, fieldType(name))
+	}
+
+	var indexes [][]string
+	var place []string
+	for _, name := range []string{"cc", "admin1", "admin2", "city"} {
+		if names[name] != "" {
+			place = append(place, names[name])
+		}
+	}
+	if place != nil {
+		indexes = append(indexes, place)
+	}
+	if names["geonameid"] != "" {
+		indexes = append(indexes, []string{names["geonameid"]})
+	}
+	return table, indexes
+}
+
+// write writes the result res for the query coord, read from the fields of
+// record, which is nil if there is no input record.
+func (rw *resultWriter) write(coord [2]float64, record []string, res geodecode.Result) error {
+	if rw.tmpl != nil {
+		return rw.execute(coord, record, res)
+	}
+	if rw.table != nil || rw.parquet != nil {
+		row := rw.row[:0]
+		if record == nil {
+			row = append(row, coord[0], coord[1])
+		}
+		for _, value := range record[:min(len(record), len(rw.columns))] {
+			row = append(row, value)
+		}
+		for range len(rw.columns) - len(record) {
+			row = append(row, nil) // Short rows of CSV input
+		}
+		for _, name := range rw.fields {
+			var value any
+			if res.Found {
+				value = fieldValue(name, res)
+			}
+			if i, ok := value.(int); ok {
+				value = int64(i)
+			}
+			row = append(row, value)
+		}
+		rw.row = row
+		rw.n++
+		if rw.table != nil {
+			return rw.table.Write(row)
+		}
+		for i, value := range row {
+			rw.group[i] = append(rw.group[i], value)
+		}
+		if len(rw.group[0]) < parquetRowGroup {
+			return nil
+		}
+		return rw.flushGroup()
+	}
+	if rw.csv != nil {
+		row := record
+		if row == nil {
+			row = []string{strconv.FormatFloat(coord[0], 'f', -1, 64), strconv.FormatFloat(coord[1], 'f', -1, 64)}
+		}
+		row = slices.Clip(row)
+		for _, name := range rw.fields {
+			row = append(row, fieldText(name, res))
+		}
+		return rw.csv.Write(row)
+	}
+
+	buf := rw.buf[:0]
+	switch {
+	case rw.format == "json" && rw.n == 0:
+		buf = append(buf, "[\n"...)
+	case rw.format == "geojson" && rw.n == 0:
+		buf = append(buf, `{"type":"FeatureCollection","features":[`+"\n"...)
+	case rw.format != "jsonl":
+		buf = append(buf, ",\n"...)
+	}
+	if rw.format == "geojson" {
+		buf = append(buf, `{"type":"Feature","geometry":{"type":"Point","coordinates":[`...)
+		buf = strconv.AppendFloat(buf, coord[1], 'f', -1, 64)
+		buf = strconv.AppendFloat(append(buf, ','), coord[0], 'f', -1, 64)
+		buf = append(buf, `]},"properties":`...)
+	}
+	buf = rw.appendObject(buf, coord, record, res)
+	switch rw.format {
+	case "geojson":
+		buf = append(buf, '}')
+	case "jsonl":
+		buf = append(buf, '\n')
+	}
+	rw.buf = buf
+	rw.n++
+	_, err := rw.w.Write(buf)
+	return err
+}
+
+// execute writes the output of the template for the result res for the
+// query coord and record, followed by a newline unless it ends with one.
+func (rw *resultWriter) execute(coord [2]float64, record []string, res geodecode.Result) error {
+	data := templateData{
+		Location:   res.Location,
+		Found:      res.Found,
+		Distance:   res.Distance,
+		Unit:       res.Unit,
+		DistanceKM: res.DistanceKM,
+		Confidence: res.Confidence,
+		QueryLat:   coord[0],
+		QueryLon:   coord[1],
+	}
+	if record != nil {
+		data.Record = make(map[string]string, len(rw.columns))
+		for i, value := range record[:min(len(record), len(rw.columns))] {
+			data.Record[rw.columns[i]] = value
+		}
+	}
+	rw.text.Reset()
+	if err := rw.tmpl.Execute(&rw.text, data); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(rw.text.Bytes(), []byte("\n")) {
+		rw.text.WriteByte('\n')
+	}
+	rw.n++
+	_, err := rw.w.Write(rw.text.Bytes())
+	return err
+}
+
+// appendObject appends the JSON object of the result res for the query
+// coord and record to buf. The coordinate is left out of geojson properties.
+func (rw *resultWriter) appendObject(buf []byte, coord [2]float64, record []string, res geodecode.Result) []byte {
+	buf = append(buf, '{')
+	more := false
+	switch {
+	case record != nil:
+		for i, value := range record[:min(len(record), len(rw.columns))] {
+			if more {
+				buf = append(buf, ',')
+			}
+			buf = append(appendJSONString(buf, rw.columns[i]), ':')
+			buf = appendJSONString(buf, value)
+			more = true
+		}
+	case rw.format != "geojson":
+		buf = strconv.AppendFloat(append(buf, `"lat":`...), coord[0], 'f', -1, 64)
+		buf = strconv.AppendFloat(append(buf, `,"lon":`...), coord[1], 'f', -1, 64)
+		more = true
+	}
+	return append(appendFieldsJSON(buf, more, rw.fields, res), '}')
+}
+
+// flushGroup writes the pending parquet row group.
+func (rw *resultWriter) flushGroup() error {
+	err := rw.parquet.WriteRowGroup(rw.group)
+	for i := range rw.group {
+		rw.group[i] = rw.group[i][:0]
+	}
+	return err
+}
+
+// close completes the output.
+func (rw *resultWriter) close() error {
+	if rw.tmpl != nil {
+		return nil
+	}
+	var end string
+	switch rw.format {
+	case "sqlite":
+		return rw.table.Close()
+	case "parquet":
+		if len(rw.group[0]) > 0 {
+			if err := rw.flushGroup(); err != nil {
+				return err
+			}
+		}
+		return rw.parquet.Close()
+	case "csv", "tsv":
+		rw.csv.Flush()
+		return rw.csv.Error()
+	case "json":
+		end = "\n]\n"
+		if rw.n == 0 {
+			end = "[]\n"
+		}
+	case "geojson":
+		end = "\n]}\n"
+		if rw.n == 0 {
+			end = `{"type":"FeatureCollection","features":[]}` + "\n"
+		}
+	}
+	_, err := io.WriteString(rw.w, end)
+	return err
+}
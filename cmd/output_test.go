@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+	"text/template"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// featureCollection is the part of a GeoJSON FeatureCollection the tests
+// check.
+type featureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Geometry struct {
+			Type        string     `json:"type"`
+			Coordinates [2]float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]any `json:"properties"`
+	} `json:"features"`
+}
+
+func TestResultWriter(t *testing.T) {
+	fields, err := parseFields("city, CC,distance")
+	if err != nil {
+		t.Fatalf("parseFields: %v", err)
+	}
+	found := geodecode.Result{Found: true, Location: geodecode.Location{City: "Berlin", CC: "DE"}, Distance: 2.3254}
+	tests := []struct {
+		format  string
+		columns []string
+		record  []string
+		want    string
+	}{
+		{"csv", nil, nil, "lat,lon,city,cc,distance\n52.5,13.4,Berlin,DE,2.325\n0,0,,,\n"},
+		{"tsv", []string{"id"}, []string{"a b"}, "id\tcity\tcc\tdistance\na b\tBerlin\tDE\t2.325\na b\t\t\t\n"},
+		{"json", nil, nil, "[\n" + `{"lat":52.5,"lon":13.4,"found":true,"city":"Berlin","cc":"DE","distance":2.325}` + ",\n" + `{"lat":0,"lon":0,"found":false}` + "\n]\n"},
+		{"jsonl", []string{"id"}, []string{"7"}, `{"id":"7","found":true,"city":"Berlin","cc":"DE","distance":2.325}` + "\n" + `{"id":"7","found":false}` + "\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		rw, err := newResultWriter(&out, outputOptions{format: tt.format, fields: fields}, tt.columns, 0)
+		if err != nil {
+			t.Fatalf("newResultWriter(%q): %v", tt.format, err)
+		}
+		rw.write([2]float64{52.5, 13.4}, tt.record, found)
+		rw.write([2]float64{0, 0}, tt.record, geodecode.Result{})
+		if err := rw.close(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s: Expected %q, got %q", tt.format, tt.want, out.String())
+		}
+	}
+
+	var out bytes.Buffer
+	rw, _ := newResultWriter(&out, outputOptions{format: "json", fields: fields}, nil, 0)
+	if rw.close(); out.String() != "[]\n" {
+		t.Errorf("Expected an empty array, got %q", out.String())
+	}
+	if _, err := newResultWriter(&out, outputOptions{format: "xml"}, nil, 0); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+	if _, err := parseFields("city,elevation"); err == nil {
+		t.Errorf("Expected an error for an unknown field")
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := lookup(&out, geocoder, [][2]float64{{52.5, 13.4}, {0, 0}}, outputOptions{format: "geojson", fields: defaultFields}); err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	var fc featureCollection
+	if err := json.Unmarshal(out.Bytes(), &fc); err != nil {
+		t.Fatalf("Expected valid JSON, got %v: %s", err, out.String())
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 2 {
+		t.Fatalf("Expected a FeatureCollection of 2 features, got %s", out.String())
+	}
+	berlin := fc.Features[0]
+	if berlin.Geometry.Type != "Point" || berlin.Geometry.Coordinates != [2]float64{13.4, 52.5} {
+		t.Errorf("Expected the query point [13.4, 52.5], got %+v", berlin.Geometry)
+	}
+	if berlin.Properties["city"] != "Berlin" || berlin.Properties["found"] != true || berlin.Properties["distance"] != 2.325 {
+		t.Errorf("Expected the properties of Berlin, got %v", berlin.Properties)
+	}
+	if props := fc.Features[1].Properties; props["found"] != false || len(props) != 1 {
+		t.Errorf("Expected only found=false for a miss, got %v", props)
+	}
+
+	out.Reset()
+	opts := batchOptions{latCol: "lat", lonCol: "lon", outputOptions: outputOptions{format: "geojson", fields: defaultFields}}
+	if err := batch(&out, strings.NewReader("id,lat,lon\n7,48.9,2.3\n"), geocoder, opts); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	fc = featureCollection{}
+	if err := json.Unmarshal(out.Bytes(), &fc); err != nil {
+		t.Fatalf("Expected valid JSON, got %v: %s", err, out.String())
+	}
+	if len(fc.Features) != 1 || fc.Features[0].Properties["id"] != "7" || fc.Features[0].Properties["city"] != "Paris" {
+		t.Errorf("Expected Paris with the input fields, got %s", out.String())
+	}
+
+	out.Reset()
+	if err := batch(&out, strings.NewReader("lat,lon\n"), geocoder, opts); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if err := json.Unmarshal(out.Bytes(), &fc); err != nil || len(fc.Features) != 0 {
+		t.Errorf("Expected an empty FeatureCollection, got %s", out.String())
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	outputOpts := outputFlags(fs, outputFormats)
+	if err := fs.Parse([]string{"-template", `{{if .Found}}{{.City}}, {{.Country}} ({{printf "%.1f" .DistanceKM}} {{.Unit}}){{else}}{{.QueryLat}},{{.QueryLon}}: none{{end}}`}); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := outputOpts()
+	if err != nil {
+		t.Fatalf("outputFlags: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := lookup(&out, geocoder, [][2]float64{{52.5, 13.4}, {0, 0}}, opts); err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if want := "Berlin, Germany (2.3 km)\n0,0: none\n"; out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	if err := lookupStream(&out, strings.NewReader("lat,lon\n48.9,2.3\n{\"lat\":52.5,\"lon\":13.4}\n"), geocoder, opts); err != nil {
+		t.Fatalf("lookupStream: %v", err)
+	}
+	if want := "Paris, France (5.8 km)\nBerlin, Germany (2.3 km)\n"; out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	opts.tmpl = template.Must(template.New("").Parse(`{{.Record.id}}={{.CC}}` + "\n"))
+	if err := batch(&out, strings.NewReader("id,lat,lon\n7,48.9,2.3\n"), geocoder, batchOptions{latCol: "lat", lonCol: "lon", outputOptions: opts}); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if want := "7=FR\n"; out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	outputOpts = outputFlags(fs, outputFormats)
+	fs.Parse([]string{"-template", "{{.City"})
+	if _, err := outputOpts(); err == nil {
+		t.Errorf("Expected an error for a malformed template")
+	}
+}
+
+func TestTableSchema(t *testing.T) {
+	table, indexes := tableSchema([]string{"id", "City", "city_2"}, []string{"city", "cc", "distance", "geonameid"})
+	var got []string
+	for _, c := range table {
+		got = append(got, c.Name+" "+c.Type)
+	}
+	want := "id TEXT,City TEXT,city_2 TEXT,city_3 TEXT,cc TEXT,distance REAL,geonameid INTEGER"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Expected columns %q, got %q", want, strings.Join(got, ","))
+	}
+	if fmt.Sprint(indexes) != "[[cc city_3] [geonameid]]" {
+		t.Errorf("Expected indexes on cc, city_3 and on geonameid, got %v", indexes)
+	}
+
+	table, indexes = tableSchema(nil, []string{"distance"})
+	if len(table) != 3 || table[0].Name != "lat" || table[0].Type != "REAL" || indexes != nil {
+		t.Errorf("Expected lat, lon and distance without indexes, got %v and %v", table, indexes)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// jsonCoordinate holds the coordinate of a JSON record. Keys are matched
+// case-insensitively.
+type jsonCoordinate struct {
+	Lat       *float64 `json:"lat"`
+	Latitude  *float64 `json:"latitude"`
+	Lon       *float64 `json:"lon"`
+	Lng       *float64 `json:"lng"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// coord returns the coordinate of c, or false if c lacks the latitude or
+// the longitude.
+func (c jsonCoordinate) coord() ([2]float64, bool) {
+	lat := cmp.Or(c.Lat, c.Latitude)
+	lon := cmp.Or(c.Lon, c.Lng, c.Longitude)
+	if lat == nil || lon == nil {
+		return [2]float64{}, false
+	}
+	return [2]float64{*lat, *lon}, true
+}
+
+// lookupStream reads records from r, one per line, and writes each to w with
+// the nearest location added, so the lookup command composes with shell
+// pipelines. A line is either a JSON object with the keys lat (or latitude)
+// and lon (or lng, longitude), which gets the key found and fields added, or
+// CSV with the latitude and longitude in its first two fields, which gets
+// fields appended. A first CSV line that is not a coordinate is taken for a
+// header. Empty lines are copied as they are. With opts.k above 1, each
+// record is written once for each of its k nearest locations, nearest first.
+// If opts has a template, each record is replaced by its output instead. Output is flushed whenever no
+// more input is buffered, so each record is answered as soon as it is read.
+func lookupStream(w io.Writer, r io.Reader, geocoder *geodecode.RGeocoder, opts outputOptions) error {
+	in := bufio.NewReader(r)
+	out := bufio.NewWriter(w)
+	var tw *resultWriter
+	if opts.tmpl != nil {
+		tw, _ = newResultWriter(out, opts, nil, 0) // Cannot fail with a template
+	}
+	for line := 1; ; line++ {
+		text, err := in.ReadBytes('\n')
+		if len(text) == 0 && err == io.EOF {
+			break
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if err := enrichLine(out, bytes.TrimSpace(text), line, geocoder, opts, tw); err != nil {
+			return wrapf(err, "line %d", line)
+		}
+		if in.Buffered() == 0 {
+			if err := out.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return out.Flush()
+}
+
+// enrichLine writes the record text, the line numbered line, to w with the
+// fields of opts of each nearest location added, or the output of tw for
+// them if tw is not nil.
+func enrichLine(w *bufio.Writer, text []byte, line int, geocoder *geodecode.RGeocoder, opts outputOptions, tw *resultWriter) error {
+	fields := opts.fields
+	switch {
+	case len(text) == 0:
+		return w.WriteByte('\n')
+	case text[0] == '{':
+		var c jsonCoordinate
+		if err := json.Unmarshal(text, &c); err != nil {
+			return err
+		}
+		coord, ok := c.coord()
+		if !ok {
+			return errors.New("missing lat or lon")
+		}
+		results, err := resolveK(geocoder, coord, opts.k)
+		if err != nil {
+			return err
+		}
+		more := len(bytes.TrimSpace(text[1:len(text)-1])) > 0
+		for _, res := range results {
+			if tw != nil {
+				if err := tw.write(coord, nil, res); err != nil {
+					return err
+				}
+				continue
+			}
+			w.Write(appendFieldsJSON(text[:len(text)-1:len(text)-1], more, fields, res))
+			if _, err := w.WriteString("}\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		reader := csv.NewReader(bytes.NewReader(text))
+		reader.FieldsPerRecord = -1
+		record, err := reader.Read()
+		if err != nil {
+			return err
+		}
+		if len(record) < 2 {
+			return fmt.Errorf("want lat,lon, got %d fields", len(record))
+		}
+		writer := csv.NewWriter(w)
+		coord, err := parseLatLon(record[0], record[1])
+		if err != nil {
+			if line == 1 && tw != nil {
+				return nil
+			}
+			if line == 1 {
+				writer.Write(append(record, fields...))
+				writer.Flush()
+				return writer.Error()
+			}
+			return err
+		}
+		results, err := resolveK(geocoder, coord, opts.k)
+		if err != nil {
+			return err
+		}
+		for _, res := range results {
+			if tw != nil {
+				if err := tw.write(coord, nil, res); err != nil {
+					return err
+				}
+				continue
+			}
+			row := slices.Clip(record)
+			for _, name := range fields {
+				row = append(row, fieldText(name, res))
+			}
+			writer.Write(row)
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+}
+
+// resolveK returns the k nearest locations of coord, nearest first, or a
+// single result that is not found if none is in range.
+func resolveK(geocoder *geodecode.RGeocoder, coord [2]float64, k int) ([]geodecode.Result, error) {
+	if k <= 1 {
+		return geocoder.Resolve(coord)
+	}
+	results, err := geocoder.Nearest(coord, k)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return []geodecode.Result{{}}, nil
+	}
+	return results, nil
+}
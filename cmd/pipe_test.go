@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestLookupStream(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	input := "lat,lon,name\n52.5,13.4,\"a, b\"\n\n{\"id\": 1, \"Latitude\": 48.9, \"lng\": 2.3}\n{\"lat\":0,\"lon\":0}"
+	want := "lat,lon,name,city,admin1,admin2,cc,country,distance\n" +
+		"52.5,13.4,\"a, b\",Berlin,Berlin,,DE,Germany,2.325\n" +
+		"\n" +
+		"{\"id\": 1, \"Latitude\": 48.9, \"lng\": 2.3,\"found\":true,\"city\":\"Paris\",\"admin1\":\"Ile-de-France\",\"admin2\":\"\",\"cc\":\"FR\",\"country\":\"France\",\"distance\":5.758}\n" +
+		"{\"lat\":0,\"lon\":0,\"found\":false}\n"
+	var out bytes.Buffer
+	if err := lookupStream(&out, strings.NewReader(input), geocoder, outputOptions{fields: defaultFields}); err != nil {
+		t.Fatalf("lookupStream: %v", err)
+	}
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	for _, input := range []string{"52.5,13.4\nlat,lon\n", "52.5\n", "{\"lat\":52.5}\n", "{\"lat\":\n", "91,0\n"} {
+		if err := lookupStream(&out, strings.NewReader(input), geocoder, outputOptions{fields: defaultFields}); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressInterval is how often a progress bar is redrawn.
+const progressInterval = 250 * time.Millisecond
+
+// progressWidth is the number of cells of a progress bar.
+const progressWidth = 30
+
+// progress draws a progress bar of the batch command on a terminal: the
+// share of the input read, the rows resolved, their rate and the estimated
+// time left. The share and the estimate need the size of the input, which
+// is unknown for pipes.
+type progress struct {
+	w     io.Writer
+	total int64        // Size of the input in bytes, or -1 if unknown
+	read  atomic.Int64 // Bytes of the input read so far
+	rows  atomic.Int64 // Rows resolved so far
+	start time.Time
+	width int // Length of the line drawn last
+
+	stopOnce sync.Once
+	quit     chan struct{}
+	done     chan struct{}
+}
+
+// isTerminal tells whether f is a terminal, so output written to it is
+// read by a person rather than a program.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// startProgress starts drawing the progress of an input of total bytes, or
+// -1 if unknown, to w until stop is called.
+func startProgress(w io.Writer, total int64) *progress {
+	p := &progress{w: w, total: total, start: time.Now(), quit: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.draw(time.Now())
+			case <-p.quit:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// reader returns a reader of r that counts the bytes read into p.
+func (p *progress) reader(r io.Reader) io.Reader {
+	return &countingReader{r: r, n: &p.read}
+}
+
+// add records that n more rows were resolved. It does nothing if p is nil.
+func (p *progress) add(n int) {
+	if p != nil {
+		p.rows.Add(int64(n))
+	}
+}
+
+// stop draws the final state of p and ends the line. It does nothing if p
+// is nil.
+func (p *progress) stop() {
+	if p == nil {
+		return
+	}
+	p.stopOnce.Do(func() {
+		close(p.quit)
+		<-p.done
+		p.draw(time.Now())
+		fmt.Fprintln(p.w)
+	})
+}
+
+// draw redraws the progress bar as of now, over the line drawn last.
+func (p *progress) draw(now time.Time) {
+	line := p.line(now)
+	pad := max(p.width-len(line), 0)
+	p.width = len(line)
+	fmt.Fprintf(p.w, "\r%s%s", line, strings.Repeat(" ", pad))
+}
+
+// line returns the progress as of now as a line of text.
+func (p *progress) line(now time.Time) string {
+	elapsed := now.Sub(p.start)
+	rows, read := p.rows.Load(), p.read.Load()
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(rows) / elapsed.Seconds()
+	}
+	stats := fmt.Sprintf("%d rows  %.0f rows/s  %s", rows, rate, elapsed.Round(time.Second))
+	if p.total <= 0 {
+		return stats
+	}
+
+	share := min(float64(read)/float64(p.total), 1)
+	cells := int(share * progressWidth)
+	bar := strings.Repeat("=", cells) + strings.Repeat(" ", progressWidth-cells)
+	eta := "?"
+	if read > 0 {
+		left := time.Duration(float64(elapsed) * (1 - share) / share)
+		eta = left.Round(time.Second).String()
+	}
+	return fmt.Sprintf("[%s] %3.0f%%  %s  ETA %s", bar, share*100, stats, eta)
+}
+
+// countingReader is a reader that adds the number of bytes read to n.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &progress{total: 1000, start: start}
+	if _, err := io.Copy(io.Discard, p.reader(strings.NewReader(strings.Repeat("x", 250)))); err != nil {
+		t.Fatal(err)
+	}
+	p.add(500)
+	want := "[=======                       ]  25%  500 rows  50 rows/s  10s  ETA 30s"
+	if got := p.line(start.Add(10 * time.Second)); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	p.total = -1
+	want = "500 rows  50 rows/s  10s"
+	if got := p.line(start.Add(10 * time.Second)); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	var out bytes.Buffer
+	p = startProgress(&out, -1)
+	p.add(3)
+	p.stop()
+	p.stop()
+	if !strings.HasPrefix(out.String(), "\r3 rows") || !strings.HasSuffix(out.String(), "\n") || strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("Expected a final line with 3 rows, got %q", out.String())
+	}
+	var none *progress
+	none.add(1)
+	none.stop()
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimitSweep is how often buckets that have filled up again are
+// forgotten, so clients seen once do not take memory forever.
+const rateLimitSweep = time.Minute
+
+// rateLimiter limits the coordinates each client resolves with a token
+// bucket per client: a bucket holds up to burst tokens and gains rate tokens
+// per second. Requests are let through while their client's bucket is not
+// empty and take a token per coordinate, which may leave the bucket in debt,
+// so large batches are allowed but delay the client's next requests in
+// proportion. A nil *rateLimiter lets everything through.
+type rateLimiter struct {
+	rate     float64 // Tokens gained per second
+	burst    float64 // Most tokens a bucket holds
+	ipHeader string  // Header with the client address set by a proxy; empty to use the peer address
+	now      func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// tokenBucket is the bucket of a client.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // When tokens was last brought up to date
+	burst  float64   // Most tokens the bucket holds, as of last
+	rate   float64   // Tokens gained per second, as of last
+}
+
+// limitedClient is a client and its limit.
+type limitedClient struct {
+	id    string  // IP address, or the name of its API key prefixed with "key:"
+	rate  float64 // Tokens gained per second; 0 for no limit
+	burst float64 // Most tokens its bucket holds
+}
+
+// newRateLimiter returns a limiter letting clients resolve rate coordinates
+// per second, in bursts of up to burst. Clients authenticated with an API
+// key with a limit of its own are limited by it instead.
+func newRateLimiter(rate float64, burst int, ipHeader string) *rateLimiter {
+	return &rateLimiter{
+		rate:     rate,
+		burst:    float64(burst),
+		ipHeader: ipHeader,
+		now:      time.Now,
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+// defaultBurst returns the burst of a rate limit when none is given: one
+// second's worth of coordinates.
+func defaultBurst(rate float64) int {
+	return max(1, int(math.Ceil(rate)))
+}
+
+// client returns the client of r: the API key it was authenticated with,
+// whose limit overrides that of l, or else its IP address, from l.ipHeader
+// if set.
+func (l *rateLimiter) client(r *http.Request) limitedClient {
+	if l == nil {
+		return limitedClient{}
+	}
+	if key := requestAPIKey(r.Context()); key != nil {
+		if key.rate == 0 {
+			return limitedClient{id: "key:" + key.name, rate: l.rate, burst: l.burst}
+		}
+		return limitedClient{id: "key:" + key.name, rate: key.rate, burst: float64(cmp.Or(key.burst, defaultBurst(key.rate)))}
+	}
+	c := limitedClient{rate: l.rate, burst: l.burst}
+	if l.ipHeader != "" {
+		// A proxy appends the address of its peer, so the last one is the
+		// one it vouches for.
+		values := r.Header.Values(l.ipHeader)
+		if len(values) > 0 {
+			list := values[len(values)-1]
+			if c.id = strings.TrimSpace(list[strings.LastIndexByte(list, ',')+1:]); c.id != "" {
+				return c
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	c.id = host
+	return c
+}
+
+// take takes n tokens from the bucket of c if it is not empty. Otherwise it
+// returns false and how long until the bucket has a token.
+func (l *rateLimiter) take(c limitedClient, n int) (bool, time.Duration) {
+	if l == nil || c.rate <= 0 {
+		return true, 0
+	}
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	b := l.buckets[c.id]
+	if b == nil {
+		b = &tokenBucket{tokens: c.burst, last: now}
+		l.buckets[c.id] = b
+	}
+	b.rate, b.burst = c.rate, max(c.burst, 1)
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second)))
+	}
+	b.tokens -= float64(n)
+	return true, 0
+}
+
+// wait takes n tokens from the bucket of client, waiting until it is not
+// empty or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context, client limitedClient, n int) error {
+	for {
+		ok, delay := l.take(client, n)
+		if ok {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// sweep forgets the buckets that are full again, at most every
+// rateLimitSweep. l.mu must be held.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweep {
+		return
+	}
+	l.lastSweep = now
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// limit returns h limited to a coordinate per request by l. Requests of
+// clients with empty buckets get status 429 with a Retry-After header.
+func (l *rateLimiter) limit(h http.Handler) http.Handler {
+	if l == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, delay := l.take(l.client(r), 1); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, fmt.Errorf("rate limit exceeded, retry in %v", delay.Round(time.Millisecond)))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// charge takes n more tokens from the bucket of the client of r, for the
+// coordinates of a batch beyond the first, which limit took.
+func (l *rateLimiter) charge(r *http.Request, n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	client := l.client(r)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b := l.buckets[client.id]; b != nil {
+		b.tokens -= float64(n)
+	}
+}
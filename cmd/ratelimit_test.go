@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newRateLimiter(2, 3, "")
+	l.now = func() time.Time { return now }
+	a, b, c := limitedClient{"a", 2, 3}, limitedClient{"b", 2, 3}, limitedClient{"c", 2, 3}
+
+	for i := range 3 {
+		if ok, _ := l.take(a, 1); !ok {
+			t.Errorf("Expected request %d of the burst to pass", i)
+		}
+	}
+	if ok, delay := l.take(a, 1); ok || delay != 500*time.Millisecond {
+		t.Errorf("Expected to wait 500ms, got %t, %v", ok, delay)
+	}
+	if ok, _ := l.take(b, 1); !ok {
+		t.Errorf("Expected another client to pass")
+	}
+
+	// A batch leaves the bucket in debt.
+	now = now.Add(time.Second)
+	if ok, _ := l.take(a, 10); !ok {
+		t.Errorf("Expected a batch to pass with tokens left")
+	}
+	now = now.Add(3 * time.Second)
+	if ok, delay := l.take(a, 1); ok || delay != 1500*time.Millisecond {
+		t.Errorf("Expected to wait 1.5s after the batch, got %t, %v", ok, delay)
+	}
+
+	// Full buckets are forgotten.
+	now = now.Add(time.Hour)
+	l.take(c, 1)
+	if len(l.buckets) != 1 {
+		t.Errorf("Expected 1 bucket after the sweep, got %d", len(l.buckets))
+	}
+	if ok, _ := l.take(limitedClient{"unlimited", 0, 0}, 100); !ok || len(l.buckets) != 1 {
+		t.Errorf("Expected clients without a rate not to be limited")
+	}
+}
+
+func TestRateLimiterClient(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/reverse", nil)
+	req.RemoteAddr = "10.0.0.1:4321"
+	req.Header.Add("X-Forwarded-For", "1.1.1.1, 203.0.113.7")
+	if got := newRateLimiter(1, 1, "").client(req); got != (limitedClient{"10.0.0.1", 1, 1}) {
+		t.Errorf("Expected the peer address, got %+v", got)
+	}
+	if got := newRateLimiter(1, 1, "X-Forwarded-For").client(req); got != (limitedClient{"203.0.113.7", 1, 1}) {
+		t.Errorf("Expected the address appended by the proxy, got %+v", got)
+	}
+
+	// API keys are limited by their own limit if they have one.
+	for key, want := range map[*apiKey]limitedClient{
+		{name: "app"}:                       {"key:app", 1, 1},
+		{name: "app", rate: 2.5}:            {"key:app", 2.5, 3},
+		{name: "app", rate: 10, burst: 100}: {"key:app", 10, 100},
+	} {
+		req := req.WithContext(context.WithValue(req.Context(), apiKeyContext{}, key))
+		if got := newRateLimiter(1, 1, "").client(req); got != want {
+			t.Errorf("Expected %+v for %+v, got %+v", want, *key, got)
+		}
+	}
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	limiter := newRateLimiter(1, 2, "")
+	handler := newHandler(geocoder, serveOptions{maxBatch: 10, limiter: limiter})
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := serve(http.MethodPost, "/reverse/batch", "[[48.9, 2.3], [52.5, 13.4], [0, 0]]"); rec.Code != http.StatusOK {
+		t.Errorf("Expected the batch to pass, got %d", rec.Code)
+	}
+	rec := serve(http.MethodGet, "/reverse?lat=48.9&lon=2.3", "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" || !strings.Contains(rec.Body.String(), `{"error":"rate limit exceeded`) {
+		t.Errorf("Expected 429 with Retry-After 2 after the batch, got %d with %q, %s", rec.Code, rec.Header().Get("Retry-After"), rec.Body.String())
+	}
+	if rec := serve(http.MethodGet, "/healthz", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected /healthz not to be limited, got %d", rec.Code)
+	}
+}
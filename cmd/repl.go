@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// replHelp describes the input the repl command accepts.
+const replHelp = `Enter a coordinate as "lat,lon" or "lat lon" to find the nearest locations.
+Commands:
+  :k <n>      show the n nearest locations (currently %d)
+  :history    list the inputs entered so far
+  !!          repeat the last input
+  !<n>        repeat input n of :history
+  :help       show this help
+  :quit       exit (or end the input)
+`
+
+// runRepl implements the repl command: it answers coordinates typed on
+// standard input until the input ends.
+func runRepl(args []string) error {
+	fs := flag.NewFlagSet("repl", flag.ContinueOnError)
+	newGeocoder := geocoderFlags(fs)
+	k := fs.Int("k", 1, "number of nearest locations to show")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("geodecode repl [flags]")
+	}
+	if *k < 1 {
+		return fmt.Errorf("k must be at least 1, got %d", *k)
+	}
+	geocoder, err := newGeocoder()
+	if err != nil {
+		return err
+	}
+	return repl(os.Stdout, os.Stdin, geocoder, *k)
+}
+
+// repl reads coordinates and commands from r, one per line, and writes the
+// answers to w, showing the k nearest locations of each coordinate. Errors in
+// the input are reported to w, and do not end the session.
+func repl(w io.Writer, r io.Reader, geocoder *geodecode.RGeocoder, k int) error {
+	fmt.Fprintf(w, "Type :help for help.\n> ")
+	var history []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		input := strings.TrimSpace(scanner.Text())
+		if recalled, ok, err := recall(input, history); err != nil {
+			fmt.Fprintln(w, err)
+			fmt.Fprint(w, "> ")
+			continue
+		} else if ok {
+			input = recalled
+			fmt.Fprintln(w, input)
+		}
+		if input != "" && !strings.HasPrefix(input, ":history") {
+			history = append(history, input)
+		}
+
+		switch cmd, arg, _ := strings.Cut(input, " "); cmd {
+		case "":
+		case ":q", ":quit", ":exit":
+			return nil
+		case ":help":
+			fmt.Fprintf(w, replHelp, k)
+		case ":history":
+			for i, h := range history {
+				fmt.Fprintf(w, "%4d  %s\n", i+1, h)
+			}
+		case ":k":
+			n, err := strconv.Atoi(strings.TrimSpace(arg))
+			if err != nil || n < 1 {
+				fmt.Fprintf(w, "k must be a positive number, got %q\n", arg)
+				break
+			}
+			k = n
+		default:
+			if strings.HasPrefix(cmd, ":") {
+				fmt.Fprintf(w, "unknown command %s, type :help for help\n", cmd)
+				break
+			}
+			if err := replLookup(w, geocoder, input, k); err != nil {
+				fmt.Fprintln(w, err)
+			}
+		}
+		fmt.Fprint(w, "> ")
+	}
+	fmt.Fprintln(w)
+	return scanner.Err()
+}
+
+// recall returns the input of history that input refers to with "!!" or
+// "!<n>", and whether it refers to one.
+func recall(input string, history []string) (string, bool, error) {
+	if !strings.HasPrefix(input, "!") {
+		return "", false, nil
+	}
+	if len(history) == 0 {
+		return "", false, errors.New("no history yet")
+	}
+	if input == "!!" {
+		return history[len(history)-1], true, nil
+	}
+	n, err := strconv.Atoi(input[1:])
+	if err != nil || n < 1 || n > len(history) {
+		return "", false, fmt.Errorf("no input %s in the history", input[1:])
+	}
+	return history[n-1], true, nil
+}
+
+// replLookup answers the coordinate input with its k nearest locations.
+func replLookup(w io.Writer, geocoder *geodecode.RGeocoder, input string, k int) error {
+	coord, err := parseCoordinate(strings.Join(strings.Fields(strings.ReplaceAll(input, ",", " ")), ","))
+	if err != nil {
+		return fmt.Errorf(`expected "lat,lon" or a command, got %q`, input)
+	}
+	results, err := geocoder.Nearest(coord, k)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(w, "not found")
+	}
+	for i, r := range results {
+		fmt.Fprintf(w, "%2d. %s\t%.1f %s\n", i+1, placeName(r.Location), r.Distance, r.Unit)
+	}
+	return nil
+}
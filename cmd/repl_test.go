@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestRepl(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	input := "52.5 13.4\n:k 2\n!1\n:k zero\n:history\n!7\nnorth\n91,0\n:quit\n48.9,2.3\n"
+	var out bytes.Buffer
+	if err := repl(&out, strings.NewReader(input), geocoder, 1); err != nil {
+		t.Fatalf("repl: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		">  1. Berlin, Berlin, DE\t2.3 km\n> ",
+		"> 52.5 13.4\n 1. Berlin, Berlin, DE\t2.3 km\n 2. Paris, Ile-de-France, FR\t",
+		"k must be a positive number",
+		"   1  52.5 13.4\n   2  :k 2\n   3  52.5 13.4\n   4  :k zero\n",
+		"no input 7 in the history",
+		`expected "lat,lon" or a command, got "north"`,
+		"invalid coordinate",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected the output to contain %q, got %q", want, got)
+		}
+	}
+	if strings.Contains(got, "Paris, Ile-de-France, FR\t5.8") {
+		t.Errorf("Expected no input to be answered after :quit, got %q", got)
+	}
+}
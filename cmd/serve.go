@@ -0,0 +1,304 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// shutdownTimeout is how long the serve command waits for requests in flight
+// when it is stopped, by default.
+const shutdownTimeout = 5 * time.Second
+
+// runServe implements the serve command: it answers reverse geocoding
+// requests over HTTP until interrupted or terminated, and then waits for the
+// requests in flight. SIGHUP reloads the dataset, and the API key file, in
+// place, without dropping requests.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	newGeocoder := geocoderFlags(fs)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	snakeCase := fs.Bool("snake-case", false, "name JSON fields in snake_case instead of camelCase")
+	maxBatch := fs.Int("max-batch", 10000, "most coordinates accepted per POST /reverse/batch request")
+	grpc := fs.Bool("grpc", false, "also serve the gRPC service of geodecodepb/geodecode.proto, over unencrypted HTTP/2")
+	rateLimit := fs.Float64("rate-limit", 0, "coordinates each client may resolve per second, on average (0 for no limit)")
+	rateBurst := fs.Int("rate-burst", 0, "coordinates each client may resolve at once beyond -rate-limit (default one second's worth)")
+	clientIPHeader := fs.String("client-ip-header", "", "header a trusted proxy puts the client address in, such as X-Forwarded-For, to rate limit by")
+	apiKeyList := fs.String("api-keys", "", "comma-separated name=key pairs of the API keys to require; prefer the config file or -api-key-file, as command lines are visible to other users")
+	apiKeyFile := fs.String("api-key-file", "", "file of API keys to require, one name and key per line, optionally followed by its rate and burst limits; reloaded when it changes")
+	corsOrigins := fs.String("cors-origins", "", "comma-separated origins of the web pages allowed to call the API from browsers, such as https://app.example.com, or * for any")
+	corsMethods := fs.String("cors-methods", "GET,POST", "comma-separated methods pages of -cors-origins may use")
+	debugAddr := fs.String("debug-addr", "", "private address to serve the profiles of /debug/pprof/ and the statistics of /debug/geodecode on, such as localhost:6060")
+	maxAge := fs.Duration("cache-max-age", time.Hour, "how long clients and caches may reuse results of GET /reverse before revalidating them with their ETag (0 to always revalidate)")
+	drain := fs.Duration("shutdown-timeout", shutdownTimeout, "how long to wait for requests in flight, and to end WebSocket streams, when stopped")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("geodecode serve [flags]")
+	}
+	if *maxBatch < 1 {
+		return badInputf("max-batch must be at least 1, got %d", *maxBatch)
+	}
+	if *rateLimit < 0 || *rateBurst < 0 {
+		return badInputf("rate-limit and rate-burst must not be negative")
+	}
+	if *maxAge < 0 {
+		return badInputf("cache-max-age must not be negative, got %s", *maxAge)
+	}
+	if *drain < 0 {
+		return badInputf("shutdown-timeout must not be negative, got %s", *drain)
+	}
+	cors, err := newCORSPolicy(*corsOrigins, *corsMethods)
+	if err != nil {
+		return withExitCode(exitBadInput, err)
+	}
+	keys, err := newAPIKeys(*apiKeyList, *apiKeyFile)
+	if err != nil {
+		return withExitCode(exitBadInput, err)
+	}
+	defer keys.watch(apiKeyPoll)()
+	var limiter *rateLimiter
+	if *rateLimit > 0 || keys != nil {
+		// Keys may have limits of their own.
+		limiter = newRateLimiter(*rateLimit, cmp.Or(*rateBurst, defaultBurst(*rateLimit)), *clientIPHeader)
+	}
+	collector := geodecode.NewCollector()
+	geocoder, err := newGeocoder(geodecode.WithCollector(collector))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+	streams := newStreamGroup()
+	health := newLoadState(geocoder)
+	// Results depend on these settings as well as on the dataset, so a
+	// restart with other settings changes their ETags.
+	var variant strings.Builder
+	for _, name := range []string{"max-distance", "units", "metric", "snake-case"} {
+		fmt.Fprintf(&variant, "%s=%s\n", name, fs.Lookup(name).Value)
+	}
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newHandler(geocoder, serveOptions{json: geodecode.JSONOptions{SnakeCase: *snakeCase}, maxBatch: *maxBatch, collector: collector, limiter: limiter, keys: keys, cors: cors, streams: streams, health: health, variant: variant.String(), maxAge: *maxAge}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	server.RegisterOnShutdown(streams.close)
+	if *grpc {
+		// gRPC clients connect with HTTP/2 without TLS ("h2c" with prior
+		// knowledge), on the same port as the HTTP API.
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+		server.Handler = withGRPC(server.Handler, grpcHandler(geocoder, keys, limiter))
+	}
+	errc := make(chan error, 2)
+	go func() { errc <- server.ListenAndServe() }()
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", *addr)
+	if *debugAddr != "" {
+		// Apart from the API, so profiles are not exposed with it.
+		debugServer := &http.Server{Addr: *debugAddr, Handler: newDebugHandler(geocoder), ReadHeaderTimeout: 10 * time.Second}
+		defer debugServer.Close()
+		go func() { errc <- debugServer.ListenAndServe() }()
+		fmt.Fprintf(os.Stderr, "Serving debug endpoints on %s\n", *debugAddr)
+	}
+
+	for ctx.Err() == nil {
+		select {
+		case err := <-errc:
+			return err
+		case <-hangup:
+			reloadServe(geocoder, keys, health)
+		case <-ctx.Done():
+		}
+	}
+	// Stop accepting connections and wait for the requests in flight, while
+	// the streams answer the messages they have read and close.
+	fmt.Fprintln(os.Stderr, "Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *drain)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	return streams.wait(shutdownCtx)
+}
+
+// reloadServe reloads the dataset of geocoder and keys for SIGHUP. Requests
+// are answered with the old ones until the new ones are ready, and failures
+// keep the old ones and are reported on standard error, and by /healthz
+// through health.
+func reloadServe(geocoder *geodecode.RGeocoder, keys *apiKeys, health *loadState) {
+	start := time.Now()
+	err := geocoder.Reload()
+	health.reloaded(err)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "geodecode: reloading the dataset failed, keeping the previous one: %v\n", err)
+	} else {
+		fmt.Fprintf(os.Stderr, "Reloaded the dataset in %s\n", time.Since(start).Round(time.Millisecond))
+	}
+	if keys != nil {
+		if err := keys.reload(); err != nil {
+			fmt.Fprintf(os.Stderr, "geodecode: reloading API keys failed, keeping the previous ones: %v\n", err)
+		}
+	}
+}
+
+// serveOptions configures the HTTP handler of the serve command.
+type serveOptions struct {
+	json      geodecode.JSONOptions // Encoding of results
+	maxBatch  int                   // Most coordinates accepted per batch request
+	collector *geodecode.Collector  // Metrics of the geocoder served at /metrics; may be nil
+	limiter   *rateLimiter          // Limits the coordinates resolved per client; nil for no limit
+	keys      *apiKeys              // API keys required to resolve coordinates; nil to require none
+	cors      *corsPolicy           // Origins allowed to call from browsers; nil for none
+	streams   *streamGroup          // Ends the WebSocket streams on shutdown; may be nil
+	health    *loadState            // Outcome of loading the dataset, for /healthz; nil to load it when the handler is made
+	variant   string                // Settings results depend on besides the dataset, for their ETags
+	maxAge    time.Duration         // How long caches may reuse results without revalidating them
+}
+
+// newHandler returns the HTTP handler of the serve command. GET /reverse
+// with the query parameters lat and lon (or lng, longitude) returns the
+// result for the coordinate as JSON encoded with opts.json, cacheable for
+// opts.maxAge and with an ETag of the dataset and the coordinate, POST
+// /reverse/batch the results for many coordinates (see handleBatch), GET
+// /stream resolves coordinates sent over a WebSocket (see handleStream), GET
+// /healthz reports whether the dataset is loaded, as recorded in opts.health,
+// GET /openapi.json describes the API, and GET /metrics serves the metrics of
+// the requests and of opts.collector for Prometheus. The routes resolving
+// coordinates require one of opts.keys, if any, and opts.limiter limits the
+// coordinates resolved by each client. Pages of the origins of opts.cors may
+// call every route from browsers. Errors are JSON objects with the key error.
+func newHandler(geocoder *geodecode.RGeocoder, opts serveOptions) http.Handler {
+	health := opts.health
+	if health == nil {
+		health = newLoadState(geocoder)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /reverse", opts.keys.require(opts.limiter.limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		latText := cmp.Or(query.Get("lat"), query.Get("latitude"))
+		lonText := cmp.Or(query.Get("lon"), query.Get("lng"), query.Get("longitude"))
+		if latText == "" || lonText == "" {
+			writeError(w, http.StatusBadRequest, errors.New("lat and lon are required"))
+			return
+		}
+		lat, latErr := strconv.ParseFloat(latText, 64)
+		lon, lonErr := strconv.ParseFloat(lonText, 64)
+		if latErr != nil || lonErr != nil {
+			writeError(w, http.StatusBadRequest, errors.New("lat and lon must be numbers"))
+			return
+		}
+		opts.keys.used(r, 1)
+		// Take the hash before resolving, so that the result of a dataset
+		// replaced meanwhile is not tagged as one of the new dataset.
+		hash := geocoder.DatasetHash()
+		results, err := geocoder.Resolve([2]float64{lat, lon})
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		body, err := opts.json.Result(results[0]).MarshalJSON()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeCacheable(w, r, resultETag(hash, opts.variant, lat, lon), cacheControl(opts.maxAge, opts.keys != nil), append(body, '\n'))
+	}))))
+	mux.Handle("POST /reverse/batch", opts.keys.require(opts.limiter.limit(handleBatch(geocoder, opts))))
+	mux.Handle("GET /stream", opts.keys.require(opts.limiter.limit(handleStream(geocoder, opts))))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		loadErr, reloadErr := health.state()
+		if loadErr != nil {
+			writeError(w, http.StatusServiceUnavailable, loadErr)
+			return
+		}
+		if reloadErr != nil {
+			// Still answering from the previous dataset.
+			fmt.Fprintf(w, "ok, serving the previous dataset as reloading failed: %v\n", errorMessage(reloadErr))
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("GET /openapi.json", handleOpenAPI)
+	metrics := newHTTPMetrics(opts.collector, opts.keys)
+	mux.Handle("GET /metrics", metrics)
+	return metrics.instrument(opts.cors.handle(mux))
+}
+
+// loadState is the outcome of the last attempt to load the dataset of the
+// serve command, which /healthz reports without loading it again: probes
+// must not retry a failing load every few seconds, which is left to SIGHUP.
+type loadState struct {
+	mu        sync.Mutex
+	loadErr   error // Why the dataset could not be loaded; queries fail while set
+	reloadErr error // Why the last reload failed, keeping the previous dataset
+}
+
+// newLoadState loads the dataset of geocoder, if it is not loaded yet, and
+// returns the outcome.
+func newLoadState(geocoder *geodecode.RGeocoder) *loadState {
+	return &loadState{loadErr: geocoder.Load()}
+}
+
+// reloaded records the outcome err of reloading the dataset. A failed reload
+// keeps the previous dataset, if there is one.
+func (s *loadState) reloaded(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case err == nil:
+		s.loadErr, s.reloadErr = nil, nil
+	case s.loadErr != nil:
+		s.loadErr = err
+	default:
+		s.reloadErr = err
+	}
+}
+
+// state returns why the dataset could not be loaded, and why the last reload
+// failed.
+func (s *loadState) state() (loadErr, reloadErr error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadErr, s.reloadErr
+}
+
+// errorStatus returns the HTTP status of a failed query: bad request for
+// invalid coordinates, and service unavailable if the dataset cannot be
+// loaded.
+func errorStatus(err error) int {
+	if errors.Is(err, geodecode.ErrInvalidCoordinate) {
+		return http.StatusBadRequest
+	}
+	return http.StatusServiceUnavailable
+}
+
+// writeError writes err as a JSON object with the key error and the status
+// code.
+func writeError(w http.ResponseWriter, code int, err error) {
+	body, _ := json.Marshal(struct {
+		Error string `json:"error"`
+	}{err.Error()}) // Cannot fail for a string
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	w.Write(append(body, '\n'))
+}
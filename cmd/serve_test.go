@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestHandler(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	handler := newHandler(geocoder, serveOptions{json: geodecode.JSONOptions{SnakeCase: true}, maxBatch: 10})
+
+	tests := []struct {
+		target string
+		status int
+		body   string
+	}{
+		{"/reverse?lat=48.9&lon=2.3", http.StatusOK, `"city":"Paris"`},
+		{"/reverse?latitude=52.5&lng=13.4", http.StatusOK, `"city":"Berlin"`},
+		{"/reverse?lat=91&lon=0", http.StatusBadRequest, `{"error":"geodecode: invalid coordinate`},
+		{"/reverse?lat=north&lon=0", http.StatusBadRequest, `{"error":"lat and lon must be numbers"}`},
+		{"/reverse?lat=52.5", http.StatusBadRequest, `{"error":"lat and lon are required"}`},
+		{"/healthz", http.StatusOK, "ok"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("GET %s: Expected %d with %q, got %d with %q", tt.target, tt.status, tt.body, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	path := t.TempDir() + "/cities.csv"
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	health := newLoadState(geocoder)
+	handler := newHandler(geocoder, serveOptions{maxBatch: 10, health: health})
+	healthz := func() (int, string) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	if code, _ := healthz(); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without a dataset, got %d", code)
+	}
+	// Probes report the failed load rather than loading again.
+	data, err := os.ReadFile(writeTestDataset(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := healthz(); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 until the dataset is reloaded, got %d", code)
+	}
+	health.reloaded(geocoder.Reload())
+	if code, body := healthz(); code != http.StatusOK || body != "ok\n" {
+		t.Errorf("Expected 200 ok after reloading, got %d %q", code, body)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	health.reloaded(geocoder.Reload())
+	if code, body := healthz(); code != http.StatusOK || !strings.Contains(body, "reloading failed") {
+		t.Errorf("Expected 200 with the failed reload, got %d %q", code, body)
+	}
+	health.reloaded(nil)
+	if code, body := healthz(); code != http.StatusOK || body != "ok\n" {
+		t.Errorf("Expected 200 ok after a successful reload, got %d %q", code, body)
+	}
+}
+
+func TestBatchHandler(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	handler := newHandler(geocoder, serveOptions{json: geodecode.JSONOptions{OmitEmpty: true, OmitDistance: true}, maxBatch: 3})
+	paris := `{"found":true,"location":{"lat":48.86,"lon":2.35,"city":"Paris","admin1":"Ile-de-France","cc":"FR","country":"France"}}`
+	berlin := `{"found":true,"location":{"lat":52.52,"lon":13.41,"city":"Berlin","admin1":"Berlin","cc":"DE","country":"Germany"}}`
+
+	tests := []struct {
+		contentType, body string
+		status            int
+		want              string
+	}{
+		{"application/json", `[[48.9, 2.3], {"lat": 91, "lon": 0}, {"latitude": 52.5, "lng": 13.4}]`, http.StatusOK,
+			"[\n" + paris + ",\n" + `{"error":"invalid coordinate: lat=91, lon=0"}` + ",\n" + berlin + "\n]\n"},
+		{"", `[]`, http.StatusOK, "[]\n"},
+		{"application/x-ndjson", "{\"lat\":52.5,\"lon\":13.4}\n\n[0,0]\n", http.StatusOK, berlin + "\n" + `{"found":false}` + "\n"},
+		{"text/csv; charset=utf-8", "id,lng,lat\n1,2.3,48.9\n", http.StatusOK, "[\n" + paris + "\n]\n"},
+		{"text/csv", "48.9,2.3\n52.5,13.4\n", http.StatusOK, "[\n" + paris + ",\n" + berlin + "\n]\n"},
+		{"application/json", `[[1,2],[3,4],[5,6],[7,8]]`, http.StatusRequestEntityTooLarge, "at most 3 coordinates"},
+		{"application/json", `{"lat":1,"lon":2}`, http.StatusBadRequest, "want a JSON array"},
+		{"application/json", `[[1,2],[3]]`, http.StatusBadRequest, "coordinate 1: want [lat, lon]"},
+		{"application/x-ndjson", "{\"lat\":1}\n", http.StatusBadRequest, "line 1: missing lat or lon"},
+		{"text/csv", "a,b\n1,2\n", http.StatusBadRequest, "columns lat and lon not found"},
+		{"application/xml", `<coords/>`, http.StatusUnsupportedMediaType, "unsupported content type"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/reverse/batch", strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status || tt.status == http.StatusOK && rec.Body.String() != tt.want || !strings.Contains(rec.Body.String(), tt.want) {
+			t.Errorf("POST %q as %q: Expected %d with %q, got %d with %q", tt.body, tt.contentType, tt.status, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestResolveBatch(t *testing.T) {
+	var queries int
+	hook := func([2]float64, geodecode.Location, error, time.Duration) { queries++ }
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithQueryHook(hook))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	coords := [][2]float64{{91, 0}, {48.9, 2.3}, {0, 181}}
+	items := resolveBatch(geocoder, coords, geodecode.JSONOptions{})
+	if queries != 1 {
+		t.Errorf("Expected only the valid coordinate to be queried, once, got %d queries", queries)
+	}
+	want := []string{`{"error":"invalid coordinate: lat=91, lon=0"}`, `"city":"Paris"`, `{"error":"invalid coordinate: lat=0, lon=181"}`}
+	for i, item := range items {
+		if !strings.Contains(string(item), want[i]) {
+			t.Errorf("Item %d: Expected %s, got %s", i, want[i], item)
+		}
+	}
+
+	// Coordinates that cannot be resolved report why.
+	broken, err := geodecode.New(geodecode.WithDataset(t.TempDir() + "/missing.csv"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	items = resolveBatch(broken, coords, geodecode.JSONOptions{})
+	if !strings.Contains(string(items[1]), `"error":"no data loaded`) || !strings.Contains(string(items[0]), "invalid coordinate") {
+		t.Errorf("Expected the load error and the invalid coordinate, got %s and %s", items[1], items[0])
+	}
+}
+
+func TestStreamHandler(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	server := httptest.NewServer(newHandler(geocoder, serveOptions{json: geodecode.JSONOptions{OmitEmpty: true, OmitDistance: true}, maxBatch: 10}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stream")
+	if err != nil {
+		t.Fatalf("GET /stream: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected GET /stream without an upgrade to return 400, got %d", resp.StatusCode)
+	}
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	// The example key and accept key of RFC 6455.
+	conn.Write([]byte("GET /stream HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"))
+	r := bufio.NewReader(conn)
+	resp, err = http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatalf("ReadResponse: %v", err)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); resp.StatusCode != http.StatusSwitchingProtocols || got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Expected 101 with the accept key, got %d with %q", resp.StatusCode, got)
+	}
+
+	// Send every message before reading the answers.
+	for _, msg := range []string{`{"id":1,"lat":48.9,"lon":2.3}`, `[91, 0]`, `"paris"`, `{"id":"b","latitude":52.5,"lng":13.4}`} {
+		conn.Write(clientFrame(true, wsText, []byte(msg)))
+	}
+	for _, want := range []string{
+		`{"id":1,"found":true,"location":{"lat":48.86,"lon":2.35,"city":"Paris","admin1":"Ile-de-France","cc":"FR","country":"France"}}`,
+		`{"error":"invalid coordinate: lat=91, lon=0"}`,
+		`{"error":"want [lat, lon] or an object with lat and lon"}`,
+		`{"id":"b","found":true,"location":{"lat":52.52,"lon":13.41,"city":"Berlin","admin1":"Berlin","cc":"DE","country":"Germany"}}`,
+	} {
+		op, payload, err := serverFrame(r)
+		if err != nil || op != wsText || string(payload) != want {
+			t.Errorf("Expected %s, got opcode %d with %s, %v", want, op, payload, err)
+		}
+	}
+
+	conn.Write(clientFrame(true, wsClose, []byte{0x03, 0xE8}))
+	op, payload, err := serverFrame(r)
+	if err != nil || op != wsClose || len(payload) < 2 || binary.BigEndian.Uint16(payload) != wsCloseNormal {
+		t.Errorf("Expected a close frame with code %d, got opcode %d with %q, %v", wsCloseNormal, op, payload, err)
+	}
+}
+
+func TestStreamShutdown(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	streams := newStreamGroup()
+	handler := newHandler(geocoder, serveOptions{maxBatch: 10, streams: streams})
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.RegisterOnShutdown(streams.close)
+	server.Start()
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("GET /stream HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"))
+	r := bufio.NewReader(conn)
+	if resp, err := http.ReadResponse(r, nil); err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected 101, got %v, %v", resp, err)
+	}
+	conn.Write(clientFrame(true, wsText, []byte(`[48.9, 2.3]`)))
+	if op, _, err := serverFrame(r); err != nil || op != wsText {
+		t.Fatalf("Expected an answer, got opcode %d, %v", op, err)
+	}
+
+	// Shutting down ends the stream, which Shutdown itself does not wait for.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Config.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	op, payload, err := serverFrame(r)
+	if err != nil || op != wsClose || len(payload) < 2 || binary.BigEndian.Uint16(payload) != wsCloseGoingAway {
+		t.Errorf("Expected a close frame with code %d, got opcode %d with %q, %v", wsCloseGoingAway, op, payload, err)
+	}
+	if err := streams.wait(ctx); err != nil {
+		t.Errorf("Expected the stream to end, got %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected new streams to be refused with 503 after shutdown, got %d", rec.Code)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	collector := geodecode.NewCollector()
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithCollector(collector))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	handler := newHandler(geocoder, serveOptions{maxBatch: 10, collector: collector})
+	for _, target := range []string{"/reverse?lat=48.9&lon=2.3", "/reverse?lat=52.5&lon=13.4", "/reverse?lat=91&lon=0", "/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`geodecode_queries_total{result="found"} 2` + "\n",
+		`geodecode_queries_total{result="invalid"} 1` + "\n",
+		"geodecode_dataset_locations 2\n",
+		"geodecode_http_requests_in_flight 1\n",
+		`geodecode_http_requests_total{route="/reverse",code="200"} 2` + "\n",
+		`geodecode_http_requests_total{route="/reverse",code="400"} 1` + "\n",
+		`geodecode_http_requests_total{route="unmatched",code="404"} 1` + "\n",
+		`geodecode_http_request_duration_seconds_count{route="/reverse"} 3` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in\n%s", want, body)
+		}
+	}
+}
+
+// streamHandshake opens a WebSocket connection to GET /stream of the server
+// at addr from a page of origin, and returns the status of the response.
+func streamHandshake(t *testing.T, addr, origin string) int {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	req := "GET /stream HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n"
+	if origin != "" {
+		req += "Origin: " + origin + "\r\n"
+	}
+	conn.Write([]byte(req + "\r\n"))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("ReadResponse: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestStreamOrigin(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	server := httptest.NewServer(newHandler(geocoder, serveOptions{maxBatch: 10}))
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+
+	for _, origin := range []string{"", "http://example.com", "https://example.com"} {
+		if status := streamHandshake(t, addr, origin); status != http.StatusSwitchingProtocols {
+			t.Errorf("Expected a stream from origin %q to be accepted, got %d", origin, status)
+		}
+	}
+	for _, origin := range []string{"https://evil.example", "null"} {
+		if status := streamHandshake(t, addr, origin); status != http.StatusForbidden {
+			t.Errorf("Expected a stream from origin %q to be refused with 403, got %d", origin, status)
+		}
+	}
+}
+
+func TestStreamCORSOrigin(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	cors, err := newCORSPolicy("https://app.example", "GET")
+	if err != nil {
+		t.Fatalf("newCORSPolicy: %v", err)
+	}
+	server := httptest.NewServer(newHandler(geocoder, serveOptions{maxBatch: 10, cors: cors}))
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+
+	tests := []struct {
+		origin string
+		status int
+	}{
+		{"https://app.example", http.StatusSwitchingProtocols},
+		{"http://example.com", http.StatusSwitchingProtocols},
+		{"", http.StatusSwitchingProtocols},
+		{"https://evil.example", http.StatusForbidden},
+		{"http://app.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if status := streamHandshake(t, addr, tt.origin); status != tt.status {
+			t.Errorf("Expected a stream from origin %q to get %d, got %d", tt.origin, tt.status, status)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// batchBytesPerCoordinate bounds the size of the body of a batch request
+// per coordinate accepted, so a body cannot be arbitrarily large.
+const batchBytesPerCoordinate = 512
+
+// errBatchTooLarge reports a batch request with more coordinates than
+// accepted.
+var errBatchTooLarge = errors.New("too many coordinates")
+
+// handleBatch returns the handler of POST /reverse/batch: the body holds
+// coordinates as a JSON array, JSON lines or CSV, selected by its content
+// type, and the response holds a result for each, in the same order. The
+// results are JSON encoded with opts, as a JSON array, or as JSON lines for
+// JSON lines requests. Coordinates out of range get an object with the key
+// error instead of a result. Results are resolved and written batchSize at
+// a time, so large responses start streaming right away.
+func handleBatch(geocoder *geodecode.RGeocoder, opts serveOptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mediaType := "application/json"
+		if ct := r.Header.Get("Content-Type"); ct != "" {
+			var err error
+			if mediaType, _, err = mime.ParseMediaType(ct); err != nil {
+				writeError(w, http.StatusUnsupportedMediaType, err)
+				return
+			}
+		}
+		lines := mediaType == "application/x-ndjson" || mediaType == "application/jsonl"
+		body := http.MaxBytesReader(w, r.Body, int64(opts.maxBatch)*batchBytesPerCoordinate)
+		coords, err := readBatch(body, mediaType, opts.maxBatch)
+		var maxBytes *http.MaxBytesError
+		switch {
+		case errors.Is(err, errors.ErrUnsupported):
+			writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content type %q, want application/json, application/x-ndjson or text/csv", mediaType))
+			return
+		case errors.Is(err, errBatchTooLarge) || errors.As(err, &maxBytes):
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("at most %d coordinates are accepted per request", opts.maxBatch))
+			return
+		case err != nil:
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := geocoder.Load(); err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		opts.limiter.charge(r, len(coords)-1)
+		opts.keys.used(r, len(coords))
+
+		if lines {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		rc := http.NewResponseController(w)
+		var buf []byte
+		for start := 0; start < len(coords); start += batchSize {
+			buf = buf[:0]
+			for i, item := range resolveBatch(geocoder, coords[start:min(start+batchSize, len(coords))], opts.json) {
+				switch {
+				case lines:
+				case start+i == 0:
+					buf = append(buf, "[\n"...)
+				default:
+					buf = append(buf, ",\n"...)
+				}
+				buf = append(buf, item...)
+				if lines {
+					buf = append(buf, '\n')
+				}
+			}
+			if _, err := w.Write(buf); err != nil {
+				return
+			}
+			rc.Flush()
+		}
+		switch {
+		case lines:
+		case len(coords) == 0:
+			io.WriteString(w, "[]\n")
+		default:
+			io.WriteString(w, "\n]\n")
+		}
+	}
+}
+
+// resolveBatch returns the JSON encoding of the result for each of coords
+// with opts, or of the error for coordinates that could not be resolved.
+// Invalid coordinates are set aside first, so the others are resolved in a
+// single call and each coordinate is queried at most once.
+func resolveBatch(geocoder *geodecode.RGeocoder, coords [][2]float64, opts geodecode.JSONOptions) [][]byte {
+	items := make([][]byte, len(coords))
+	valid := make([][2]float64, 0, len(coords))
+	for i, coord := range coords {
+		if err := geocoder.Validate(coord); err != nil {
+			items[i] = itemError(err)
+			continue
+		}
+		valid = append(valid, coord)
+	}
+	var results []geodecode.Result
+	var err error
+	if len(valid) > 0 {
+		results, err = geocoder.Resolve(valid...)
+	}
+	for i := range items {
+		switch {
+		case items[i] != nil:
+		case err != nil:
+			items[i] = itemError(err)
+		default:
+			items[i], _ = opts.Result(results[0]).MarshalJSON() // Cannot fail
+			results = results[1:]
+		}
+	}
+	return items
+}
+
+// itemError returns the JSON encoding of err in place of the result of a
+// coordinate of a batch.
+func itemError(err error) []byte {
+	item, _ := json.Marshal(struct {
+		Error string `json:"error"`
+	}{errorMessage(err)})
+	return item
+}
+
+// readBatch reads at most limit coordinates from r in the format of
+// mediaType: a JSON array, JSON lines or CSV. Coordinates in JSON are
+// [lat, lon] arrays or objects with the keys lat (or latitude) and lon (or
+// lng, longitude). CSV lines hold the latitude and the longitude, or else
+// the columns named lat and lon (or lng) in a header. It returns
+// errors.ErrUnsupported for other media types.
+func readBatch(r io.Reader, mediaType string, limit int) ([][2]float64, error) {
+	var coords [][2]float64
+	add := func(coord [2]float64) error {
+		if len(coords) == limit {
+			return errBatchTooLarge
+		}
+		coords = append(coords, coord)
+		return nil
+	}
+	switch mediaType {
+	case "application/json":
+		dec := json.NewDecoder(r)
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return nil, errors.New("want a JSON array of coordinates")
+		}
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return nil, err
+			}
+			coord, err := batchCoordinate(raw)
+			if err != nil {
+				return nil, fmt.Errorf("coordinate %d: %w", len(coords), err)
+			}
+			if err := add(coord); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return coords, nil
+
+	case "application/x-ndjson", "application/jsonl":
+		scanner := bufio.NewScanner(r)
+		for line := 1; scanner.Scan(); line++ {
+			text := bytes.TrimSpace(scanner.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+			coord, err := batchCoordinate(text)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := add(coord); err != nil {
+				return nil, err
+			}
+		}
+		return coords, scanner.Err()
+
+	case "text/csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		latIdx, lonIdx := 0, 1
+		for first := true; ; first = false {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return coords, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			coord, err := rowCoordinate(record, latIdx, lonIdx)
+			if err != nil && first {
+				latIdx, lonIdx = columnIndex(record, "lat"), max(columnIndex(record, "lon"), columnIndex(record, "lng"))
+				if latIdx < 0 || lonIdx < 0 {
+					return nil, fmt.Errorf("columns lat and lon not found in header %q", record)
+				}
+				continue
+			}
+			if err != nil {
+				line, _ := reader.FieldPos(0)
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := add(coord); err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		return nil, errors.ErrUnsupported
+	}
+}
+
+// batchCoordinate parses a coordinate of a JSON batch request: a [lat, lon]
+// array or an object with the keys of jsonCoordinate.
+func batchCoordinate(raw []byte) ([2]float64, error) {
+	if strings.HasPrefix(string(raw), "[") {
+		var pair []float64
+		if err := json.Unmarshal(raw, &pair); err != nil || len(pair) != 2 {
+			return [2]float64{}, errors.New("want [lat, lon]")
+		}
+		return [2]float64{pair[0], pair[1]}, nil
+	}
+	var c jsonCoordinate
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return [2]float64{}, err
+	}
+	coord, ok := c.coord()
+	if !ok {
+		return [2]float64{}, errors.New("missing lat or lon")
+	}
+	return coord, nil
+}
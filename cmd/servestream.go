@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// streamQueue is the number of messages of a stream read ahead of the one
+// being resolved. Clients sending faster than results are written are held
+// back by TCP once it is full.
+const streamQueue = 256
+
+// streamMessage is a coordinate received on a stream, with the id its
+// client gave it, or the error it could not be parsed with.
+type streamMessage struct {
+	id    json.RawMessage
+	coord [2]float64
+	err   error
+}
+
+// streamGroup tracks the open streams, so the server can end them when it
+// shuts down: net/http leaves the connections taken over by handlers alone.
+// A nil *streamGroup never ends them.
+type streamGroup struct {
+	mu      sync.Mutex
+	closed  bool
+	closing chan struct{} // Closed by close
+	wg      sync.WaitGroup
+}
+
+func newStreamGroup() *streamGroup {
+	return &streamGroup{closing: make(chan struct{})}
+}
+
+// add adds a stream to g, unless g is closed.
+func (g *streamGroup) add() bool {
+	if g == nil {
+		return true
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return false
+	}
+	g.wg.Add(1)
+	return true
+}
+
+// done removes an ended stream from g.
+func (g *streamGroup) done() {
+	if g != nil {
+		g.wg.Done()
+	}
+}
+
+// ending returns a channel that is closed once the streams are to end; nil,
+// which blocks forever, for a nil g.
+func (g *streamGroup) ending() <-chan struct{} {
+	if g == nil {
+		return nil
+	}
+	return g.closing
+}
+
+// close tells the streams to end: they stop reading messages, answer those
+// already read and close with the status going away. Streams cannot be
+// added anymore.
+func (g *streamGroup) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.closed {
+		g.closed = true
+		close(g.closing)
+	}
+}
+
+// wait waits until the streams of g have ended or ctx is done.
+func (g *streamGroup) wait(ctx context.Context) error {
+	ended := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(ended)
+	}()
+	select {
+	case <-ended:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handleStream returns the handler of GET /stream, a WebSocket endpoint:
+// each text message from the client holds a coordinate, as in a JSON batch
+// request, and is answered by a message with its result encoded with
+// opts.json. Objects may have the key id, which is copied to the answer so
+// clients can match them. Messages are answered in order, but clients need
+// not wait for an answer before sending more; the messages queued meanwhile
+// are resolved together. Messages that are not coordinates, or are out of
+// range, are answered with an object with the key error. Pages of the origins
+// of opts.cors may open streams like those of the server's own origin.
+// Streams end when opts.streams is closed.
+func handleStream(geocoder *geodecode.RGeocoder, opts serveOptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := geocoder.Load(); err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		if !opts.streams.add() {
+			writeError(w, http.StatusServiceUnavailable, errors.New("the server is shutting down"))
+			return
+		}
+		defer opts.streams.done()
+		conn, err := upgradeWebSocket(w, r, opts.cors.allows)
+		if err != nil {
+			return
+		}
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			select {
+			case <-opts.streams.ending():
+				// Interrupt the reading of the next message, or the wait
+				// for the rate limit.
+				cancel()
+				conn.conn.SetReadDeadline(time.Now())
+			case <-ctx.Done():
+			}
+		}()
+
+		queue := make(chan streamMessage, streamQueue)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			answerStream(conn, queue, geocoder, opts.json)
+		}()
+
+		client := opts.limiter.client(r)
+		var closeErr *wsCloseError
+		for {
+			msg, err := conn.readMessage()
+			if err == nil {
+				// Hold back clients over their rate limit rather than
+				// failing their messages.
+				err = opts.limiter.wait(ctx, client, 1)
+			}
+			if err != nil {
+				if errors.As(err, &closeErr) {
+					break
+				}
+				select {
+				case <-opts.streams.ending():
+					closeErr = &wsCloseError{wsCloseGoingAway, "server shutting down"}
+				default:
+					closeErr = &wsCloseError{wsCloseGoingAway, err.Error()}
+				}
+				break
+			}
+			opts.keys.used(r, 1)
+			queue <- parseStreamMessage(msg)
+		}
+		close(queue)
+		<-done
+		conn.close(closeErr.code, closeErr.msg)
+	}
+}
+
+// parseStreamMessage parses a message of a stream.
+func parseStreamMessage(msg []byte) streamMessage {
+	var m streamMessage
+	var withID struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if json.Unmarshal(msg, &withID) == nil {
+		m.id = withID.ID
+	}
+	var err error
+	if m.coord, err = batchCoordinate(msg); err != nil {
+		m.err = errors.New("want [lat, lon] or an object with lat and lon")
+	}
+	return m
+}
+
+// answerStream writes the answers to the messages of queue to conn until
+// queue is closed. Once a write fails, the rest of queue is discarded.
+func answerStream(conn *wsConn, queue <-chan streamMessage, geocoder *geodecode.RGeocoder, opts geodecode.JSONOptions) {
+	failed := false
+	var msgs []streamMessage
+	for m := range queue {
+		// Take the messages already queued too, to resolve them at once.
+		msgs = append(msgs[:0], m)
+	more:
+		for len(msgs) < batchSize {
+			select {
+			case m, ok := <-queue:
+				if !ok {
+					break more
+				}
+				msgs = append(msgs, m)
+			default:
+				break more
+			}
+		}
+		if failed {
+			continue
+		}
+
+		var coords [][2]float64
+		for _, m := range msgs {
+			if m.err == nil {
+				coords = append(coords, m.coord)
+			}
+		}
+		items := resolveBatch(geocoder, coords, opts)
+		answers := make([][]byte, len(msgs))
+		for i, m := range msgs {
+			var answer []byte
+			if m.err != nil {
+				answer, _ = json.Marshal(struct {
+					Error string `json:"error"`
+				}{m.err.Error()})
+			} else {
+				answer, items = items[0], items[1:]
+			}
+			if len(m.id) > 0 {
+				// Put the id first: answers are JSON objects.
+				answer = append(append(append([]byte(`{"id":`), m.id...), ','), answer[1:]...)
+			}
+			answers[i] = answer
+		}
+		if err := conn.writeText(answers...); err != nil {
+			// Stop the reading of the stream too.
+			conn.conn.Close()
+			failed = true
+		}
+	}
+}
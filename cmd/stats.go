@@ -0,0 +1,136 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// datasetReport is what the stats command reports about a dataset.
+type datasetReport struct {
+	Source       string                    `json:"source"`
+	Location     string                    `json:"location"`
+	Records      int                       `json:"records"`
+	Hash         string                    `json:"sha256"`
+	LoadDuration time.Duration             `json:"loadDurationNs"`
+	Bounds       geodecode.Bounds          `json:"bounds"`
+	TreeDepth    int                       `json:"treeDepth"`
+	Memory       geodecode.Footprint       `json:"memoryBytes"`
+	Countries    map[string]int            `json:"countries"`
+	Admin1       map[string]map[string]int `json:"admin1,omitempty"`
+}
+
+// runStats implements the stats command: it loads the dataset selected by
+// the flags and prints its size, coverage, load time and memory estimate,
+// to check a custom gazetteer before deploying it.
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	newGeocoder := geocoderFlags(fs)
+	format := fs.String("format", "text", "output format: text or json")
+	admin1 := fs.Bool("admin1", false, "also count the records of each admin1 region")
+	top := fs.Int("top", 20, "number of countries to list in text output, 0 for all")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageError("geodecode stats [flags]")
+	}
+	if *format != "text" && *format != "json" {
+		return badInputf("unknown output format %q", *format)
+	}
+	geocoder, err := newGeocoder()
+	if err != nil {
+		return err
+	}
+
+	report := newDatasetReport(geocoder, *admin1)
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return report.write(os.Stdout, *top)
+}
+
+// newDatasetReport describes the dataset of geocoder, with the records of
+// each admin1 region if admin1 is set.
+func newDatasetReport(geocoder *geodecode.RGeocoder, admin1 bool) datasetReport {
+	info, stats := geocoder.DatasetInfo(), geocoder.Stats()
+	report := datasetReport{
+		Source:       info.Source,
+		Location:     info.Location,
+		Records:      info.Records,
+		Hash:         info.Hash,
+		LoadDuration: info.LoadDuration,
+		Bounds:       stats.Bounds,
+		TreeDepth:    stats.TreeDepth,
+		Memory:       geocoder.MemoryFootprint(),
+		Countries:    stats.Countries,
+	}
+	if admin1 {
+		report.Admin1 = stats.Admin1
+	}
+	return report
+}
+
+// write writes r as text to w, listing the top countries by records, or
+// all of them if top is 0.
+func (r datasetReport) write(w io.Writer, top int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	b := r.Bounds
+	fmt.Fprintf(tw, "Dataset:\t%s %s\n", r.Source, r.Location)
+	fmt.Fprintf(tw, "Records:\t%d in %d countries\n", r.Records, len(r.Countries))
+	fmt.Fprintf(tw, "SHA-256:\t%s\n", r.Hash)
+	fmt.Fprintf(tw, "Load time:\t%s\n", r.LoadDuration.Round(time.Millisecond))
+	fmt.Fprintf(tw, "Bounds:\tlat %.4f to %.4f, lon %.4f to %.4f\n", b.MinLat, b.MaxLat, b.MinLon, b.MaxLon)
+	fmt.Fprintf(tw, "Tree depth:\t%d\n", r.TreeDepth)
+	m := r.Memory
+	fmt.Fprintf(tw, "Memory:\t%s (locations %s, strings %s, index %s, names %s)\n",
+		formatBytes(m.Total), formatBytes(m.Locations), formatBytes(m.Strings), formatBytes(m.Tree), formatBytes(m.Names))
+
+	ccs := byCount(r.Countries)
+	if top > 0 && len(ccs) > top {
+		fmt.Fprintf(tw, "\nTop %d countries:\n", top)
+		ccs = ccs[:top]
+	} else {
+		fmt.Fprintln(tw, "\nCountries:")
+	}
+	for _, cc := range ccs {
+		fmt.Fprintf(tw, "  %s\t%d\n", cc, r.Countries[cc])
+		for _, name := range byCount(r.Admin1[cc]) {
+			fmt.Fprintf(tw, "    %s\t%d\n", cmp.Or(name, "(none)"), r.Admin1[cc][name])
+		}
+	}
+	return tw.Flush()
+}
+
+// byCount returns the keys of counts, largest count first and then in
+// alphabetical order.
+func byCount(counts map[string]int) []string {
+	return slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+}
+
+// formatBytes formats n bytes in binary units.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestStats(t *testing.T) {
+	path := writeTestDataset(t)
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	report := newDatasetReport(geocoder, true)
+	if report.Records != 2 || report.Countries["DE"] != 1 || report.Admin1["FR"]["Ile-de-France"] != 1 {
+		t.Errorf("Expected 2 records in DE and FR, got %+v", report)
+	}
+	if report.Memory.Total <= 0 {
+		t.Errorf("Expected a memory estimate, got %d", report.Memory.Total)
+	}
+
+	var out bytes.Buffer
+	if err := report.write(&out, 1); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, want := range []string{
+		"Dataset:     file " + path + "\n",
+		"Records:     2 in 2 countries\n",
+		"Bounds:      lat 48.8600 to 52.5200, lon 2.3500 to 13.4100\n",
+		"\nTop 1 countries:\n  DE        1\n    Berlin  1\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in %q", want, out.String())
+		}
+	}
+
+	if got := formatBytes(3 << 20); got != "3.0 MiB" {
+		t.Errorf("Expected 3.0 MiB, got %s", got)
+	}
+	for _, args := range [][]string{{"-format", "xml"}, {"extra"}} {
+		if err := runStats(args); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"cmp"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// gpxFile holds the points of a GPX file: those of its tracks and routes.
+// Elements are matched regardless of the GPX version's namespace.
+type gpxFile struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+// gpxPoint is a track or route point of a GPX file.
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Time string  `xml:"time"` // RFC 3339, if recorded
+}
+
+// trackPoint is a point of a track with its parsed time, which is zero if
+// it was not recorded.
+type trackPoint struct {
+	coord [2]float64
+	time  time.Time
+}
+
+// visit is a run of consecutive track points resolved to the same place.
+type visit struct {
+	from, to trackPoint // First and last point of the run
+	points   int
+	result   geodecode.Result // Result of the first point
+}
+
+// runTrack implements the track command: it resolves the points of a GPX
+// track and prints the places the track passes through.
+func runTrack(args []string) error {
+	fs := flag.NewFlagSet("track", flag.ContinueOnError)
+	newGeocoder := geocoderFlags(fs)
+	outputOpts := outputFlags(fs, append([]string{"text"}, outputFormats...))
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	opts, err := outputOpts()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("geodecode track [flags] <route.gpx | ->")
+	}
+	if opts.k > 1 {
+		return errors.New("track lists the nearest place of each point only, -k is not supported")
+	}
+
+	in := io.Reader(os.Stdin)
+	if fs.Arg(0) != "-" {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+	points, err := readGPX(in)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(0), err)
+	}
+	geocoder, err := newGeocoder(geodecode.WithAdaptiveParallelism())
+	if err != nil {
+		return err
+	}
+	return track(os.Stdout, points, geocoder, opts)
+}
+
+// readGPX returns the points of the tracks and then the routes of the GPX
+// file read from r.
+func readGPX(r io.Reader) ([]trackPoint, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	var points []trackPoint
+	add := func(p gpxPoint) error {
+		tp := trackPoint{coord: [2]float64{p.Lat, p.Lon}}
+		if p.Time != "" {
+			t, err := time.Parse(time.RFC3339, p.Time)
+			if err != nil {
+				return fmt.Errorf("point %d: invalid time %q", len(points)+1, p.Time)
+			}
+			tp.time = t
+		}
+		points = append(points, tp)
+		return nil
+	}
+	for _, trk := range file.Tracks {
+		for _, seg := range trk.Segments {
+			for _, p := range seg.Points {
+				if err := add(p); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, rte := range file.Routes {
+		for _, p := range rte.Points {
+			if err := add(p); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(points) == 0 {
+		return nil, errors.New("no track or route points")
+	}
+	return points, nil
+}
+
+// visits collapses the points of a track, resolved to results, into the
+// places visited in order, merging consecutive points of the same place.
+// Points without a location in range are skipped.
+func visits(points []trackPoint, results []geodecode.Result) []visit {
+	var vs []visit
+	for i, res := range results {
+		if !res.Found {
+			continue
+		}
+		if n := len(vs); n > 0 && placeName(vs[n-1].result.Location) == placeName(res.Location) {
+			vs[n-1].to = points[i]
+			vs[n-1].points++
+			continue
+		}
+		vs = append(vs, visit{from: points[i], to: points[i], points: 1, result: res})
+	}
+	return vs
+}
+
+// track resolves points with geocoder and writes the places visited to w as
+// selected by opts: one line per place with the times of its first and last
+// point for the format "text", or else with a resultWriter, with the
+// columns from, to and points in place of an input record.
+func track(w io.Writer, points []trackPoint, geocoder *geodecode.RGeocoder, opts outputOptions) error {
+	coords := make([][2]float64, len(points))
+	for i, p := range points {
+		coords[i] = p.coord
+	}
+	results, err := geocoder.Resolve(coords...)
+	if err != nil {
+		return err
+	}
+	vs := visits(points, results)
+
+	if opts.format != "text" || opts.tmpl != nil {
+		rw, err := newResultWriter(w, opts, []string{"from", "to", "points"}, 0)
+		if err != nil {
+			return err
+		}
+		for _, v := range vs {
+			record := []string{formatTime(v.from.time), formatTime(v.to.time), strconv.Itoa(v.points)}
+			if err := rw.write(v.from.coord, record, v.result); err != nil {
+				return err
+			}
+		}
+		return rw.close()
+	}
+	for _, v := range vs {
+		from, to := cmp.Or(formatTime(v.from.time), "-"), cmp.Or(formatTime(v.to.time), "-")
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", from, to, placeName(v.result.Location)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatTime formats t in RFC 3339, or returns "" if t is zero.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
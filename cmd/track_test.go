@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+const testGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk><name>Tour</name><trkseg>
+    <trkpt lat="52.50" lon="13.40"><time>2024-05-01T08:00:00Z</time></trkpt>
+    <trkpt lat="52.51" lon="13.41"><time>2024-05-01T08:10:00Z</time></trkpt>
+    <trkpt lat="0" lon="0"><time>2024-05-01T08:20:00Z</time></trkpt>
+    <trkpt lat="52.52" lon="13.42"><time>2024-05-01T08:30:00Z</time></trkpt>
+  </trkseg><trkseg>
+    <trkpt lat="48.9" lon="2.3"><time>2024-05-02T09:00:00Z</time></trkpt>
+  </trkseg></trk>
+  <rte><rtept lat="52.5" lon="13.4"/></rte>
+</gpx>`
+
+func TestTrack(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	points, err := readGPX(strings.NewReader(testGPX))
+	if err != nil {
+		t.Fatalf("readGPX: %v", err)
+	}
+	if len(points) != 6 {
+		t.Fatalf("Expected 6 points, got %d", len(points))
+	}
+
+	var out bytes.Buffer
+	if err := track(&out, points, geocoder, outputOptions{format: "text"}); err != nil {
+		t.Fatalf("track: %v", err)
+	}
+	want := "2024-05-01T08:00:00Z\t2024-05-01T08:30:00Z\tBerlin, Berlin, DE\n" +
+		"2024-05-02T09:00:00Z\t2024-05-02T09:00:00Z\tParis, Ile-de-France, FR\n" +
+		"-\t-\tBerlin, Berlin, DE\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	if err := track(&out, points[:2], geocoder, outputOptions{format: "csv", fields: []string{"city"}}); err != nil {
+		t.Fatalf("track: %v", err)
+	}
+	want = "from,to,points,city\n2024-05-01T08:00:00Z,2024-05-01T08:10:00Z,2,Berlin\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+
+	for _, input := range []string{"", "<gpx></gpx>", `<gpx><trk><trkseg><trkpt lat="1" lon="2"><time>noon</time></trkpt></trkseg></trk></gpx>`} {
+		if _, err := readGPX(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// geoNamesBaseURL is the default location of the GeoNames dumps.
+const geoNamesBaseURL = "https://download.geonames.org/export/dump/"
+
+// geoNamesDatasets lists the dumps accepted by the update command.
+var geoNamesDatasets = []string{"cities500", "cities1000", "cities5000", "cities15000", "allCountries"}
+
+// runUpdate implements the update command: it downloads a GeoNames dump,
+// converts it to the package's CSV format and writes it to the data directory.
+func runUpdate(args []string) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	dataset := fs.String("dataset", "cities1000", "GeoNames dump to download: "+strings.Join(geoNamesDatasets, ", "))
+	dir := fs.String("dir", defaultDataDir(), "directory the converted dataset is written to")
+	baseURL := fs.String("base-url", geoNamesBaseURL, "base URL of the GeoNames dumps")
+	embedded := fs.String("regenerate-embedded", "", "also write the dataset in binary format to this embedded file (e.g. rg_cities1000.bin.gz, for maintainers)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if !slices.Contains(geoNamesDatasets, *dataset) {
+		return fmt.Errorf("unknown dataset %q, want one of %s", *dataset, strings.Join(geoNamesDatasets, ", "))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "geodecode-update-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	base := strings.TrimSuffix(*baseURL, "/") + "/"
+	fmt.Printf("Downloading %s from %s...\n", *dataset, base)
+	zipPath := filepath.Join(tmpDir, *dataset+".zip")
+	if err := download(base+*dataset+".zip", zipPath); err != nil {
+		return err
+	}
+	admin1Path := filepath.Join(tmpDir, "admin1CodesASCII.txt")
+	if err := download(base+"admin1CodesASCII.txt", admin1Path); err != nil {
+		return err
+	}
+	admin2Path := filepath.Join(tmpDir, "admin2Codes.txt")
+	if err := download(base+"admin2Codes.txt", admin2Path); err != nil {
+		return err
+	}
+
+	locations, err := convertGeoNames(zipPath, *dataset+".txt", admin1Path, admin2Path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+	outPath := filepath.Join(*dir, "rg_"+*dataset+".csv.gz")
+	if err := writeDataset(outPath, locations); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d locations to %s\n", len(locations), outPath)
+
+	if *embedded != "" {
+		if err := writeEmbedded(*embedded, locations); err != nil {
+			return err
+		}
+		fmt.Printf("Regenerated embedded dataset %s\n", *embedded)
+	}
+	return nil
+}
+
+// defaultDataDir returns the directory datasets are stored in by default.
+func defaultDataDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "geodecode-data"
+	}
+	return filepath.Join(dir, "geodecode")
+}
+
+// download stores the resource at url in the file at path.
+func download(url, path string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: unexpected status %s", url, resp.Status)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, resp.Body)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("downloading %s: %w", url, err)
+	}
+	if resp.ContentLength >= 0 && n != resp.ContentLength {
+		f.Close()
+		return fmt.Errorf("downloading %s: got %d of %d bytes", url, n, resp.ContentLength)
+	}
+	return f.Close()
+}
+
+// convertGeoNames reads the GeoNames dump named member from the zip archive at
+// zipPath and resolves its administrative codes to names, matching the layout
+// of the embedded dataset.
+func convertGeoNames(zipPath, member, admin1Path, admin2Path string) ([]geodecode.Location, error) {
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", zipPath, err)
+	}
+	defer archive.Close()
+
+	dump, err := archive.Open(member)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s in archive: %w", member, err)
+	}
+	defer dump.Close()
+
+	admin1, err := os.Open(admin1Path)
+	if err != nil {
+		return nil, err
+	}
+	defer admin1.Close()
+	admin2, err := os.Open(admin2Path)
+	if err != nil {
+		return nil, err
+	}
+	defer admin2.Close()
+
+	locations, err := geodecode.ReadLocations(dump,
+		geodecode.WithFormat(geodecode.FormatGeoNames),
+		geodecode.WithAdmin1Names(admin1),
+		geodecode.WithAdmin2Names(admin2),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The package format stores the admin2 name rather than its code.
+	for i := range locations {
+		locations[i].Admin2 = locations[i].Admin2Name
+		locations[i].Admin2Name = ""
+	}
+	return locations, nil
+}
+
+// writeEmbedded writes locations in the format of the embedded dataset, the
+// binary format compressed with gzip, to path.
+func writeEmbedded(path string, locations []geodecode.Location) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	gz, err := gzip.NewWriterLevel(f, gzip.BestCompression)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := geodecode.WriteBinary(gz, locations); err != nil {
+		f.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeDataset writes locations as a gzip-compressed CSV file to path.
+func writeDataset(path string, locations []geodecode.Location) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	gz, err := gzip.NewWriterLevel(f, gzip.BestCompression)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := geodecode.WriteCSV(gz, locations); err != nil {
+		f.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"archive/zip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// newGeoNamesServer serves the test fixtures in the layout of the GeoNames
+// dump directory.
+func newGeoNamesServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+
+	zf, err := os.Create(filepath.Join(dir, "cities1000.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(zf)
+	w, err := zw.Create("cities1000.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump, err := os.ReadFile(filepath.Join("..", "testdata", "cities.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(dump)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zf.Close()
+
+	for _, name := range []string{"admin1CodesASCII.txt", "admin2Codes.txt"} {
+		data, err := os.ReadFile(filepath.Join("..", "testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunUpdate(t *testing.T) {
+	server := newGeoNamesServer(t)
+	dataDir := t.TempDir()
+	embedded := filepath.Join(t.TempDir(), "rg_cities1000.bin.gz")
+
+	err := runUpdate([]string{"-base-url", server.URL, "-dir", dataDir, "-regenerate-embedded", embedded})
+	if err != nil {
+		t.Fatalf("runUpdate: %v", err)
+	}
+
+	for path, format := range map[string]geodecode.Format{
+		filepath.Join(dataDir, "rg_cities1000.csv.gz"): geodecode.FormatCSV,
+		embedded: geodecode.FormatBinary,
+	} {
+		geocoder, err := geodecode.New(geodecode.WithDataset(path, geodecode.WithFormat(format)))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		got := geocoder.Query([2]float64{48.1, 11.6})
+		if len(got) != 1 || got[0].City != "Munich" || got[0].Admin1 != "Bavaria" || got[0].Admin2 != "Upper Bavaria" {
+			t.Errorf("Expected Munich, Bavaria, Upper Bavaria from %s, got %+v", path, got)
+		}
+	}
+}
+
+func TestRunUpdateErrors(t *testing.T) {
+	server := newGeoNamesServer(t)
+	if err := runUpdate([]string{"-dataset", "cities42"}); err == nil {
+		t.Errorf("Expected an error for an unknown dataset")
+	}
+	if err := runUpdate([]string{"-base-url", server.URL, "-dataset", "cities15000", "-dir", t.TempDir()}); err == nil {
+		t.Errorf("Expected an error for a dataset missing on the server")
+	}
+}
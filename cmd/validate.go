@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// errInvalidDataset is returned by the validate command when the dataset has
+// problems, so the process exits with a non-zero status.
+var errInvalidDataset = errors.New("dataset is invalid")
+
+// runValidate implements the validate command: it checks a CSV dataset and
+// prints the problems found.
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	delimiter := fs.String("delimiter", ",", "CSV field delimiter")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("geodecode validate [flags] <file>")
+	}
+	if utf8.RuneCountInString(*delimiter) != 1 {
+		return fmt.Errorf("delimiter must be a single character, got %q", *delimiter)
+	}
+	delim, _ := utf8.DecodeRuneInString(*delimiter)
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := geodecode.ValidateDataset(file, geodecode.WithDelimiter(delim))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printReport(report)
+	}
+	if !report.OK() {
+		return errInvalidDataset
+	}
+	return nil
+}
+
+// printReport writes a human-readable summary of report to stdout.
+func printReport(report geodecode.Report) {
+	for _, col := range report.MissingColumns {
+		fmt.Printf("missing required column %q\n", col)
+	}
+	for _, issue := range report.Issues {
+		fmt.Println(issue)
+	}
+	fmt.Printf("%d rows, %d valid, %d issues\n", report.Rows, report.ValidRows, len(report.Issues))
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunValidate(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.csv")
+	if err := os.WriteFile(good, []byte("lat;lon;city;admin1;admin2;cc\n52.52;13.41;Berlin;Berlin;;DE\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runValidate([]string{"-delimiter", ";", good}); err != nil {
+		t.Errorf("Expected valid dataset, got %v", err)
+	}
+
+	if err := runValidate([]string{filepath.Join("..", "testdata", "cities.csv")}); !errors.Is(err, errInvalidDataset) {
+		t.Errorf("Expected errInvalidDataset for the malformed fixture, got %v", err)
+	}
+	if err := runValidate(nil); err == nil {
+		t.Errorf("Expected a usage error without a file")
+	}
+}
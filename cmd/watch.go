@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// watchOptions selects how the watch command reads and writes records.
+type watchOptions struct {
+	output   outputOptions
+	latField string    // Key of the latitude, with dots separating nested keys
+	lonField string    // Key of the longitude, likewise
+	errors   *errorLog // Receives the lines that cannot be resolved
+}
+
+// runWatch implements the watch command: it follows a growing JSON lines
+// file, like tail -f, and writes each record appended to it with the
+// nearest location added, until interrupted.
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	newGeocoder := geocoderFlags(fs)
+	outputOpts := outputFlags(fs, []string{"jsonl"})
+	path := fs.String("follow", "", "JSON lines file to follow")
+	latField := fs.String("lat-field", "lat", "key of the latitude, such as position.lat for nested objects")
+	lonField := fs.String("lon-field", "lon", "key of the longitude")
+	fromStart := fs.Bool("from-start", false, "resolve the records already in the file before following it")
+	poll := fs.Duration("poll", 250*time.Millisecond, "how often to check the file for new records")
+	errorsPath := fs.String("errors", "-", "file to write the lines that cannot be resolved to as JSON lines, - for standard error")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *path == "" || fs.NArg() != 0 {
+		return usageError("geodecode watch -follow <file.jsonl> [flags]")
+	}
+	if *latField == "" || *lonField == "" {
+		return badInputf("-lat-field and -lon-field must not be empty")
+	}
+	if *poll <= 0 {
+		return badInputf("-poll must be positive, got %s", *poll)
+	}
+	output, err := outputOpts()
+	if err != nil {
+		return err
+	}
+	opts := watchOptions{output: output, latField: *latField, lonField: *lonField, errors: &errorLog{w: os.Stderr}}
+	if *errorsPath != "-" {
+		f, err := os.Create(*errorsPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		opts.errors.w = f
+	}
+	geocoder, err := newGeocoder()
+	if err != nil {
+		return err
+	}
+	t, err := openTail(*path, *fromStart, *poll)
+	if err != nil {
+		return err
+	}
+	defer t.close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return watch(ctx, os.Stdout, t, geocoder, opts)
+}
+
+// watch writes the records read from t to w with the fields of
+// opts.output of their nearest locations added, as lookupStream does for
+// JSON objects, until ctx is done. Records are read only as fast as they
+// are written, so a slow reader of w holds back the reading of t rather
+// than records piling up in memory. Lines that cannot be resolved are
+// written to opts.errors and skipped, so one malformed event does not stop
+// the stream.
+func watch(ctx context.Context, w io.Writer, t *tailFile, geocoder *geodecode.RGeocoder, opts watchOptions) error {
+	out := bufio.NewWriter(w)
+	var tw *resultWriter
+	if opts.output.tmpl != nil {
+		tw, _ = newResultWriter(out, opts.output, nil, 0) // Cannot fail with a template
+	}
+	for {
+		text, err := t.next(ctx, out.Flush)
+		if errors.Is(err, context.Canceled) {
+			return out.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		text = bytes.TrimSpace(text)
+		if len(text) == 0 {
+			continue
+		}
+		if err := watchRecord(out, text, geocoder, opts, tw); err != nil {
+			if err := opts.errors.write(rowFailure{line: t.line, record: []string{string(text)}, err: err}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchRecord writes the JSON object text to w once for each of its
+// nearest locations, with their fields added, or the output of tw for them
+// if tw is not nil.
+func watchRecord(w *bufio.Writer, text []byte, geocoder *geodecode.RGeocoder, opts watchOptions, tw *resultWriter) error {
+	if text[0] != '{' || !json.Valid(text) {
+		return errors.New("not a JSON object")
+	}
+	lat, err := jsonField(text, opts.latField)
+	if err != nil {
+		return err
+	}
+	lon, err := jsonField(text, opts.lonField)
+	if err != nil {
+		return err
+	}
+	coord, err := parseLatLon(lat, lon)
+	if err != nil {
+		return err
+	}
+	results, err := resolveK(geocoder, coord, opts.output.k)
+	if err != nil {
+		return err
+	}
+	more := len(bytes.TrimSpace(text[1:len(text)-1])) > 0
+	for _, res := range results {
+		if tw != nil {
+			if err := tw.write(coord, nil, res); err != nil {
+				return err
+			}
+			continue
+		}
+		w.Write(appendFieldsJSON(text[:len(text)-1:len(text)-1], more, opts.output.fields, res))
+		if _, err := w.WriteString("}\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonField returns the value of the key path of the JSON object obj as
+// text, following dots into nested objects. The value must be a number or
+// a string.
+func jsonField(obj []byte, path string) (string, error) {
+	value := json.RawMessage(obj)
+	for _, key := range strings.Split(path, ".") {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return "", fmt.Errorf("%s: not an object", path)
+		}
+		var ok bool
+		if value, ok = fields[key]; !ok {
+			return "", fmt.Errorf("missing %s", path)
+		}
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(value, &n); err != nil {
+		return "", fmt.Errorf("%s: want a number, got %s", path, value)
+	}
+	return n.String(), nil
+}
+
+// tailFile reads the lines appended to a file as it grows. If the file is
+// truncated, it is read again from the start, and if it is replaced, as by
+// log rotation, the new file is followed from its start.
+type tailFile struct {
+	path    string
+	f       *os.File
+	info    os.FileInfo // Of f, to tell when path names another file
+	r       *bufio.Reader
+	offset  int64  // Bytes of f read so far
+	partial []byte // Start of a line whose end is not written yet
+	line    int    // Number of the line returned last, counted from where reading started
+	poll    time.Duration
+}
+
+// openTail opens the file path for following, from its start if fromStart
+// is set and else from its current end, and checks it for new lines every
+// poll.
+func openTail(path string, fromStart bool, poll time.Duration) (*tailFile, error) {
+	t := &tailFile{path: path, poll: poll}
+	if err := t.open(); err != nil {
+		return nil, err
+	}
+	if !fromStart {
+		offset, err := t.f.Seek(0, io.SeekEnd)
+		if err != nil {
+			t.close()
+			return nil, err
+		}
+		t.offset = offset
+	}
+	return t, nil
+}
+
+// open opens t.path to be read from its start.
+func (t *tailFile) open() error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	t.close()
+	t.f, t.info, t.offset, t.partial, t.line = f, info, 0, nil, 0
+	t.r = bufio.NewReader(f)
+	return nil
+}
+
+// next returns the next complete line, without its newline. Whenever it has
+// to wait for the file to grow, it calls idle first, and it returns
+// ctx.Err() once ctx is done.
+func (t *tailFile) next(ctx context.Context, idle func() error) ([]byte, error) {
+	for {
+		text, err := t.r.ReadBytes('\n')
+		t.offset += int64(len(text))
+		if err == nil {
+			t.line++
+			if len(t.partial) > 0 {
+				text = append(t.partial, text...)
+				t.partial = nil
+			}
+			return text[:len(text)-1], nil
+		}
+		if err != io.EOF {
+			return nil, err
+		}
+		t.partial = append(t.partial, text...)
+
+		if err := idle(); err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(t.poll):
+		}
+		if err := t.check(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// check starts reading t again from the start if its file was truncated or
+// replaced since it was last read to the end.
+func (t *tailFile) check() error {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return nil // Being rotated; keep the old file until the new one appears
+	}
+	if !os.SameFile(info, t.info) {
+		return t.open()
+	}
+	if info.Size() < t.offset {
+		if _, err := t.f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		t.r.Reset(t.f)
+		t.offset, t.partial, t.line = 0, nil, 0
+	}
+	return nil
+}
+
+// close closes the file t is reading.
+func (t *tailFile) close() {
+	if t.f != nil {
+		t.f.Close()
+	}
+}
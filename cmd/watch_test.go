@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatch(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(writeTestDataset(t)), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "events.log")
+	if err := os.WriteFile(path, []byte(`{"id":0,"pos":{"lat":1,"lon":1}}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tail, err := openTail(path, false, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("openTail: %v", err)
+	}
+	defer tail.close()
+
+	var out, errs syncBuffer
+	opts := watchOptions{
+		output:   outputOptions{fields: []string{"city"}, k: 1},
+		latField: "pos.lat",
+		lonField: "pos.lon",
+		errors:   &errorLog{w: &errs},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watch(ctx, &out, tail, geocoder, opts) }()
+
+	appendFile := func(data string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor := func(b *syncBuffer, want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for b.String() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected %q, got %q", want, b.String())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	appendFile(`{"id":1,"pos":{"lat":52.5,"lon":"13.4"}}` + "\n" + `{"id":2,"pos":`)
+	want := `{"id":1,"pos":{"lat":52.5,"lon":"13.4"},"found":true,"city":"Berlin"}` + "\n"
+	waitFor(&out, want)
+	appendFile(`{"lat":48.9,"lon":2.3}}` + "\n\nnot json\n" + `{"pos":{"lat":91,"lon":0}}` + "\n")
+	want += `{"id":2,"pos":{"lat":48.9,"lon":2.3},"found":true,"city":"Paris"}` + "\n"
+	waitFor(&out, want)
+	waitFor(&errs, `{"line":4,"record":["not json"],"error":"not a JSON object"}`+"\n"+
+		`{"line":5,"record":["{\"pos\":{\"lat\":91,\"lon\":0}}"],"error":"invalid coordinate: coordinate 0: lat=91, lon=0"}`+"\n")
+
+	// Rotation: the file is replaced by a new one, which is read from its start.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"pos":{"lat":48.9,"lon":2.3}}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want += `{"pos":{"lat":48.9,"lon":2.3},"found":true,"city":"Paris"}` + "\n"
+	waitFor(&out, want)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected no error after cancelling, got %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "\n") {
+		t.Errorf("Expected complete lines, got %q", out.String())
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// WebSocket opcodes, from RFC 6455.
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// WebSocket close codes, from RFC 6455.
+const (
+	wsCloseNormal      = 1000
+	wsCloseGoingAway   = 1001
+	wsCloseProtocol    = 1002
+	wsCloseUnsupported = 1003
+	wsCloseInvalidData = 1007
+	wsCloseTooBig      = 1009
+)
+
+// wsMaxMessage is the size of the largest message accepted from clients.
+const wsMaxMessage = 64 << 10
+
+// wsGUID is appended to the key of the client to compute the accept key of
+// the handshake.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsCloseError is the reason a WebSocket connection is closed: the close
+// code sent to the peer and a message.
+type wsCloseError struct {
+	code int
+	msg  string
+}
+
+func (e *wsCloseError) Error() string {
+	return fmt.Sprintf("websocket closed with code %d: %s", e.code, e.msg)
+}
+
+// wsConn is the server side of a WebSocket connection. Messages are read by
+// one goroutine; writes are safe for concurrent use.
+type wsConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	mu   sync.Mutex // Serializes writes
+}
+
+// upgradeWebSocket completes the opening handshake of a WebSocket request
+// and takes over its connection. If the request is not a valid WebSocket
+// request, it writes an error response and returns an error. Browsers let
+// pages of any site open WebSocket connections, with the cookies and other
+// credentials of the server, so requests from pages of other origins are
+// refused with status 403 (see sameOrigin), unless allowOrigin reports true
+// for their origin.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request, allowOrigin func(origin string) bool) (*wsConn, error) {
+	var err error
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.ProtoMajor != 1:
+		err = errors.New("WebSocket connections need HTTP/1.1")
+	case !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket"):
+		err = errors.New("want a WebSocket upgrade request")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		writeError(w, http.StatusUpgradeRequired, errors.New("unsupported WebSocket version, want 13"))
+		return nil, errors.New("unsupported WebSocket version")
+	default:
+		if b, decErr := base64.StdEncoding.DecodeString(key); decErr != nil || len(b) != 16 {
+			err = errors.New("invalid Sec-WebSocket-Key")
+		}
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil, err
+	}
+	if origin := r.Header.Get("Origin"); !sameOrigin(origin, r.Host) && !allowOrigin(origin) {
+		err := fmt.Errorf("WebSocket connections from origin %s are not allowed", origin)
+		writeError(w, http.StatusForbidden, err)
+		return nil, err
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, err
+	}
+	// Clear the deadlines of the server, which apply to requests.
+	conn.SetDeadline(time.Time{})
+	sum := sha1.Sum([]byte(key + wsGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, r: rw.Reader}, nil
+}
+
+// sameOrigin tells whether the Origin header origin of a request to host
+// names a page of host itself. Requests without an Origin header, which
+// browsers always send with WebSocket requests, are not from pages and pass.
+func sameOrigin(origin, host string) bool {
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && strings.EqualFold(u.Host, host)
+}
+
+// headerHasToken tells whether the comma-separated header key of h holds
+// token, ignoring case.
+func headerHasToken(h http.Header, key, token string) bool {
+	for _, v := range h.Values(key) {
+		for t := range strings.SplitSeq(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readMessage returns the next text message. It answers pings, and returns
+// a *wsCloseError when the peer closes the connection or breaks the
+// protocol; the connection must then be closed with that code.
+func (c *wsConn) readMessage() ([]byte, error) {
+	var msg []byte
+	started := false
+	for {
+		fin, op, payload, err := readFrame(c.r, wsMaxMessage-len(msg))
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			code := wsCloseNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			return nil, &wsCloseError{code, "closed by the client"}
+		case wsText, wsBinary:
+			if started {
+				return nil, &wsCloseError{wsCloseProtocol, "new message before the end of the previous one"}
+			}
+			if op == wsBinary {
+				return nil, &wsCloseError{wsCloseUnsupported, "binary messages are not supported"}
+			}
+			started = true
+		case wsContinuation:
+			if !started {
+				return nil, &wsCloseError{wsCloseProtocol, "continuation without a message"}
+			}
+		default:
+			return nil, &wsCloseError{wsCloseProtocol, fmt.Sprintf("unknown opcode %d", op)}
+		}
+		msg = append(msg, payload...)
+		if fin {
+			if !utf8.Valid(msg) {
+				return nil, &wsCloseError{wsCloseInvalidData, "text message is not valid UTF-8"}
+			}
+			return msg, nil
+		}
+	}
+}
+
+// readFrame reads a frame from a client, which must be masked, with a
+// payload of at most limit bytes for data frames, and unmasks its payload.
+func readFrame(r io.Reader, limit int) (fin bool, op byte, payload []byte, err error) {
+	var head [14]byte
+	if _, err := io.ReadFull(r, head[:2]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, op = head[0]&0x80 != 0, head[0]&0x0F
+	if head[0]&0x70 != 0 {
+		return false, 0, nil, &wsCloseError{wsCloseProtocol, "reserved bits set"}
+	}
+	if head[1]&0x80 == 0 {
+		return false, 0, nil, &wsCloseError{wsCloseProtocol, "frames from clients must be masked"}
+	}
+	size := uint64(head[1] & 0x7F)
+	switch size {
+	case 126:
+		if _, err := io.ReadFull(r, head[2:4]); err != nil {
+			return false, 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(head[2:4]))
+	case 127:
+		if _, err := io.ReadFull(r, head[2:10]); err != nil {
+			return false, 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(head[2:10])
+	}
+	if op >= wsClose && (!fin || size > 125) {
+		return false, 0, nil, &wsCloseError{wsCloseProtocol, "invalid control frame"}
+	}
+	if op < wsClose && size > uint64(limit) {
+		return false, 0, nil, &wsCloseError{wsCloseTooBig, fmt.Sprintf("messages are limited to %d bytes", wsMaxMessage)}
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// appendFrame appends an unmasked frame with the opcode op and the payload,
+// as servers send them.
+func appendFrame(buf []byte, op byte, payload []byte) []byte {
+	buf = append(buf, 0x80|op)
+	switch n := len(payload); {
+	case n <= 125:
+		buf = append(buf, byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, 126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, 127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	return append(buf, payload...)
+}
+
+// writeFrame writes a frame with the opcode op and the payload.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.conn.Write(appendFrame(nil, op, payload))
+	return err
+}
+
+// writeText writes the text messages msgs at once.
+func (c *wsConn) writeText(msgs ...[]byte) error {
+	var buf []byte
+	for _, msg := range msgs {
+		buf = appendFrame(buf, wsText, msg)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+// close sends a close frame with the code and reason and closes the
+// connection.
+func (c *wsConn) close(code int, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	c.writeFrame(wsClose, append(payload, reason...))
+	return c.conn.Close()
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// clientFrame returns a frame as clients send them, masked.
+func clientFrame(fin bool, op byte, payload []byte) []byte {
+	b := op
+	if fin {
+		b |= 0x80
+	}
+	buf := []byte{b}
+	switch n := len(payload); {
+	case n <= 125:
+		buf = append(buf, 0x80|byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, 0x80|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, 0x80|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	mask := [4]byte{0x12, 0x34, 0x56, 0x78}
+	buf = append(buf, mask[:]...)
+	for i, c := range payload {
+		buf = append(buf, c^mask[i%4])
+	}
+	return buf
+}
+
+// serverFrame reads an unmasked frame, as servers send them.
+func serverFrame(r io.Reader) (op byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, nil, err
+	}
+	size := int(head[1] & 0x7F)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		size = int(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		size = int(binary.BigEndian.Uint64(ext[:]))
+	}
+	payload = make([]byte, size)
+	_, err = io.ReadFull(r, payload)
+	return head[0] & 0x0F, payload, err
+}
+
+// recordConn is a connection recording what is written to it.
+type recordConn struct {
+	net.Conn
+	out bytes.Buffer
+}
+
+func (c *recordConn) Write(b []byte) (int, error) { return c.out.Write(b) }
+
+func TestReadMessage(t *testing.T) {
+	var in []byte
+	in = append(in, clientFrame(false, wsText, []byte("[48.9,"))...)
+	in = append(in, clientFrame(true, wsPing, []byte("hi"))...)
+	in = append(in, clientFrame(true, wsContinuation, []byte(" 2.3]"))...)
+	in = append(in, clientFrame(true, wsText, bytes.Repeat([]byte(" "), 300))...)
+	in = append(in, clientFrame(true, wsClose, []byte{0x03, 0xE8})...)
+	conn := &recordConn{}
+	c := &wsConn{conn: conn, r: bufio.NewReader(bytes.NewReader(in))}
+
+	msg, err := c.readMessage()
+	if err != nil || string(msg) != "[48.9, 2.3]" {
+		t.Errorf("Expected the fragmented message, got %q, %v", msg, err)
+	}
+	if op, payload, _ := serverFrame(&conn.out); op != wsPong || string(payload) != "hi" {
+		t.Errorf("Expected a pong with hi, got opcode %d with %q", op, payload)
+	}
+	if msg, err := c.readMessage(); err != nil || len(msg) != 300 {
+		t.Errorf("Expected a message of 300 bytes, got %d bytes, %v", len(msg), err)
+	}
+	var closeErr *wsCloseError
+	if _, err := c.readMessage(); !errors.As(err, &closeErr) || closeErr.code != wsCloseNormal {
+		t.Errorf("Expected a close with code %d, got %v", wsCloseNormal, err)
+	}
+}
+
+func TestReadMessageErrors(t *testing.T) {
+	unmasked := clientFrame(true, wsText, []byte("{}"))
+	unmasked[1] &^= 0x80
+
+	tests := []struct {
+		name string
+		in   []byte
+		code int
+	}{
+		{"unmasked", unmasked, wsCloseProtocol},
+		{"binary", clientFrame(true, wsBinary, []byte{1}), wsCloseUnsupported},
+		{"too big", clientFrame(true, wsText, make([]byte, wsMaxMessage+1)), wsCloseTooBig},
+		{"too big in fragments", append(clientFrame(false, wsText, make([]byte, wsMaxMessage)), clientFrame(true, wsContinuation, []byte{' '})...), wsCloseTooBig},
+		{"invalid UTF-8", clientFrame(true, wsText, []byte{0xFF}), wsCloseInvalidData},
+		{"continuation first", clientFrame(true, wsContinuation, []byte("{}")), wsCloseProtocol},
+		{"fragmented ping", clientFrame(false, wsPing, nil), wsCloseProtocol},
+	}
+	for _, tt := range tests {
+		c := &wsConn{conn: &recordConn{}, r: bufio.NewReader(bytes.NewReader(tt.in))}
+		var closeErr *wsCloseError
+		if _, err := c.readMessage(); !errors.As(err, &closeErr) || closeErr.code != tt.code {
+			t.Errorf("%s: Expected a close with code %d, got %v", tt.name, tt.code, err)
+		}
+	}
+}
+
+func TestAppendFrame(t *testing.T) {
+	for _, size := range []int{0, 125, 126, 0xFFFF, 0x10000} {
+		payload := []byte(strings.Repeat("x", size))
+		op, got, err := serverFrame(bytes.NewReader(appendFrame(nil, wsText, payload)))
+		if err != nil || op != wsText || !bytes.Equal(got, payload) {
+			t.Errorf("size %d: Expected the payload back, got opcode %d with %d bytes, %v", size, op, len(got), err)
+		}
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		origin, host string
+		want         bool
+	}{
+		{"", "example.com", true},
+		{"https://example.com", "example.com", true},
+		{"http://Example.com:8080", "example.com:8080", true},
+		{"https://example.com", "example.com:8080", false},
+		{"https://evil.example", "example.com", false},
+		{"null", "example.com", false},
+		{"file://example.com", "example.com", false},
+	}
+	for _, tt := range tests {
+		if got := sameOrigin(tt.origin, tt.host); got != tt.want {
+			t.Errorf("Expected sameOrigin(%q, %q) to be %t, got %t", tt.origin, tt.host, tt.want, got)
+		}
+	}
+}
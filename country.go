@@ -0,0 +1,80 @@
+package geodecode
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/biter777/countries"
+)
+
+//go:generate go run ./cmd/countrynames-gen -out zz_country_names.go
+
+// CountryInfo holds details about a location's country, filled in when the
+// geocoder is created with WithCountryInfo.
+type CountryInfo struct {
+	ISO3        string // ISO 3166-1 alpha-3 code (e.g., DEU).
+	Continent   string // Continent or region (e.g., Europe, North America).
+	Currency    string // ISO 4217 currency code (e.g., EUR).
+	CallingCode string // International calling code (e.g., +49).
+}
+
+// countriesByAlpha2 maps ISO 3166-1 alpha-2 codes to countries.
+var countriesByAlpha2 = sync.OnceValue(func() map[string]countries.CountryCode {
+	all := countries.All()
+	codes := make(map[string]countries.CountryCode, len(all))
+	for _, country := range all {
+		codes[country.Alpha2()] = country
+	}
+	return codes
+})
+
+// countryByCode returns the country with ISO code cc. Upper-case alpha-2
+// codes, as stored in datasets, are looked up in a map; anything else goes
+// through countries.ByName, which is much slower and allocates.
+func countryByCode(cc string) countries.CountryCode {
+	if country, ok := countriesByAlpha2()[cc]; ok {
+		return country
+	}
+	return countries.ByName(cc)
+}
+
+// countryName returns the English name of the country with ISO code cc, or
+// "" if the code is unknown.
+func countryName(cc string) string {
+	country := countryByCode(cc)
+	if !country.IsValid() {
+		return ""
+	}
+	return country.String() // Info would also list every subdivision, which is slow
+}
+
+// countryInfo returns the details of the country with ISO code cc. All fields
+// are empty if the code is unknown.
+func countryInfo(cc string) CountryInfo {
+	country := countryByCode(cc)
+	if !country.IsValid() {
+		return CountryInfo{}
+	}
+	ci := CountryInfo{
+		ISO3:      country.Alpha3(),
+		Continent: country.Region().String(),
+		Currency:  country.Currency().Alpha(),
+	}
+	if codes := country.CallCodes(); len(codes) > 0 {
+		ci.CallingCode = codes[0].String()
+	}
+	return ci
+}
+
+// localizedCountryNames returns the table of country names for locale, which
+// is matched case-insensitively and falls back to the base language (e.g.
+// "fr-CA" uses "fr"). It reports false if there is no table for locale.
+func localizedCountryNames(locale string) (map[string]string, bool) {
+	key := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if names, ok := countryNames[key]; ok {
+		return names, true
+	}
+	base, _, _ := strings.Cut(key, "-")
+	names, ok := countryNames[base]
+	return names, ok
+}
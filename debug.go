@@ -0,0 +1,65 @@
+package geodecode
+
+import "log/slog"
+
+// QueryTrace describes the work performed by the KD-Tree or grid search for a
+// single query made through QueryDebug.
+type QueryTrace struct {
+	NodesVisited int     // Number of tree nodes, or grid cells, entered during the search.
+	Candidates   int     // Number of points that became the running best match.
+	Distance     float64 // Squared distance as used by the index: degrees for MetricEuclidean and MetricEquirectangular, chord length on the unit sphere for MetricHaversine.
+	DistanceKM   float64 // Great-circle distance to the match in kilometers.
+}
+
+// QueryDebug finds the nearest location to coord like Query, and additionally
+// reports the internals of the search. It is intended for diagnosing
+// unexpected matches and is slower than Query.
+// It returns an empty Location and a zero QueryTrace if the coordinate is
+// invalid or no data is loaded, and an empty Location with the trace of the
+// search if the match is farther away than the distance set with
+// WithMaxDistance.
+//
+// coord: [lat, lng]
+func (rg *RGeocoder) QueryDebug(coord [2]float64) (Location, QueryTrace) {
+	ds := rg.current()
+
+	var trace QueryTrace
+	coord, ok := rg.normalize(coord)
+	if ok {
+		ds = rg.loadRegions(ds, coord, 1)
+	}
+	if !ok || ds.locations.len() == 0 {
+		return Location{}, trace
+	}
+
+	sp := ds.spatial()
+	if sp == nil {
+		// Only one location was loaded, so no index was built.
+		loc := rg.result(ds, ds.locations.at(0))
+		q, p := indexPoint(ds.metric, coord[0], coord[1]), indexPoint(ds.metric, loc.Lat, loc.Lon)
+		trace.NodesVisited = 1
+		trace.Candidates = 1
+		trace.Distance = indexDist(indexWeights(ds.metric, coord[0]), q, p)
+		trace.DistanceKM = haversineKM(coord[0], coord[1], loc.Lat, loc.Lon)
+		loc, _ = rg.withinRange(coord, loc)
+		return loc, trace
+	}
+
+	best, distSq := sp.nearest(coord, &trace)
+	trace.Distance = distSq
+	if best < 0 || best >= ds.locations.len() {
+		if rg.verbose {
+			rg.log(slog.LevelWarn, "geodecode: no nearest point found", "lat", coord[0], "lon", coord[1])
+		}
+		return Location{}, QueryTrace{}
+	}
+
+	loc := rg.result(ds, ds.locations.at(best))
+	trace.DistanceKM = haversineKM(coord[0], coord[1], loc.Lat, loc.Lon)
+	if rg.verbose {
+		rg.log(slog.LevelDebug, "geodecode: debug query", "lat", coord[0], "lon", coord[1],
+			"nodes_visited", trace.NodesVisited, "candidates", trace.Candidates, "city", loc.City, "distance_km", trace.DistanceKM)
+	}
+	loc, _ = rg.withinRange(coord, loc)
+	return loc, trace
+}
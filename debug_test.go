@@ -0,0 +1,36 @@
+package geodecode_test
+
+import (
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestQueryDebug(t *testing.T) {
+	geocoder := geodecode.GetRGeocoder(false)
+	coords := [][2]float64{
+		{52.5200, 13.4050},   // Berlin
+		{-33.8688, 151.2093}, // Sydney
+		{0.0, 0.0},           // Gulf of Guinea
+	}
+	for _, coord := range coords {
+		want := geocoder.Query(coord)[0]
+		got, trace := geocoder.QueryDebug(coord)
+		if got != want {
+			t.Errorf("QueryDebug(%v) = %+v, Query returned %+v", coord, got, want)
+		}
+		if trace.NodesVisited == 0 || trace.Candidates == 0 {
+			t.Errorf("QueryDebug(%v): expected search statistics, got %+v", coord, trace)
+		}
+		if trace.Candidates > trace.NodesVisited {
+			t.Errorf("QueryDebug(%v): more candidates than visited nodes: %+v", coord, trace)
+		}
+		if trace.DistanceKM <= 0 || trace.DistanceKM > 1000 {
+			t.Errorf("QueryDebug(%v): implausible distance %.3f km", coord, trace.DistanceKM)
+		}
+	}
+
+	if _, trace := geocoder.QueryDebug([2]float64{999, 999}); trace != (geodecode.QueryTrace{}) {
+		t.Errorf("Expected zero trace for invalid coordinate, got %+v", trace)
+	}
+}
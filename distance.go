@@ -0,0 +1,188 @@
+package geodecode
+
+import (
+	"fmt"
+	"math"
+)
+
+// earthRadiusKM is the mean radius of the Earth in kilometers.
+const earthRadiusKM = 6371.0088
+
+// haversineKM returns the great-circle distance in kilometers between two
+// points given in decimal degrees.
+func haversineKM(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dPhi := (lat2 - lat1) * math.Pi / 180
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusKM * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// validCoordinate reports whether lat and lon are within the valid WGS84 range.
+func validCoordinate(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// Unit is a unit of distance, selected with WithUnits.
+type Unit int
+
+const (
+	Kilometers    Unit = iota // Kilometers, the default.
+	Miles                     // International miles of 1609.344 m.
+	NauticalMiles             // Nautical miles of 1852 m.
+)
+
+// String returns the symbol of u: "km", "mi" or "nmi".
+func (u Unit) String() string {
+	switch u {
+	case Kilometers:
+		return "km"
+	case Miles:
+		return "mi"
+	case NauticalMiles:
+		return "nmi"
+	default:
+		return fmt.Sprintf("Unit(%d)", int(u))
+	}
+}
+
+// meters returns the length of one u in meters, the unit distances are
+// compared in.
+func (u Unit) meters() float64 {
+	switch u {
+	case Miles:
+		return 1609.344
+	case NauticalMiles:
+		return 1852
+	default:
+		return 1000
+	}
+}
+
+// fromKM converts km kilometers into u.
+func (u Unit) fromKM(km float64) float64 {
+	return km * 1000 / u.meters()
+}
+
+// CoordinatePolicy selects how queries treat coordinates outside the valid
+// WGS84 range. NaN and infinite values are always rejected.
+type CoordinatePolicy int
+
+const (
+	// RejectInvalid rejects invalid coordinates. It is the default.
+	RejectInvalid CoordinatePolicy = iota
+	// ClampInvalid moves latitudes and longitudes outside the valid range to
+	// the nearest bound, so a longitude of 190.5 becomes 180.
+	ClampInvalid
+	// WrapLongitude wraps longitudes into [-180, 180], so a longitude of
+	// 190.5 becomes -169.5. Latitudes outside the valid range are rejected.
+	WrapLongitude
+)
+
+// normalize applies p to the coordinate lat, lon. It reports false if the
+// coordinate is invalid and p cannot fix it.
+func (p CoordinatePolicy) normalize(lat, lon float64) (float64, float64, bool) {
+	if math.IsNaN(lat) || math.IsNaN(lon) || math.IsInf(lat, 0) || math.IsInf(lon, 0) {
+		return lat, lon, false
+	}
+	switch p {
+	case ClampInvalid:
+		lat = math.Max(-90, math.Min(90, lat))
+		lon = math.Max(-180, math.Min(180, lon))
+	case WrapLongitude:
+		if lon < -180 || lon > 180 {
+			lon = math.Mod(lon+180, 360)
+			if lon < 0 {
+				lon += 360
+			}
+			lon -= 180
+		}
+	}
+	return lat, lon, validCoordinate(lat, lon)
+}
+
+// Metric selects how the distance between a query and a location is measured
+// when searching for the nearest location.
+type Metric int
+
+const (
+	// MetricEuclidean compares coordinates as points on a flat grid of
+	// degrees. It is fast and the default, but distorts distances away from
+	// the equator and does not wrap around the antimeridian.
+	MetricEuclidean Metric = iota
+	// MetricHaversine compares great-circle distances, so results are
+	// correct near the poles and across the antimeridian.
+	MetricHaversine
+	// MetricEquirectangular compares coordinates on a grid of degrees like
+	// MetricEuclidean, but scales differences in longitude by the cosine of
+	// the query's latitude, so that matches away from the equator are no
+	// longer skewed east and west. It costs little more than
+	// MetricEuclidean and, like it, does not wrap around the antimeridian.
+	MetricEquirectangular
+)
+
+// String returns the name of m.
+func (m Metric) String() string {
+	switch m {
+	case MetricEuclidean:
+		return "euclidean"
+	case MetricHaversine:
+		return "haversine"
+	case MetricEquirectangular:
+		return "equirectangular"
+	default:
+		return fmt.Sprintf("Metric(%d)", int(m))
+	}
+}
+
+// dims returns the number of dimensions of the points the spatial indexes
+// compare under m.
+func (m Metric) dims() int {
+	if m == MetricHaversine {
+		return 3
+	}
+	return 2
+}
+
+// indexPoint returns the coordinates the spatial indexes compare for lat and
+// lon under metric: the degrees themselves for MetricEuclidean, and the point
+// on the unit sphere for MetricHaversine. The straight-line (chord) distance
+// between points on the unit sphere grows monotonically with their
+// great-circle distance, so the nearest point by one is the nearest by the
+// other.
+func indexPoint(metric Metric, lat, lon float64) [3]float64 {
+	if metric != MetricHaversine {
+		return [3]float64{lat, lon, 0}
+	}
+	phi := lat * math.Pi / 180
+	lambda := lon * math.Pi / 180
+	return [3]float64{math.Cos(phi) * math.Cos(lambda), math.Cos(phi) * math.Sin(lambda), math.Sin(phi)}
+}
+
+// indexWeights returns the factors by which the squared differences along
+// each dimension of the points returned by indexPoint are multiplied to
+// measure distances from a query at lat under metric: the squared cosine of
+// lat for longitudes under MetricEquirectangular, and 1 otherwise.
+func indexWeights(metric Metric, lat float64) [3]float64 {
+	if metric != MetricEquirectangular {
+		return [3]float64{1, 1, 1}
+	}
+	c := math.Cos(lat * math.Pi / 180)
+	return [3]float64{1, c * c, 1}
+}
+
+// indexDist returns the squared distance between the points q and p, as
+// returned by indexPoint, weighted by w as returned by indexWeights.
+func indexDist(w, q, p [3]float64) float64 {
+	dx, dy, dz := q[0]-p[0], q[1]-p[1], q[2]-p[2]
+	return w[0]*dx*dx + w[1]*dy*dy + w[2]*dz*dz
+}
+
+// chordToKM converts a squared chord distance on the unit sphere to a
+// great-circle distance in kilometers.
+func chordToKM(chordSq float64) float64 {
+	return 2 * earthRadiusKM * math.Asin(math.Min(1, math.Sqrt(chordSq)/2))
+}
@@ -0,0 +1,18 @@
+//go:build !geodecode_cities500 && !geodecode_cities5000 && !geodecode_cities15000 && !geodecode_noembed && !geodecode_generated
+
+package geodecode
+
+import _ "embed"
+
+// embeddedData holds the default dataset, covering places with a population
+// of at least 1000, in the binary format compressed with gzip (see
+// embeddedBinary). Build with one of the tags geodecode_cities500,
+// geodecode_cities5000 or geodecode_cities15000 to embed a different
+// dataset. It is generated from rg_cities1000.csv.gz.
+//
+//go:generate go run -tags geodecode_noembed ./cmd/geodecode-gen -in rg_cities1000.csv.gz -binary -out rg_cities1000.bin.gz
+//go:embed rg_cities1000.bin.gz
+var embeddedData []byte
+
+// embeddedDataset names the dataset compiled into the package.
+const embeddedDataset = "cities1000"
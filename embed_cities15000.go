@@ -0,0 +1,18 @@
+//go:build geodecode_cities15000 && !geodecode_noembed && !geodecode_generated
+
+package geodecode
+
+import _ "embed"
+
+// embeddedData holds the GeoNames cities15000 dataset, covering places with a
+// population of at least 15000, in the binary format compressed with gzip (see
+// embeddedBinary). It is selected with the geodecode_cities15000 build tag.
+// rg_cities15000.bin.gz is not checked in: generate it with go generate -tags
+// geodecode_cities15000, or the build fails for want of it.
+//
+//go:generate go run ./cmd update -dataset cities15000 -regenerate-embedded rg_cities15000.bin.gz
+//go:embed rg_cities15000.bin.gz
+var embeddedData []byte
+
+// embeddedDataset names the dataset compiled into the package.
+const embeddedDataset = "cities15000"
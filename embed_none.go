@@ -0,0 +1,11 @@
+//go:build geodecode_noembed && !geodecode_generated
+
+package geodecode
+
+// embeddedData is empty when the package is built with the
+// geodecode_noembed tag. Data must then be supplied with WithDataset,
+// LoadFromFile or LoadFromReader.
+var embeddedData []byte
+
+// embeddedDataset is empty because no dataset is compiled into the package.
+const embeddedDataset = ""
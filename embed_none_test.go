@@ -0,0 +1,27 @@
+//go:build geodecode_noembed && !geodecode_generated
+
+package geodecode_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestNoEmbed(t *testing.T) {
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if results := geocoder.Query([2]float64{52.52, 13.405}); len(results) != 0 {
+		t.Errorf("Expected no results without embedded data, got %+v", results)
+	}
+
+	if err := geocoder.LoadFromFile(filepath.Join("testdata", "cities.csv")); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if results := geocoder.Query([2]float64{52.52, 13.405}); len(results) != 1 || results[0].City != "Berlin" {
+		t.Errorf("Expected Berlin after explicit load, got %+v", results)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !geodecode_generated
+
+package geodecode
+
+//go:generate go run ./cmd/geodecode-gen -in rg_cities1000.csv.gz -out zz_generated_dataset.go
+
+// generatedLocations holds the dataset compiled into the package as Go code
+// by cmd/geodecode-gen. It is only populated when the package is built with
+// the geodecode_generated tag.
+var generatedLocations []Location
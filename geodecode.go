@@ -5,317 +5,430 @@
 package geodecode
 
 import (
-	"bytes"
-	_ "embed"
-	"encoding/csv"
-	"io"
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"math"
-	"os"
-	"path/filepath"
-	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/biter777/countries"
-	"gonum.org/v1/gonum/spatial/kdtree"
-)
-
-//go:embed rg_cities1000.csv
-var rawCSVData []byte
-
-const (
-	rgFilename = "rg_cities1000.csv"
 )
 
 // Location represents a geographical point with associated administrative data.
 type Location struct {
-	Lat     float64 // Latitude of the location.
-	Lon     float64 // Longitude of the location.
-	City    string  // Name of the location (e.g., city name).
-	Admin1  string  // First-level administrative division (e.g., state, province).
-	Admin2  string  // Second-level administrative division (e.g., county, region).
-	CC      string  // Country Code (e.g., US, GB).
-	Country string  // Name of the country
+	GeonameID  int     // GeoNames identifier, if the dataset provides one.
+	Lat        float64 // Latitude of the location.
+	Lon        float64 // Longitude of the location.
+	City       string  // Name of the location (e.g., city name).
+	Admin1     string  // First-level administrative division (e.g., state, province).
+	Admin1Code string  // Raw first-level division code, if kept with WithAdmin1Code.
+	Admin2     string  // Second-level administrative division (e.g., county, region).
+	Admin2Name string  // Name of the second-level division, if resolved with WithAdmin2Names.
+	CC         string  // Country Code (e.g., US, GB).
+	Country    string  // Name of the country
+	Timezone   string  // IANA time zone (e.g., Europe/Berlin), if the dataset provides one. The embedded dataset only has it for countries with a single time zone.
+	Elevation  int     // Elevation in meters, or 0 if the dataset does not provide one.
+	Population int     // Number of inhabitants, or 0 if the dataset does not provide one.
+
+	CountryInfo CountryInfo // Details about the country, if enabled with WithCountryInfo.
 }
 
-// geoPoint wraps a Location and satisfies kdtree.Comparable
-type geoPoint struct {
-	LatLon [2]float64
-	Index  int // Store the original index to retrieve the full Location data
+// RGeocoder represents the main reverse geocoding service.
+// It holds the KD-Tree and the loaded location data.
+//
+// An RGeocoder is safe for concurrent use. Its settings are fixed when it is
+// created, and the loaded data is an immutable snapshot: Reload, the Load
+// methods and AddLocations build a new snapshot and swap it in atomically, so
+// a query sees either the old or the new data, never a mix of both. Calls
+// that replace the data are serialized, and a query on loaded data does not
+// wait for them. Queries do take the same lock to load data: the first
+// query loads the dataset if no Load method was called, and with
+// WithLazyRegions a query that needs a region not loaded yet rebuilds the
+// index while holding the lock, so concurrent reloads, and queries that need
+// other regions, wait until it is done.
+type RGeocoder struct {
+	st     atomic.Pointer[store] // Dataset state; created on first use, see shared
+	config                       // Settings, fixed when the geocoder is created
 }
 
-// Compare returns the signed distance of p from the plane passing through
-// c and perpendicular to the dimension d.
-func (p geoPoint) Compare(c kdtree.Comparable, d kdtree.Dim) float64 {
-	q := c.(geoPoint)
-	return p.LatLon[d] - q.LatLon[d] // d is kdtree.Dim, which correctly indexes [2]float64
+// store holds a geocoder's dataset and where it is loaded from. The
+// instances returned by GetRGeocoder share one store.
+type store struct {
+	data  atomic.Pointer[dataset] // Current dataset; nil until loaded
+	mu    sync.Mutex              // Serializes loading and replacing the dataset
+	src   source                  // Where the dataset is (re)loaded from
+	added []Location              // Locations added with AddLocations
 }
 
-// Dims returns the number of dimensions described by the receiver (2 for Lat/Lon).
-func (p geoPoint) Dims() int {
-	return 2
-}
+// config holds a geocoder's settings. It is filled in by New and never
+// modified afterwards, so it can be read without synchronization.
+type config struct {
+	verbose  bool
+	language string       // Language of returned city names, set with WithLanguage
+	logger   *slog.Logger // Destination of log messages; nil means slog.Default
+	quiet    bool         // Discard log messages, set with WithLogger(nil)
 
-// Distance returns the squared Euclidean distance between c and the receiver.
-func (p geoPoint) Distance(c kdtree.Comparable) float64 {
-	q := c.(geoPoint)
-	dLat := p.LatLon[0] - q.LatLon[0]
-	dLon := p.LatLon[1] - q.LatLon[1]
-	return dLat*dLat + dLon*dLon
-}
+	noCountryNames bool // Leave Location.Country empty, set with WithCountryNames(false)
+	countryInfo    bool // Fill in Location.CountryInfo, set with WithCountryInfo
 
-// geoPoints implements kdtree.Interface AND sort.Interface for a slice of geoPoint
-type geoPoints []geoPoint
+	countryNames map[string]string // Localized country names by code; nil means English
 
-// Len returns the length of the list.
-func (p geoPoints) Len() int {
-	return len(p)
+	maxDistance float64          // Matches farther away are not returned, in units; 0 means no limit
+	units       Unit             // Unit of distances accepted and reported, set with WithUnits
+	indexSpec                    // Spatial index built for the dataset
+	coordPolicy CoordinatePolicy // How invalid query coordinates are treated
+	hooks       []QueryHook      // Called after every coordinate is resolved
+
+	cacheSize      int  // Maximum number of cached results, set with WithCache; 0 disables the cache
+	cachePrecision *int // Decimal places of cache keys, set with WithCachePrecision; nil means the default
+	cache          *resultCache
+	snap           float64 // Size of the cells queries are snapped to, set with WithApproximate; 0 means exact
+
+	lazyRegions bool        // Load the dataset country by country, set with WithLazyRegions
+	tuner       *batchTuner // Splits large batches across goroutines, set with WithAdaptiveParallelism
 }
 
-// Index returns the ith element of the list of points.
-func (p geoPoints) Index(i int) kdtree.Comparable {
-	return p[i]
+// indexSpec describes the spatial index of a dataset. Datasets are rebuilt
+// when they were built for a different spec than the geocoder's.
+type indexSpec struct {
+	metric   Metric
+	index    Index
+	single   bool // Coordinates are stored as float32, set with WithFloat32Coordinates
+	leafSize int  // Maximum number of points in a KD-Tree leaf, set with WithLeafSize; 0 means the default
 }
 
-// Swap swaps the elements at indices i and j.
-func (p geoPoints) Swap(i, j int) {
-	if i < 0 || j < 0 || i >= p.Len() || j >= p.Len() {
-		return
+// leaf returns the maximum number of points in a KD-Tree leaf under s.
+func (s indexSpec) leaf() int {
+	if s.leafSize == 0 {
+		return defaultLeafSize
 	}
-	p[i], p[j] = p[j], p[i]
+	return s.leafSize
 }
 
-// currentSortDim is a package-level variable used by Less to know which dimension to sort by.
-var currentSortDim kdtree.Dim
-
-// Less reports whether the element at index i should sort before the element at index j.
-func (p geoPoints) Less(i, j int) bool {
-	// Explicitly convert kdtree.Dim to int for array indexing
-	return p[i].LatLon[int(currentSortDim)] < p[j].LatLon[int(currentSortDim)]
+// resolved returns s with defaults filled in, for comparing specs.
+func (s indexSpec) resolved() indexSpec {
+	s.leafSize = s.leaf()
+	return s
 }
 
-// Pivot partitions the list based on the dimension specified.
-func (p geoPoints) Pivot(dim kdtree.Dim) int {
-	currentSortDim = dim // Set the package-level variable
-	// It's important that Partition handles the base cases (len <= 1)
-	// gracefully without trying to access out-of-bounds indices.
-	// If Gonum's Partition itself panics with 1 element, it might be a library bug,
-	// or we need to ensure we don't call it with less than 2 elements from our side.
-	return kdtree.Partition(p, int(dim))
+// shared returns the geocoder's store, creating it on first use so that the
+// zero RGeocoder is ready to use.
+func (rg *RGeocoder) shared() *store {
+	if s := rg.st.Load(); s != nil {
+		return s
+	}
+	rg.st.CompareAndSwap(nil, &store{})
+	return rg.st.Load()
 }
 
-// Slice returns a slice of the list using zero-based half-open indexing.
-func (p geoPoints) Slice(start, end int) kdtree.Interface {
-	return p[start:end]
-}
-
-// RGeocoder represents the main reverse geocoding service.
-// It holds the KD-Tree and the loaded location data.
-type RGeocoder struct {
-	tree      *kdtree.Tree
-	locations []Location // Store original Location structs, indexed by geoPoint.Index
-	once      sync.Once
-	verbose   bool
+// dataset is an immutable snapshot of the loaded data. Loading a new dataset
+// replaces the snapshot atomically, so queries in flight keep using the one
+// they started with.
+type dataset struct {
+	indexSpec // Which of the indexes below is built, and how
+	tree      *kdTree[float64]
+	tree32    *kdTree[float32] // The KD-Tree if single is set
+	grid      *gridIndex
+	vp        *vpTree
+	locations locationTable      // Locations, indexed by the spatial indexes
+	names     map[nameKey]string // Localized city names, loaded with WithAlternateNames
+
+	// Regions that are loaded on demand with WithLazyRegions, and which of
+	// them locations holds. regions is nil if the dataset is loaded
+	// completely.
+	regions       *regionSet
+	loadedRegions []bool
+
+	// Metadata reported by DatasetInfo.
+	src          source
+	loadedAt     time.Time // Zero if loading failed
+	loadDuration time.Duration
+	added        int   // Number of locations added with AddLocations
+	loadErr      error // Why loading failed; the dataset is empty if set
+	hashOnce     sync.Once
+	hash         string
 }
 
 var (
-	geocoderInstance *RGeocoder
-	geocoderOnce     sync.Once
+	geocoderQuiet   *RGeocoder // Shared instance returned by GetRGeocoder(false)
+	geocoderVerbose *RGeocoder // Shared instance returned by GetRGeocoder(true)
+	geocoderOnce    sync.Once
 )
 
-// GetRGeocoder returns a singleton instance of the reverse geocoder.
+// GetRGeocoder returns a shared instance of the reverse geocoder.
 // The geocoder's data is loaded and the KD-Tree is built only once,
-// on the first call to this function.
+// on the first query through any shared instance.
 // The 'verbose' parameter controls whether detailed loading and warning messages
-// are printed to the console.
+// are printed to the console. Verbose and quiet callers get different
+// instances backed by the same data, so one caller's setting does not affect
+// another's.
+//
+// Deprecated: The shared instances cannot be configured and their dataset is
+// affected by every caller in the process. Use New to create independent
+// geocoders.
 func GetRGeocoder(verbose bool) *RGeocoder {
 	geocoderOnce.Do(func() {
-		geocoderInstance = &RGeocoder{
-			verbose: verbose,
-		}
+		shared := &store{}
+		geocoderQuiet = &RGeocoder{}
+		geocoderQuiet.st.Store(shared)
+		geocoderVerbose = &RGeocoder{config: config{verbose: true}}
+		geocoderVerbose.st.Store(shared)
 	})
-	geocoderInstance.verbose = verbose
-	return geocoderInstance
-}
-
-// loadData loads the data from the embedded CSV and builds the KD-Tree.
-func (rg *RGeocoder) loadData() {
-	if rg.verbose {
-		log.Println("geodecode: Loading and processing geodata...")
+	if verbose {
+		return geocoderVerbose
 	}
+	return geocoderQuiet
+}
 
-	startTime := time.Now()
-
-	var reader *csv.Reader
-	if len(rawCSVData) > 0 {
-		reader = csv.NewReader(bytes.NewReader(rawCSVData))
-	} else {
-		filePath := filepath.Join(".", rgFilename)
-		file, err := os.Open(filePath)
-		if err != nil {
-			log.Printf("geodecode: Error: Data file '%s' not found: %v", filePath, err)
-			return
-		}
-		defer file.Close()
-		reader = csv.NewReader(file)
+// Query finds the nearest location to the given coordinate.
+// It returns a Location struct if found, otherwise an empty Location{}.
+// A match farther away than the distance set with WithMaxDistance is
+// returned as an empty Location{} as well.
+// It also performs validation on the input coordinate.
+//
+// Use Lookup to find out why a coordinate has no result.
+func (rg *RGeocoder) Query(coordinates ...[2]float64) []Location {
+	results, err := rg.Lookup(coordinates...)
+	switch {
+	case errors.Is(err, ErrInvalidCoordinate):
+		return nil
+	case errors.Is(err, ErrDataNotLoaded):
+		return []Location{}
 	}
+	return results
+}
 
-	header, err := reader.Read()
+// Lookup is like Query, but reports why coordinates have no result. An
+// invalid coordinate fails the whole call with ErrInvalidCoordinate, and
+// ErrDataNotLoaded is returned if the dataset could not be loaded. Otherwise
+// a Location is returned for every coordinate; if some have no match, for
+// example because of WithMaxDistance, their Location is empty and the
+// returned error wraps ErrNoResult for each of them.
+//
+// Example usage:
+//
+//	locations, err := geocoder.Lookup([2]float64{52.52, 13.405})
+//	if errors.Is(err, geodecode.ErrNoResult) {
+//	    // Nothing within the maximum distance
+//	}
+func (rg *RGeocoder) Lookup(coordinates ...[2]float64) ([]Location, error) {
+	ds, err := rg.prepare(coordinates)
 	if err != nil {
-		log.Printf("geodecode: Error reading CSV header: %v", err)
-		return
-	}
-
-	colMap := make(map[string]int)
-	for i, col := range header {
-		colMap[col] = i
-	}
-
-	requiredCols := []string{"lat", "lon", "city", "admin1", "admin2", "cc"}
-	for _, reqCol := range requiredCols {
-		if _, ok := colMap[reqCol]; !ok {
-			log.Printf("geodecode: Error: CSV file missing required column: %s", reqCol)
-			return
+		if errors.Is(err, ErrDataNotLoaded) {
+			return []Location{}, err
 		}
+		return nil, err
 	}
 
-	var parsedGeoPoints geoPoints  // This will hold our kdtree.Comparable points
-	var loadedLocations []Location // This will hold the full Location data
-
-	for i := 0; ; i++ { // Start from 0 for index, CSV row number starts at 1 (after header)
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Printf("geodecode: Warning: Skipping row %d due to read error: %v", i+1, err)
-			continue
-		}
-
-		latStr := record[colMap["lat"]]
-		lonStr := record[colMap["lon"]]
-
-		lat, errLat := strconv.ParseFloat(latStr, 64)
-		lon, errLon := strconv.ParseFloat(lonStr, 64)
+	return resolveAll(rg, ds, make([]Location, 0, len(coordinates)), coordinates, (*RGeocoder).nearest)
+}
 
-		if errLat != nil || errLon != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
-			if rg.verbose {
-				log.Printf("geodecode: Warning: Skipping row %d with invalid coordinates: lat='%s', lon='%s', Error: %v, %v", i+1, latStr, lonStr, errLat, errLon)
-			}
-			continue
+// prepare returns the dataset to query for coordinates, loading it if
+// necessary. An error is returned if a coordinate is invalid under the
+// geocoder's CoordinatePolicy or no data is loaded. The coordinates are not
+// modified; callers normalize each one as they resolve it, which saves
+// copying them.
+func (rg *RGeocoder) prepare(coordinates [][2]float64) (*dataset, error) {
+	ds := rg.current() // Ensure data is loaded lazily
+
+	for i, coord := range coordinates {
+		if _, ok := rg.normalize(coord); !ok {
+			return nil, rg.invalid(i, coord)
 		}
-
-		// Store the full location data
-		fullLocation := Location{
-			Lat:    lat,
-			Lon:    lon,
-			City:   record[colMap["city"]],
-			Admin1: record[colMap["admin1"]],
-			Admin2: record[colMap["admin2"]],
-			CC:     record[colMap["cc"]],
+	}
+	if ds.locations.len() == 0 && ds.regions == nil { // Check if data loading failed or was empty
+		err := ds.notLoaded()
+		for _, coord := range coordinates {
+			coord, _ = rg.normalize(coord)
+			rg.runHooks(coord, Location{}, err, 0)
 		}
-		loadedLocations = append(loadedLocations, fullLocation)
-
-		// Create the geoPoint for the KD-Tree, linking back to the original index
-		parsedGeoPoints = append(parsedGeoPoints, geoPoint{
-			LatLon: [2]float64{lat, lon},
-			Index:  len(loadedLocations) - 1, // Index in the loadedLocations slice
-		})
-
+		return nil, err
 	}
+	return ds, nil
+}
 
-	if len(parsedGeoPoints) == 0 {
-		log.Println("geodecode: Warning: No valid coordinates loaded.")
-		return
-	}
+// invalid returns the error reported for the invalid i-th coordinate of a
+// query, after logging it and passing it to the query hooks.
+func (rg *RGeocoder) invalid(i int, coord [2]float64) error {
 	if rg.verbose {
-		log.Printf("geodecode: Successfully parsed %d valid points from CSV.", len(parsedGeoPoints))
+		rg.log(slog.LevelWarn, "geodecode: invalid query coordinate, returning empty location", "lat", coord[0], "lon", coord[1])
 	}
+	err := fmt.Errorf("%w: coordinate %d: lat=%v, lon=%v", ErrInvalidCoordinate, i, coord[0], coord[1])
+	rg.runHooks(coord, Location{}, err, 0)
+	return err
+}
 
-	if len(parsedGeoPoints) == 1 {
-		log.Println("geodecode: Only one valid coordinate loaded. KDTree will not be built.")
-		rg.locations = loadedLocations
-		rg.tree = nil
-		return
+// runHooks calls the hooks registered with WithQueryHook.
+func (rg *RGeocoder) runHooks(coord [2]float64, loc Location, err error, latency time.Duration) {
+	for _, hook := range rg.hooks {
+		hook(coord, loc, err, latency)
 	}
+}
 
-	// Build the KD-Tree
-	rg.tree = kdtree.New(parsedGeoPoints, false) // `false` for no bounding (not strictly needed for nearest neighbor)
-	rg.locations = loadedLocations               // Store the full location data
-
-	if rg.verbose {
-		endTime := time.Now()
-		log.Printf("geodecode: Data loaded, KDTree built in %.2f seconds. %d locations indexed.",
-			endTime.Sub(startTime).Seconds(), len(rg.locations))
+// Validate returns an error wrapping ErrInvalidCoordinate if coord is not a
+// valid coordinate under the geocoder's CoordinatePolicy, and nil otherwise.
+// It neither loads the dataset nor counts as a query, so callers resolving
+// many coordinates can set the invalid ones aside before resolving the
+// others in one call.
+//
+// Example usage:
+//
+//	if err := geocoder.Validate(coord); err != nil {
+//	    return err
+//	}
+func (rg *RGeocoder) Validate(coord [2]float64) error {
+	if _, ok := rg.normalize(coord); !ok {
+		return fmt.Errorf("%w: lat=%v, lon=%v", ErrInvalidCoordinate, coord[0], coord[1])
 	}
+	return nil
 }
 
-// Query finds the nearest location to the given coordinate.
-// It returns a Location struct if found, otherwise an empty Location{}.
-// It also performs validation on the input coordinate.
-func (rg *RGeocoder) Query(coordinates ...[2]float64) []Location {
-	rg.once.Do(rg.loadData) // Ensure data is loaded lazily
+// normalize applies the geocoder's CoordinatePolicy to coord. It reports
+// false if coord is invalid.
+func (rg *RGeocoder) normalize(coord [2]float64) ([2]float64, bool) {
+	lat, lon, ok := rg.coordPolicy.normalize(coord[0], coord[1])
+	return [2]float64{lat, lon}, ok
+}
 
-	if rg.tree == nil && len(rg.locations) == 0 { // Check if data loading failed or was empty
-		return []Location{}
+// nearest returns the location in ds nearest to coord.
+func (rg *RGeocoder) nearest(ds *dataset, coord [2]float64) (loc Location, err error) {
+	if len(rg.hooks) > 0 {
+		start := time.Now()
+		defer func() { rg.runHooks(coord, loc, err, time.Since(start)) }()
 	}
 
-	if len(coordinates) == 0 {
-		return []Location{}
+	search := coord
+	if rg.snap > 0 {
+		search = snapToCell(coord, rg.snap)
 	}
+	if rg.cache != nil {
+		cached, cachedErr, ok := rg.cache.get(ds, search)
+		if !ok {
+			ds = rg.loadRegions(ds, search, 1)
+			cached, cachedErr = rg.search(ds, search)
+			rg.cache.put(ds, search, cached, cachedErr)
+		}
+		loc, err = cached, cachedErr
+	} else {
+		ds = rg.loadRegions(ds, search, 1)
+		loc, err = rg.search(ds, search)
+	}
+	if err != nil {
+		return Location{}, err
+	}
+	return rg.withinRange(coord, loc)
+}
 
-	results := make([]Location, 0, len(coordinates))
-
-	for _, coord := range coordinates {
+// search returns the location in ds nearest to coord, regardless of the
+// distance set with WithMaxDistance.
+func (rg *RGeocoder) search(ds *dataset, coord [2]float64) (Location, error) {
+	sp := ds.spatial()
+	if sp == nil {
 		// Handle case where only one location was loaded and no KDTree was built
-		lat := coord[0]
-		lon := coord[1]
-
-		if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
-			if rg.verbose {
-				log.Printf("geodecode: Invalid query coordinate received: Lat=%.4f, Lon=%.4f. Returning empty location.", lat, lon)
-			}
-			return nil
+		if ds.locations.len() == 1 {
+			return rg.result(ds, ds.locations.at(0)), nil
 		}
-		if rg.tree == nil && len(rg.locations) == 1 {
-			// If there's only one location, that must be the nearest.
-			results = append(results, rg.locations[0])
-			continue
+		return Location{}, ErrNoResult
+	}
+
+	index, _ := sp.nearest(coord, nil)
+	if index < 0 {
+		// No nearest point found (e.g., empty tree)
+		if rg.verbose {
+			rg.log(slog.LevelWarn, "geodecode: no nearest point found", "lat", coord[0], "lon", coord[1])
 		}
+		return Location{}, ErrNoResult
+	}
 
-		queryPoint := geoPoint{LatLon: coord} // Create a geoPoint for querying
+	// Retrieve the full Location data using the stored index
+	if index >= ds.locations.len() {
+		rg.log(slog.LevelError, "geodecode: spatial index returned an invalid index", "index", index)
+		return Location{}, fmt.Errorf("spatial index returned invalid index %d", index)
+	}
+	return rg.result(ds, ds.locations.at(index)), nil
+}
 
-		// Use the KD-Tree's Nearest method
-		nearestComparable, distSq := rg.tree.Nearest(queryPoint)
+// nearestIndex returns the index of the location in ds nearest to coord and
+// its squared distance as compared by the spatial indexes, or -1 if ds is
+// empty.
+func (ds *dataset) nearestIndex(coord [2]float64) (int, float64) {
+	if sp := ds.spatial(); sp != nil {
+		return sp.nearest(coord, nil)
+	}
+	if ds.locations.len() == 0 {
+		return -1, math.Inf(1)
+	}
+	lat, lon := ds.locations.coord(0)
+	q, p := indexPoint(ds.metric, coord[0], coord[1]), indexPoint(ds.metric, lat, lon)
+	return 0, indexDist(indexWeights(ds.metric, coord[0]), q, p)
+}
 
-		if nearestComparable == nil || math.IsInf(distSq, 1) {
-			// No nearest point found (e.g., empty tree)
-			if rg.verbose {
-				log.Printf("geodecode: Warning: No nearest point found for %v", coord)
-			}
-			results = append(results, Location{}) // Append an empty Location for consistency
-			continue
-		}
+// spatial returns the spatial index built for ds, or nil if none is.
+func (ds *dataset) spatial() spatialIndex {
+	switch {
+	case ds.grid != nil:
+		return ds.grid
+	case ds.vp != nil:
+		return ds.vp
+	case ds.tree != nil:
+		return ds.tree
+	case ds.tree32 != nil:
+		return ds.tree32
+	default:
+		return nil
+	}
+}
 
-		nearestGeoPoint, ok := nearestComparable.(geoPoint)
-		if !ok {
-			// This should not happen if our implementation is correct
-			log.Printf("geodecode: Error: KDTree returned a non-geoPoint type.")
-			results = append(results, Location{})
-			continue
+// withinRange returns loc if it lies within the distance set with
+// WithMaxDistance of coord, and an empty Location and ErrNoResult otherwise.
+func (rg *RGeocoder) withinRange(coord [2]float64, loc Location) (Location, error) {
+	if rg.maxDistance > 0 && haversineKM(coord[0], coord[1], loc.Lat, loc.Lon)*1000 > rg.maxDistance*rg.units.meters() {
+		if rg.verbose {
+			rg.log(slog.LevelInfo, "geodecode: nearest location is beyond the maximum distance", "lat", coord[0], "lon", coord[1], "max_distance", rg.maxDistance, "unit", rg.units.String())
 		}
+		return Location{}, fmt.Errorf("%w within %v %s", ErrNoResult, rg.maxDistance, rg.units)
+	}
+	return loc, nil
+}
 
-		// Retrieve the full Location data using the stored index
-		if nearestGeoPoint.Index >= 0 && nearestGeoPoint.Index < len(rg.locations) {
-			results = append(results, rg.locations[nearestGeoPoint.Index])
-		} else {
-			log.Printf("geodecode: Error: KDTree returned invalid index %d", nearestGeoPoint.Index)
-			results = append(results, Location{})
+// result returns the stored location loc as queries return it, with its
+// names localized and its country details filled in as configured.
+func (rg *RGeocoder) result(ds *dataset, loc Location) Location {
+	loc = rg.localize(ds, loc)
+	if !rg.noCountryNames {
+		if name, ok := rg.countryNames[loc.CC]; ok {
+			loc.Country = name
+		} else if loc.Country == "" {
+			loc.Country = countryName(loc.CC)
 		}
 	}
+	if rg.countryInfo {
+		loc.CountryInfo = countryInfo(loc.CC)
+	}
+	return loc
+}
 
-	return results
+// notLoaded returns the error reported for queries against the empty ds.
+func (ds *dataset) notLoaded() error {
+	if ds.loadErr != nil {
+		return fmt.Errorf("%w: %w", ErrDataNotLoaded, ds.loadErr)
+	}
+	return ErrDataNotLoaded
+}
+
+// log writes a message at the given level to the geocoder's logger, or to
+// slog.Default if none was set with WithLogger.
+func (rg *RGeocoder) log(level slog.Level, msg string, args ...any) {
+	if rg.quiet {
+		return
+	}
+	logger := rg.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Log(context.Background(), level, msg, args...)
 }
 
 // FindLocation is a convenience function to query the geocoder directly
@@ -323,6 +436,7 @@ func (rg *RGeocoder) Query(coordinates ...[2]float64) []Location {
 // It returns a pointer to the nearest Location found, or nil if no location
 // is found (e.g., input coordinate is invalid or no data is loaded).
 // The 'verbose' parameter controls logging for the internal geocoder instance.
+// Use LookupLocation to find out why no location was found.
 //
 // coordinate: [lat, lng]
 //
@@ -333,19 +447,40 @@ func (rg *RGeocoder) Query(coordinates ...[2]float64) []Location {
 //	    fmt.Printf("City: %s, Country: %s\n", location.City, location.Country)
 //	}
 func FindLocation(coordinate [2]float64, verbose bool) *Location {
-	if coordinate[0] < -90 || coordinate[0] > 90 || coordinate[1] < -180 || coordinate[1] > 180 {
-		// If the coordinate itself is outside valid bounds, return nil
+	location, err := lookupLocation(coordinate, verbose)
+	if err != nil && !errors.Is(err, ErrNoResult) {
 		return nil
 	}
-	geocoder := GetRGeocoder(verbose)
-	results := geocoder.Query(coordinate)
-	if len(results) > 0 {
-		result := &results[0]
-		country := countries.ByName(result.CC)
-		result.Country = country.Info().Name
-		return &results[0]
+	return location
+}
+
+// LookupLocation is like FindLocation, but returns an error wrapping
+// ErrInvalidCoordinate, ErrDataNotLoaded or ErrNoResult instead of nil or an
+// empty Location when no location is found.
+//
+// Example usage:
+//
+//	location, err := geodecode.LookupLocation([2]float64{34.0522, -118.2437})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("City: %s, Country: %s\n", location.City, location.Country)
+func LookupLocation(coordinate [2]float64) (*Location, error) {
+	location, err := lookupLocation(coordinate, false)
+	if err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// lookupLocation queries the shared geocoder for coordinate. On ErrNoResult
+// the empty Location is returned along with the error.
+func lookupLocation(coordinate [2]float64, verbose bool) (*Location, error) {
+	results, err := GetRGeocoder(verbose).Lookup(coordinate)
+	if len(results) == 0 {
+		return nil, err
 	}
-	return nil
+	return &results[0], err
 }
 
 // GetCountryByCode returns the full country name for a given ISO country code (e.g., "US", "GB").
@@ -356,6 +491,77 @@ func FindLocation(coordinate [2]float64, verbose bool) *Location {
 //	countryName := geodecode.GetCountryByCode("US")
 //	fmt.Println(countryName) // Output: United States
 func GetCountryByCode(code string) string {
-	country := countries.ByName(code)
-	return country.Info().Name
+	return countryByCode(code).String() // As Location.Country
+}
+
+// IsNear reports whether coord lies within the distance within of the city
+// with the given name in the country identified by cc. Name and country code
+// are matched case-insensitively. If the dataset holds several cities with the
+// same name in that country, IsNear reports true if any of them is in range.
+//
+// within is given in the units set with WithUnits, kilometers by default.
+// An error is returned if the coordinate is invalid, within is negative, or
+// no matching city exists in the loaded dataset.
+//
+// coord: [lat, lng]
+func (rg *RGeocoder) IsNear(coord [2]float64, city string, cc string, within float64) (bool, error) {
+	normalized, ok := rg.normalize(coord)
+	if !ok {
+		return false, fmt.Errorf("%w: %v", ErrInvalidCoordinate, coord)
+	}
+	coord = normalized
+	if within < 0 || math.IsNaN(within) {
+		return false, fmt.Errorf("geodecode: invalid distance %v %s", within, rg.units)
+	}
+
+	ds := rg.loadCountry(rg.current(), cc)
+	if ds.locations.len() == 0 && ds.regions == nil {
+		return false, ds.notLoaded()
+	}
+	found := false
+	for _, loc := range ds.locations.all() {
+		if !strings.EqualFold(loc.City, city) || !strings.EqualFold(loc.CC, cc) {
+			continue
+		}
+		found = true
+		if haversineKM(coord[0], coord[1], loc.Lat, loc.Lon)*1000 <= within*rg.units.meters() {
+			return true, nil
+		}
+	}
+	if !found {
+		return false, fmt.Errorf("%w: %q in %q", ErrCityNotFound, city, cc)
+	}
+	return false, nil
+}
+
+var (
+	// ErrCityNotFound is returned by IsNear when no city matches the given
+	// name and country code.
+	ErrCityNotFound = errors.New("geodecode: city not found")
+
+	// ErrInvalidCoordinate is returned for a coordinate outside the valid
+	// latitude and longitude ranges, or one that is NaN or infinite.
+	ErrInvalidCoordinate = errors.New("geodecode: invalid coordinate")
+
+	// ErrDataNotLoaded is returned when the dataset could not be loaded or
+	// holds no locations. It wraps the loading error, if any.
+	ErrDataNotLoaded = errors.New("geodecode: no data loaded")
+
+	// ErrNoResult is returned by Lookup and LookupLocation when there is no
+	// location near a valid coordinate, for example because the nearest one
+	// is farther away than the distance set with WithMaxDistance.
+	ErrNoResult = errors.New("geodecode: no location found")
+)
+
+// IsNear is a convenience function that calls IsNear on the shared geocoder
+// instance.
+//
+// Example usage:
+//
+//	near, err := geodecode.IsNear([2]float64{48.8606, 2.3376}, "Paris", "FR", 10)
+//	if err == nil && near {
+//	    fmt.Println("Within 10 km of Paris")
+//	}
+func IsNear(coord [2]float64, city string, cc string, withinKM float64) (bool, error) {
+	return GetRGeocoder(false).IsNear(coord, city, cc, withinKM)
 }
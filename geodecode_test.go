@@ -1,7 +1,11 @@
 package geodecode_test
 
 import (
+	"errors"
+	"io/fs"
 	"log"
+	"strings"
+	"sync"
 	"testing"
 
 	geodecode "github.com/sdwillbrand/GeoDecode"
@@ -59,3 +63,146 @@ func TestFindLocation(t *testing.T) {
 	}
 	log.Printf("Confirmed nil for truly invalid coordinate %v", invalidCoord)
 }
+
+func TestIsNear(t *testing.T) {
+	louvre := [2]float64{48.8606, 2.3376}
+
+	near, err := geodecode.IsNear(louvre, "paris", "fr", 10)
+	if err != nil {
+		t.Fatalf("IsNear(%v, Paris, FR): unexpected error: %v", louvre, err)
+	}
+	if !near {
+		t.Errorf("Expected %v to be within 10 km of Paris", louvre)
+	}
+
+	near, err = geodecode.IsNear([2]float64{52.5200, 13.4050}, "Paris", "FR", 10) // Berlin
+	if err != nil {
+		t.Fatalf("IsNear(Berlin, Paris, FR): unexpected error: %v", err)
+	}
+	if near {
+		t.Errorf("Expected Berlin not to be within 10 km of Paris")
+	}
+
+	if _, err := geodecode.IsNear(louvre, "Atlantis", "FR", 10); !errors.Is(err, geodecode.ErrCityNotFound) {
+		t.Errorf("Expected ErrCityNotFound for unknown city, got %v", err)
+	}
+	if _, err := geodecode.IsNear([2]float64{999, 999}, "Paris", "FR", 10); err == nil {
+		t.Errorf("Expected an error for invalid coordinate")
+	}
+	if _, err := geodecode.IsNear(louvre, "Paris", "FR", -1); err == nil {
+		t.Errorf("Expected an error for negative distance")
+	}
+}
+
+func TestGetRGeocoderConcurrent(t *testing.T) {
+	coord := [2]float64{48.8606, 2.3376}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(verbose bool) {
+			defer wg.Done()
+			geodecode.GetRGeocoder(verbose).Query(coord)
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	quiet, verbose := geodecode.GetRGeocoder(false), geodecode.GetRGeocoder(true)
+	if quiet == verbose {
+		t.Errorf("Expected separate instances for verbose and quiet geocoders")
+	}
+	if quiet != geodecode.GetRGeocoder(false) {
+		t.Errorf("Expected GetRGeocoder(false) to return the same instance on every call")
+	}
+	if q, v := quiet.DatasetInfo(), verbose.DatasetInfo(); q.Hash != v.Hash || !q.LoadedAt.Equal(v.LoadedAt) {
+		t.Errorf("Expected verbose and quiet geocoders to share one dataset, got %+v and %+v", q, v)
+	}
+}
+
+func TestLookupErrors(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader("lat,lon,city,admin1,admin2,cc\n52.52,13.405,Berlin,,,DE\n48.8566,2.3522,Paris,,,FR\n")); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+
+	if _, err := geocoder.Lookup([2]float64{52.5, 13.4}, [2]float64{999, 0}); !errors.Is(err, geodecode.ErrInvalidCoordinate) {
+		t.Errorf("Expected ErrInvalidCoordinate, got %v", err)
+	}
+
+	locations, err := geocoder.Lookup([2]float64{52.5, 13.4}, [2]float64{0, 0})
+	if !errors.Is(err, geodecode.ErrNoResult) {
+		t.Errorf("Expected ErrNoResult for a coordinate far from any city, got %v", err)
+	}
+	if len(locations) != 2 || locations[0].City != "Berlin" || locations[1].City != "" {
+		t.Errorf("Expected Berlin and an empty location, got %+v", locations)
+	}
+
+	missing, err := geodecode.New(geodecode.WithDataset("testdata/does-not-exist.csv"), geodecode.WithLogger(nil))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := missing.Lookup([2]float64{52.5, 13.4}); !errors.Is(err, geodecode.ErrDataNotLoaded) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected ErrDataNotLoaded wrapping the loading error, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	missing, err := geodecode.New(geodecode.WithDataset("testdata/does-not-exist.csv"), geodecode.WithLogger(nil))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := missing.Validate([2]float64{52.5, 13.4}); err != nil {
+		t.Errorf("Expected a valid coordinate without loading the dataset, got %v", err)
+	}
+	if err := missing.Validate([2]float64{52.5, 190.5}); !errors.Is(err, geodecode.ErrInvalidCoordinate) {
+		t.Errorf("Expected ErrInvalidCoordinate, got %v", err)
+	}
+
+	wrapping, err := geodecode.New(geodecode.WithCoordinatePolicy(geodecode.WrapLongitude))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := wrapping.Validate([2]float64{52.5, 190.5}); err != nil {
+		t.Errorf("Expected a longitude wrapped by the policy to be valid, got %v", err)
+	}
+	if err := wrapping.Validate([2]float64{91, 0}); !errors.Is(err, geodecode.ErrInvalidCoordinate) {
+		t.Errorf("Expected ErrInvalidCoordinate for a latitude out of range, got %v", err)
+	}
+}
+
+func TestLookupLocation(t *testing.T) {
+	location, err := geodecode.LookupLocation([2]float64{64.73424, 177.5103})
+	if err != nil {
+		t.Fatalf("LookupLocation: unexpected error: %v", err)
+	}
+	if location.City != "Anadyr" || location.Country == "" {
+		t.Errorf("Expected Anadyr with a country name, got %+v", location)
+	}
+
+	if _, err := geodecode.LookupLocation([2]float64{999, 999}); !errors.Is(err, geodecode.ErrInvalidCoordinate) {
+		t.Errorf("Expected ErrInvalidCoordinate, got %v", err)
+	}
+}
+
+func TestEmbeddedTimezone(t *testing.T) {
+	tests := []struct {
+		coord    [2]float64
+		timezone string
+	}{
+		{[2]float64{48.8566, 2.3522}, "Europe/Paris"},
+		{[2]float64{35.6895, 139.6917}, "Asia/Tokyo"},
+		{[2]float64{-1.2864, 36.8172}, "Africa/Nairobi"},
+	}
+	for _, tt := range tests {
+		location, err := geodecode.LookupLocation(tt.coord)
+		if err != nil {
+			t.Fatalf("LookupLocation(%v): unexpected error: %v", tt.coord, err)
+		}
+		if location.Timezone != tt.timezone {
+			t.Errorf("Expected time zone %q for %v, got %q (%+v)", tt.timezone, tt.coord, location.Timezone, location)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+// Package geodecodepb implements the messages of the gRPC service defined
+// in geodecode.proto and their framing on a gRPC stream, without generated
+// code or dependencies. The messages are encoded in the protocol buffer
+// wire format, so clients generated from geodecode.proto in any language
+// can call a server built with this package, such as geodecode serve -grpc.
+package geodecodepb
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// Names of the service and the full method names used as request paths.
+const (
+	ServiceName                = "geodecode.v1.Geocoder"
+	ReverseGeocodeMethod       = "/geodecode.v1.Geocoder/ReverseGeocode"
+	ReverseGeocodeStreamMethod = "/geodecode.v1.Geocoder/ReverseGeocodeStream"
+)
+
+// MaxMessageSize is the size of the largest message ReadMessage accepts.
+const MaxMessageSize = 4 << 20
+
+// Wire types of the protocol buffer encoding.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// ReverseGeocodeRequest is a coordinate in decimal degrees.
+type ReverseGeocodeRequest struct {
+	Lat float64
+	Lon float64
+}
+
+// ReverseGeocodeResponse is the nearest location of a coordinate.
+type ReverseGeocodeResponse struct {
+	Found      bool
+	Location   *Location // Nil if Found is false
+	Distance   float64
+	Unit       string
+	DistanceKM float64
+	Confidence float64
+}
+
+// Location is a place of the dataset.
+type Location struct {
+	GeonameID  int64
+	Lat        float64
+	Lon        float64
+	City       string
+	Admin1     string
+	Admin2     string
+	CC         string
+	Country    string
+	Timezone   string
+	Population int64
+	Elevation  int64
+}
+
+// FromResult returns the response for the result res.
+func FromResult(res geodecode.Result) *ReverseGeocodeResponse {
+	m := &ReverseGeocodeResponse{Found: res.Found}
+	if !res.Found {
+		return m
+	}
+	loc := res.Location
+	m.Location = &Location{
+		GeonameID:  int64(loc.GeonameID),
+		Lat:        loc.Lat,
+		Lon:        loc.Lon,
+		City:       loc.City,
+		Admin1:     loc.Admin1,
+		Admin2:     loc.Admin2,
+		CC:         loc.CC,
+		Country:    loc.Country,
+		Timezone:   loc.Timezone,
+		Population: int64(loc.Population),
+		Elevation:  int64(loc.Elevation),
+	}
+	m.Distance, m.Unit, m.DistanceKM, m.Confidence = res.Distance, res.Unit.String(), res.DistanceKM, res.Confidence
+	return m
+}
+
+// MarshalBinary encodes m in the protocol buffer wire format.
+func (m *ReverseGeocodeRequest) MarshalBinary() ([]byte, error) {
+	buf := appendDouble(nil, 1, m.Lat)
+	return appendDouble(buf, 2, m.Lon), nil
+}
+
+// UnmarshalBinary decodes m from the protocol buffer wire format.
+func (m *ReverseGeocodeRequest) UnmarshalBinary(data []byte) error {
+	*m = ReverseGeocodeRequest{}
+	return decodeFields(data, func(num int, f field) error {
+		switch num {
+		case 1:
+			return f.double(&m.Lat)
+		case 2:
+			return f.double(&m.Lon)
+		}
+		return nil
+	})
+}
+
+// MarshalBinary encodes m in the protocol buffer wire format.
+func (m *ReverseGeocodeResponse) MarshalBinary() ([]byte, error) {
+	var buf []byte
+	if m.Found {
+		buf = appendVarint(buf, 1, 1)
+	}
+	if m.Location != nil {
+		loc, _ := m.Location.MarshalBinary() // Cannot fail
+		buf = appendBytes(buf, 2, loc)
+	}
+	buf = appendDouble(buf, 3, m.Distance)
+	buf = appendString(buf, 4, m.Unit)
+	buf = appendDouble(buf, 5, m.DistanceKM)
+	return appendDouble(buf, 6, m.Confidence), nil
+}
+
+// UnmarshalBinary decodes m from the protocol buffer wire format.
+func (m *ReverseGeocodeResponse) UnmarshalBinary(data []byte) error {
+	*m = ReverseGeocodeResponse{}
+	return decodeFields(data, func(num int, f field) error {
+		switch num {
+		case 1:
+			var v int64
+			err := f.varint(&v)
+			m.Found = v != 0
+			return err
+		case 2:
+			if f.wire != wireBytes {
+				return errWireType
+			}
+			m.Location = new(Location)
+			return m.Location.UnmarshalBinary(f.bytes)
+		case 3:
+			return f.double(&m.Distance)
+		case 4:
+			return f.string(&m.Unit)
+		case 5:
+			return f.double(&m.DistanceKM)
+		case 6:
+			return f.double(&m.Confidence)
+		}
+		return nil
+	})
+}
+
+// MarshalBinary encodes m in the protocol buffer wire format.
+func (m *Location) MarshalBinary() ([]byte, error) {
+	buf := appendVarint(nil, 1, m.GeonameID)
+	buf = appendDouble(buf, 2, m.Lat)
+	buf = appendDouble(buf, 3, m.Lon)
+	buf = appendString(buf, 4, m.City)
+	buf = appendString(buf, 5, m.Admin1)
+	buf = appendString(buf, 6, m.Admin2)
+	buf = appendString(buf, 7, m.CC)
+	buf = appendString(buf, 8, m.Country)
+	buf = appendString(buf, 9, m.Timezone)
+	buf = appendVarint(buf, 10, m.Population)
+	return appendVarint(buf, 11, m.Elevation), nil
+}
+
+// UnmarshalBinary decodes m from the protocol buffer wire format.
+func (m *Location) UnmarshalBinary(data []byte) error {
+	*m = Location{}
+	return decodeFields(data, func(num int, f field) error {
+		switch num {
+		case 1:
+			return f.varint(&m.GeonameID)
+		case 2:
+			return f.double(&m.Lat)
+		case 3:
+			return f.double(&m.Lon)
+		case 4:
+			return f.string(&m.City)
+		case 5:
+			return f.string(&m.Admin1)
+		case 6:
+			return f.string(&m.Admin2)
+		case 7:
+			return f.string(&m.CC)
+		case 8:
+			return f.string(&m.Country)
+		case 9:
+			return f.string(&m.Timezone)
+		case 10:
+			return f.varint(&m.Population)
+		case 11:
+			return f.varint(&m.Elevation)
+		}
+		return nil
+	})
+}
+
+// WriteMessage writes m to w as a gRPC length-prefixed message, which is
+// not compressed.
+func WriteMessage(w io.Writer, m encoding.BinaryMarshaler) error {
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+	_, err = w.Write(append(frame, data...))
+	return err
+}
+
+// ReadMessage reads a gRPC length-prefixed message from r into m. It
+// returns io.EOF if r ends before the message starts, and an error for
+// compressed messages and messages larger than MaxMessageSize.
+func ReadMessage(r io.Reader, m encoding.BinaryUnmarshaler) error {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return errors.New("geodecodepb: truncated message")
+		}
+		return err
+	}
+	if prefix[0] != 0 {
+		return errors.New("geodecodepb: compressed messages are not supported")
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > MaxMessageSize {
+		return fmt.Errorf("geodecodepb: message of %d bytes is larger than %d", size, MaxMessageSize)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return errors.New("geodecodepb: truncated message")
+	}
+	return m.UnmarshalBinary(data)
+}
+
+// appendVarint appends the field num with the value v as a varint, unless
+// v is zero.
+func appendVarint(buf []byte, num int, v int64) []byte {
+	if v == 0 {
+		return buf
+	}
+	buf = binary.AppendUvarint(buf, uint64(num)<<3|wireVarint)
+	return binary.AppendUvarint(buf, uint64(v))
+}
+
+// appendDouble appends the field num with the value v, unless v is zero.
+func appendDouble(buf []byte, num int, v float64) []byte {
+	if v == 0 {
+		return buf
+	}
+	buf = binary.AppendUvarint(buf, uint64(num)<<3|wireFixed64)
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+}
+
+// appendString appends the field num with the value s, unless s is empty.
+func appendString(buf []byte, num int, s string) []byte {
+	if s == "" {
+		return buf
+	}
+	return appendBytes(buf, num, []byte(s))
+}
+
+// appendBytes appends the length-delimited field num with the value b.
+func appendBytes(buf []byte, num int, b []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(num)<<3|wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+// errWireType reports a field encoded with another wire type than its type
+// has.
+var errWireType = errors.New("geodecodepb: unexpected wire type")
+
+// field is an encoded field: its wire type and value, the number for
+// varints and fixed64s, and the bytes for length-delimited fields.
+type field struct {
+	wire  int
+	num   uint64
+	bytes []byte
+}
+
+func (f field) varint(v *int64) error {
+	if f.wire != wireVarint {
+		return errWireType
+	}
+	*v = int64(f.num)
+	return nil
+}
+
+func (f field) double(v *float64) error {
+	if f.wire != wireFixed64 {
+		return errWireType
+	}
+	*v = math.Float64frombits(f.num)
+	return nil
+}
+
+func (f field) string(s *string) error {
+	if f.wire != wireBytes {
+		return errWireType
+	}
+	*s = string(f.bytes)
+	return nil
+}
+
+// decodeFields calls fn with each field of the message data and its
+// number. Fields fn does not know are skipped, as the format requires.
+func decodeFields(data []byte, fn func(num int, f field) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("geodecodepb: invalid tag")
+		}
+		data = data[n:]
+		f := field{wire: int(tag & 7)}
+		switch f.wire {
+		case wireVarint:
+			if f.num, n = binary.Uvarint(data); n <= 0 {
+				return errors.New("geodecodepb: invalid varint")
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return errors.New("geodecodepb: truncated fixed64")
+			}
+			f.num, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || size > uint64(len(data)-n) {
+				return errors.New("geodecodepb: truncated field")
+			}
+			f.bytes, data = data[n:n+int(size)], data[n+int(size):]
+		case wireFixed32:
+			if len(data) < 4 {
+				return errors.New("geodecodepb: truncated fixed32")
+			}
+			f.num, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		default:
+			return fmt.Errorf("geodecodepb: unsupported wire type %d", f.wire)
+		}
+		if tag>>3 == 0 || tag>>3 > math.MaxInt32 {
+			return errors.New("geodecodepb: invalid field number")
+		}
+		if err := fn(int(tag>>3), f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
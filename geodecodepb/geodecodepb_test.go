@@ -0,0 +1,78 @@
+package geodecodepb_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/geodecodepb"
+)
+
+func TestRequestEncoding(t *testing.T) {
+	data, err := (&geodecodepb.ReverseGeocodeRequest{Lat: 52.5, Lon: 13.25}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	want := binary.LittleEndian.AppendUint64([]byte{0x09}, math.Float64bits(52.5))
+	want = binary.LittleEndian.AppendUint64(append(want, 0x11), math.Float64bits(13.25))
+	if !bytes.Equal(data, want) {
+		t.Errorf("Expected % x, got % x", want, data)
+	}
+
+	// Unknown fields of any wire type are skipped.
+	data = append([]byte{0x18, 0x96, 0x01, 0x22, 0x02, 'h', 'i', 0x2d, 1, 2, 3, 4}, data...)
+	var req geodecodepb.ReverseGeocodeRequest
+	if err := req.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if req.Lat != 52.5 || req.Lon != 13.25 {
+		t.Errorf("Expected 52.5,13.25, got %v,%v", req.Lat, req.Lon)
+	}
+
+	for _, data := range [][]byte{{0x09, 1, 2}, {0x08, 1}, {0x22, 0x05, 'h'}, {0x80}, {0x0b}} {
+		if err := req.UnmarshalBinary(data); err == nil {
+			t.Errorf("Expected an error for % x", data)
+		}
+	}
+}
+
+func TestResponseRoundTrip(t *testing.T) {
+	res := geodecode.Result{
+		Found:      true,
+		Location:   geodecode.Location{GeonameID: 2950159, Lat: 52.52437, Lon: 13.41053, City: "Berlin", Admin1: "Berlin", CC: "DE", Country: "Germany", Timezone: "Europe/Berlin", Population: 3426354, Elevation: -2},
+		Distance:   1.5,
+		Unit:       geodecode.Miles,
+		DistanceKM: 2.414,
+		Confidence: 0.9,
+	}
+	for _, m := range []*geodecodepb.ReverseGeocodeResponse{geodecodepb.FromResult(res), geodecodepb.FromResult(geodecode.Result{})} {
+		var buf bytes.Buffer
+		if err := geodecodepb.WriteMessage(&buf, m); err != nil {
+			t.Fatalf("WriteMessage: %v", err)
+		}
+		var got geodecodepb.ReverseGeocodeResponse
+		if err := geodecodepb.ReadMessage(&buf, &got); err != nil {
+			t.Fatalf("ReadMessage: %v", err)
+		}
+		if !reflect.DeepEqual(&got, m) {
+			t.Errorf("Expected %+v, got %+v", m, &got)
+		}
+		if err := geodecodepb.ReadMessage(&buf, &got); err != io.EOF {
+			t.Errorf("Expected io.EOF after the last message, got %v", err)
+		}
+	}
+	if m := geodecodepb.FromResult(res); m.Unit != "mi" || m.Location.City != "Berlin" || m.Location.Elevation != -2 {
+		t.Errorf("Expected Berlin in mi, got %+v", m)
+	}
+
+	var m geodecodepb.ReverseGeocodeResponse
+	for _, frame := range [][]byte{{1, 0, 0, 0, 0}, {0, 0, 0, 0, 2, 0x08}, {0, 0, 0}, {0, 0xff, 0, 0, 0}} {
+		if err := geodecodepb.ReadMessage(bytes.NewReader(frame), &m); err == nil || err == io.EOF {
+			t.Errorf("Expected an error for % x, got %v", frame, err)
+		}
+	}
+}
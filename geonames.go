@@ -0,0 +1,184 @@
+package geodecode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Column indices of the GeoNames main table, as documented in
+// https://download.geonames.org/export/dump/readme.txt.
+const (
+	gnGeonameID = iota
+	gnName
+	gnASCIIName
+	gnAlternateNames
+	gnLatitude
+	gnLongitude
+	gnFeatureClass
+	gnFeatureCode
+	gnCountryCode
+	gnCC2
+	gnAdmin1Code
+	gnAdmin2Code
+	gnAdmin3Code
+	gnAdmin4Code
+	gnPopulation
+	gnElevation
+	gnDEM
+	gnTimezone
+	gnModificationDate
+	gnColumns // Number of columns in the GeoNames main table
+)
+
+// maxGeoNamesLine bounds the length of a single GeoNames row. The
+// alternatenames column makes some rows considerably longer than usual.
+const maxGeoNamesLine = 1 << 20
+
+// readGeoNames parses the tab-separated GeoNames dump format used by
+// cities1000.txt, cities15000.txt, allCountries.txt and similar files. The
+// files have no header; every row must have the 19 documented columns. Rows
+// excluded by the feature filters in cfg are skipped silently. size is the
+// number of bytes of the dataset, or -1 if unknown.
+func (rg *RGeocoder) readGeoNames(r io.Reader, cfg *loadConfig, size int) ([]Location, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxGeoNamesLine)
+
+	loadedLocations := make([]Location, 0, sizeSampleRows)
+	strs := make(stringInterner)
+	var (
+		fields [gnColumns]string // Reused for every row
+		offset int               // Bytes read so far
+	)
+
+	for row := 1; scanner.Scan(); row++ {
+		if row == sizeSampleRows {
+			loadedLocations = growToEstimate(loadedLocations, offset, size)
+		}
+		offset += len(scanner.Bytes()) + 1
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if n := splitFields(line, fields[:]); n != gnColumns {
+			if cfg.strict {
+				return nil, fmt.Errorf("geodecode: GeoNames row %d has %d columns, want %d", row, n, gnColumns)
+			}
+			rg.log(slog.LevelWarn, "geodecode: skipping row with wrong number of columns", "row", row, "columns", n, "want", gnColumns)
+			continue
+		}
+
+		if !cfg.keepFeature(fields[gnFeatureClass], fields[gnFeatureCode]) {
+			continue
+		}
+
+		latStr := fields[gnLatitude]
+		lonStr := fields[gnLongitude]
+
+		lat, errLat := strconv.ParseFloat(latStr, 64)
+		lon, errLon := strconv.ParseFloat(lonStr, 64)
+
+		if errLat != nil || errLon != nil || !validCoordinate(lat, lon) {
+			if cfg.strict {
+				return nil, fmt.Errorf("geodecode: GeoNames row %d has invalid coordinates: lat='%s', lon='%s'", row, latStr, lonStr)
+			}
+			if rg.verbose {
+				rg.log(slog.LevelWarn, "geodecode: skipping row with invalid coordinates",
+					"row", row, "lat", latStr, "lon", lonStr, "error", errors.Join(errLat, errLon))
+			}
+			continue
+		}
+
+		id, _ := strconv.Atoi(fields[gnGeonameID])
+		population, _ := strconv.Atoi(fields[gnPopulation])
+		loadedLocations = append(loadedLocations, strs.location(Location{
+			GeonameID:  id,
+			Lat:        lat,
+			Lon:        lon,
+			City:       fields[gnName],
+			Admin1:     fields[gnAdmin1Code],
+			Admin2:     fields[gnAdmin2Code],
+			CC:         fields[gnCountryCode],
+			Timezone:   fields[gnTimezone],
+			Elevation:  parseElevation(fields[gnElevation], fields[gnDEM]),
+			Population: population,
+		}))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("geodecode: reading GeoNames data: %w", err)
+	}
+
+	if len(loadedLocations) == 0 {
+		return nil, errors.New("geodecode: no valid coordinates loaded")
+	}
+	if rg.verbose {
+		rg.log(slog.LevelInfo, "geodecode: parsed GeoNames data", "locations", len(loadedLocations))
+	}
+	return loadedLocations, nil
+}
+
+// splitFields splits the tab-separated line into fields and returns the
+// number of fields it has. Fields beyond len(fields) are counted but not
+// stored.
+func splitFields(line string, fields []string) int {
+	n := 0
+	for {
+		i := strings.IndexByte(line, '\t')
+		if i < 0 {
+			break
+		}
+		if n < len(fields) {
+			fields[n] = line[:i]
+		}
+		n++
+		line = line[i+1:]
+	}
+	if n < len(fields) {
+		fields[n] = line
+	}
+	return n + 1
+}
+
+// demNoData is the value GeoNames uses in the dem column where no digital
+// elevation model data is available.
+const demNoData = -9999
+
+// parseElevation returns the elevation in meters from the GeoNames elevation
+// column, falling back to the digital elevation model column. It returns 0 if
+// neither holds a usable value.
+func parseElevation(elevation, dem string) int {
+	if v, err := strconv.Atoi(elevation); err == nil {
+		return v
+	}
+	if v, err := strconv.Atoi(dem); err == nil && v != demNoData {
+		return v
+	}
+	return 0
+}
+
+// keepFeature reports whether a GeoNames row with the given feature class and
+// code passes the feature filters.
+func (cfg *loadConfig) keepFeature(class, code string) bool {
+	if len(cfg.featureClasses) > 0 && !slices.Contains(cfg.featureClasses, class) {
+		return false
+	}
+	if len(cfg.featureCodes) == 0 {
+		return true
+	}
+	for _, pattern := range cfg.featureCodes {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(code, prefix) {
+				return true
+			}
+		} else if code == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package geodecode_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestLoadGeoNames(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(
+		filepath.Join("testdata", "cities.txt"),
+		geodecode.WithFormat(geodecode.FormatGeoNames),
+	))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	results := geocoder.Query([2]float64{34.0, -118.2})
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	want := geodecode.Location{
+		GeonameID:  5368361,
+		Lat:        34.05223,
+		Lon:        -118.24368,
+		City:       "Los Angeles",
+		Admin1:     "CA",
+		Admin2:     "037",
+		CC:         "US",
+		Country:    "United States",
+		Timezone:   "America/Los_Angeles",
+		Elevation:  89,
+		Population: 3820914,
+	}
+	if results[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, results[0])
+	}
+
+	err = geocoder.LoadFromFile(filepath.Join("testdata", "cities.txt"),
+		geodecode.WithFormat(geodecode.FormatGeoNames), geodecode.WithStrict())
+	if err == nil {
+		t.Errorf("Expected strict loading to fail on the truncated row")
+	}
+}
+
+func TestLoadGeoNamesFeatureFilter(t *testing.T) {
+	path := filepath.Join("testdata", "cities.txt")
+	germany := [2]float64{51.5, 10.6}
+
+	tests := []struct {
+		name string
+		opts []geodecode.LoadOption
+		want string
+	}{
+		{"no filter", nil, "Germany"},
+		{"populated places", []geodecode.LoadOption{geodecode.WithFeatureClasses("P")}, "Hamburg"},
+		{"capitals", []geodecode.LoadOption{geodecode.WithFeatureCodes("PPLC")}, "Berlin"},
+		{"admin seats", []geodecode.LoadOption{geodecode.WithFeatureClasses("P"), geodecode.WithFeatureCodes("PPLA*")}, "Hamburg"},
+		{"exact code", []geodecode.LoadOption{geodecode.WithFeatureCodes("PPLA2")}, "Los Angeles"},
+	}
+	for _, tt := range tests {
+		geocoder, err := geodecode.New()
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		opts := append([]geodecode.LoadOption{geodecode.WithFormat(geodecode.FormatGeoNames)}, tt.opts...)
+		if err := geocoder.LoadFromFile(path, opts...); err != nil {
+			t.Fatalf("%s: LoadFromFile: %v", tt.name, err)
+		}
+		results := geocoder.Query(germany)
+		if len(results) != 1 || results[0].City != tt.want {
+			t.Errorf("%s: Expected %q, got %+v", tt.name, tt.want, results)
+		}
+	}
+}
+
+func TestLoadGeoNamesElevation(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(
+		filepath.Join("testdata", "cities.txt"),
+		geodecode.WithFormat(geodecode.FormatGeoNames),
+	))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// Berlin has an explicit elevation, Munich only a DEM value.
+	tests := []struct {
+		coord [2]float64
+		want  int
+	}{
+		{[2]float64{52.5, 13.4}, 74},
+		{[2]float64{48.1, 11.6}, 524},
+	}
+	for _, tt := range tests {
+		if got := geocoder.Query(tt.coord)[0].Elevation; got != tt.want {
+			t.Errorf("Query(%v).Elevation = %d, want %d", tt.coord, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ module github.com/sdwillbrand/GeoDecode
 
 go 1.24.4
 
-require gonum.org/v1/gonum v0.16.0
-
-require github.com/biter777/countries v1.7.5
+require (
+	github.com/biter777/countries v1.7.5
+	github.com/golang/geo v0.0.0-20230421003525-6adc56603217
+)
@@ -0,0 +1,196 @@
+package geodecode
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// Index selects the spatial index used to search for the nearest location.
+type Index int
+
+const (
+	// KDTreeIndex searches a KD-Tree over the locations. It is the default
+	// and performs well on any distribution of locations.
+	KDTreeIndex Index = iota
+	// GridIndex buckets the locations into cells of one degree of latitude
+	// and longitude, and searches the cells around the query. For city
+	// datasets, where most cells hold few locations, lookups touch only a
+	// handful of cells and are faster than KD-Tree searches, and the index is
+	// quicker to build. Queries far from any location, such as in the middle
+	// of an ocean, have to scan more cells.
+	GridIndex
+	// VPTreeIndex searches a vantage-point tree, which partitions the
+	// locations by their distance from one another under the metric itself.
+	// With MetricHaversine it works with great-circle distances directly,
+	// computed with the haversine formula, rather than with points converted
+	// to three dimensions.
+	VPTreeIndex
+)
+
+// String returns the name of i.
+func (i Index) String() string {
+	switch i {
+	case KDTreeIndex:
+		return "kdtree"
+	case GridIndex:
+		return "grid"
+	case VPTreeIndex:
+		return "vptree"
+	default:
+		return fmt.Sprintf("Index(%d)", int(i))
+	}
+}
+
+// Dimensions of the grid in cells.
+const (
+	gridRows = 180
+	gridCols = 360
+)
+
+// gridIndex is the spatial index selected with GridIndex. The points of each
+// cell are stored contiguously, in row-major cell order.
+type gridIndex struct {
+	metric Metric
+	start  []int32      // Position in points of the first point of each cell, plus the total
+	points [][3]float64 // Coordinates as returned by indexPoint
+	index  []int32      // Location index of each point
+}
+
+// gridCell returns the row and column of the cell holding lat and lon.
+func gridCell(lat, lon float64) (row, col int) {
+	row = min(max(int(math.Floor(lat+90)), 0), gridRows-1)
+	col = min(max(int(math.Floor(lon+180)), 0), gridCols-1)
+	return row, col
+}
+
+// newGridIndex builds a grid index over locs for metric.
+func newGridIndex(locs []Location, metric Metric) *gridIndex {
+	g := &gridIndex{
+		metric: metric,
+		start:  make([]int32, gridRows*gridCols+1),
+		points: make([][3]float64, len(locs)),
+		index:  make([]int32, len(locs)),
+	}
+	cells := make([]int32, len(locs))
+	for i, loc := range locs {
+		row, col := gridCell(loc.Lat, loc.Lon)
+		cells[i] = int32(row*gridCols + col)
+		g.start[cells[i]+1]++
+	}
+	for c := 1; c < len(g.start); c++ {
+		g.start[c] += g.start[c-1]
+	}
+	next := append([]int32(nil), g.start[:len(g.start)-1]...)
+	for i, loc := range locs {
+		pos := next[cells[i]]
+		next[cells[i]]++
+		g.points[pos] = indexPoint(metric, loc.Lat, loc.Lon)
+		g.index[pos] = int32(i)
+	}
+	return g
+}
+
+// nearest implements spatialIndex. trace counts the cells entered and the
+// running best matches.
+func (g *gridIndex) nearest(coord [2]float64, trace *QueryTrace) (int, float64) {
+	q, w := indexPoint(g.metric, coord[0], coord[1]), indexWeights(g.metric, coord[0])
+	best, bestDist := -1, math.Inf(1)
+	visit := func(row, col int) {
+		if trace != nil {
+			trace.NodesVisited++
+		}
+		cell := row*gridCols + col
+		for p := g.start[cell]; p < g.start[cell+1]; p++ {
+			d := indexDist(w, q, g.points[p])
+			if i := int(g.index[p]); d < bestDist || (d == bestDist && i < best) {
+				if trace != nil && d < bestDist {
+					trace.Candidates++
+				}
+				best, bestDist = i, d
+			}
+		}
+	}
+
+	// Find a first match in rings of cells of growing size around the
+	// query's cell.
+	row0, col0 := gridCell(coord[0], coord[1])
+	radius := 0
+	for ; best < 0 && radius < gridCols; radius++ {
+		for row := row0 - radius; row <= row0+radius; row++ {
+			if row < 0 || row >= gridRows {
+				continue
+			}
+			step := 2 * radius // Only the first and last column inside the ring
+			if row == row0-radius || row == row0+radius || radius == 0 {
+				step = 1
+			}
+			for col := col0 - radius; col <= col0+radius; col += step {
+				if col >= 0 && col < gridCols {
+					visit(row, col)
+				}
+			}
+		}
+	}
+	if best < 0 {
+		return -1, bestDist
+	}
+
+	// Any closer location lies in the box the match's distance spans around
+	// the query. Scan the cells of the box the rings did not cover.
+	scanned := radius - 1
+	minRow, maxRow, minCol, maxCol := g.searchBox(coord, bestDist, w)
+	for row := minRow; row <= maxRow; row++ {
+		for k := minCol; k <= maxCol; k++ {
+			if k >= 0 && k < gridCols && abs(row-row0) <= scanned && abs(k-col0) <= scanned {
+				continue
+			}
+			visit(row, ((k%gridCols)+gridCols)%gridCols)
+		}
+	}
+	return best, bestDist
+}
+
+// searchBox returns the rows and columns of the cells that may hold locations
+// within the squared distance distSq of coord, weighted by w as returned by
+// indexWeights. Columns outside [0, gridCols) wrap around the antimeridian.
+func (g *gridIndex) searchBox(coord [2]float64, distSq float64, w [3]float64) (minRow, maxRow, minCol, maxCol int) {
+	const margin = 1e-9 // Guards against rounding at the edges of the box
+	lat, lon := coord[0], coord[1]
+
+	if g.metric != MetricHaversine {
+		d := math.Sqrt(distSq)
+		dLon := d/math.Sqrt(w[1]) + margin
+		minRow, minCol = gridCell(lat-d-margin, lon-dLon)
+		maxRow, maxCol = gridCell(lat+d+margin, lon+dLon)
+		return minRow, maxRow, minCol, maxCol
+	}
+
+	angle := 2 * math.Asin(math.Min(1, math.Sqrt(distSq)/2))
+	d := angle*180/math.Pi + margin
+	minRow, _ = gridCell(lat-d, 0)
+	maxRow, _ = gridCell(lat+d, 0)
+	if lat+d >= 90 || lat-d <= -90 {
+		return minRow, maxRow, 0, gridCols - 1 // The box covers a pole
+	}
+	dLon := math.Asin(math.Min(1, math.Sin(angle)/math.Cos(lat*math.Pi/180)))*180/math.Pi + margin
+	minCol = int(math.Floor(lon - dLon + 180))
+	maxCol = int(math.Floor(lon + dLon + 180))
+	if maxCol-minCol >= gridCols {
+		return minRow, maxRow, 0, gridCols - 1
+	}
+	return minRow, maxRow, minCol, maxCol
+}
+
+// bytes implements spatialIndex.
+func (g *gridIndex) bytes() int64 {
+	return int64(cap(g.start))*4 + int64(cap(g.points))*int64(unsafe.Sizeof([3]float64{})) + int64(cap(g.index))*4
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,90 @@
+package geodecode_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// randomCoords returns n reproducible coordinates spread over the globe,
+// including the poles and the antimeridian.
+func randomCoords(n int) [][2]float64 {
+	r := rand.New(rand.NewPCG(1, 2))
+	coords := [][2]float64{{90, 0}, {-90, 0}, {0, 180}, {0, -180}, {-16.5, 179.99}, {71.2, -179.9}}
+	for len(coords) < n {
+		coords = append(coords, [2]float64{r.Float64()*180 - 90, r.Float64()*360 - 180})
+	}
+	return coords
+}
+
+func TestWithIndex(t *testing.T) {
+	if _, err := geodecode.New(geodecode.WithIndex(geodecode.Index(7))); err == nil {
+		t.Errorf("Expected an error for an unknown index")
+	}
+
+	for _, metric := range []geodecode.Metric{geodecode.MetricEuclidean, geodecode.MetricHaversine, geodecode.MetricEquirectangular} {
+		tree, _ := geodecode.New(geodecode.WithMetric(metric))
+		grid, _ := geodecode.New(geodecode.WithMetric(metric), geodecode.WithIndex(geodecode.GridIndex))
+		for _, coord := range randomCoords(2000) {
+			want, wantTrace := tree.QueryDebug(coord)
+			got, gotTrace := grid.QueryDebug(coord)
+			// Of several equally close locations, both indexes return the
+			// one loaded first.
+			if got != want || gotTrace.Distance != wantTrace.Distance {
+				t.Errorf("%s: grid found %s (%v) for %v, KD-Tree %s (%v)",
+					metric, got.City, gotTrace.Distance, coord, want.City, wantTrace.Distance)
+			}
+		}
+	}
+
+	grid, _ := geodecode.New(geodecode.WithIndex(geodecode.GridIndex))
+	if got := grid.Query([2]float64{0, 0}); len(got) != 1 || got[0].City != "Takoradi" {
+		t.Errorf("Expected Takoradi for (0,0), got %+v", got)
+	}
+	if stats := grid.Stats(); stats.TreeDepth != 0 {
+		t.Errorf("Expected no KD-Tree with GridIndex, got depth %d", stats.TreeDepth)
+	}
+}
+
+func BenchmarkQuery(b *testing.B) {
+	// Queries near cities, as in typical use, and anywhere on the globe,
+	// mostly far out at sea.
+	var nearCities [][2]float64
+	r := rand.New(rand.NewPCG(3, 4))
+	geocoder, _ := geodecode.New()
+	for loc := range geocoder.Locations() {
+		if r.IntN(100) == 0 {
+			nearCities = append(nearCities, [2]float64{loc.Lat + r.Float64() - 0.5, loc.Lon + r.Float64() - 0.5})
+		}
+	}
+	queries := map[string][][2]float64{"cities": nearCities, "globe": randomCoords(1024)}
+
+	for _, index := range []geodecode.Index{geodecode.KDTreeIndex, geodecode.GridIndex, geodecode.VPTreeIndex} {
+		for _, metric := range []geodecode.Metric{geodecode.MetricEuclidean, geodecode.MetricHaversine} {
+			geocoder, _ := geodecode.New(geodecode.WithIndex(index), geodecode.WithMetric(metric))
+			geocoder.Load()
+			for _, name := range []string{"cities", "globe"} {
+				coords := queries[name]
+				b.Run(index.String()+"/"+metric.String()+"/"+name, func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						geocoder.Query(coords[i%len(coords)])
+					}
+				})
+			}
+		}
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	for _, index := range []geodecode.Index{geodecode.KDTreeIndex, geodecode.GridIndex, geodecode.VPTreeIndex} {
+		b.Run(index.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				geocoder, _ := geodecode.New(geodecode.WithIndex(index))
+				if err := geocoder.Load(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package geodecode
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// indexVersion identifies the layout of files written by SaveIndex. It must be
+// incremented whenever indexFile changes incompatibly. Files of version 1,
+// which stored the KD-Tree as linked nodes, are still read; their tree is
+// rebuilt from the locations.
+const indexVersion = 2
+
+// indexFile is the serialized form of a loaded dataset and its KD-Tree.
+type indexFile struct {
+	Version   int
+	Metric    Metric // Metric the tree was built for; absent in older files, meaning MetricEuclidean
+	Index     Index  // Spatial index of the dataset; absent in older files, meaning KDTreeIndex
+	Float32   bool   // Coordinates were stored as float32; the KD-Tree is then not stored
+	LeafSize  int    // Maximum number of points in a KD-Tree leaf; absent in older files, meaning 1
+	Locations []Location
+	Names     []indexName
+	Order     []int32 // Location index of each KD-Tree node, in the tree's layout
+	Planes    []uint8 // Splitting plane of each KD-Tree node
+}
+
+// indexName is a serialized entry of the localized name index.
+type indexName struct {
+	ID   int
+	Lang string
+	Name string
+}
+
+// SaveIndex writes the loaded dataset, including the KD-Tree layout, to the
+// file at path. The file can be loaded with LoadIndex, which skips CSV parsing
+// and tree construction entirely; this makes startup much faster in
+// short-lived processes. The dataset is loaded first if necessary.
+func (rg *RGeocoder) SaveIndex(path string) error {
+	ds := rg.loadAllRegions(rg.current())
+	if ds.locations.len() == 0 {
+		return ds.notLoaded()
+	}
+
+	idx := indexFile{
+		Version:   indexVersion,
+		Metric:    ds.metric,
+		Index:     ds.index,
+		Float32:   ds.single,
+		LeafSize:  ds.leaf(),
+		Locations: ds.locations.slice(),
+	}
+	for key, name := range ds.names {
+		idx.Names = append(idx.Names, indexName{ID: key.id, Lang: key.lang, Name: name})
+	}
+	if ds.tree != nil {
+		idx.Order = make([]int32, len(ds.tree.nodes))
+		idx.Planes = make([]uint8, len(ds.tree.nodes))
+		for i, n := range ds.tree.nodes {
+			idx.Order[i], idx.Planes[i] = n.index, n.plane
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("geodecode: creating index file: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	if err := gob.NewEncoder(w).Encode(&idx); err != nil {
+		file.Close()
+		return fmt.Errorf("geodecode: encoding index: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("geodecode: writing index: %w", err)
+	}
+	return file.Close()
+}
+
+// LoadIndex replaces the geocoder's dataset with an index previously written
+// by SaveIndex. Reload reads the index file again.
+func (rg *RGeocoder) LoadIndex(path string) error {
+	return rg.loadFrom(source{kind: sourceIndex, location: path})
+}
+
+// readIndexFile reads the index stored at path.
+func readIndexFile(path string) (*dataset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: opening index file: %w", err)
+	}
+	defer file.Close()
+	return readIndex(bufio.NewReader(file))
+}
+
+// readIndex decodes an index written by SaveIndex and rebuilds the KD-Tree
+// from the stored layout.
+func readIndex(r io.Reader) (*dataset, error) {
+	var idx indexFile
+	if err := gob.NewDecoder(r).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("geodecode: decoding index: %w", err)
+	}
+	if idx.Version != indexVersion && idx.Version != 1 {
+		return nil, fmt.Errorf("geodecode: unsupported index version %d, want %d", idx.Version, indexVersion)
+	}
+	if len(idx.Locations) == 0 {
+		return nil, errors.New("geodecode: index contains no locations")
+	}
+
+	var names map[nameKey]string
+	if len(idx.Names) > 0 {
+		names = make(map[nameKey]string, len(idx.Names))
+		for _, n := range idx.Names {
+			names[nameKey{id: n.ID, lang: n.Lang}] = n.Name
+		}
+	}
+
+	if idx.Metric != MetricEuclidean && idx.Metric != MetricHaversine && idx.Metric != MetricEquirectangular {
+		return nil, fmt.Errorf("geodecode: index uses unknown metric %d", idx.Metric)
+	}
+	if idx.LeafSize < 0 {
+		return nil, fmt.Errorf("geodecode: index has invalid leaf size %d", idx.LeafSize)
+	}
+	spec := indexSpec{metric: idx.Metric, index: idx.Index, single: idx.Float32, leafSize: max(idx.LeafSize, 1)}
+	if idx.Index != KDTreeIndex || idx.Version == 1 || idx.Float32 {
+		// Only the KD-Tree of float64 coordinates is stored; other indexes
+		// are rebuilt, and so is the tree of version 1 files.
+		return newDataset(idx.Locations, names, spec), nil
+	}
+
+	ds := &dataset{locations: newLocationTable(idx.Locations, false), names: names, indexSpec: spec}
+	if len(idx.Order) == 0 {
+		if len(idx.Locations) != 1 {
+			return nil, errors.New("geodecode: index is missing its KD-Tree")
+		}
+		return ds, nil
+	}
+	if len(idx.Order) != len(idx.Locations) || len(idx.Planes) != len(idx.Order) {
+		return nil, errors.New("geodecode: index KD-Tree does not match its locations")
+	}
+
+	tree := &kdTree[float64]{metric: idx.Metric, leafSize: spec.leafSize, nodes: make([]kdNode[float64], len(idx.Order))}
+	seen := make([]bool, len(idx.Locations))
+	for i, index := range idx.Order {
+		if index < 0 || int(index) >= len(idx.Locations) || seen[index] || int(idx.Planes[i]) >= idx.Metric.dims() {
+			return nil, fmt.Errorf("geodecode: index node %d is corrupt", i)
+		}
+		seen[index] = true
+		loc := idx.Locations[index]
+		tree.nodes[i] = kdNode[float64]{point: indexPoint(idx.Metric, loc.Lat, loc.Lon), index: index, plane: idx.Planes[i]}
+	}
+	ds.tree = tree
+	return ds, nil
+}
@@ -0,0 +1,70 @@
+package geodecode_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestSaveLoadIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.idx")
+
+	source := geodecode.GetRGeocoder(false)
+	if err := source.SaveIndex(path); err != nil {
+		t.Fatalf("SaveIndex: %v", err)
+	}
+
+	loaded, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := loaded.LoadIndex(path); err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+
+	coords := [][2]float64{
+		{52.5200, 13.4050},
+		{40.7128, -74.0060},
+		{-33.8688, 151.2093},
+		{0, 0},
+		{64.73424, 177.5103},
+	}
+	want := source.Query(coords...)
+	got := loaded.Query(coords...)
+	for i := range coords {
+		if got[i] != want[i] {
+			t.Errorf("Query(%v) from index = %+v, want %+v", coords[i], got[i], want[i])
+		}
+	}
+
+	if err := loaded.LoadIndex(filepath.Join("testdata", "cities.csv")); err == nil {
+		t.Errorf("Expected an error loading a CSV file as index")
+	}
+}
+
+func TestSaveLoadIndexLocalizedNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.idx")
+
+	source, err := geodecode.New(geodecode.WithDataset(filepath.Join("testdata", "cities.txt"),
+		geodecode.WithFormat(geodecode.FormatGeoNames),
+		geodecode.WithAlternateNames(openFixture(t, "alternateNamesV2.txt"), "de"),
+	))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := source.SaveIndex(path); err != nil {
+		t.Fatalf("SaveIndex: %v", err)
+	}
+
+	loaded, err := geodecode.New(geodecode.WithLanguage("de"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := loaded.LoadIndex(path); err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	if got := loaded.Query([2]float64{48.1, 11.6})[0].City; got != "München" {
+		t.Errorf("Expected localized name München from index, got %q", got)
+	}
+}
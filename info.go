@@ -0,0 +1,210 @@
+package geodecode
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"iter"
+	"math"
+	"time"
+)
+
+// DatasetInfo describes the dataset a geocoder has loaded, so operators can
+// verify which gazetteer version a running service uses.
+type DatasetInfo struct {
+	Records      int            // Number of locations, including those added with AddLocations.
+	Added        int            // Number of locations added with AddLocations.
+	Source       string         // Where the data came from: "embedded", "file", "url", "index" or "reader".
+	Location     string         // File path or URL, or the name of the embedded dataset.
+	LoadedAt     time.Time      // When the dataset was loaded; zero if loading failed.
+	LoadDuration time.Duration  // Time spent reading the data and building the KD-Tree.
+	Countries    map[string]int // Number of locations per country code.
+	Hash         string         // Hex-encoded SHA-256 of the location data.
+}
+
+// DatasetInfo returns metadata about the geocoder's current dataset, loading
+// it first if necessary. The hash covers the loaded locations rather than the
+// source file, so it identifies the same data regardless of compression or
+// file format, and changes when locations are added.
+//
+// Example usage:
+//
+//	info := geocoder.DatasetInfo()
+//	log.Printf("geocoder: %d records from %s %s, sha256 %s", info.Records, info.Source, info.Location, info.Hash)
+func (rg *RGeocoder) DatasetInfo() DatasetInfo {
+	ds := rg.current()
+
+	info := DatasetInfo{
+		Records:      ds.locations.len(),
+		Added:        ds.added,
+		Source:       ds.src.kind.String(),
+		Location:     ds.src.location,
+		LoadedAt:     ds.loadedAt,
+		LoadDuration: ds.loadDuration,
+		Countries:    make(map[string]int),
+		Hash:         ds.contentHash(),
+	}
+	if ds.src.kind == sourceEmbedded {
+		info.Location = embeddedDataset
+	}
+	for _, loc := range ds.locations.all() {
+		info.Countries[loc.CC]++
+	}
+	return info
+}
+
+// DatasetHash returns the Hash of DatasetInfo for the geocoder's current
+// dataset, loading it first if necessary. The hash is computed once per
+// dataset, so unlike DatasetInfo, DatasetHash is cheap enough to call per
+// query, for example to tag cached results with the data they came from.
+func (rg *RGeocoder) DatasetHash() string {
+	return rg.current().contentHash()
+}
+
+// Stats summarizes the coverage of a geocoder's dataset.
+type Stats struct {
+	Locations int                       // Number of locations.
+	Countries map[string]int            // Number of locations per country code.
+	Admin1    map[string]map[string]int // Number of locations per admin1 name, by country code.
+	Bounds    Bounds                    // Smallest box containing every location.
+	TreeDepth int                       // Depth of the KD-Tree or VP-Tree; 0 if no tree was built, as with GridIndex.
+
+	// InternedBytes is the text the dataset does not hold because locations
+	// share identical strings, such as admin and country names, instead of
+	// keeping a copy each.
+	InternedBytes int64
+}
+
+// Bounds is a latitude and longitude bounding box. It does not wrap around
+// the antimeridian, so datasets spanning it cover nearly all longitudes.
+type Bounds struct {
+	MinLat, MinLon float64
+	MaxLat, MaxLon float64
+}
+
+// Stats returns statistics about the geocoder's dataset, loading it first if
+// necessary, for example to build coverage dashboards. It walks the whole
+// dataset, so it is not meant to be called per query.
+func (rg *RGeocoder) Stats() Stats {
+	ds := rg.current()
+
+	stats := Stats{
+		Locations: ds.locations.len(),
+		Countries: make(map[string]int),
+		Admin1:    make(map[string]map[string]int),
+	}
+	for i, loc := range ds.locations.all() {
+		stats.Countries[loc.CC]++
+		admin1 := stats.Admin1[loc.CC]
+		if admin1 == nil {
+			admin1 = make(map[string]int)
+			stats.Admin1[loc.CC] = admin1
+		}
+		admin1[loc.Admin1]++
+
+		b := &stats.Bounds
+		if i == 0 {
+			*b = Bounds{MinLat: loc.Lat, MinLon: loc.Lon, MaxLat: loc.Lat, MaxLon: loc.Lon}
+			continue
+		}
+		b.MinLat, b.MaxLat = math.Min(b.MinLat, loc.Lat), math.Max(b.MaxLat, loc.Lat)
+		b.MinLon, b.MaxLon = math.Min(b.MinLon, loc.Lon), math.Max(b.MaxLon, loc.Lon)
+	}
+	switch {
+	case ds.tree != nil:
+		stats.TreeDepth = ds.tree.depth()
+	case ds.tree32 != nil:
+		stats.TreeDepth = ds.tree32.depth()
+	case ds.vp != nil:
+		stats.TreeDepth = ds.vp.depth()
+	}
+	_, stats.InternedBytes = ds.locations.stringBytes()
+	return stats
+}
+
+// Locations returns an iterator over the geocoder's locations, loading the
+// dataset first if necessary. The locations are yielded as loaded, without
+// the localized names and country details that queries add. The iterator
+// covers the dataset current when Locations is called, even if it is
+// replaced while iterating.
+//
+// Example usage:
+//
+//	for loc := range geocoder.Locations() {
+//	    fmt.Println(loc.City, loc.CC)
+//	}
+func (rg *RGeocoder) Locations() iter.Seq[Location] {
+	ds := rg.loadAllRegions(rg.current())
+	return func(yield func(Location) bool) {
+		for _, loc := range ds.locations.all() {
+			if !yield(loc) {
+				return
+			}
+		}
+	}
+}
+
+// String returns the name DatasetInfo uses for k.
+func (k sourceKind) String() string {
+	switch k {
+	case sourceFile:
+		return "file"
+	case sourceURL:
+		return "url"
+	case sourceIndex:
+		return "index"
+	case sourceReader:
+		return "reader"
+	default:
+		return "embedded"
+	}
+}
+
+// setLoaded records that ds was loaded from src, starting at start.
+func (ds *dataset) setLoaded(src source, start time.Time) {
+	ds.src = src
+	ds.loadedAt = time.Now()
+	ds.loadDuration = ds.loadedAt.Sub(start)
+}
+
+// contentHash returns the hex-encoded SHA-256 of the dataset's locations.
+// It is computed on first use and cached.
+func (ds *dataset) contentHash() string {
+	ds.hashOnce.Do(func() {
+		h := sha256.New()
+		for _, loc := range ds.locations.all() {
+			hashLocation(h, loc)
+		}
+		ds.hash = hex.EncodeToString(h.Sum(nil))
+	})
+	return ds.hash
+}
+
+// hashLocation writes every field of loc to h. Strings are length-prefixed
+// so that adjacent fields cannot run into each other.
+func hashLocation(h hash.Hash, loc Location) {
+	var buf [8]byte
+	num := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	str := func(s string) {
+		num(uint64(len(s)))
+		h.Write([]byte(s))
+	}
+
+	num(uint64(loc.GeonameID))
+	num(math.Float64bits(loc.Lat))
+	num(math.Float64bits(loc.Lon))
+	str(loc.City)
+	str(loc.Admin1)
+	str(loc.Admin1Code)
+	str(loc.Admin2)
+	str(loc.Admin2Name)
+	str(loc.CC)
+	str(loc.Country)
+	str(loc.Timezone)
+	num(uint64(loc.Elevation))
+	num(uint64(loc.Population))
+}
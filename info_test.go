@@ -0,0 +1,141 @@
+package geodecode_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"unsafe"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestDatasetInfo(t *testing.T) {
+	path := filepath.Join("testdata", "cities.csv")
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	info := geocoder.DatasetInfo()
+	if info.Source != "file" || info.Location != path {
+		t.Errorf("Expected source file %s, got %s %s", path, info.Source, info.Location)
+	}
+	if info.Records != 4 || info.Countries["DE"] != 2 || info.Countries["US"] != 1 {
+		t.Errorf("Expected 4 records (2 DE, 1 US), got %d %v", info.Records, info.Countries)
+	}
+	if info.LoadedAt.IsZero() || len(info.Hash) != 64 {
+		t.Errorf("Expected load time and hash, got %v %q", info.LoadedAt, info.Hash)
+	}
+	if got := geocoder.DatasetHash(); got != info.Hash {
+		t.Errorf("Expected DatasetHash %s, got %s", info.Hash, got)
+	}
+
+	// The same data read from a reader has the same hash.
+	other, _ := geodecode.New()
+	if err := other.LoadFromReader(openFixture(t, "cities.csv")); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	if got := other.DatasetInfo(); got.Hash != info.Hash || got.Source != "reader" {
+		t.Errorf("Expected reader source with hash %s, got %s %s", info.Hash, got.Source, got.Hash)
+	}
+
+	if err := geocoder.AddLocations(geodecode.Location{Lat: 1, Lon: 1, City: "Depot", CC: "GH"}); err != nil {
+		t.Fatalf("AddLocations: %v", err)
+	}
+	added := geocoder.DatasetInfo()
+	if added.Records != 5 || added.Added != 1 || added.Hash == info.Hash || !added.LoadedAt.Equal(info.LoadedAt) {
+		t.Errorf("Unexpected info after AddLocations: %+v", added)
+	}
+	if got := geocoder.DatasetHash(); got != added.Hash {
+		t.Errorf("Expected DatasetHash %s after AddLocations, got %s", added.Hash, got)
+	}
+
+	failed, _ := geodecode.New(geodecode.WithDataset(filepath.Join(t.TempDir(), "missing.csv")))
+	if got := failed.DatasetInfo(); got.Records != 0 || !got.LoadedAt.IsZero() || !strings.HasSuffix(got.Location, "missing.csv") {
+		t.Errorf("Unexpected info for a failed load: %+v", got)
+	}
+}
+
+func TestLocations(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(filepath.Join("testdata", "cities.csv")))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var cities []string
+	for loc := range geocoder.Locations() {
+		cities = append(cities, loc.City)
+	}
+	if len(cities) != 4 {
+		t.Errorf("Expected 4 locations, got %v", cities)
+	}
+
+	for range geocoder.Locations() {
+		break // Stopping early must not panic
+	}
+}
+
+func TestStats(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithLeafSize(1))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+
+	stats := geocoder.Stats()
+	if stats.Locations != 3 || stats.Countries["NO"] != 1 || stats.Admin1["FJ"][""] != 1 {
+		t.Errorf("Unexpected counts: %+v", stats)
+	}
+	want := geodecode.Bounds{MinLat: 0, MinLon: -179.9, MaxLat: 60, MaxLon: 178}
+	if stats.Bounds != want {
+		t.Errorf("Expected bounds %+v, got %+v", want, stats.Bounds)
+	}
+	if stats.TreeDepth != 2 {
+		t.Errorf("Expected a tree of depth 2 for 3 locations, got %d", stats.TreeDepth)
+	}
+}
+
+func TestStatsInterning(t *testing.T) {
+	data := "lat,lon,city,admin1,admin2,cc\n" +
+		"48.13743,11.57549,Munich,Bavaria,,DE\n" +
+		"49.45421,11.07752,Nuremberg,Bavaria,,DE\n" +
+		"49.79391,9.95121,Wurzburg,Bavaria,,DE\n"
+
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	// Two of the three copies of "Bavaria" and "DE" are shared.
+	if got, want := geocoder.Stats().InternedBytes, int64(2*len("Bavaria")+2*len("DE")); got != want {
+		t.Errorf("Expected %d interned bytes, got %d", want, got)
+	}
+}
+
+func TestMemoryFootprint(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithDataset(filepath.Join("testdata", "cities.csv")))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	fp := geocoder.MemoryFootprint()
+	// Locations are stored column by column, in less memory than []Location.
+	if full := 4 * int64(unsafe.Sizeof(geodecode.Location{})); fp.Locations == 0 || fp.Locations >= full {
+		t.Errorf("Expected less than %d bytes for 4 locations, got %d", full, fp.Locations)
+	}
+	if fp.Strings == 0 || fp.Tree == 0 || fp.Names != 0 {
+		t.Errorf("Expected strings and tree but no names, got %+v", fp)
+	}
+	if fp.Total != fp.Locations+fp.Strings+fp.Tree+fp.Names {
+		t.Errorf("Expected Total to be the sum of the parts, got %+v", fp)
+	}
+
+	empty, _ := geodecode.New(geodecode.WithDataset(filepath.Join(t.TempDir(), "missing.csv")), geodecode.WithLogger(nil))
+	if got := empty.MemoryFootprint(); got != (geodecode.Footprint{}) {
+		t.Errorf("Expected an empty footprint when loading failed, got %+v", got)
+	}
+}
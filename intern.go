@@ -0,0 +1,38 @@
+package geodecode
+
+import "strings"
+
+// stringInterner hands out a single shared copy of each distinct string.
+// Thousands of locations carry the same admin, country and timezone names,
+// so sharing them instead of keeping one copy per row saves a lot of memory
+// on large datasets such as allCountries.
+type stringInterner map[string]string
+
+// intern returns the shared copy of s, making one on first use. The copy
+// does not reference the memory s was sliced from.
+func (in stringInterner) intern(s string) string {
+	if s == "" {
+		return ""
+	}
+	if shared, ok := in[s]; ok {
+		return shared
+	}
+	s = strings.Clone(s)
+	in[s] = s
+	return s
+}
+
+// location returns loc with its admin, country and timezone strings
+// interned. The city name is mostly unique and is copied instead, so that
+// loc no longer keeps the row it was parsed from alive.
+func (in stringInterner) location(loc Location) Location {
+	loc.City = strings.Clone(loc.City)
+	loc.Admin1 = in.intern(loc.Admin1)
+	loc.Admin1Code = in.intern(loc.Admin1Code)
+	loc.Admin2 = in.intern(loc.Admin2)
+	loc.Admin2Name = in.intern(loc.Admin2Name)
+	loc.CC = in.intern(loc.CC)
+	loc.Country = in.intern(loc.Country)
+	loc.Timezone = in.intern(loc.Timezone)
+	return loc
+}
@@ -0,0 +1,294 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// decodeHybrid decodes n values of bitWidth bits from the RLE/bit-packing
+// hybrid encoding used for definition levels and dictionary indices.
+func decodeHybrid(data []byte, bitWidth, n int) ([]uint32, error) {
+	if bitWidth < 0 || bitWidth > 32 {
+		return nil, fmt.Errorf("parquet: invalid bit width %d", bitWidth)
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("parquet: invalid value count %d", n)
+	}
+	if bitWidth == 0 {
+		return make([]uint32, n), nil // Every value is zero
+	}
+	out := make([]uint32, 0, min(n, 1<<16))
+	byteWidth := (bitWidth + 7) / 8
+	for len(out) < n {
+		header, k := binary.Uvarint(data)
+		if k <= 0 {
+			return nil, errors.New("parquet: truncated RLE data")
+		}
+		data = data[k:]
+
+		if header&1 == 0 {
+			// RLE run: one value repeated header>>1 times.
+			count := header >> 1
+			if len(data) < byteWidth {
+				return nil, errors.New("parquet: truncated RLE run")
+			}
+			var buf [4]byte
+			copy(buf[:], data[:byteWidth])
+			v := binary.LittleEndian.Uint32(buf[:])
+			data = data[byteWidth:]
+			for ; count > 0 && len(out) < n; count-- {
+				out = append(out, v)
+			}
+			continue
+		}
+
+		// Bit-packed run: header>>1 groups of 8 values, least significant
+		// bit first.
+		size := (header >> 1) * uint64(bitWidth)
+		if size > uint64(len(data)) {
+			return nil, errors.New("parquet: truncated bit-packed run")
+		}
+		packed := data[:size]
+		data = data[size:]
+		count := int(header>>1) * 8
+		for i := 0; i < count && len(out) < n; i++ {
+			var v uint32
+			bit := i * bitWidth
+			for b := 0; b < bitWidth; b++ {
+				if packed[(bit+b)/8]&(1<<((bit+b)%8)) != 0 {
+					v |= 1 << b
+				}
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// values holds decoded values of one physical type; only the slice matching
+// the type is used.
+type values struct {
+	ints   []int64
+	floats []float64
+	strs   []string
+	bools  []bool
+}
+
+func (v *values) len(typ Type) int {
+	switch typ.kind() {
+	case kindInt:
+		return len(v.ints)
+	case kindFloat:
+		return len(v.floats)
+	case kindString:
+		return len(v.strs)
+	default:
+		return len(v.bools)
+	}
+}
+
+// appendFrom appends the value at index i of src to v.
+func (v *values) appendFrom(typ Type, src *values, i int) {
+	switch typ.kind() {
+	case kindInt:
+		v.ints = append(v.ints, src.ints[i])
+	case kindFloat:
+		v.floats = append(v.floats, src.floats[i])
+	case kindString:
+		v.strs = append(v.strs, src.strs[i])
+	default:
+		v.bools = append(v.bools, src.bools[i])
+	}
+}
+
+// appendZero appends the zero value, used for nulls.
+func (v *values) appendZero(typ Type) {
+	switch typ.kind() {
+	case kindInt:
+		v.ints = append(v.ints, 0)
+	case kindFloat:
+		v.floats = append(v.floats, 0)
+	case kindString:
+		v.strs = append(v.strs, "")
+	default:
+		v.bools = append(v.bools, false)
+	}
+}
+
+var errTruncatedValues = errors.New("parquet: truncated values")
+
+// decodePlain decodes n PLAIN-encoded values of type typ. typeLength is the
+// size of FixedLenByteArray values.
+func decodePlain(data []byte, typ Type, typeLength, n int) (*values, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("parquet: invalid value count %d", n)
+	}
+	v := &values{}
+	switch typ {
+	case Boolean:
+		if len(data)*8 < n {
+			return nil, errTruncatedValues
+		}
+		v.bools = make([]bool, n)
+		for i := range v.bools {
+			v.bools[i] = data[i/8]&(1<<(i%8)) != 0
+		}
+	case Int32:
+		if len(data) < 4*n {
+			return nil, errTruncatedValues
+		}
+		v.ints = make([]int64, n)
+		for i := range v.ints {
+			v.ints[i] = int64(int32(binary.LittleEndian.Uint32(data[4*i:])))
+		}
+	case Int64:
+		if len(data) < 8*n {
+			return nil, errTruncatedValues
+		}
+		v.ints = make([]int64, n)
+		for i := range v.ints {
+			v.ints[i] = int64(binary.LittleEndian.Uint64(data[8*i:]))
+		}
+	case Float:
+		if len(data) < 4*n {
+			return nil, errTruncatedValues
+		}
+		v.floats = make([]float64, n)
+		for i := range v.floats {
+			v.floats[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:])))
+		}
+	case Double:
+		if len(data) < 8*n {
+			return nil, errTruncatedValues
+		}
+		v.floats = make([]float64, n)
+		for i := range v.floats {
+			v.floats[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+		}
+	case ByteArray:
+		v.strs = make([]string, 0, min(n, len(data)/4))
+		for i := 0; i < n; i++ {
+			if len(data) < 4 {
+				return nil, errTruncatedValues
+			}
+			size := binary.LittleEndian.Uint32(data)
+			data = data[4:]
+			if uint64(size) > uint64(len(data)) {
+				return nil, errTruncatedValues
+			}
+			v.strs = append(v.strs, string(data[:size]))
+			data = data[size:]
+		}
+	case FixedLenByteArray:
+		if typeLength <= 0 || len(data) < typeLength*n {
+			return nil, errTruncatedValues
+		}
+		v.strs = make([]string, n)
+		for i := range v.strs {
+			v.strs[i] = string(data[i*typeLength : (i+1)*typeLength])
+		}
+	default:
+		return nil, fmt.Errorf("parquet: unsupported physical type %s", typ)
+	}
+	return v, nil
+}
+
+// decompress returns the uncompressed contents of a page compressed with
+// codec.
+func decompress(codec Codec, data []byte, size int) ([]byte, error) {
+	switch codec {
+	case Uncompressed:
+		return data, nil
+	case Snappy:
+		return snappyDecode(data)
+	case Gzip:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("parquet: reading gzip page: %w", err)
+		}
+		out := bytes.NewBuffer(make([]byte, 0, max(size, 0)))
+		if _, err := io.Copy(out, gz); err != nil {
+			return nil, fmt.Errorf("parquet: reading gzip page: %w", err)
+		}
+		return out.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("compression codec %s is not supported, only UNCOMPRESSED, SNAPPY and GZIP are", codec)
+	}
+}
+
+// snappyDecode decodes a block in the raw Snappy format, which Parquet uses
+// without the framing of the Snappy stream format.
+func snappyDecode(src []byte) ([]byte, error) {
+	errCorrupt := errors.New("parquet: corrupt snappy data")
+
+	n, k := binary.Uvarint(src)
+	if k <= 0 || n > uint64(len(src))*255 { // Snappy expands at most ~255x
+		return nil, errCorrupt
+	}
+	src = src[k:]
+	dst := make([]byte, 0, n)
+
+	for len(src) > 0 {
+		tag := src[0]
+		var length, offset int
+		switch tag & 3 {
+		case 0: // Literal
+			length = int(tag>>2) + 1
+			src = src[1:]
+			if extra := int(tag>>2) - 59; extra > 0 {
+				// Lengths of 61 and more are stored in the next 1-4 bytes.
+				if len(src) < extra {
+					return nil, errCorrupt
+				}
+				var buf [4]byte
+				copy(buf[:], src[:extra])
+				length = int(binary.LittleEndian.Uint32(buf[:])) + 1
+				src = src[extra:]
+			}
+			if length <= 0 || length > len(src) || len(dst)+length > int(n) {
+				return nil, errCorrupt
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+			continue
+		case 1: // Copy with 1-byte offset
+			if len(src) < 2 {
+				return nil, errCorrupt
+			}
+			length = 4 + int(tag>>2)&7
+			offset = int(tag&0xe0)<<3 | int(src[1])
+			src = src[2:]
+		case 2: // Copy with 2-byte offset
+			if len(src) < 3 {
+				return nil, errCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[1:]))
+			src = src[3:]
+		case 3: // Copy with 4-byte offset
+			if len(src) < 5 {
+				return nil, errCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[1:]))
+			src = src[5:]
+		}
+		if offset <= 0 || offset > len(dst) || len(dst)+length > int(n) {
+			return nil, errCorrupt
+		}
+		// Copies may overlap their own output, so copy byte by byte.
+		start := len(dst) - offset
+		for i := 0; i < length; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+	if len(dst) != int(n) {
+		return nil, errCorrupt
+	}
+	return dst, nil
+}
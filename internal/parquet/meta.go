@@ -0,0 +1,294 @@
+package parquet
+
+import "strconv"
+
+// Field ids and enumerations from parquet.thrift, limited to what the reader
+// and writer use.
+
+// Repetition types of schema elements.
+const (
+	repRequired int32 = 0
+	repOptional int32 = 1
+	repRepeated int32 = 2
+)
+
+// convertedUTF8 is the converted type of BYTE_ARRAY columns holding UTF-8
+// strings.
+const convertedUTF8 int32 = 0
+
+// Page types.
+const (
+	pageData       int32 = 0
+	pageIndex      int32 = 1
+	pageDictionary int32 = 2
+	pageDataV2     int32 = 3
+)
+
+// Encodings.
+const (
+	encPlain           int32 = 0
+	encPlainDictionary int32 = 2
+	encRLE             int32 = 3
+	encBitPacked       int32 = 4
+	encRLEDictionary   int32 = 8
+)
+
+// encodingNames holds the names of the encodings, indexed by their value.
+var encodingNames = []string{"PLAIN", "GROUP_VAR_INT", "PLAIN_DICTIONARY", "RLE", "BIT_PACKED",
+	"DELTA_BINARY_PACKED", "DELTA_LENGTH_BYTE_ARRAY", "DELTA_BYTE_ARRAY", "RLE_DICTIONARY", "BYTE_STREAM_SPLIT"}
+
+// encodingName returns the name of encoding e, as parquet.thrift has it.
+func encodingName(e int32) string {
+	if e >= 0 && int(e) < len(encodingNames) {
+		return encodingNames[e]
+	}
+	return "encoding " + strconv.Itoa(int(e))
+}
+
+// schemaElement is a node of the flattened schema tree.
+type schemaElement struct {
+	typ         Type
+	hasType     bool // Leaves have a type, groups do not
+	typeLength  int32
+	repetition  int32
+	name        string
+	numChildren int32
+}
+
+// columnMeta is the metadata of a column chunk within a row group.
+type columnMeta struct {
+	typ             Type
+	path            []string
+	codec           Codec
+	numValues       int64
+	totalCompressed int64
+	dataPageOffset  int64
+	dictPageOffset  int64 // 0 if the chunk has no dictionary page
+}
+
+type rowGroup struct {
+	columns []columnMeta
+	numRows int64
+}
+
+type fileMeta struct {
+	schema    []schemaElement
+	numRows   int64
+	rowGroups []rowGroup
+}
+
+// pageHeader holds the fields of a page header for all page types.
+type pageHeader struct {
+	typ              int32
+	uncompressedSize int32
+	compressedSize   int32
+
+	numValues   int32 // Data, data v2 and dictionary pages
+	encoding    int32 // Data, data v2 and dictionary pages
+	defEncoding int32 // Data pages
+	repEncoding int32 // Data pages
+
+	// Data v2 pages
+	numNulls     int32
+	defLength    int32
+	repLength    int32
+	isCompressed bool
+}
+
+func readFileMeta(r *compactReader) (*fileMeta, error) {
+	m := &fileMeta{}
+	err := r.readStruct(func(id int16, typ byte) error {
+		switch {
+		case id == 2 && typ == tList:
+			n, _, err := r.listHeader()
+			if err != nil {
+				return err
+			}
+			m.schema = make([]schemaElement, n)
+			for i := range m.schema {
+				if err := readSchemaElement(r, &m.schema[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		case id == 3 && typ == tI64:
+			v, err := r.varint()
+			m.numRows = v
+			return err
+		case id == 4 && typ == tList:
+			n, _, err := r.listHeader()
+			if err != nil {
+				return err
+			}
+			m.rowGroups = make([]rowGroup, n)
+			for i := range m.rowGroups {
+				if err := readRowGroup(r, &m.rowGroups[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		default:
+			return r.skip(typ)
+		}
+	})
+	return m, err
+}
+
+func readSchemaElement(r *compactReader, e *schemaElement) error {
+	return r.readStruct(func(id int16, typ byte) (err error) {
+		switch {
+		case id == 1 && typ == tI32:
+			var v int32
+			v, err = r.i32()
+			e.typ, e.hasType = Type(v), true
+		case id == 2 && typ == tI32:
+			e.typeLength, err = r.i32()
+		case id == 3 && typ == tI32:
+			e.repetition, err = r.i32()
+		case id == 4 && typ == tBinary:
+			e.name, err = r.string()
+		case id == 5 && typ == tI32:
+			e.numChildren, err = r.i32()
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+}
+
+func readRowGroup(r *compactReader, g *rowGroup) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		switch {
+		case id == 1 && typ == tList:
+			n, _, err := r.listHeader()
+			if err != nil {
+				return err
+			}
+			g.columns = make([]columnMeta, n)
+			for i := range g.columns {
+				if err := readColumnChunk(r, &g.columns[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		case id == 3 && typ == tI64:
+			v, err := r.varint()
+			g.numRows = v
+			return err
+		default:
+			return r.skip(typ)
+		}
+	})
+}
+
+func readColumnChunk(r *compactReader, c *columnMeta) error {
+	return r.readStruct(func(id int16, typ byte) error {
+		if id == 3 && typ == tStruct {
+			return readColumnMeta(r, c)
+		}
+		return r.skip(typ)
+	})
+}
+
+func readColumnMeta(r *compactReader, c *columnMeta) error {
+	return r.readStruct(func(id int16, typ byte) (err error) {
+		switch {
+		case id == 1 && typ == tI32:
+			var v int32
+			v, err = r.i32()
+			c.typ = Type(v)
+		case id == 3 && typ == tList:
+			var n int
+			if n, _, err = r.listHeader(); err != nil {
+				return err
+			}
+			c.path = make([]string, n)
+			for i := range c.path {
+				if c.path[i], err = r.string(); err != nil {
+					return err
+				}
+			}
+		case id == 4 && typ == tI32:
+			var v int32
+			v, err = r.i32()
+			c.codec = Codec(v)
+		case id == 5 && typ == tI64:
+			c.numValues, err = r.varint()
+		case id == 7 && typ == tI64:
+			c.totalCompressed, err = r.varint()
+		case id == 9 && typ == tI64:
+			c.dataPageOffset, err = r.varint()
+		case id == 11 && typ == tI64:
+			c.dictPageOffset, err = r.varint()
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+}
+
+func readPageHeader(r *compactReader) (*pageHeader, error) {
+	h := &pageHeader{isCompressed: true}
+	err := r.readStruct(func(id int16, typ byte) (err error) {
+		switch {
+		case id == 1 && typ == tI32:
+			h.typ, err = r.i32()
+		case id == 2 && typ == tI32:
+			h.uncompressedSize, err = r.i32()
+		case id == 3 && typ == tI32:
+			h.compressedSize, err = r.i32()
+		case id == 5 && typ == tStruct: // DataPageHeader
+			err = r.readStruct(func(id int16, typ byte) (err error) {
+				switch {
+				case id == 1 && typ == tI32:
+					h.numValues, err = r.i32()
+				case id == 2 && typ == tI32:
+					h.encoding, err = r.i32()
+				case id == 3 && typ == tI32:
+					h.defEncoding, err = r.i32()
+				case id == 4 && typ == tI32:
+					h.repEncoding, err = r.i32()
+				default:
+					err = r.skip(typ)
+				}
+				return err
+			})
+		case id == 7 && typ == tStruct: // DictionaryPageHeader
+			err = r.readStruct(func(id int16, typ byte) (err error) {
+				switch {
+				case id == 1 && typ == tI32:
+					h.numValues, err = r.i32()
+				case id == 2 && typ == tI32:
+					h.encoding, err = r.i32()
+				default:
+					err = r.skip(typ)
+				}
+				return err
+			})
+		case id == 8 && typ == tStruct: // DataPageHeaderV2
+			err = r.readStruct(func(id int16, typ byte) (err error) {
+				switch {
+				case id == 1 && typ == tI32:
+					h.numValues, err = r.i32()
+				case id == 2 && typ == tI32:
+					h.numNulls, err = r.i32()
+				case id == 4 && typ == tI32:
+					h.encoding, err = r.i32()
+				case id == 5 && typ == tI32:
+					h.defLength, err = r.i32()
+				case id == 6 && typ == tI32:
+					h.repLength, err = r.i32()
+				case id == 7 && (typ == tTrue || typ == tFalse):
+					h.isCompressed = typ == tTrue
+				default:
+					err = r.skip(typ)
+				}
+				return err
+			})
+		default:
+			err = r.skip(typ)
+		}
+		return err
+	})
+	return h, err
+}
@@ -0,0 +1,355 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+//go:generate go run testdata/generate.go
+
+func testColumns() []WriteColumn {
+	return []WriteColumn{
+		{Name: "name", Type: ByteArray, Values: []any{"Berlin", "Munich", "Berlin", "Paris"}},
+		{Name: "lat", Type: Double, Values: []any{52.52437, 48.13743, 52.5, 48.85341}},
+		{Name: "lon", Type: Float, Values: []any{13.5, 11.5, 13.25, 2.25}},
+		{Name: "population", Type: Int64, Optional: true, Values: []any{int64(3426354), nil, nil, int64(2138551)}},
+		{Name: "elevation", Type: Int32, Optional: true, Values: []any{int64(34), int64(524), nil, nil}},
+		{Name: "cc", Type: ByteArray, Optional: true, Dictionary: true, Values: []any{"DE", "DE", nil, "FR"}},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, opts := range []WriteOptions{
+		{Codec: Uncompressed},
+		{Codec: Snappy},
+		{Codec: Gzip},
+		{Codec: Snappy, DataPageV2: true},
+	} {
+		var buf bytes.Buffer
+		if err := Write(&buf, testColumns(), opts); err != nil {
+			t.Fatalf("Write(%+v): %v", opts, err)
+		}
+		f, err := ReadAll(buf.Bytes())
+		if err != nil {
+			t.Fatalf("ReadAll(%+v): %v", opts, err)
+		}
+		if f.NumRows() != 4 {
+			t.Errorf("Expected 4 rows, got %d", f.NumRows())
+		}
+		if want := []string{"name", "lat", "lon", "population", "elevation", "cc"}; !reflect.DeepEqual(f.Columns(), want) {
+			t.Errorf("Expected columns %v, got %v", want, f.Columns())
+		}
+
+		for _, c := range testColumns() {
+			col, err := f.ReadColumn(c.Name)
+			if err != nil {
+				t.Fatalf("ReadColumn(%q) with %+v: %v", c.Name, opts, err)
+			}
+			if col.Len() != len(c.Values) {
+				t.Fatalf("Expected %d values in %q, got %d", len(c.Values), c.Name, col.Len())
+			}
+			for i, want := range c.Values {
+				var got any
+				switch {
+				case col.IsNull(i):
+					got = nil
+				case c.Type == ByteArray:
+					got = col.String(i)
+				case c.Type == Int32 || c.Type == Int64:
+					got, _ = col.Int(i)
+				default:
+					got, _ = col.Float(i)
+				}
+				if got != want {
+					t.Errorf("%+v: expected %q[%d] = %v, got %v", opts, c.Name, i, want, got)
+				}
+			}
+		}
+	}
+}
+
+func TestColumnConversions(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []WriteColumn{{Name: "lat", Type: ByteArray, Values: []any{"52.5", "north"}}}
+	if err := Write(&buf, columns, WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := ReadAll(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	col, err := f.ReadColumn("lat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := col.Float(0); !ok || v != 52.5 {
+		t.Errorf("Expected 52.5 parsed from a string, got %v %v", v, ok)
+	}
+	if _, ok := col.Float(1); ok {
+		t.Errorf("Expected a non-numeric string not to convert")
+	}
+	if _, err := f.ReadColumn("lon"); err == nil {
+		t.Errorf("Expected an error for a missing column")
+	}
+}
+
+func TestDecodeHybrid(t *testing.T) {
+	// An RLE run of five 3s followed by a bit-packed group of 0..7 with a
+	// width of 3 bits.
+	data := []byte{5 << 1, 3, 1<<1 | 1, 0x88, 0xc6, 0xfa}
+	got, err := decodeHybrid(data, 3, 13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint32{3, 3, 3, 3, 3, 0, 1, 2, 3, 4, 5, 6, 7}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if _, err := decodeHybrid(data[:4], 3, 13); err == nil {
+		t.Errorf("Expected an error for truncated data")
+	}
+}
+
+func TestSnappyDecode(t *testing.T) {
+	// "abcd" as a literal followed by a copy of length 8 at offset 4, which
+	// overlaps its own output.
+	data := []byte{12, 3 << 2, 'a', 'b', 'c', 'd', 1 | 4<<2, 4}
+	got, err := snappyDecode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "abcdabcdabcd" {
+		t.Errorf("Expected abcdabcdabcd, got %q", got)
+	}
+	if _, err := snappyDecode([]byte{12, 3 << 2, 'a', 'b', 'c', 'd', 1 | 4<<2, 9}); err == nil {
+		t.Errorf("Expected an error for an offset before the start of the output")
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		[]byte("PAR1PAR1"),
+		[]byte("not a parquet file at all"),
+		append([]byte("PAR1\x15\x00"), []byte("\x02\x00\x00\x00PAR1")...),
+	} {
+		if _, err := ReadAll(data); err == nil {
+			t.Errorf("Expected an error opening %q", data)
+		}
+	}
+}
+
+func TestWriterRowGroups(t *testing.T) {
+	schema := []WriteColumn{
+		{Name: "city", Type: ByteArray, Optional: true, Dictionary: true},
+		{Name: "distance", Type: Double},
+	}
+	var buf bytes.Buffer
+	pw := NewWriter(&buf, schema, WriteOptions{Codec: Snappy})
+	groups := [][][]any{
+		{{"Berlin", nil, "Paris"}, {2.5, 0.0, 5.75}},
+		{{"Berlin"}, {1.0}},
+		{{}, {}},
+	}
+	for _, values := range groups {
+		if err := pw.WriteRowGroup(values); err != nil {
+			t.Fatalf("WriteRowGroup: %v", err)
+		}
+	}
+	if err := pw.WriteRowGroup([][]any{{"Berlin"}}); err == nil {
+		t.Errorf("Expected an error for a missing column")
+	}
+	if err := pw.WriteRowGroup([][]any{{"Berlin"}, {}}); err == nil {
+		t.Errorf("Expected an error for columns of different lengths")
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	f, err := ReadAll(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if f.NumRows() != 4 {
+		t.Errorf("Expected 4 rows, got %d", f.NumRows())
+	}
+	city, err := f.ReadColumn("city")
+	if err != nil {
+		t.Fatalf("ReadColumn: %v", err)
+	}
+	distance, err := f.ReadColumn("distance")
+	if err != nil {
+		t.Fatalf("ReadColumn: %v", err)
+	}
+	var got []any
+	for i := range city.Len() {
+		d, _ := distance.Float(i)
+		if city.IsNull(i) {
+			got = append(got, nil, d)
+		} else {
+			got = append(got, city.String(i), d)
+		}
+	}
+	if want := []any{"Berlin", 2.5, nil, 0.0, "Paris", 5.75, "Berlin", 1.0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	buf.Reset()
+	if err := NewWriter(&buf, schema, WriteOptions{}).Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if f, err := ReadAll(buf.Bytes()); err != nil || f.NumRows() != 0 {
+		t.Errorf("Expected an empty file, got %v", err)
+	}
+}
+
+// writeRawChunk returns a file with a single INT64 column "x", whose chunk is
+// a data page of values written with codec and encoding as they are.
+func writeRawChunk(t *testing.T, codec Codec, encoding int32, values []byte, numValues int) []byte {
+	t.Helper()
+	var chunk bytes.Buffer
+	if err := writePage(&chunk, pageData, values, nil, numValues, 0, encoding, WriteOptions{}); err != nil {
+		t.Fatalf("writePage: %v", err)
+	}
+	var out bytes.Buffer
+	out.WriteString(magic)
+	out.Write(chunk.Bytes())
+	pw := NewWriter(&out, []WriteColumn{{Name: "x", Type: Int64}}, WriteOptions{})
+	pw.offset = int64(out.Len())
+	pw.groups = []rowGroup{{numRows: int64(numValues), columns: []columnMeta{{
+		typ: Int64, path: []string{"x"}, codec: codec, numValues: int64(numValues),
+		totalCompressed: int64(chunk.Len()), dataPageOffset: int64(len(magic)),
+	}}}}
+	if err := pw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return out.Bytes()
+}
+
+func TestReadUnsupported(t *testing.T) {
+	plain, err := encodePlain(Int64, []any{int64(1), int64(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if col, err := readRaw(t, writeRawChunk(t, Uncompressed, encPlain, plain, 2)); err != nil || col.Len() != 2 {
+		t.Fatalf("Expected the PLAIN column to be read, got %v", err)
+	}
+
+	tests := []struct {
+		codec    Codec
+		encoding int32
+		want     string
+	}{
+		{Uncompressed, 5, `parquet: reading column "x": values encoded with DELTA_BINARY_PACKED are not supported`},
+		{Uncompressed, 9, "values encoded with BYTE_STREAM_SPLIT are not supported"},
+		{Uncompressed, 42, "values encoded with encoding 42 are not supported"},
+		{6, encPlain, `parquet: reading column "x": compression codec ZSTD is not supported`},
+		{5, encPlain, "compression codec LZ4 is not supported"},
+	}
+	for _, tt := range tests {
+		_, err := readRaw(t, writeRawChunk(t, tt.codec, tt.encoding, plain, 2))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected an error with %q for codec %s and encoding %d, got %v", tt.want, tt.codec, tt.encoding, err)
+		}
+	}
+}
+
+// readRaw reads the column "x" of the file data.
+func readRaw(t *testing.T, data []byte) (*Column, error) {
+	t.Helper()
+	f, err := ReadAll(data)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	return f.ReadColumn("x")
+}
+
+// The files in testdata are written by testdata/generate.go, which lays them
+// out like pyarrow and shares no code with the package.
+
+// readFixture opens the file testdata/name.
+func readFixture(t *testing.T, name string) *File {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ReadAll(data)
+	if err != nil {
+		t.Fatalf("ReadAll(%s): %v", name, err)
+	}
+	return f
+}
+
+func TestReadFixtures(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "cities.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, records := records[0], records[1:]
+
+	for _, name := range []string{"snappy_dictionary.parquet", "snappy_v2.parquet", "gzip_plain_dictionary.parquet"} {
+		f := readFixture(t, name)
+		if f.NumRows() != int64(len(records)) {
+			t.Errorf("%s: expected %d rows, got %d", name, len(records), f.NumRows())
+		}
+		if !reflect.DeepEqual(f.Columns(), header) {
+			t.Errorf("%s: expected columns %v, got %v", name, header, f.Columns())
+		}
+		for j, path := range header {
+			col, err := f.ReadColumn(path)
+			if err != nil {
+				t.Fatalf("%s: ReadColumn(%q): %v", name, path, err)
+			}
+			if col.Len() != len(records) {
+				t.Fatalf("%s: expected %d values in %q, got %d", name, len(records), path, col.Len())
+			}
+			for i, record := range records {
+				want := record[j]
+				got := col.String(i)
+				if col.Type == Double {
+					v, _ := strconv.ParseFloat(want, 64)
+					want = strconv.FormatFloat(v, 'g', -1, 64)
+				}
+				if col.IsNull(i) != (record[j] == "") || got != want {
+					t.Errorf("%s: expected %q[%d] = %q, got %q (null %v)", name, path, i, want, got, col.IsNull(i))
+				}
+			}
+		}
+	}
+}
+
+func TestReadFixturesUnsupported(t *testing.T) {
+	tests := []struct {
+		file, column, want string
+	}{
+		{"zstd.parquet", "id", `parquet: reading column "id": compression codec ZSTD is not supported`},
+		{"zstd.parquet", "name", "compression codec ZSTD is not supported"},
+		{"delta.parquet", "id", `parquet: reading column "id": values encoded with DELTA_BINARY_PACKED are not supported`},
+		{"delta.parquet", "name", "values encoded with DELTA_LENGTH_BYTE_ARRAY are not supported"},
+		{"delta.parquet", "latitude", "values encoded with BYTE_STREAM_SPLIT are not supported"},
+	}
+	for _, tt := range tests {
+		_, err := readFixture(t, tt.file).ReadColumn(tt.column)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error with %q reading %q, got %v", tt.file, tt.want, tt.column, err)
+		}
+	}
+
+	// The other columns of delta.parquet are PLAIN.
+	col, err := readFixture(t, "delta.parquet").ReadColumn("country_code")
+	if err != nil || col.Len() != 300 || col.String(0) != "AD" {
+		t.Errorf("Expected the PLAIN column of delta.parquet to be read, got %v", err)
+	}
+}
@@ -0,0 +1,478 @@
+// Package parquet implements a minimal reader for Apache Parquet files,
+// sufficient for loading flat gazetteer tables. It supports required and
+// optional columns of every physical type except INT96, PLAIN and dictionary
+// encodings, data page versions 1 and 2, and the uncompressed, Snappy and gzip
+// codecs. Repeated columns are not supported. Reading a column of a file
+// that uses other encodings or codecs, such as DELTA_BINARY_PACKED or ZSTD,
+// fails with an error naming them. Write and Writer write files of the same
+// subset, a row group at a time.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Type is the physical type of a Parquet column.
+type Type int32
+
+// Physical types.
+const (
+	Boolean Type = iota
+	Int32
+	Int64
+	Int96
+	Float
+	Double
+	ByteArray
+	FixedLenByteArray
+)
+
+var typeNames = []string{"BOOLEAN", "INT32", "INT64", "INT96", "FLOAT", "DOUBLE", "BYTE_ARRAY", "FIXED_LEN_BYTE_ARRAY"}
+
+func (t Type) String() string {
+	if t >= 0 && int(t) < len(typeNames) {
+		return typeNames[t]
+	}
+	return "Type(" + strconv.Itoa(int(t)) + ")"
+}
+
+// valueKind groups physical types by how their values are stored.
+type valueKind int
+
+const (
+	kindBool valueKind = iota
+	kindInt
+	kindFloat
+	kindString
+)
+
+func (t Type) kind() valueKind {
+	switch t {
+	case Int32, Int64:
+		return kindInt
+	case Float, Double:
+		return kindFloat
+	case ByteArray, FixedLenByteArray:
+		return kindString
+	default:
+		return kindBool
+	}
+}
+
+// Codec is a Parquet compression codec.
+type Codec int32
+
+// Compression codecs.
+const (
+	Uncompressed Codec = 0
+	Snappy       Codec = 1
+	Gzip         Codec = 2
+)
+
+var codecNames = []string{"UNCOMPRESSED", "SNAPPY", "GZIP", "LZO", "BROTLI", "LZ4", "ZSTD", "LZ4_RAW"}
+
+func (c Codec) String() string {
+	if c >= 0 && int(c) < len(codecNames) {
+		return codecNames[c]
+	}
+	return "Codec(" + strconv.Itoa(int(c)) + ")"
+}
+
+// magic starts and ends every Parquet file.
+const magic = "PAR1"
+
+// File is an opened Parquet file.
+type File struct {
+	r      io.ReaderAt
+	meta   *fileMeta
+	leaves []leaf
+}
+
+// leaf describes a column of the schema.
+type leaf struct {
+	path       string // Dotted path of the column
+	typ        Type
+	typeLength int
+	maxDef     int // Maximum definition level
+	maxRep     int // Maximum repetition level
+}
+
+// Open reads the metadata of the Parquet file of the given size from r.
+func Open(r io.ReaderAt, size int64) (*File, error) {
+	if size < int64(2*len(magic)+4) {
+		return nil, errors.New("parquet: file too small")
+	}
+	head := make([]byte, len(magic))
+	if _, err := r.ReadAt(head, 0); err != nil {
+		return nil, fmt.Errorf("parquet: reading header: %w", err)
+	}
+	tail := make([]byte, 4+len(magic))
+	if _, err := r.ReadAt(tail, size-int64(len(tail))); err != nil {
+		return nil, fmt.Errorf("parquet: reading footer: %w", err)
+	}
+	if string(head) != magic || string(tail[4:]) != magic {
+		return nil, errors.New("parquet: not a Parquet file")
+	}
+
+	footerLen := int64(binary.LittleEndian.Uint32(tail))
+	if footerLen > size-int64(len(head)+len(tail)) {
+		return nil, errors.New("parquet: invalid footer length")
+	}
+	footer := make([]byte, footerLen)
+	if _, err := r.ReadAt(footer, size-int64(len(tail))-footerLen); err != nil {
+		return nil, fmt.Errorf("parquet: reading footer: %w", err)
+	}
+	meta, err := readFileMeta(&compactReader{buf: footer})
+	if err != nil {
+		return nil, fmt.Errorf("parquet: reading file metadata: %w", err)
+	}
+	if len(meta.schema) == 0 {
+		return nil, errors.New("parquet: file has no schema")
+	}
+
+	f := &File{r: r, meta: meta}
+	if next := f.addLeaves(1, int(meta.schema[0].numChildren), nil, 0, 0); next != len(meta.schema) {
+		return nil, errors.New("parquet: malformed schema")
+	}
+	for _, g := range meta.rowGroups {
+		if len(g.columns) != len(f.leaves) {
+			return nil, errors.New("parquet: row group does not match schema")
+		}
+	}
+	return f, nil
+}
+
+// addLeaves walks n schema elements starting at index i, which are children
+// of the group at path, and records the leaves. It returns the index after
+// the last element visited, or -1 if the schema is malformed.
+func (f *File) addLeaves(i, n int, path []string, def, rep int) int {
+	for ; n > 0; n-- {
+		if i < 0 || i >= len(f.meta.schema) || len(path) > maxNesting {
+			return -1
+		}
+		e := f.meta.schema[i]
+		d, r := def, rep
+		switch e.repetition {
+		case repOptional:
+			d++
+		case repRepeated:
+			d++
+			r++
+		}
+		p := append(path[:len(path):len(path)], e.name)
+		if e.numChildren > 0 {
+			i = f.addLeaves(i+1, int(e.numChildren), p, d, r)
+			continue
+		}
+		if !e.hasType {
+			return -1
+		}
+		f.leaves = append(f.leaves, leaf{
+			path:       strings.Join(p, "."),
+			typ:        e.typ,
+			typeLength: int(e.typeLength),
+			maxDef:     d,
+			maxRep:     r,
+		})
+		i++
+	}
+	return i
+}
+
+// NumRows returns the number of rows in the file.
+func (f *File) NumRows() int64 {
+	return f.meta.numRows
+}
+
+// Columns returns the dotted paths of the file's columns in schema order.
+func (f *File) Columns() []string {
+	cols := make([]string, len(f.leaves))
+	for i, l := range f.leaves {
+		cols[i] = l.path
+	}
+	return cols
+}
+
+// Column holds the values of one column for every row of a file.
+type Column struct {
+	Type  Type
+	vals  values
+	nulls []bool // nil if no value is null
+}
+
+// Len returns the number of rows.
+func (c *Column) Len() int {
+	return c.vals.len(c.Type)
+}
+
+// IsNull reports whether the value in row i is null.
+func (c *Column) IsNull(i int) bool {
+	return c.nulls != nil && c.nulls[i]
+}
+
+// String returns the value in row i formatted as a string, or "" if it is
+// null.
+func (c *Column) String(i int) string {
+	if c.IsNull(i) {
+		return ""
+	}
+	switch c.Type.kind() {
+	case kindInt:
+		return strconv.FormatInt(c.vals.ints[i], 10)
+	case kindFloat:
+		return strconv.FormatFloat(c.vals.floats[i], 'g', -1, 64)
+	case kindString:
+		return c.vals.strs[i]
+	default:
+		return strconv.FormatBool(c.vals.bools[i])
+	}
+}
+
+// Float returns the value in row i as a float64. Strings are parsed. It
+// reports false if the value is null or not a number.
+func (c *Column) Float(i int) (float64, bool) {
+	if c.IsNull(i) {
+		return 0, false
+	}
+	switch c.Type.kind() {
+	case kindInt:
+		return float64(c.vals.ints[i]), true
+	case kindFloat:
+		return c.vals.floats[i], true
+	case kindString:
+		v, err := strconv.ParseFloat(strings.TrimSpace(c.vals.strs[i]), 64)
+		return v, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// Int returns the value in row i as an int64. Floats are truncated and
+// strings are parsed. It reports false if the value is null or not a number.
+func (c *Column) Int(i int) (int64, bool) {
+	if c.IsNull(i) {
+		return 0, false
+	}
+	switch c.Type.kind() {
+	case kindInt:
+		return c.vals.ints[i], true
+	case kindFloat:
+		return int64(c.vals.floats[i]), true
+	case kindString:
+		v, err := strconv.ParseInt(strings.TrimSpace(c.vals.strs[i]), 10, 64)
+		return v, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// ReadColumn reads every value of the column with the given dotted path.
+func (f *File) ReadColumn(path string) (*Column, error) {
+	idx := -1
+	for i, l := range f.leaves {
+		if l.path == path {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("parquet: no column %q", path)
+	}
+	l := f.leaves[idx]
+	if l.maxRep > 0 {
+		return nil, fmt.Errorf("parquet: column %q is repeated, which is not supported", path)
+	}
+	if l.typ == Int96 {
+		return nil, fmt.Errorf("parquet: column %q has unsupported type %s", path, l.typ)
+	}
+
+	col := &Column{Type: l.typ}
+	for _, g := range f.meta.rowGroups {
+		if err := f.readChunk(col, l, g.columns[idx]); err != nil {
+			return nil, fmt.Errorf("parquet: reading column %q: %w", path, err)
+		}
+	}
+	return col, nil
+}
+
+// readChunk appends the values of a column chunk to col.
+func (f *File) readChunk(col *Column, l leaf, cm columnMeta) error {
+	start := cm.dataPageOffset
+	if cm.dictPageOffset > 0 && cm.dictPageOffset < start {
+		start = cm.dictPageOffset
+	}
+	if start < 0 || cm.totalCompressed < 0 || cm.totalCompressed > 1<<31 {
+		return errors.New("invalid column chunk offsets")
+	}
+	chunk := make([]byte, cm.totalCompressed)
+	if _, err := f.r.ReadAt(chunk, start); err != nil {
+		return fmt.Errorf("reading column chunk: %w", err)
+	}
+
+	var dict *values
+	r := &compactReader{buf: chunk}
+	for read := int64(0); read < cm.numValues; {
+		h, err := readPageHeader(r)
+		if err != nil {
+			return fmt.Errorf("reading page header: %w", err)
+		}
+		if h.compressedSize < 0 || int(h.compressedSize) > len(chunk)-r.pos {
+			return errors.New("page exceeds column chunk")
+		}
+		page := chunk[r.pos : r.pos+int(h.compressedSize)]
+		r.pos += int(h.compressedSize)
+
+		switch h.typ {
+		case pageDictionary:
+			data, err := decompress(cm.codec, page, int(h.uncompressedSize))
+			if err != nil {
+				return err
+			}
+			if h.encoding != encPlain && h.encoding != encPlainDictionary {
+				return fmt.Errorf("dictionary pages encoded with %s are not supported, only PLAIN is", encodingName(h.encoding))
+			}
+			if dict, err = decodePlain(data, l.typ, l.typeLength, int(h.numValues)); err != nil {
+				return err
+			}
+		case pageData, pageDataV2:
+			if h.numValues < 0 || int64(h.numValues) > cm.numValues-read {
+				return errors.New("invalid value count")
+			}
+			if err := readDataPage(col, l, cm.codec, h, page, dict); err != nil {
+				return err
+			}
+			read += int64(h.numValues)
+		case pageIndex:
+			// Not needed for sequential reads.
+		default:
+			return fmt.Errorf("unknown page type %d", h.typ)
+		}
+	}
+	return nil
+}
+
+// readDataPage decodes a data page and appends its values to col.
+func readDataPage(col *Column, l leaf, codec Codec, h *pageHeader, page []byte, dict *values) error {
+	n := int(h.numValues)
+	defWidth := bits.Len(uint(l.maxDef))
+
+	var defData, valueData []byte
+	if h.typ == pageDataV2 {
+		// Levels are stored uncompressed in front of the values.
+		levels := int(h.repLength) + int(h.defLength)
+		if h.repLength < 0 || h.defLength < 0 || levels > len(page) {
+			return errors.New("invalid level lengths")
+		}
+		defData = page[h.repLength:levels]
+		valueData = page[levels:]
+		if h.isCompressed {
+			var err error
+			if valueData, err = decompress(codec, valueData, int(h.uncompressedSize)-levels); err != nil {
+				return err
+			}
+		}
+	} else {
+		data, err := decompress(codec, page, int(h.uncompressedSize))
+		if err != nil {
+			return err
+		}
+		if l.maxDef > 0 {
+			if h.defEncoding != encRLE {
+				return fmt.Errorf("definition levels encoded with %s are not supported, only RLE is", encodingName(h.defEncoding))
+			}
+			if len(data) < 4 {
+				return errTruncatedValues
+			}
+			size := binary.LittleEndian.Uint32(data)
+			if uint64(size) > uint64(len(data)-4) {
+				return errTruncatedValues
+			}
+			defData, data = data[4:4+size], data[4+size:]
+		}
+		valueData = data
+	}
+
+	// Definition levels tell which rows hold a value.
+	present := n
+	var defs []uint32
+	if l.maxDef > 0 {
+		var err error
+		if defs, err = decodeHybrid(defData, defWidth, n); err != nil {
+			return err
+		}
+		present = 0
+		for _, d := range defs {
+			if int(d) == l.maxDef {
+				present++
+			}
+		}
+	}
+
+	var vals *values
+	switch h.encoding {
+	case encPlain:
+		var err error
+		if vals, err = decodePlain(valueData, l.typ, l.typeLength, present); err != nil {
+			return err
+		}
+	case encPlainDictionary, encRLEDictionary:
+		if dict == nil {
+			return errors.New("dictionary-encoded page without dictionary")
+		}
+		if len(valueData) < 1 {
+			return errTruncatedValues
+		}
+		indices, err := decodeHybrid(valueData[1:], int(valueData[0]), present)
+		if err != nil {
+			return err
+		}
+		vals = &values{}
+		size := dict.len(l.typ)
+		for _, i := range indices {
+			if int(i) >= size {
+				return fmt.Errorf("dictionary index %d out of range", i)
+			}
+			vals.appendFrom(l.typ, dict, int(i))
+		}
+	default:
+		return fmt.Errorf("values encoded with %s are not supported, only PLAIN, PLAIN_DICTIONARY and RLE_DICTIONARY are", encodingName(h.encoding))
+	}
+
+	if defs == nil {
+		for i := 0; i < n; i++ {
+			col.vals.appendFrom(l.typ, vals, i)
+		}
+		if col.nulls != nil {
+			col.nulls = append(col.nulls, make([]bool, n)...)
+		}
+		return nil
+	}
+
+	if col.nulls == nil {
+		col.nulls = make([]bool, col.Len(), col.Len()+n)
+	}
+	next := 0
+	for _, d := range defs {
+		if int(d) == l.maxDef {
+			col.vals.appendFrom(l.typ, vals, next)
+			col.nulls = append(col.nulls, false)
+			next++
+		} else {
+			col.vals.appendZero(l.typ)
+			col.nulls = append(col.nulls, true)
+		}
+	}
+	return nil
+}
+
+// ReadAll opens a Parquet file held in memory.
+func ReadAll(data []byte) (*File, error) {
+	return Open(bytes.NewReader(data), int64(len(data)))
+}
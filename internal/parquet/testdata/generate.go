@@ -0,0 +1,816 @@
+//go:build ignore
+
+// Command generate writes the Parquet fixtures of this directory from
+// cities.csv:
+//
+//	go run testdata/generate.go
+//
+// The files are laid out the way parquet-cpp, the writer of pyarrow, lays
+// them out: every column optional, a dictionary page followed by
+// RLE_DICTIONARY data pages that fall back to PLAIN once the dictionary is
+// full, Snappy pages with back-references, statistics in page headers and
+// column chunks, several row groups and data pages per chunk, and version 2
+// data pages. Other files use encodings and codecs the reader does not
+// support, really encoded with them.
+//
+// The program shares no code with the package: it has its own Thrift, Snappy,
+// ZSTD and DELTA encoders, so the fixtures test the reader against an
+// independent writer rather than against Write. The Snappy and ZSTD data can
+// be checked with any decoder.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/csv"
+	"log"
+	"math"
+	"math/bits"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// Physical types, encodings, codecs and page types of parquet.thrift.
+const (
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	encPlain           = 0
+	encPlainDictionary = 2
+	encRLE             = 3
+	encDeltaBinary     = 5
+	encDeltaLength     = 6
+	encRLEDictionary   = 8
+	encByteStreamSplit = 9
+
+	codecUncompressed = 0
+	codecSnappy       = 1
+	codecGzip         = 2
+	codecZstd         = 6
+
+	pageData       = 0
+	pageDictionary = 2
+	pageDataV2     = 3
+)
+
+// column describes a column of a fixture.
+type column struct {
+	name     string
+	typ      int32
+	dict     bool  // Dictionary-encode the column
+	fallback int   // With dict, write the pages after the first fallback ones PLAIN; 0 never
+	encoding int32 // Encoding of pages that are not dictionary-encoded
+}
+
+// fixture describes a file to write.
+type fixture struct {
+	name      string
+	codec     int32
+	v2        bool  // Write version 2 data pages
+	dictPage  int32 // Encoding of dictionary pages
+	dictData  int32 // Encoding of dictionary-encoded data pages
+	groupRows int
+	pageRows  int
+	columns   []column
+}
+
+// Columns of cities.csv, with the physical type of each.
+var cities = []column{
+	{name: "id", typ: typeInt64},
+	{name: "name", typ: typeByteArray},
+	{name: "latitude", typ: typeDouble},
+	{name: "longitude", typ: typeDouble},
+	{name: "country_code", typ: typeByteArray},
+	{name: "admin1", typ: typeByteArray},
+	{name: "admin2", typ: typeByteArray},
+	{name: "population", typ: typeInt64},
+	{name: "timezone", typ: typeByteArray},
+}
+
+// withColumns returns the columns of cities.csv changed by f.
+func withColumns(f func(c *column)) []column {
+	cols := slices.Clone(cities)
+	for i := range cols {
+		f(&cols[i])
+	}
+	return cols
+}
+
+var fixtures = []fixture{
+	{
+		// pyarrow's defaults: dictionaries for every column and version 1
+		// data pages, with the dictionary of name overflowing.
+		name: "snappy_dictionary.parquet", codec: codecSnappy,
+		dictPage: encPlain, dictData: encRLEDictionary, groupRows: 128, pageRows: 50,
+		columns: withColumns(func(c *column) {
+			c.dict = true
+			if c.name == "name" {
+				c.fallback = 1
+			}
+		}),
+	},
+	{
+		// data_page_version="2.0", with dictionaries for strings only.
+		name: "snappy_v2.parquet", codec: codecSnappy, v2: true,
+		dictPage: encPlain, dictData: encRLEDictionary, groupRows: 300, pageRows: 64,
+		columns: withColumns(func(c *column) {
+			c.dict = c.typ == typeByteArray && c.name != "name"
+		}),
+	},
+	{
+		// Format version 1.0, which writes PLAIN_DICTIONARY pages.
+		name: "gzip_plain_dictionary.parquet", codec: codecGzip,
+		dictPage: encPlainDictionary, dictData: encPlainDictionary, groupRows: 200, pageRows: 100,
+		columns: withColumns(func(c *column) { c.dict = c.typ == typeByteArray }),
+	},
+	{
+		name: "zstd.parquet", codec: codecZstd, groupRows: 300, pageRows: 300,
+		columns: cities,
+	},
+	{
+		name: "delta.parquet", codec: codecUncompressed, groupRows: 300, pageRows: 100,
+		columns: withColumns(func(c *column) {
+			switch c.name {
+			case "id":
+				c.encoding = encDeltaBinary
+			case "name":
+				c.encoding = encDeltaLength
+			case "latitude":
+				c.encoding = encByteStreamSplit
+			}
+		}),
+	},
+}
+
+func main() {
+	rows, err := readCities("testdata/cities.csv")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, fx := range fixtures {
+		if err := os.WriteFile("testdata/"+fx.name, fx.write(rows), 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// readCities returns the values of each column of the CSV file at path:
+// int64, float64 or string, or nil for empty fields.
+func readCities(path string) ([][]any, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]any, len(cities))
+	for _, record := range records[1:] {
+		for i, field := range record {
+			var v any
+			switch {
+			case field == "":
+			case cities[i].typ == typeInt64:
+				if v, err = strconv.ParseInt(field, 10, 64); err != nil {
+					return nil, err
+				}
+			case cities[i].typ == typeDouble:
+				if v, err = strconv.ParseFloat(field, 64); err != nil {
+					return nil, err
+				}
+			default:
+				v = field
+			}
+			values[i] = append(values[i], v)
+		}
+	}
+	return values, nil
+}
+
+// write returns the file fx with the given column values.
+func (fx fixture) write(values [][]any) []byte {
+	out := []byte("PAR1")
+	numRows := len(values[0])
+	meta := &thrift{}
+	meta.begin()
+	meta.i32(1, 2) // version
+	meta.list(2, tStruct, len(fx.columns)+1, func(i int) {
+		if i == 0 {
+			meta.binary(4, []byte("schema"))
+			meta.i32(5, int32(len(fx.columns)))
+			return
+		}
+		c := fx.columns[i-1]
+		meta.i32(1, c.typ)
+		meta.i32(3, 1) // OPTIONAL
+		meta.binary(4, []byte(c.name))
+		if c.typ == typeByteArray {
+			meta.i32(6, 0) // UTF8
+			meta.structField(10, func() { meta.structField(1, func() {}) })
+		}
+	})
+	meta.i64(3, int64(numRows))
+
+	var groups [][]chunk
+	for start := 0; start < numRows; start += fx.groupRows {
+		end := min(start+fx.groupRows, numRows)
+		var g []chunk
+		for i, c := range fx.columns {
+			ch := fx.writeChunk(c, values[i][start:end], int64(len(out)))
+			out = append(out, ch.data...)
+			g = append(g, ch)
+		}
+		groups = append(groups, g)
+	}
+	meta.list(4, tStruct, len(groups), func(k int) {
+		g := groups[k]
+		var uncompressed, compressed int64
+		for _, ch := range g {
+			uncompressed += ch.uncompressed
+			compressed += int64(len(ch.data))
+		}
+		meta.list(1, tStruct, len(g), func(i int) {
+			ch := g[i]
+			meta.i64(2, ch.offset)
+			meta.structField(3, func() { ch.writeMeta(meta, fx.columns[i], fx.codec) })
+		})
+		meta.i64(2, uncompressed)
+		meta.i64(3, int64(g[0].numValues))
+		meta.i64(5, g[0].offset)
+		meta.i64(6, compressed)
+		meta.i16(7, int16(k))
+	})
+	meta.binary(6, []byte("geodecode internal/parquet/testdata/generate.go"))
+	meta.list(7, tStruct, len(fx.columns), func(int) {
+		meta.structField(1, func() {}) // TYPE_ORDER
+	})
+	meta.end()
+
+	out = append(out, meta.buf...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(meta.buf)))
+	return append(out, "PAR1"...)
+}
+
+// chunk is a column chunk written by writeChunk.
+type chunk struct {
+	data         []byte
+	offset       int64 // Offset of data in the file
+	dictOffset   int64 // Offset of the dictionary page, or 0
+	dataOffset   int64 // Offset of the first data page
+	uncompressed int64 // Size of the pages, headers included, before compression
+	numValues    int
+	encodings    []int32
+	pageStats    map[[2]int32]int32 // Number of pages by page type and encoding
+	stats        stats
+}
+
+// stats are the statistics of a page or column chunk.
+type stats struct {
+	min, max []byte
+	nulls    int64
+}
+
+// write writes s as the Statistics struct.
+func (s stats) write(t *thrift) {
+	t.i64(3, s.nulls)
+	if s.max != nil {
+		t.binary(5, s.max)
+		t.binary(6, s.min)
+	}
+}
+
+// writeMeta writes the ColumnMetaData of ch.
+func (ch *chunk) writeMeta(t *thrift, c column, codec int32) {
+	t.i32(1, c.typ)
+	t.list(2, tI32, len(ch.encodings), func(i int) { t.elemI32(ch.encodings[i]) })
+	t.list(3, tBinary, 1, func(int) { t.elemBinary([]byte(c.name)) })
+	t.i32(4, codec)
+	t.i64(5, int64(ch.numValues))
+	t.i64(6, ch.uncompressed)
+	t.i64(7, int64(len(ch.data)))
+	t.i64(9, ch.dataOffset)
+	if ch.dictOffset > 0 {
+		t.i64(11, ch.dictOffset)
+	}
+	t.structField(12, func() { ch.stats.write(t) })
+	keys := make([][2]int32, 0, len(ch.pageStats))
+	for k := range ch.pageStats {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b [2]int32) int {
+		return int(a[0]-b[0])*100 + int(a[1]-b[1])
+	})
+	t.list(13, tStruct, len(keys), func(i int) {
+		t.i32(1, keys[i][0])
+		t.i32(2, keys[i][1])
+		t.i32(3, ch.pageStats[keys[i]])
+	})
+}
+
+// writeChunk returns the column chunk of c holding vals, to be written at
+// offset.
+func (fx fixture) writeChunk(c column, vals []any, offset int64) chunk {
+	ch := chunk{offset: offset, numValues: len(vals), pageStats: map[[2]int32]int32{}}
+	ch.stats = statistics(c.typ, vals)
+	addEncoding := func(e int32) {
+		if !slices.Contains(ch.encodings, e) {
+			ch.encodings = append(ch.encodings, e)
+		}
+	}
+
+	// The dictionary holds the values of the pages that use it.
+	pages := (len(vals) + fx.pageRows - 1) / fx.pageRows
+	dictPages := pages
+	if c.fallback > 0 {
+		dictPages = min(c.fallback, pages)
+	}
+	var dict []any
+	index := map[any]uint32{}
+	if c.dict {
+		for _, v := range vals[:min(dictPages*fx.pageRows, len(vals))] {
+			if _, ok := index[v]; v != nil && !ok {
+				index[v] = uint32(len(dict))
+				dict = append(dict, v)
+			}
+		}
+		page := plain(c.typ, dict)
+		header := &thrift{}
+		header.begin()
+		header.i32(1, pageDictionary)
+		header.i32(2, int32(len(page)))
+		compressed := compress(fx.codec, page)
+		header.i32(3, int32(len(compressed)))
+		header.structField(7, func() {
+			header.i32(1, int32(len(dict)))
+			header.i32(2, fx.dictPage)
+			header.boolean(3, false)
+		})
+		header.end()
+		ch.dictOffset = offset
+		ch.data = append(append(ch.data, header.buf...), compressed...)
+		ch.uncompressed += int64(len(header.buf) + len(page))
+		ch.pageStats[[2]int32{pageDictionary, fx.dictPage}]++
+		addEncoding(fx.dictPage)
+	}
+	addEncoding(encRLE)
+
+	ch.dataOffset = offset + int64(len(ch.data))
+	for p := 0; p < pages; p++ {
+		rows := vals[p*fx.pageRows : min((p+1)*fx.pageRows, len(vals))]
+		defs := make([]uint32, len(rows))
+		var present []any
+		for i, v := range rows {
+			if v != nil {
+				defs[i] = 1
+				present = append(present, v)
+			}
+		}
+
+		encoding := c.encoding
+		var values []byte
+		if c.dict && p < dictPages {
+			encoding = fx.dictData
+			indices := make([]uint32, len(present))
+			for i, v := range present {
+				indices[i] = index[v]
+			}
+			width := bits.Len(uint(max(len(dict)-1, 0)))
+			values = append([]byte{byte(width)}, hybrid(indices, width)...)
+		} else {
+			values = encode(c.typ, encoding, present)
+		}
+		addEncoding(encoding)
+		levels := hybrid(defs, 1)
+		pageStats := statistics(c.typ, rows)
+
+		header := &thrift{}
+		header.begin()
+		var body []byte
+		if fx.v2 {
+			compressed := compress(fx.codec, values)
+			body = append(slices.Clone(levels), compressed...)
+			header.i32(1, pageDataV2)
+			header.i32(2, int32(len(levels)+len(values)))
+			header.i32(3, int32(len(body)))
+			header.structField(8, func() {
+				header.i32(1, int32(len(rows)))
+				header.i32(2, int32(len(rows)-len(present)))
+				header.i32(3, int32(len(rows)))
+				header.i32(4, encoding)
+				header.i32(5, int32(len(levels)))
+				header.i32(6, 0)
+				header.boolean(7, fx.codec != codecUncompressed)
+				header.structField(8, func() { pageStats.write(header) })
+			})
+			ch.pageStats[[2]int32{pageDataV2, encoding}]++
+			ch.uncompressed += int64(len(levels) + len(values))
+		} else {
+			page := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+			page = append(append(page, levels...), values...)
+			body = compress(fx.codec, page)
+			header.i32(1, pageData)
+			header.i32(2, int32(len(page)))
+			header.i32(3, int32(len(body)))
+			header.structField(5, func() {
+				header.i32(1, int32(len(rows)))
+				header.i32(2, encoding)
+				header.i32(3, encRLE)
+				header.i32(4, encRLE)
+				header.structField(5, func() { pageStats.write(header) })
+			})
+			ch.pageStats[[2]int32{pageData, encoding}]++
+			ch.uncompressed += int64(len(page))
+		}
+		header.end()
+		ch.data = append(append(ch.data, header.buf...), body...)
+		ch.uncompressed += int64(len(header.buf))
+	}
+	return ch
+}
+
+// statistics returns the statistics of vals.
+func statistics(typ int32, vals []any) stats {
+	var s stats
+	var lo, hi any
+	for _, v := range vals {
+		if v == nil {
+			s.nulls++
+			continue
+		}
+		if lo == nil || less(v, lo) {
+			lo = v
+		}
+		if hi == nil || less(hi, v) {
+			hi = v
+		}
+	}
+	if lo != nil {
+		s.min, s.max = plain(typ, []any{lo}), plain(typ, []any{hi})
+		if typ == typeByteArray {
+			s.min, s.max = s.min[4:], s.max[4:] // Without the length
+		}
+	}
+	return s
+}
+
+// less reports whether a sorts before b; both have the same type.
+func less(a, b any) bool {
+	switch a := a.(type) {
+	case int64:
+		return a < b.(int64)
+	case float64:
+		return a < b.(float64)
+	default:
+		return a.(string) < b.(string)
+	}
+}
+
+// encode returns vals encoded with encoding.
+func encode(typ int32, encoding int32, vals []any) []byte {
+	switch encoding {
+	case encPlain:
+		return plain(typ, vals)
+	case encDeltaBinary:
+		ints := make([]int64, len(vals))
+		for i, v := range vals {
+			ints[i] = v.(int64)
+		}
+		return deltaBinaryPacked(ints)
+	case encDeltaLength:
+		lengths := make([]int64, len(vals))
+		var data []byte
+		for i, v := range vals {
+			lengths[i] = int64(len(v.(string)))
+			data = append(data, v.(string)...)
+		}
+		return append(deltaBinaryPacked(lengths), data...)
+	case encByteStreamSplit:
+		out := make([]byte, 8*len(vals))
+		for i, v := range vals {
+			u := math.Float64bits(v.(float64))
+			for k := 0; k < 8; k++ {
+				out[k*len(vals)+i] = byte(u >> (8 * k))
+			}
+		}
+		return out
+	}
+	log.Fatalf("unknown encoding %d", encoding)
+	return nil
+}
+
+// plain returns vals in the PLAIN encoding.
+func plain(typ int32, vals []any) []byte {
+	var out []byte
+	for _, v := range vals {
+		switch typ {
+		case typeInt64:
+			out = binary.LittleEndian.AppendUint64(out, uint64(v.(int64)))
+		case typeDouble:
+			out = binary.LittleEndian.AppendUint64(out, math.Float64bits(v.(float64)))
+		default:
+			out = binary.LittleEndian.AppendUint32(out, uint32(len(v.(string))))
+			out = append(out, v.(string)...)
+		}
+	}
+	return out
+}
+
+// hybrid returns vals in the RLE/bit-packing hybrid encoding, like
+// parquet-cpp: runs of 8 or more equal values as RLE runs, and the values in
+// between bit-packed in groups of 8.
+func hybrid(vals []uint32, width int) []byte {
+	var out []byte
+	runLen := func(i int) int {
+		n := 1
+		for i+n < len(vals) && vals[i+n] == vals[i] {
+			n++
+		}
+		return n
+	}
+	for i := 0; i < len(vals); {
+		if n := runLen(i); n >= 8 {
+			out = binary.AppendUvarint(out, uint64(n)<<1)
+			for b := 0; b < (width+7)/8; b++ {
+				out = append(out, byte(vals[i]>>(8*b)))
+			}
+			i += n
+			continue
+		}
+		end := i
+		for end < len(vals) && (end == i || runLen(end) < 8) {
+			end = min(end+8, len(vals))
+		}
+		groups := (end - i + 7) / 8
+		out = binary.AppendUvarint(out, uint64(groups)<<1|1)
+		out = append(out, bitPack(vals[i:end], width, groups*8)...)
+		i = end
+	}
+	return out
+}
+
+// bitPack packs vals, padded with zeros to n values, in width bits each,
+// least significant bit first.
+func bitPack(vals []uint32, width, n int) []byte {
+	out := make([]byte, (n*width+7)/8)
+	for i, v := range vals {
+		for b := 0; b < width; b++ {
+			if v&(1<<b) != 0 {
+				bit := i*width + b
+				out[bit/8] |= 1 << (bit % 8)
+			}
+		}
+	}
+	return out
+}
+
+// deltaBinaryPacked returns vals in the DELTA_BINARY_PACKED encoding, with
+// blocks of 128 values in 4 miniblocks.
+func deltaBinaryPacked(vals []int64) []byte {
+	const blockSize, miniblocks = 128, 4
+	const miniblockSize = blockSize / miniblocks
+	out := binary.AppendUvarint(nil, blockSize)
+	out = binary.AppendUvarint(out, miniblocks)
+	out = binary.AppendUvarint(out, uint64(len(vals)))
+	if len(vals) == 0 {
+		return binary.AppendVarint(out, 0)
+	}
+	out = binary.AppendVarint(out, vals[0])
+	deltas := make([]int64, len(vals)-1)
+	for i := range deltas {
+		deltas[i] = vals[i+1] - vals[i]
+	}
+	for start := 0; start < len(deltas); start += blockSize {
+		block := deltas[start:min(start+blockSize, len(deltas))]
+		minDelta := slices.Min(block)
+		out = binary.AppendVarint(out, minDelta)
+		var widths [miniblocks]int
+		var packed []byte
+		for m := 0; m < miniblocks; m++ {
+			if m*miniblockSize >= len(block) {
+				continue // Width 0 and no data for unused miniblocks
+			}
+			mb := block[m*miniblockSize : min((m+1)*miniblockSize, len(block))]
+			var rel []uint64
+			for _, d := range mb {
+				rel = append(rel, uint64(d-minDelta))
+				widths[m] = max(widths[m], bits.Len64(uint64(d-minDelta)))
+			}
+			buf := make([]byte, miniblockSize*widths[m]/8)
+			for i, v := range rel {
+				for b := 0; b < widths[m]; b++ {
+					if v&(1<<b) != 0 {
+						bit := i*widths[m] + b
+						buf[bit/8] |= 1 << (bit % 8)
+					}
+				}
+			}
+			packed = append(packed, buf...)
+		}
+		for _, w := range widths {
+			out = append(out, byte(w))
+		}
+		out = append(out, packed...)
+	}
+	return out
+}
+
+// compress returns data compressed with codec.
+func compress(codec int32, data []byte) []byte {
+	switch codec {
+	case codecUncompressed:
+		return data
+	case codecSnappy:
+		return snappy(data)
+	case codecGzip:
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(data)
+		gz.Close()
+		return buf.Bytes()
+	case codecZstd:
+		return zstdRaw(data)
+	}
+	log.Fatalf("unknown codec %d", codec)
+	return nil
+}
+
+// snappy returns data compressed in the raw Snappy format, with greedy
+// matches of 4 bytes or more found through a hash table.
+func snappy(src []byte) []byte {
+	dst := binary.AppendUvarint(nil, uint64(len(src)))
+	table := map[uint32]int{}
+	lit := 0
+	for i := 0; i+4 <= len(src); {
+		key := binary.LittleEndian.Uint32(src[i:])
+		cand, ok := table[key]
+		table[key] = i
+		if !ok || i-cand > math.MaxUint16 {
+			i++
+			continue
+		}
+		n := 4
+		for i+n < len(src) && src[cand+n] == src[i+n] {
+			n++
+		}
+		dst = snappyLiteral(dst, src[lit:i])
+		dst = snappyCopy(dst, i-cand, n)
+		i += n
+		lit = i
+	}
+	return snappyLiteral(dst, src[lit:])
+}
+
+func snappyLiteral(dst, lit []byte) []byte {
+	if len(lit) == 0 {
+		return dst
+	}
+	switch n := len(lit) - 1; {
+	case n < 60:
+		dst = append(dst, byte(n<<2))
+	case n < 1<<8:
+		dst = append(dst, 60<<2, byte(n))
+	default:
+		dst = append(dst, 61<<2, byte(n), byte(n>>8))
+	}
+	return append(dst, lit...)
+}
+
+func snappyCopy(dst []byte, offset, length int) []byte {
+	for length > 0 {
+		if length >= 4 && length < 12 && offset < 1<<11 {
+			return append(dst, byte(1|(length-4)<<2|(offset>>8)<<5), byte(offset))
+		}
+		// Leave at least 4 bytes for the last copy.
+		n := min(length, 64)
+		if length > 64 && length < 68 {
+			n = 60
+		}
+		dst = append(dst, byte(2|(n-1)<<2), byte(offset), byte(offset>>8))
+		length -= n
+	}
+	return dst
+}
+
+// zstdRaw returns data as a ZSTD frame of raw, uncompressed blocks.
+func zstdRaw(data []byte) []byte {
+	out := []byte{0x28, 0xb5, 0x2f, 0xfd, 0xa0} // Magic; single segment, 4-byte content size
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(data)))
+	const maxBlock = 128 << 10
+	for {
+		n := min(len(data), maxBlock)
+		header := uint32(n) << 3 // Raw block
+		if n == len(data) {
+			header |= 1 // Last block
+		}
+		out = append(out, byte(header), byte(header>>8), byte(header>>16))
+		out = append(out, data[:n]...)
+		data = data[n:]
+		if len(data) == 0 {
+			return out
+		}
+	}
+}
+
+// Types of the Thrift compact protocol.
+const (
+	tTrue   = 1
+	tFalse  = 2
+	tI16    = 4
+	tI32    = 5
+	tI64    = 6
+	tBinary = 8
+	tList   = 9
+	tStruct = 12
+)
+
+// thrift writes structs in the Thrift compact protocol.
+type thrift struct {
+	buf  []byte
+	last []int16 // Last field id of each open struct
+}
+
+func (t *thrift) begin() { t.last = append(t.last, 0) }
+
+func (t *thrift) end() {
+	t.buf = append(t.buf, 0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thrift) field(id int16, typ byte) {
+	last := &t.last[len(t.last)-1]
+	if d := id - *last; d > 0 && d <= 15 {
+		t.buf = append(t.buf, byte(d)<<4|typ)
+	} else {
+		t.buf = append(t.buf, typ)
+		t.buf = binary.AppendVarint(t.buf, int64(id))
+	}
+	*last = id
+}
+
+func (t *thrift) i16(id int16, v int16) {
+	t.field(id, tI16)
+	t.buf = binary.AppendVarint(t.buf, int64(v))
+}
+
+func (t *thrift) i32(id int16, v int32) {
+	t.field(id, tI32)
+	t.elemI32(v)
+}
+
+func (t *thrift) i64(id int16, v int64) {
+	t.field(id, tI64)
+	t.buf = binary.AppendVarint(t.buf, v)
+}
+
+func (t *thrift) binary(id int16, b []byte) {
+	t.field(id, tBinary)
+	t.elemBinary(b)
+}
+
+func (t *thrift) boolean(id int16, v bool) {
+	if v {
+		t.field(id, tTrue)
+	} else {
+		t.field(id, tFalse)
+	}
+}
+
+func (t *thrift) structField(id int16, f func()) {
+	t.field(id, tStruct)
+	t.begin()
+	f()
+	t.end()
+}
+
+// list writes a list of n elements of type elem, each written by f; f
+// writes the fields of struct elements.
+func (t *thrift) list(id int16, elem byte, n int, f func(i int)) {
+	t.field(id, tList)
+	if n < 15 {
+		t.buf = append(t.buf, byte(n)<<4|elem)
+	} else {
+		t.buf = append(t.buf, 0xf0|elem)
+		t.buf = binary.AppendUvarint(t.buf, uint64(n))
+	}
+	for i := 0; i < n; i++ {
+		if elem == tStruct {
+			t.begin()
+			f(i)
+			t.end()
+		} else {
+			f(i)
+		}
+	}
+}
+
+func (t *thrift) elemI32(v int32) { t.buf = binary.AppendVarint(t.buf, int64(v)) }
+
+func (t *thrift) elemBinary(b []byte) {
+	t.buf = binary.AppendUvarint(t.buf, uint64(len(b)))
+	t.buf = append(t.buf, b...)
+}
@@ -0,0 +1,287 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Thrift compact protocol type identifiers.
+const (
+	tStop   byte = 0
+	tTrue   byte = 1
+	tFalse  byte = 2
+	tByte   byte = 3
+	tI16    byte = 4
+	tI32    byte = 5
+	tI64    byte = 6
+	tDouble byte = 7
+	tBinary byte = 8
+	tList   byte = 9
+	tSet    byte = 10
+	tMap    byte = 11
+	tStruct byte = 12
+)
+
+// maxNesting limits the depth of skipped structures, so corrupt metadata
+// cannot exhaust the stack.
+const maxNesting = 64
+
+var errTruncated = errors.New("parquet: truncated metadata")
+
+// compactReader decodes the Thrift compact protocol that Parquet uses for
+// its file metadata and page headers.
+type compactReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *compactReader) byte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errTruncated
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *compactReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.pos += n
+	return v, nil
+}
+
+// varint reads a zigzag-encoded integer, as used for i16, i32 and i64.
+func (r *compactReader) varint() (int64, error) {
+	v, err := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (r *compactReader) i32() (int32, error) {
+	v, err := r.varint()
+	if err == nil && (v < math.MinInt32 || v > math.MaxInt32) {
+		return 0, fmt.Errorf("parquet: integer %d out of range", v)
+	}
+	return int32(v), err
+}
+
+func (r *compactReader) binary() ([]byte, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.buf)-r.pos) {
+		return nil, errTruncated
+	}
+	b := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+func (r *compactReader) string() (string, error) {
+	b, err := r.binary()
+	return string(b), err
+}
+
+// listHeader reads the header of a list or set and returns its size and
+// element type.
+func (r *compactReader) listHeader() (int, byte, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, 0, err
+	}
+	size := uint64(b >> 4)
+	if size == 15 {
+		if size, err = r.uvarint(); err != nil {
+			return 0, 0, err
+		}
+	}
+	// Every element takes at least one byte, which bounds the size of
+	// corrupt lists.
+	if size > uint64(len(r.buf)-r.pos) {
+		return 0, 0, errTruncated
+	}
+	return int(size), b & 0x0f, nil
+}
+
+// readStruct reads the fields of a struct, calling field for each of them.
+// field must consume the value, either by reading it or by calling skip.
+func (r *compactReader) readStruct(field func(id int16, typ byte) error) error {
+	var last int16
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return err
+		}
+		typ := b & 0x0f
+		if typ == tStop {
+			return nil
+		}
+		id := last + int16(b>>4)
+		if b>>4 == 0 {
+			v, err := r.varint()
+			if err != nil {
+				return err
+			}
+			id = int16(v)
+		}
+		last = id
+		if err := field(id, typ); err != nil {
+			return err
+		}
+	}
+}
+
+// skip consumes a value of type typ.
+func (r *compactReader) skip(typ byte) error {
+	return r.skipNested(typ, 0)
+}
+
+func (r *compactReader) skipNested(typ byte, depth int) error {
+	if depth > maxNesting {
+		return errors.New("parquet: metadata nested too deeply")
+	}
+	switch typ {
+	case tTrue, tFalse:
+		return nil // The value is part of the field header
+	case tByte:
+		_, err := r.byte()
+		return err
+	case tI16, tI32, tI64:
+		_, err := r.uvarint()
+		return err
+	case tDouble:
+		if len(r.buf)-r.pos < 8 {
+			return errTruncated
+		}
+		r.pos += 8
+		return nil
+	case tBinary:
+		_, err := r.binary()
+		return err
+	case tList, tSet:
+		n, elem, err := r.listHeader()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if elem == tTrue || elem == tFalse {
+				// Booleans in lists take one byte each.
+				if _, err := r.byte(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := r.skipNested(elem, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case tMap:
+		n, err := r.uvarint()
+		if err != nil || n == 0 {
+			return err
+		}
+		if n > uint64(len(r.buf)-r.pos) {
+			return errTruncated
+		}
+		kv, err := r.byte()
+		if err != nil {
+			return err
+		}
+		for i := uint64(0); i < n; i++ {
+			if err := r.skipNested(kv>>4, depth+1); err != nil {
+				return err
+			}
+			if err := r.skipNested(kv&0x0f, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case tStruct:
+		return r.readStruct(func(_ int16, typ byte) error {
+			return r.skipNested(typ, depth+1)
+		})
+	default:
+		return fmt.Errorf("parquet: unknown Thrift type %d", typ)
+	}
+}
+
+// compactWriter encodes the Thrift compact protocol. It supports the subset
+// needed by Write.
+type compactWriter struct {
+	buf  []byte
+	last []int16 // Last field id of each open struct
+}
+
+func (w *compactWriter) uvarint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *compactWriter) varint(v int64) {
+	w.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (w *compactWriter) field(id int16, typ byte) {
+	last := w.last[len(w.last)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.varint(int64(id))
+	}
+	w.last[len(w.last)-1] = id
+}
+
+func (w *compactWriter) beginStruct() {
+	w.last = append(w.last, 0)
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf = append(w.buf, tStop)
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *compactWriter) structField(id int16) {
+	w.field(id, tStruct)
+	w.beginStruct()
+}
+
+func (w *compactWriter) i32Field(id int16, v int32) {
+	w.field(id, tI32)
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) i64Field(id int16, v int64) {
+	w.field(id, tI64)
+	w.varint(v)
+}
+
+func (w *compactWriter) stringField(id int16, s string) {
+	w.field(id, tBinary)
+	w.uvarint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *compactWriter) boolField(id int16, v bool) {
+	if v {
+		w.field(id, tTrue)
+	} else {
+		w.field(id, tFalse)
+	}
+}
+
+// listField writes the header of a list field with n elements of type elem.
+func (w *compactWriter) listField(id int16, n int, elem byte) {
+	w.field(id, tList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|elem)
+		return
+	}
+	w.buf = append(w.buf, 0xf0|elem)
+	w.uvarint(uint64(n))
+}
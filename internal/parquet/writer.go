@@ -0,0 +1,402 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// WriteColumn describes a column written by Write.
+type WriteColumn struct {
+	Name       string
+	Type       Type  // Int32, Int64, Float, Double or ByteArray
+	Optional   bool  // Whether Values may contain nil
+	Dictionary bool  // Use dictionary encoding instead of PLAIN
+	Values     []any // One value per row: int64, float64, string or nil
+}
+
+// WriteOptions configures Write and NewWriter.
+type WriteOptions struct {
+	Codec      Codec // Uncompressed, Snappy or Gzip
+	DataPageV2 bool  // Write version 2 data pages
+}
+
+// Write writes columns to w as a Parquet file with a single row group. It
+// supports the same subset of the format as the reader.
+func Write(w io.Writer, columns []WriteColumn, opts WriteOptions) error {
+	pw := NewWriter(w, columns, opts)
+	values := make([][]any, len(columns))
+	for i, c := range columns {
+		values[i] = c.Values
+	}
+	if err := pw.WriteRowGroup(values); err != nil {
+		return err
+	}
+	return pw.Close()
+}
+
+// Writer writes a Parquet file a row group at a time, so files of any
+// number of rows can be written in bounded memory.
+type Writer struct {
+	w       io.Writer
+	columns []WriteColumn // Schema; their Values are ignored
+	opts    WriteOptions
+	offset  int64 // Bytes written so far
+	groups  []rowGroup
+	err     error
+}
+
+// NewWriter returns a Writer writing a file with the schema columns to w.
+// The Values of columns are ignored. Close must be called to complete the
+// file.
+func NewWriter(w io.Writer, columns []WriteColumn, opts WriteOptions) *Writer {
+	return &Writer{w: w, columns: columns, opts: opts}
+}
+
+// WriteRowGroup writes a row group with values, the values of each column
+// of the schema in turn: int64, float64, string or nil, one per row.
+func (pw *Writer) WriteRowGroup(values [][]any) error {
+	if pw.err != nil {
+		return pw.err
+	}
+	if len(values) != len(pw.columns) {
+		return fmt.Errorf("parquet: %d columns of values for %d columns", len(values), len(pw.columns))
+	}
+	var buf bytes.Buffer
+	if pw.offset == 0 {
+		buf.WriteString(magic)
+	}
+	var g rowGroup
+	if len(values) > 0 {
+		g.numRows = int64(len(values[0]))
+	}
+	for i, c := range pw.columns {
+		c.Values = values[i]
+		if int64(len(c.Values)) != g.numRows {
+			return fmt.Errorf("parquet: column %q has %d values, want %d", c.Name, len(c.Values), g.numRows)
+		}
+		cm, err := writeChunk(&buf, c, pw.opts)
+		if err != nil {
+			return err
+		}
+		cm.dataPageOffset += pw.offset
+		if c.Dictionary {
+			cm.dictPageOffset += pw.offset
+		}
+		g.columns = append(g.columns, cm)
+	}
+	pw.groups = append(pw.groups, g)
+	return pw.write(buf.Bytes())
+}
+
+// write writes data to the underlying writer, keeping the first error.
+func (pw *Writer) write(data []byte) error {
+	if pw.err != nil {
+		return pw.err
+	}
+	n, err := pw.w.Write(data)
+	pw.offset += int64(n)
+	pw.err = err
+	return err
+}
+
+// Close writes the file metadata. It does not close the underlying writer.
+func (pw *Writer) Close() error {
+	if pw.offset == 0 {
+		if err := pw.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+	var numRows int64
+	for _, g := range pw.groups {
+		numRows += g.numRows
+	}
+
+	cw := &compactWriter{}
+	cw.beginStruct()
+	cw.i32Field(1, 1) // version
+	cw.listField(2, len(pw.columns)+1, tStruct)
+	cw.beginStruct()
+	cw.stringField(4, "schema")
+	cw.i32Field(5, int32(len(pw.columns)))
+	cw.endStruct()
+	for _, c := range pw.columns {
+		rep := repRequired
+		if c.Optional {
+			rep = repOptional
+		}
+		cw.beginStruct()
+		cw.i32Field(1, int32(c.Type))
+		cw.i32Field(3, rep)
+		cw.stringField(4, c.Name)
+		if c.Type == ByteArray {
+			cw.i32Field(6, convertedUTF8) // Strings rather than binary
+		}
+		cw.endStruct()
+	}
+	cw.i64Field(3, numRows)
+	cw.listField(4, len(pw.groups), tStruct)
+	for _, g := range pw.groups {
+		cw.beginStruct()
+		cw.listField(1, len(g.columns), tStruct)
+		var total int64
+		for _, cm := range g.columns {
+			total += cm.totalCompressed
+			cw.beginStruct()
+			cw.i64Field(2, cm.dataPageOffset)
+			cw.structField(3)
+			cw.i32Field(1, int32(cm.typ))
+			cw.listField(2, 1, tI32)
+			cw.varint(int64(encPlain))
+			cw.listField(3, 1, tBinary)
+			cw.uvarint(uint64(len(cm.path[0])))
+			cw.buf = append(cw.buf, cm.path[0]...)
+			cw.i32Field(4, int32(cm.codec))
+			cw.i64Field(5, cm.numValues)
+			cw.i64Field(6, cm.totalCompressed)
+			cw.i64Field(7, cm.totalCompressed)
+			cw.i64Field(9, cm.dataPageOffset)
+			if cm.dictPageOffset > 0 {
+				cw.i64Field(11, cm.dictPageOffset)
+			}
+			cw.endStruct()
+			cw.endStruct()
+		}
+		cw.i64Field(2, total)
+		cw.i64Field(3, g.numRows)
+		cw.endStruct()
+	}
+	cw.endStruct()
+
+	footer := binary.LittleEndian.AppendUint32(cw.buf, uint32(len(cw.buf)))
+	return pw.write(append(footer, magic...))
+}
+
+// writeChunk writes the pages of column c to buf and returns the chunk's
+// metadata.
+func writeChunk(buf *bytes.Buffer, c WriteColumn, opts WriteOptions) (columnMeta, error) {
+	cm := columnMeta{typ: c.Type, path: []string{c.Name}, codec: opts.Codec, numValues: int64(len(c.Values))}
+	start := buf.Len()
+
+	var defs []uint32
+	var present []any
+	for _, v := range c.Values {
+		if v == nil {
+			if !c.Optional {
+				return cm, fmt.Errorf("parquet: null value in required column %q", c.Name)
+			}
+			defs = append(defs, 0)
+			continue
+		}
+		defs = append(defs, 1)
+		present = append(present, v)
+	}
+
+	var values []byte
+	encoding := encPlain
+	if c.Dictionary {
+		var dict []any
+		index := make(map[any]uint32)
+		var indices []uint32
+		for _, v := range present {
+			i, ok := index[v]
+			if !ok {
+				i = uint32(len(dict))
+				index[v] = i
+				dict = append(dict, v)
+			}
+			indices = append(indices, i)
+		}
+		plain, err := encodePlain(c.Type, dict)
+		if err != nil {
+			return cm, err
+		}
+		cm.dictPageOffset = int64(buf.Len())
+		if err := writePage(buf, pageDictionary, plain, nil, len(dict), 0, encPlainDictionary, opts); err != nil {
+			return cm, err
+		}
+		width := bits.Len(uint(max(len(dict)-1, 0)))
+		values = append([]byte{byte(width)}, encodeBitPacked(indices, width)...)
+		encoding = encPlainDictionary
+		if opts.DataPageV2 {
+			encoding = encRLEDictionary
+		}
+	} else {
+		var err error
+		if values, err = encodePlain(c.Type, present); err != nil {
+			return cm, err
+		}
+	}
+
+	var levels []byte
+	if c.Optional {
+		levels = encodeBitPacked(defs, 1)
+	}
+	cm.dataPageOffset = int64(buf.Len())
+	if err := writePage(buf, pageData, values, levels, len(c.Values), len(c.Values)-len(present), encoding, opts); err != nil {
+		return cm, err
+	}
+	cm.totalCompressed = int64(buf.Len() - start)
+	return cm, nil
+}
+
+// writePage writes a page with its header. levels holds the encoded
+// definition levels of data pages, if any.
+func writePage(buf *bytes.Buffer, typ int32, values, levels []byte, numValues, numNulls int, encoding int32, opts WriteOptions) error {
+	var body []byte
+	uncompressed := len(values) + len(levels)
+	switch {
+	case typ == pageData && opts.DataPageV2:
+		typ = pageDataV2
+		compressed, err := compress(opts.Codec, values)
+		if err != nil {
+			return err
+		}
+		body = append(append([]byte(nil), levels...), compressed...)
+	case typ == pageData:
+		var page []byte
+		if levels != nil {
+			page = binary.LittleEndian.AppendUint32(page, uint32(len(levels)))
+			page = append(page, levels...)
+			uncompressed += 4
+		}
+		page = append(page, values...)
+		var err error
+		if body, err = compress(opts.Codec, page); err != nil {
+			return err
+		}
+	default:
+		var err error
+		if body, err = compress(opts.Codec, values); err != nil {
+			return err
+		}
+	}
+
+	cw := &compactWriter{}
+	cw.beginStruct()
+	cw.i32Field(1, typ)
+	cw.i32Field(2, int32(uncompressed))
+	cw.i32Field(3, int32(len(body)))
+	switch typ {
+	case pageDictionary:
+		cw.structField(7)
+		cw.i32Field(1, int32(numValues))
+		cw.i32Field(2, encoding)
+		cw.endStruct()
+	case pageData:
+		cw.structField(5)
+		cw.i32Field(1, int32(numValues))
+		cw.i32Field(2, encoding)
+		cw.i32Field(3, encRLE)
+		cw.i32Field(4, encRLE)
+		cw.endStruct()
+	case pageDataV2:
+		cw.structField(8)
+		cw.i32Field(1, int32(numValues))
+		cw.i32Field(2, int32(numNulls))
+		cw.i32Field(3, int32(numValues))
+		cw.i32Field(4, encoding)
+		cw.i32Field(5, int32(len(levels)))
+		cw.i32Field(6, 0)
+		cw.boolField(7, true)
+		cw.endStruct()
+	}
+	cw.endStruct()
+
+	buf.Write(cw.buf)
+	buf.Write(body)
+	return nil
+}
+
+// encodePlain encodes values with the PLAIN encoding.
+func encodePlain(typ Type, values []any) ([]byte, error) {
+	var out []byte
+	for _, v := range values {
+		switch typ {
+		case Int32:
+			i, ok := v.(int64)
+			if !ok {
+				return nil, fmt.Errorf("parquet: value %v is not an int64", v)
+			}
+			out = binary.LittleEndian.AppendUint32(out, uint32(int32(i)))
+		case Int64:
+			i, ok := v.(int64)
+			if !ok {
+				return nil, fmt.Errorf("parquet: value %v is not an int64", v)
+			}
+			out = binary.LittleEndian.AppendUint64(out, uint64(i))
+		case Float:
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("parquet: value %v is not a float64", v)
+			}
+			out = binary.LittleEndian.AppendUint32(out, math.Float32bits(float32(f)))
+		case Double:
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("parquet: value %v is not a float64", v)
+			}
+			out = binary.LittleEndian.AppendUint64(out, math.Float64bits(f))
+		case ByteArray:
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("parquet: value %v is not a string", v)
+			}
+			out = binary.LittleEndian.AppendUint32(out, uint32(len(s)))
+			out = append(out, s...)
+		default:
+			return nil, fmt.Errorf("parquet: writing type %s is not supported", typ)
+		}
+	}
+	return out, nil
+}
+
+// encodeBitPacked encodes values as a single bit-packed run of the
+// RLE/bit-packing hybrid encoding.
+func encodeBitPacked(values []uint32, width int) []byte {
+	groups := (len(values) + 7) / 8
+	out := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+	packed := make([]byte, groups*width)
+	for i, v := range values {
+		for b := 0; b < width; b++ {
+			if v&(1<<b) != 0 {
+				bit := i*width + b
+				packed[bit/8] |= 1 << (bit % 8)
+			}
+		}
+	}
+	return append(out, packed...)
+}
+
+// compress compresses data with codec. Snappy output consists of literals
+// only, which is valid but not smaller than the input.
+func compress(codec Codec, data []byte) ([]byte, error) {
+	switch codec {
+	case Uncompressed:
+		return data, nil
+	case Snappy:
+		out := binary.AppendUvarint(nil, uint64(len(data)))
+		for len(data) > 0 {
+			n := min(len(data), 1<<16)
+			out = append(out, 61<<2) // Literal with a 2-byte length
+			out = binary.LittleEndian.AppendUint16(out, uint16(n-1))
+			out = append(out, data[:n]...)
+			data = data[n:]
+		}
+		return out, nil
+	case Gzip:
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(data)
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("parquet: writing codec %s is not supported", codec)
+	}
+}
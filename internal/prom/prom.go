@@ -0,0 +1,147 @@
+// Package prom writes metrics in the Prometheus text exposition format,
+// sufficient for counters, gauges and histograms with constant labels, so
+// that metrics can be scraped without depending on the Prometheus client
+// library.
+package prom
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Types of metrics.
+const (
+	Counter   = "counter"
+	Gauge     = "gauge"
+	Histogram = "histogram"
+)
+
+// Writer writes metrics to an io.Writer. Errors are kept until Flush.
+type Writer struct {
+	w   *bufio.Writer
+	buf []byte
+}
+
+// NewWriter returns a Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Header writes the HELP and TYPE lines of the metric name, which precede
+// its samples.
+func (w *Writer) Header(name, help, typ string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+	w.w.WriteString("# HELP " + name + " " + help + "\n")
+	w.w.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// Sample writes a sample of the metric name with the value v. labels are
+// pairs of label names and values.
+func (w *Writer) Sample(name string, v float64, labels ...string) {
+	b := append(w.buf[:0], name...)
+	if len(labels) > 0 {
+		b = append(b, '{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, labels[i]...)
+			b = append(b, `="`...)
+			b = appendLabelValue(b, labels[i+1])
+			b = append(b, '"')
+		}
+		b = append(b, '}')
+	}
+	b = append(b, ' ')
+	b = appendValue(b, v)
+	b = append(b, '\n')
+	w.w.Write(b)
+	w.buf = b
+}
+
+// Flush writes any buffered data and returns the first error writing.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// appendLabelValue appends v escaped as a label value.
+func appendLabelValue(b []byte, v string) []byte {
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; c {
+		case '\\':
+			b = append(b, `\\`...)
+		case '"':
+			b = append(b, `\"`...)
+		case '\n':
+			b = append(b, `\n`...)
+		default:
+			b = append(b, c)
+		}
+	}
+	return b
+}
+
+// appendValue appends v as the format writes values.
+func appendValue(b []byte, v float64) []byte {
+	switch {
+	case math.IsInf(v, 1):
+		return append(b, "+Inf"...)
+	case math.IsInf(v, -1):
+		return append(b, "-Inf"...)
+	case math.IsNaN(v):
+		return append(b, "NaN"...)
+	}
+	return strconv.AppendFloat(b, v, 'g', -1, 64)
+}
+
+// Hist is a histogram of observations, safe for concurrent use.
+type Hist struct {
+	bounds []float64       // Upper bounds of the buckets, ascending
+	counts []atomic.Uint64 // Observations per bucket, the last for +Inf
+	sum    atomic.Uint64   // Bits of the float64 sum of observations
+}
+
+// NewHist returns a histogram with buckets of the upper bounds, which must
+// be ascending. A bucket for +Inf is added.
+func NewHist(bounds ...float64) *Hist {
+	return &Hist{bounds: bounds, counts: make([]atomic.Uint64, len(bounds)+1)}
+}
+
+// Observe records the value v.
+func (h *Hist) Observe(v float64) {
+	i := 0
+	for i < len(h.bounds) && v > h.bounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	for {
+		old := h.sum.Load()
+		if h.sum.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+// Write writes the samples of h as the histogram name with labels, which
+// are pairs of label names and values. The header is not written.
+func (h *Hist) Write(w *Writer, name string, labels ...string) {
+	var count uint64
+	le := append(labels[:len(labels):len(labels)], "le", "")
+	for i := range h.counts {
+		count += h.counts[i].Load()
+		le[len(le)-1] = "+Inf"
+		if i < len(h.bounds) {
+			le[len(le)-1] = strconv.FormatFloat(h.bounds[i], 'g', -1, 64)
+		}
+		w.Sample(name+"_bucket", float64(count), le...)
+	}
+	w.Sample(name+"_sum", math.Float64frombits(h.sum.Load()), labels...)
+	w.Sample(name+"_count", float64(count), labels...)
+}
@@ -0,0 +1,46 @@
+package prom
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Header("queries_total", "Queries by\nresult.", Counter)
+	w.Sample("queries_total", 3, "result", "found", "path", `C:\a "b"`)
+	w.Sample("up", 1)
+	w.Sample("ratio", math.Inf(1))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	want := "# HELP queries_total Queries by\\nresult.\n" +
+		"# TYPE queries_total counter\n" +
+		`queries_total{result="found",path="C:\\a \"b\""} 3` + "\n" +
+		"up 1\n" +
+		"ratio +Inf\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestHist(t *testing.T) {
+	h := NewHist(0.1, 1)
+	for _, v := range []float64{0.05, 0.1, 0.5, 2} {
+		h.Observe(v)
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	h.Write(w, "latency_seconds", "route", "/reverse")
+	w.Flush()
+	want := `latency_seconds_bucket{route="/reverse",le="0.1"} 2` + "\n" +
+		`latency_seconds_bucket{route="/reverse",le="1"} 3` + "\n" +
+		`latency_seconds_bucket{route="/reverse",le="+Inf"} 4` + "\n" +
+		`latency_seconds_sum{route="/reverse"} 2.65` + "\n" +
+		`latency_seconds_count{route="/reverse"} 4` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+}
@@ -0,0 +1,545 @@
+// Package sqlite implements a minimal writer of SQLite database files,
+// sufficient for storing a single table of results with secondary indexes
+// so it can be queried without an import step. The table is written in one
+// pass as rows arrive, while the keys of the indexes are kept in memory and
+// sorted when the writer is closed.
+package sqlite
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// pageSize is the size of the pages of the files written, all of which is
+// usable.
+const pageSize = 4096
+
+// Page types of b-tree pages.
+const (
+	interiorIndex byte = 0x02
+	interiorTable byte = 0x05
+	leafIndex     byte = 0x0a
+	leafTable     byte = 0x0d
+)
+
+// Limits of the payload stored on a b-tree page itself; the rest goes to
+// overflow pages.
+const (
+	maxLocalTable = pageSize - 35
+	maxLocalIndex = (pageSize-12)*64/255 - 23
+	minLocal      = (pageSize-12)*32/255 - 23
+)
+
+// Column is a column of the table written by a Writer.
+type Column struct {
+	Name string
+	Type string // Declared type, such as TEXT, REAL or INTEGER
+}
+
+// Writer writes a database file holding a single table and its indexes.
+// Values are nil, int, int64, float64 or string.
+type Writer struct {
+	w       io.WriterAt
+	table   string
+	columns []Column
+	indexes []index
+	pages   uint32 // Pages allocated so far, page 1 included
+	rowid   int64
+
+	leaf     [][]byte // Cells of the table leaf not written yet
+	leafSize int      // Bytes taken on that page by leaf
+	leaves   []child  // Table leaves written
+	closed   bool
+}
+
+// index is an index of the table and the keys of the rows written so far.
+type index struct {
+	name    string
+	columns []int
+	keys    [][]any // Values of columns followed by the rowid, for each row
+}
+
+// child is a page of a b-tree level along with the largest rowid in it.
+type child struct {
+	page  uint32
+	rowid int64
+}
+
+// NewWriter returns a Writer writing a table named table with columns to
+// w, which must be empty. indexes lists the columns of each index to create
+// on the table. Close must be called to complete the file.
+func NewWriter(w io.WriterAt, table string, columns []Column, indexes [][]string) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("sqlite: no columns")
+	}
+	names := make(map[string]bool)
+	for _, c := range columns {
+		if names[strings.ToLower(c.Name)] {
+			return nil, fmt.Errorf("sqlite: duplicate column %q", c.Name)
+		}
+		names[strings.ToLower(c.Name)] = true
+	}
+	sw := &Writer{w: w, table: table, columns: columns, pages: 1, leafSize: 8}
+	for _, cols := range indexes {
+		ix := index{name: table + "_" + strings.Join(cols, "_")}
+		for _, name := range cols {
+			i := slices.IndexFunc(columns, func(c Column) bool { return strings.EqualFold(c.Name, name) })
+			if i < 0 {
+				return nil, fmt.Errorf("sqlite: index column %q not in the table", name)
+			}
+			ix.columns = append(ix.columns, i)
+		}
+		sw.indexes = append(sw.indexes, ix)
+	}
+	return sw, nil
+}
+
+// Write appends a row with values, one for each column.
+func (sw *Writer) Write(values []any) error {
+	if sw.closed {
+		return errors.New("sqlite: write after close")
+	}
+	if len(values) != len(sw.columns) {
+		return fmt.Errorf("sqlite: %d values for %d columns", len(values), len(sw.columns))
+	}
+	payload, err := appendRecord(nil, values)
+	if err != nil {
+		return err
+	}
+	prefix := appendVarint(nil, uint64(len(payload)))
+	prefix = appendVarint(prefix, uint64(sw.rowid+1))
+	cell, err := sw.cell(prefix, payload, maxLocalTable)
+	if err != nil {
+		return err
+	}
+	if sw.leafSize+2+len(cell) > pageSize {
+		if err := sw.flushLeaf(); err != nil {
+			return err
+		}
+	}
+	sw.rowid++
+	sw.leaf = append(sw.leaf, cell)
+	sw.leafSize += 2 + len(cell)
+
+	for i := range sw.indexes {
+		ix := &sw.indexes[i]
+		key := make([]any, 0, len(ix.columns)+1)
+		for _, c := range ix.columns {
+			key = append(key, values[c])
+		}
+		ix.keys = append(ix.keys, append(key, sw.rowid))
+	}
+	return nil
+}
+
+// Close writes the indexes, the upper levels of the table and the schema.
+// It does not close the underlying writer.
+func (sw *Writer) Close() error {
+	if sw.closed {
+		return nil
+	}
+	sw.closed = true
+	if len(sw.leaf) > 0 || len(sw.leaves) == 0 {
+		if err := sw.flushLeaf(); err != nil {
+			return err
+		}
+	}
+	root, err := sw.tableRoot()
+	if err != nil {
+		return err
+	}
+
+	quoted := make([]string, len(sw.columns))
+	for i, c := range sw.columns {
+		quoted[i] = quote(c.Name) + " " + c.Type
+	}
+	schema := [][]any{{"table", sw.table, sw.table, int64(root),
+		fmt.Sprintf("CREATE TABLE %s(%s)", quote(sw.table), strings.Join(quoted, ", "))}}
+	for _, ix := range sw.indexes {
+		root, err := sw.indexRoot(ix.keys)
+		if err != nil {
+			return err
+		}
+		cols := make([]string, len(ix.columns))
+		for i, c := range ix.columns {
+			cols[i] = quote(sw.columns[c].Name)
+		}
+		schema = append(schema, []any{"index", ix.name, sw.table, int64(root),
+			fmt.Sprintf("CREATE INDEX %s ON %s(%s)", quote(ix.name), quote(sw.table), strings.Join(cols, ", "))})
+	}
+	return sw.writeFirstPage(schema)
+}
+
+// writeFirstPage writes page 1: the file header and the schema table with
+// the records of schema.
+func (sw *Writer) writeFirstPage(schema [][]any) error {
+	var cells [][]byte
+	size := 100 + 8
+	for i, record := range schema {
+		payload, err := appendRecord(nil, record)
+		if err != nil {
+			return err
+		}
+		cell := appendVarint(nil, uint64(len(payload)))
+		cell = append(appendVarint(cell, uint64(i+1)), payload...)
+		cells = append(cells, cell)
+		size += 2 + len(cell)
+	}
+	if size > pageSize {
+		return errors.New("sqlite: schema does not fit on the first page")
+	}
+	page := buildPage(leafTable, cells, 0, 100)
+
+	h := page[:100]
+	copy(h, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(h[16:], pageSize)
+	h[18], h[19] = 1, 1              // Legacy file format versions
+	h[21], h[22], h[23] = 64, 32, 32 // Payload fractions, fixed by the format
+	binary.BigEndian.PutUint32(h[24:], 1)
+	binary.BigEndian.PutUint32(h[28:], sw.pages)
+	binary.BigEndian.PutUint32(h[40:], 1) // Schema cookie
+	binary.BigEndian.PutUint32(h[44:], 4) // Schema format
+	binary.BigEndian.PutUint32(h[56:], 1) // UTF-8
+	binary.BigEndian.PutUint32(h[92:], 1) // Matches the change counter at 24
+	binary.BigEndian.PutUint32(h[96:], 3045000)
+	_, err := sw.w.WriteAt(page, 0)
+	return err
+}
+
+// writePage writes data as the next page and returns its number.
+func (sw *Writer) writePage(data []byte) (uint32, error) {
+	sw.pages++
+	_, err := sw.w.WriteAt(data, int64(sw.pages-1)*pageSize)
+	return sw.pages, err
+}
+
+// cell returns prefix followed by as much of payload as is stored on a
+// b-tree page with the limit maxLocal, writing the rest to overflow pages.
+func (sw *Writer) cell(prefix, payload []byte, maxLocal int) ([]byte, error) {
+	if len(payload) <= maxLocal {
+		return append(prefix, payload...), nil
+	}
+	local := minLocal + (len(payload)-minLocal)%(pageSize-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+	cell := append(prefix, payload[:local]...)
+	cell = binary.BigEndian.AppendUint32(cell, sw.pages+1)
+	for rest := payload[local:]; len(rest) > 0; {
+		page := make([]byte, pageSize)
+		n := copy(page[4:], rest)
+		if rest = rest[n:]; len(rest) > 0 {
+			binary.BigEndian.PutUint32(page, sw.pages+2)
+		}
+		if _, err := sw.writePage(page); err != nil {
+			return nil, err
+		}
+	}
+	return cell, nil
+}
+
+// flushLeaf writes the pending cells as a table leaf.
+func (sw *Writer) flushLeaf() error {
+	page, err := sw.writePage(buildPage(leafTable, sw.leaf, 0, 0))
+	if err != nil {
+		return err
+	}
+	sw.leaves = append(sw.leaves, child{page, sw.rowid})
+	sw.leaf, sw.leafSize = sw.leaf[:0], 8
+	return nil
+}
+
+// tableRoot writes the interior pages of the table above its leaves and
+// returns the root page.
+func (sw *Writer) tableRoot() (uint32, error) {
+	// Interior cells are a page number and a rowid, at most 13 bytes.
+	const fanout = (pageSize-12)/(2+4+9) + 1
+	level := sw.leaves
+	for len(level) > 1 {
+		var groups [][]child
+		for c := range slices.Chunk(level, fanout) {
+			groups = append(groups, c)
+		}
+		// An interior page needs a cell besides its right-most child.
+		if last := len(groups) - 1; last > 0 && len(groups[last]) == 1 {
+			prev := groups[last-1]
+			groups[last-1], groups[last] = prev[:len(prev)-1], append([]child{prev[len(prev)-1]}, groups[last]...)
+		}
+		var next []child
+		for _, g := range groups {
+			cells := make([][]byte, len(g)-1)
+			for i, c := range g[:len(g)-1] {
+				cells[i] = appendVarint(binary.BigEndian.AppendUint32(nil, c.page), uint64(c.rowid))
+			}
+			last := g[len(g)-1]
+			page, err := sw.writePage(buildPage(interiorTable, cells, last.page, 0))
+			if err != nil {
+				return 0, err
+			}
+			next = append(next, child{page, last.rowid})
+		}
+		level = next
+	}
+	return level[0].page, nil
+}
+
+// indexRoot sorts keys, writes them as an index b-tree and returns its root
+// page.
+func (sw *Writer) indexRoot(keys [][]any) (uint32, error) {
+	slices.SortFunc(keys, compareKeys)
+	bodies := make([][]byte, len(keys))
+	for i, key := range keys {
+		payload, err := appendRecord(nil, key)
+		if err != nil {
+			return 0, err
+		}
+		if bodies[i], err = sw.cell(appendVarint(nil, uint64(len(payload))), payload, maxLocalIndex); err != nil {
+			return 0, err
+		}
+	}
+	pages, bodies, err := sw.indexLevel(bodies, nil)
+	for err == nil && len(pages) > 1 {
+		pages, bodies, err = sw.indexLevel(bodies, pages)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return pages[0], nil
+}
+
+// indexLevel writes a level of an index b-tree holding the cells bodies and,
+// unless it is the leaf level, the pages children of the level below, one
+// more than the cells. It returns the pages written and the cells moved up
+// to separate them, one fewer than the pages.
+func (sw *Writer) indexLevel(bodies [][]byte, children []uint32) (pages []uint32, seps [][]byte, err error) {
+	cell := func(i int) []byte {
+		if children == nil {
+			return bodies[i]
+		}
+		return append(binary.BigEndian.AppendUint32(nil, children[i]), bodies[i]...)
+	}
+	header := 8
+	if children != nil {
+		header = 12
+	}
+	// Split the cells into pages, each range of cells ending before one
+	// that moves up.
+	var ranges [][2]int
+	for start := 0; ; {
+		end, size := start, header
+		for end < len(bodies) && size+2+len(cell(end)) <= pageSize {
+			size += 2 + len(cell(end))
+			end++
+		}
+		ranges = append(ranges, [2]int{start, end})
+		if end == len(bodies) {
+			break
+		}
+		start = end + 1
+		if start == len(bodies) {
+			// The last page would be empty: move the previous page's last
+			// cell up instead, and the separator down onto the last page.
+			prev := &ranges[len(ranges)-1]
+			prev[1]--
+			ranges = append(ranges, [2]int{end, len(bodies)})
+			break
+		}
+	}
+
+	for i, r := range ranges {
+		cells := make([][]byte, 0, r[1]-r[0])
+		for j := r[0]; j < r[1]; j++ {
+			cells = append(cells, cell(j))
+		}
+		typ, right := leafIndex, uint32(0)
+		if children != nil {
+			typ, right = interiorIndex, children[r[1]]
+		}
+		page, err := sw.writePage(buildPage(typ, cells, right, 0))
+		if err != nil {
+			return nil, nil, err
+		}
+		pages = append(pages, page)
+		if i < len(ranges)-1 {
+			seps = append(seps, bodies[r[1]])
+		}
+	}
+	return pages, seps, nil
+}
+
+// buildPage returns a b-tree page of type typ holding cells, with the
+// right-most child right on interior pages. The b-tree header starts at
+// offset, which is 100 on page 1.
+func buildPage(typ byte, cells [][]byte, right uint32, offset int) []byte {
+	page := make([]byte, pageSize)
+	header := 8
+	if typ == interiorIndex || typ == interiorTable {
+		header = 12
+		binary.BigEndian.PutUint32(page[offset+8:], right)
+	}
+	page[offset] = typ
+	binary.BigEndian.PutUint16(page[offset+3:], uint16(len(cells)))
+	end := pageSize
+	for i, c := range cells {
+		end -= len(c)
+		copy(page[end:], c)
+		binary.BigEndian.PutUint16(page[offset+header+2*i:], uint16(end))
+	}
+	binary.BigEndian.PutUint16(page[offset+5:], uint16(end))
+	return page
+}
+
+// appendRecord appends values to buf in the record format.
+func appendRecord(buf []byte, values []any) ([]byte, error) {
+	var types, body []byte
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+			types = append(types, 0)
+		case int:
+			types, body = appendInt(types, body, int64(v))
+		case int64:
+			types, body = appendInt(types, body, v)
+		case float64:
+			types = append(types, 7)
+			body = binary.BigEndian.AppendUint64(body, math.Float64bits(v))
+		case string:
+			types = appendVarint(types, uint64(2*len(v)+13))
+			body = append(body, v...)
+		default:
+			return nil, fmt.Errorf("sqlite: unsupported value of type %T", v)
+		}
+	}
+	// The size of the header includes its own varint.
+	n := 1
+	for varintLen(uint64(len(types)+n)) > n {
+		n++
+	}
+	buf = appendVarint(buf, uint64(len(types)+n))
+	return append(append(buf, types...), body...), nil
+}
+
+// appendInt appends the serial type of v to types and its bytes to body,
+// using as few bytes as fit.
+func appendInt(types, body []byte, v int64) ([]byte, []byte) {
+	switch {
+	case v == 0:
+		return append(types, 8), body
+	case v == 1:
+		return append(types, 9), body
+	}
+	serial, n := byte(6), 8
+	for _, size := range []struct {
+		serial byte
+		n      int
+	}{{1, 1}, {2, 2}, {3, 3}, {4, 4}, {5, 6}} {
+		if limit := int64(1) << (8*size.n - 1); v >= -limit && v < limit {
+			serial, n = size.serial, size.n
+			break
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		body = append(body, byte(v>>(8*i)))
+	}
+	return append(types, serial), body
+}
+
+// appendVarint appends v in the variable-length integer format of SQLite:
+// big-endian groups of 7 bits, the last of 9 bytes taking 8 bits.
+func appendVarint(buf []byte, v uint64) []byte {
+	if v > 1<<56-1 {
+		var b [9]byte
+		b[8] = byte(v)
+		v >>= 8
+		for i := 7; i >= 0; i-- {
+			b[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+		return append(buf, b[:]...)
+	}
+	var b [8]byte
+	i := len(b)
+	for {
+		i--
+		b[i] = byte(v&0x7f) | 0x80
+		if v >>= 7; v == 0 {
+			break
+		}
+	}
+	b[len(b)-1] &= 0x7f
+	return append(buf, b[i:]...)
+}
+
+// varintLen returns the length of v as a varint.
+func varintLen(v uint64) int {
+	return len(appendVarint(nil, v))
+}
+
+// compareKeys compares index keys as SQLite does with the BINARY collation:
+// NULL before numbers before text, numbers by value and text by bytes.
+func compareKeys(a, b []any) int {
+	for i := range min(len(a), len(b)) {
+		if c := compareValues(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+func compareValues(a, b any) int {
+	if c := cmp.Compare(class(a), class(b)); c != 0 {
+		return c
+	}
+	switch a := a.(type) {
+	case string:
+		return bytes.Compare([]byte(a), []byte(b.(string)))
+	case nil:
+		return 0
+	}
+	x, y := number(a), number(b)
+	if xi, ok := x.(int64); ok {
+		if yi, ok := y.(int64); ok {
+			return cmp.Compare(xi, yi)
+		}
+	}
+	return cmp.Compare(toFloat(x), toFloat(y))
+}
+
+// class orders the storage classes of values.
+func class(v any) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case string:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// number returns the number v as an int64 or a float64.
+func number(v any) any {
+	if i, ok := v.(int); ok {
+		return int64(i)
+	}
+	return v
+}
+
+func toFloat(v any) float64 {
+	if i, ok := v.(int64); ok {
+		return float64(i)
+	}
+	return v.(float64)
+}
+
+// quote quotes an identifier for SQL.
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
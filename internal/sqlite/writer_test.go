@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppendVarint(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want []byte
+	}{
+		{0, []byte{0}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x81, 0x00}},
+		{200, []byte{0x81, 0x48}},
+		{1<<56 - 1, append(bytes.Repeat([]byte{0xff}, 7), 0x7f)},
+		{1 << 63, []byte{0x80 | 0x40, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}},
+	}
+	for _, tt := range tests {
+		if got := appendVarint(nil, tt.v); !bytes.Equal(got, tt.want) {
+			t.Errorf("appendVarint(%d): Expected % x, got % x", tt.v, tt.want, got)
+		}
+	}
+}
+
+func TestAppendRecord(t *testing.T) {
+	got, err := appendRecord(nil, []any{nil, 0, int64(1), 300, -2, 1.5, "ab"})
+	if err != nil {
+		t.Fatalf("appendRecord: %v", err)
+	}
+	want := []byte{8, 0, 8, 9, 2, 1, 7, 17, 0x01, 0x2c, 0xfe, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0, 'a', 'b'}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Expected % x, got % x", want, got)
+	}
+	if _, err := appendRecord(nil, []any{true}); err == nil {
+		t.Errorf("Expected an error for a bool")
+	}
+}
+
+func TestCompareKeys(t *testing.T) {
+	sorted := [][]any{{nil, int64(2)}, {2.5, int64(4)}, {3, int64(5)}, {"a", int64(0)}, {"a", int64(3)}, {"b", int64(1)}}
+	for i := range sorted[1:] {
+		if compareKeys(sorted[i], sorted[i+1]) >= 0 {
+			t.Errorf("Expected %v before %v", sorted[i], sorted[i+1])
+		}
+	}
+	if compareKeys(sorted[0], sorted[0]) != 0 {
+		t.Errorf("Expected a key to equal itself")
+	}
+}
+
+// TestWriter checks the files written with the sqlite3 command, if it is
+// installed.
+func TestWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.db")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns := []Column{{"id", "INTEGER"}, {"name", "TEXT"}, {"cc", "TEXT"}, {"distance", "REAL"}, {"note", "TEXT"}}
+	w, err := NewWriter(f, "results", columns, [][]string{{"cc", "name"}, {"note"}})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	const rows = 20000
+	for i := range rows {
+		var cc, note any
+		if i%7 != 0 {
+			cc = []string{"DE", "FR", "US"}[i%3]
+		}
+		if i%1000 == 0 {
+			note = strings.Repeat(fmt.Sprint(i), 3000) // Overflows
+		}
+		if err := w.Write([]any{i, fmt.Sprintf("place %d", i*7919%rows), cc, float64(i) / 4, note}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Write([]any{1}); err == nil {
+		t.Errorf("Expected an error for a short row")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	f.Close()
+	if w.Write(make([]any, len(columns))) == nil {
+		t.Errorf("Expected an error after Close")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("SQLite format 3\x00")) || len(data)%pageSize != 0 {
+		t.Fatalf("Expected a database file, got %d bytes starting with %q", len(data), data[:16])
+	}
+
+	sqlite3, err := exec.LookPath("sqlite3")
+	if err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	query := "PRAGMA integrity_check;" +
+		"SELECT count(*), sum(id), sum(distance), count(cc), count(note) FROM results;" +
+		"SELECT id FROM results WHERE cc = 'FR' AND name = 'place 7919';" +
+		"SELECT length(note) FROM results WHERE note LIKE '5000%';" +
+		"EXPLAIN QUERY PLAN SELECT * FROM results WHERE cc = 'DE';"
+	out, err := exec.Command(sqlite3, path, query).CombinedOutput()
+	if err != nil {
+		t.Fatalf("sqlite3: %v\n%s", err, out)
+	}
+	got := string(out)
+	for _, want := range []string{"ok\n", "20000|199990000|49997500.0|17142|20\n", "1\n", "12000\n", "USING INDEX results_cc_name (cc=?)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestNewWriterInvalid(t *testing.T) {
+	var f *os.File
+	if _, err := NewWriter(f, "t", nil, nil); err == nil {
+		t.Errorf("Expected an error without columns")
+	}
+	if _, err := NewWriter(f, "t", []Column{{"a", "TEXT"}, {"A", "TEXT"}}, nil); err == nil {
+		t.Errorf("Expected an error for duplicate columns")
+	}
+	if _, err := NewWriter(f, "t", []Column{{"a", "TEXT"}}, [][]string{{"b"}}); err == nil {
+		t.Errorf("Expected an error for an unknown index column")
+	}
+}
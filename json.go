@@ -0,0 +1,179 @@
+package geodecode
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// JSONOptions controls how locations and results are encoded as JSON. The
+// zero value produces the same output as Location.MarshalJSON and
+// Result.MarshalJSON: camelCase field names, with every field present.
+//
+// Example usage:
+//
+//	opts := geodecode.JSONOptions{SnakeCase: true, OmitEmpty: true}
+//	json.NewEncoder(w).Encode(struct {
+//	    Place json.Marshaler `json:"place"`
+//	}{opts.Location(loc)})
+type JSONOptions struct {
+	SnakeCase bool // Name fields in snake_case (admin1_code) instead of camelCase (admin1Code)
+	OmitEmpty bool // Leave out fields with zero values; lat and lon are always present
+
+	OmitDistance bool // Leave out the distance and confidence of results
+}
+
+// MarshalJSON encodes l with camelCase field names, for example
+// {"geonameId":2950159,"lat":52.52437,"lon":13.41053,"city":"Berlin",...}.
+// Use JSONOptions to change the field names or leave out empty fields.
+func (l Location) MarshalJSON() ([]byte, error) {
+	return JSONOptions{}.appendLocation(nil, l), nil
+}
+
+// Location returns a json.Marshaler that encodes loc with the options o.
+func (o JSONOptions) Location(loc Location) json.Marshaler {
+	return marshalerFunc(func() ([]byte, error) {
+		return o.appendLocation(nil, loc), nil
+	})
+}
+
+// Locations returns a json.Marshaler that encodes locs as a JSON array with
+// the options o.
+func (o JSONOptions) Locations(locs []Location) json.Marshaler {
+	return marshalerFunc(func() ([]byte, error) {
+		buf := []byte{'['}
+		for i, loc := range locs {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = o.appendLocation(buf, loc)
+		}
+		return append(buf, ']'), nil
+	})
+}
+
+// MarshalJSON encodes r with camelCase field names, for example
+// {"found":true,"distance":1.2,"unit":"km","confidence":0.95,"location":{...}}.
+// Use JSONOptions to change the field names or leave out empty fields or the
+// distance.
+func (r Result) MarshalJSON() ([]byte, error) {
+	return JSONOptions{}.appendResult(nil, r), nil
+}
+
+// Result returns a json.Marshaler that encodes r with the options o.
+func (o JSONOptions) Result(r Result) json.Marshaler {
+	return marshalerFunc(func() ([]byte, error) {
+		return o.appendResult(nil, r), nil
+	})
+}
+
+// Results returns a json.Marshaler that encodes results as a JSON array with
+// the options o.
+func (o JSONOptions) Results(results []Result) json.Marshaler {
+	return marshalerFunc(func() ([]byte, error) {
+		buf := []byte{'['}
+		for i, r := range results {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = o.appendResult(buf, r)
+		}
+		return append(buf, ']'), nil
+	})
+}
+
+// marshalerFunc adapts a function to json.Marshaler.
+type marshalerFunc func() ([]byte, error)
+
+func (f marshalerFunc) MarshalJSON() ([]byte, error) { return f() }
+
+// appendLocation appends the JSON encoding of loc to buf.
+func (o JSONOptions) appendLocation(buf []byte, loc Location) []byte {
+	w := jsonObject{buf: append(buf, '{'), opts: o}
+	w.int("geonameId", "geoname_id", loc.GeonameID)
+	w.float("lat", "lat", loc.Lat)
+	w.float("lon", "lon", loc.Lon)
+	w.string("city", "city", loc.City)
+	w.string("admin1", "admin1", loc.Admin1)
+	w.string("admin1Code", "admin1_code", loc.Admin1Code)
+	w.string("admin2", "admin2", loc.Admin2)
+	w.string("admin2Name", "admin2_name", loc.Admin2Name)
+	w.string("cc", "cc", loc.CC)
+	w.string("country", "country", loc.Country)
+	w.string("timezone", "timezone", loc.Timezone)
+	w.int("elevation", "elevation", loc.Elevation)
+	w.int("population", "population", loc.Population)
+	if ci := loc.CountryInfo; ci != (CountryInfo{}) || !o.OmitEmpty {
+		w.key("countryInfo", "country_info")
+		info := jsonObject{buf: append(w.buf, '{'), opts: o}
+		info.string("iso3", "iso3", ci.ISO3)
+		info.string("continent", "continent", ci.Continent)
+		info.string("currency", "currency", ci.Currency)
+		info.string("callingCode", "calling_code", ci.CallingCode)
+		w.buf = append(info.buf, '}')
+	}
+	return append(w.buf, '}')
+}
+
+// appendResult appends the JSON encoding of r to buf. The found field is
+// always present; the location is left out of misses if OmitEmpty is set.
+func (o JSONOptions) appendResult(buf []byte, r Result) []byte {
+	w := jsonObject{buf: append(buf, '{'), opts: o}
+	w.key("found", "found")
+	w.buf = strconv.AppendBool(w.buf, r.Found)
+	if !o.OmitDistance && (r.Found || !o.OmitEmpty) {
+		w.float("distance", "distance", r.Distance)
+		w.string("unit", "unit", r.Unit.String())
+		w.float("confidence", "confidence", r.Confidence)
+	}
+	if r.Found || !o.OmitEmpty {
+		w.key("location", "location")
+		w.buf = o.appendLocation(w.buf, r.Location)
+	}
+	return append(w.buf, '}')
+}
+
+// jsonObject appends the fields of a JSON object, honoring JSONOptions.
+type jsonObject struct {
+	buf    []byte
+	opts   JSONOptions
+	fields int // Fields written so far
+}
+
+// key writes the name of the next field, camel or snake depending on the
+// options.
+func (w *jsonObject) key(camel, snake string) {
+	if w.fields > 0 {
+		w.buf = append(w.buf, ',')
+	}
+	w.fields++
+	name := camel
+	if w.opts.SnakeCase {
+		name = snake
+	}
+	w.buf = strconv.AppendQuote(w.buf, name) // Field names are plain ASCII
+	w.buf = append(w.buf, ':')
+}
+
+func (w *jsonObject) string(camel, snake, v string) {
+	if v == "" && w.opts.OmitEmpty {
+		return
+	}
+	w.key(camel, snake)
+	quoted, _ := json.Marshal(v) // Cannot fail for a string
+	w.buf = append(w.buf, quoted...)
+}
+
+func (w *jsonObject) int(camel, snake string, v int) {
+	if v == 0 && w.opts.OmitEmpty {
+		return
+	}
+	w.key(camel, snake)
+	w.buf = strconv.AppendInt(w.buf, int64(v), 10)
+}
+
+// float writes v even if it is zero, since zero coordinates and distances
+// are meaningful.
+func (w *jsonObject) float(camel, snake string, v float64) {
+	w.key(camel, snake)
+	w.buf = strconv.AppendFloat(w.buf, v, 'f', -1, 64)
+}
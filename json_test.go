@@ -0,0 +1,44 @@
+package geodecode_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestLocationJSON(t *testing.T) {
+	loc := geodecode.Location{
+		Lat:         52.52437,
+		Lon:         13.41053,
+		City:        "Berlin",
+		Admin1Code:  "16",
+		CC:          "DE",
+		Country:     "Germany",
+		CountryInfo: geodecode.CountryInfo{ISO3: "DEU", CallingCode: "+49"},
+	}
+
+	for _, tc := range []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"default", loc, `{"geonameId":0,"lat":52.52437,"lon":13.41053,"city":"Berlin","admin1":"","admin1Code":"16","admin2":"","admin2Name":"","cc":"DE","country":"Germany","timezone":"","elevation":0,"population":0,"countryInfo":{"iso3":"DEU","continent":"","currency":"","callingCode":"+49"}}`},
+		{"snake case, omit empty", geodecode.JSONOptions{SnakeCase: true, OmitEmpty: true}.Location(loc), `{"lat":52.52437,"lon":13.41053,"city":"Berlin","admin1_code":"16","cc":"DE","country":"Germany","country_info":{"iso3":"DEU","calling_code":"+49"}}`},
+		{"array", geodecode.JSONOptions{OmitEmpty: true}.Locations([]geodecode.Location{{City: "Null Island"}, {Lat: 1, City: `"Quoted" <City>`}}), `[{"lat":0,"lon":0,"city":"Null Island"},{"lat":1,"lon":0,"city":"\"Quoted\" \u003cCity\u003e"}]`},
+	} {
+		got, err := json.Marshal(tc.v)
+		if err != nil {
+			t.Fatalf("%s: Marshal: %v", tc.name, err)
+		}
+		if string(got) != tc.want {
+			t.Errorf("%s: Expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+
+	var decoded geodecode.Location
+	raw, _ := json.Marshal(loc)
+	if err := json.Unmarshal(raw, &decoded); err != nil || decoded != loc {
+		t.Errorf("Expected the default encoding to decode back to %+v, got %+v (%v)", loc, decoded, err)
+	}
+}
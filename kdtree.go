@@ -0,0 +1,247 @@
+package geodecode
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// spatialIndex finds the location nearest to a coordinate. It is implemented
+// by kdTree, gridIndex and vpTree.
+type spatialIndex interface {
+	// nearest returns the index of the location nearest to coord and its
+	// squared distance as compared under the index's metric, or -1 if the
+	// index is empty. Of several locations at the same distance, the one
+	// loaded first is returned. If trace is not nil, the work done is
+	// counted in it.
+	nearest(coord [2]float64, trace *QueryTrace) (int, float64)
+
+	// bytes returns the memory held by the index.
+	bytes() int64
+}
+
+// kdTree is the KD-Tree selected with KDTreeIndex. It is stored implicitly in
+// a flat slice: the node of the range nodes[lo:hi] is nodes[(lo+hi)/2], and it
+// splits the other nodes of the range along its plane into the ranges before
+// and after it. Ranges of up to leafSize nodes are leaves, which are not
+// split and are scanned linearly. The tree is never modified once built, so
+// it can be searched concurrently. Its points are stored as F, float32 with
+// WithFloat32Coordinates and float64 otherwise.
+type kdTree[F float32 | float64] struct {
+	metric   Metric
+	leafSize int
+	nodes    []kdNode[F]
+}
+
+// kdNode is a node of a kdTree.
+type kdNode[F float32 | float64] struct {
+	point [3]F  // Coordinates as returned by indexPoint
+	index int32 // Index of the location
+	plane uint8 // Dimension the node splits its range along; 0 in leaves
+}
+
+// newKDTree builds a KD-Tree over locs for metric with leaves of up to
+// leafSize points.
+func newKDTree[F float32 | float64](locs []Location, metric Metric, leafSize int) *kdTree[F] {
+	t := &kdTree[F]{metric: metric, leafSize: leafSize, nodes: make([]kdNode[F], len(locs))}
+	for i, loc := range locs {
+		p := indexPoint(metric, loc.Lat, loc.Lon)
+		t.nodes[i] = kdNode[F]{point: [3]F{F(p[0]), F(p[1]), F(p[2])}, index: int32(i)}
+	}
+	b := kdBuild[F]{dims: metric.dims(), leafSize: leafSize, workers: make(chan struct{}, runtime.GOMAXPROCS(0)-1)}
+	b.build(t.nodes)
+	b.wg.Wait()
+	return t
+}
+
+// defaultLeafSize is the number of points a KD-Tree leaf holds unless set
+// with WithLeafSize. Scanning a few adjacent points is cheaper than
+// descending further.
+const defaultLeafSize = 8
+
+// parallelBuildMin is the smallest range whose subtrees are built
+// concurrently; smaller ranges are not worth a goroutine.
+const parallelBuildMin = 1 << 14
+
+// kdBuild is the state of a KD-Tree construction.
+type kdBuild[F float32 | float64] struct {
+	dims     int
+	leafSize int
+	workers  chan struct{} // Holds a token per goroutine building a subtree
+	wg       sync.WaitGroup
+}
+
+// build arranges nodes as a KD-Tree. Each range larger than a leaf is split
+// at its median along the dimension in which its points are spread the
+// widest. The
+// subtrees of large ranges are built concurrently, on at most GOMAXPROCS
+// goroutines in total.
+func (b *kdBuild[F]) build(nodes []kdNode[F]) {
+	for len(nodes) > b.leafSize {
+		plane := widestDim(nodes, b.dims)
+		mid := len(nodes) / 2
+		selectKD(nodes, mid, plane)
+		nodes[mid].plane = uint8(plane)
+		left := nodes[:mid]
+		nodes = nodes[mid+1:]
+		if len(left) < parallelBuildMin {
+			b.build(left)
+			continue
+		}
+		select {
+		case b.workers <- struct{}{}:
+			b.wg.Add(1)
+			go func() {
+				defer b.wg.Done()
+				b.build(left)
+				<-b.workers
+			}()
+		default:
+			b.build(left)
+		}
+	}
+}
+
+// widestDim returns the dimension in which nodes are spread the widest.
+func widestDim[F float32 | float64](nodes []kdNode[F], dims int) int {
+	lo := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	hi := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for i := range nodes {
+		for d := range dims {
+			lo[d] = min(lo[d], float64(nodes[i].point[d]))
+			hi[d] = max(hi[d], float64(nodes[i].point[d]))
+		}
+	}
+	widest := 0
+	for d := 1; d < dims; d++ {
+		if hi[d]-lo[d] > hi[widest]-lo[widest] {
+			widest = d
+		}
+	}
+	return widest
+}
+
+// selectKD reorders nodes so that nodes[k] holds the node that would be there
+// if they were sorted along plane, with no node before it greater and no node
+// after it smaller.
+func selectKD[F float32 | float64](nodes []kdNode[F], k, plane int) {
+	lo, hi := 0, len(nodes)-1
+	for lo < hi {
+		pivot := nodes[lo+(hi-lo)/2].point[plane]
+		i, j := lo, hi
+		for i <= j {
+			for nodes[i].point[plane] < pivot {
+				i++
+			}
+			for nodes[j].point[plane] > pivot {
+				j--
+			}
+			if i <= j {
+				nodes[i], nodes[j] = nodes[j], nodes[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// nearest implements spatialIndex. trace counts the nodes entered and the
+// running best matches.
+func (t *kdTree[F]) nearest(coord [2]float64, trace *QueryTrace) (int, float64) {
+	s := kdSearch[F]{nodes: t.nodes, leafSize: t.leafSize, q: indexPoint(t.metric, coord[0], coord[1]), w: indexWeights(t.metric, coord[0]), best: -1, dist: math.Inf(1), trace: trace}
+	s.search(0, len(t.nodes))
+	return s.best, s.dist
+}
+
+// nearestK returns the k locations nearest to coord, as compared under the
+// tree's metric, nearest first.
+func (t *kdTree[F]) nearestK(coord [2]float64, k int) []neighbor {
+	knn := &neighbors{k: k}
+	s := kdSearch[F]{nodes: t.nodes, leafSize: t.leafSize, q: indexPoint(t.metric, coord[0], coord[1]), w: indexWeights(t.metric, coord[0]), best: math.MaxInt, dist: math.Inf(1), knn: knn}
+	s.search(0, len(t.nodes))
+	return knn.list
+}
+
+// kdSearch is the state of a nearest neighbor search in a kdTree.
+type kdSearch[F float32 | float64] struct {
+	nodes    []kdNode[F]
+	leafSize int
+	q        [3]float64
+	w        [3]float64 // Weights of the dimensions, as returned by indexWeights
+	best     int        // Location index of the running best match
+	dist     float64    // Squared distance of the running best match, or bound of knn
+	knn      *neighbors // Collects the k nearest matches instead, with best math.MaxInt, if not nil
+	trace    *QueryTrace
+}
+
+// search searches the subtree of the range nodes[lo:hi].
+func (s *kdSearch[F]) search(lo, hi int) {
+	for hi-lo > s.leafSize {
+		mid := lo + (hi-lo)/2
+		n := &s.nodes[mid]
+		s.visit(n)
+
+		// Search the side of the plane the query is on first, then the other
+		// side if it may hold a match at least as close.
+		diff := s.q[n.plane] - float64(n.point[n.plane])
+		if diff < 0 {
+			s.search(lo, mid)
+			lo = mid + 1
+		} else {
+			s.search(mid+1, hi)
+			hi = mid
+		}
+		if s.w[n.plane]*diff*diff > s.dist {
+			return
+		}
+	}
+	for i := lo; i < hi; i++ {
+		s.visit(&s.nodes[i])
+	}
+}
+
+// visit makes n the running best match if it is closer than the current one,
+// or offers it to knn.
+func (s *kdSearch[F]) visit(n *kdNode[F]) {
+	if s.trace != nil {
+		s.trace.NodesVisited++
+	}
+	dx, dy, dz := s.q[0]-float64(n.point[0]), s.q[1]-float64(n.point[1]), s.q[2]-float64(n.point[2])
+	if d := s.w[0]*dx*dx + s.w[1]*dy*dy + s.w[2]*dz*dz; d < s.dist || (d == s.dist && int(n.index) < s.best) {
+		if s.knn != nil {
+			s.knn.offer(int(n.index), d)
+			s.dist = s.knn.bound()
+			return
+		}
+		if s.trace != nil && d < s.dist {
+			s.trace.Candidates++
+		}
+		s.best, s.dist = int(n.index), d
+	}
+}
+
+// depth returns the number of levels of the tree, counting leaves as one.
+func (t *kdTree[F]) depth() int {
+	depth := 0
+	for n := len(t.nodes); n > 0; n /= 2 {
+		depth++
+		if n <= t.leafSize {
+			break
+		}
+	}
+	return depth
+}
+
+// bytes implements spatialIndex.
+func (t *kdTree[F]) bytes() int64 {
+	return int64(cap(t.nodes)) * int64(unsafe.Sizeof(kdNode[F]{}))
+}
@@ -0,0 +1,90 @@
+package geodecode_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestConcurrentTreeBuilds(t *testing.T) {
+	coords := randomCoords(500)
+	metrics := []geodecode.Metric{geodecode.MetricEuclidean, geodecode.MetricHaversine}
+	want := make(map[geodecode.Metric][]geodecode.Location)
+	for _, metric := range metrics {
+		geocoder, _ := geodecode.New(geodecode.WithMetric(metric))
+		for _, coord := range coords {
+			loc, _ := geocoder.QueryDebug(coord)
+			want[metric] = append(want[metric], loc)
+		}
+	}
+
+	// Trees built at the same time must not affect each other.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		metric := metrics[i%len(metrics)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			geocoder, err := geodecode.New(geodecode.WithMetric(metric))
+			if err != nil {
+				t.Errorf("New: %v", err)
+				return
+			}
+			if err := geocoder.Load(); err != nil {
+				t.Errorf("Load: %v", err)
+				return
+			}
+			for j, coord := range coords {
+				if got, _ := geocoder.QueryDebug(coord); got != want[metric][j] {
+					t.Errorf("%s: expected %s for %v, got %s", metric, want[metric][j].City, coord, got.City)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWithLeafSize(t *testing.T) {
+	if _, err := geodecode.New(geodecode.WithLeafSize(0)); err == nil {
+		t.Errorf("Expected an error for a leaf size of 0")
+	}
+
+	coords := randomCoords(1000)
+	full, _ := geodecode.New(geodecode.WithLeafSize(1))
+	fullDepth := full.Stats().TreeDepth
+	for _, leaf := range []int{3, 64} {
+		geocoder, err := geodecode.New(geodecode.WithLeafSize(leaf))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		for _, coord := range coords {
+			want, _ := full.QueryDebug(coord)
+			if got, _ := geocoder.QueryDebug(coord); got != want {
+				t.Errorf("Leaf size %d: expected %s for %v, got %s", leaf, want.City, coord, got.City)
+			}
+		}
+		if depth := geocoder.Stats().TreeDepth; depth >= fullDepth {
+			t.Errorf("Leaf size %d: expected a depth below %d, got %d", leaf, fullDepth, depth)
+		}
+	}
+
+	// Saved indexes keep their leaf size.
+	path := filepath.Join(t.TempDir(), "cities.idx")
+	geocoder, _ := geodecode.New(geodecode.WithLeafSize(64))
+	if err := geocoder.SaveIndex(path); err != nil {
+		t.Fatalf("SaveIndex: %v", err)
+	}
+	loaded, _ := geodecode.New(geodecode.WithLeafSize(64))
+	if err := loaded.LoadIndex(path); err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	for _, coord := range coords[:100] {
+		want, _ := full.QueryDebug(coord)
+		if got, _ := loaded.QueryDebug(coord); got != want {
+			t.Errorf("Expected %s for %v from index, got %s", want.City, coord, got.City)
+		}
+	}
+}
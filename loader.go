@@ -0,0 +1,779 @@
+package geodecode
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// LoadOption configures how a dataset is parsed by LoadFromReader,
+// LoadFromFile, WithDataset and WithEmbeddedDataset.
+type LoadOption func(*loadConfig)
+
+// Format identifies the file format of a dataset.
+type Format int
+
+const (
+	// FormatCSV is the package's own CSV format with a header row containing
+	// at least the columns lat, lon, city, admin1, admin2 and cc.
+	FormatCSV Format = iota
+	// FormatGeoNames is the tab-separated GeoNames dump format used by
+	// cities500.txt, cities1000.txt, cities5000.txt and cities15000.txt.
+	// Admin1 and Admin2 hold the raw GeoNames administrative codes.
+	FormatGeoNames
+	// FormatParquet is an Apache Parquet file with the same columns as
+	// FormatCSV. Column names can be mapped with WithColumns. Flat schemas
+	// with uncompressed, Snappy or gzip compressed pages and PLAIN or
+	// dictionary-encoded values are supported.
+	FormatParquet
+	// FormatBinary is the package's compact binary format written by
+	// WriteBinary. It loads much faster than the text formats, and is used
+	// for the embedded dataset.
+	FormatBinary
+)
+
+// loadConfig holds the settings assembled from LoadOptions.
+type loadConfig struct {
+	format         Format
+	strict         bool
+	featureClasses []string          // GeoNames feature classes to keep; empty keeps all
+	featureCodes   []string          // GeoNames feature code patterns to keep; empty keeps all
+	admin1Names    map[string]string // Admin1 names keyed by "CC.code"
+	keepAdmin1Code bool
+	admin2Names    map[string]string // Admin2 names keyed by "CC.admin1.admin2"
+	altNames       map[nameKey]string
+	cacheDir       string          // Cache directory for remote datasets
+	httpClient     *http.Client    // Client for remote datasets
+	countries      map[string]bool // Country codes to keep; empty keeps all
+	minPopulation  int
+	columns        map[string]string     // CSV header names keyed by the package's column names
+	delimiter      rune                  // CSV field delimiter; 0 means comma
+	filters        []func(Location) bool // Predicates a location must satisfy to be kept
+	progress       func(done, total int) // Called as the dataset is read; may be nil
+	mmap           bool                  // Memory-map FormatBinary files instead of reading them
+	err            error                 // First error encountered while applying options
+}
+
+// newLoadConfig applies opts to a default loadConfig.
+func newLoadConfig(opts []LoadOption) *loadConfig {
+	cfg := &loadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithFormat sets the file format of the dataset. The default is FormatCSV.
+//
+// Example usage:
+//
+//	err := geocoder.LoadFromFile("cities15000.txt", geodecode.WithFormat(geodecode.FormatGeoNames))
+func WithFormat(format Format) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.format = format
+	}
+}
+
+// WithFeatureClasses restricts a GeoNames dataset to rows whose feature class
+// is one of classes, for example "P" for populated places or "A" for
+// administrative areas. It has no effect on CSV datasets.
+func WithFeatureClasses(classes ...string) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.featureClasses = append(cfg.featureClasses, classes...)
+	}
+}
+
+// WithFeatureCodes restricts a GeoNames dataset to rows whose feature code
+// matches one of codes. A code ending in "*" matches every feature code with
+// that prefix, so "PPLA*" keeps all seats of administrative divisions.
+// It has no effect on CSV datasets.
+//
+// Example usage, loading villages, towns and cities from allCountries.txt:
+//
+//	err := geocoder.LoadFromFile("allCountries.txt",
+//	    geodecode.WithFormat(geodecode.FormatGeoNames),
+//	    geodecode.WithFeatureClasses("P"),
+//	    geodecode.WithFeatureCodes("PPL*"),
+//	)
+func WithFeatureCodes(codes ...string) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.featureCodes = append(cfg.featureCodes, codes...)
+	}
+}
+
+// WithAdmin1Names resolves the Admin1 field of loaded locations from raw
+// codes (e.g. "CA" or "06") to human-readable names (e.g. "California") using
+// a GeoNames admin1CodesASCII.txt table read from r. The table is read when the
+// option is applied; codes missing from the table are left unchanged.
+//
+// Example usage:
+//
+//	codes, err := os.Open("admin1CodesASCII.txt")
+//	if err != nil {
+//	    return err
+//	}
+//	defer codes.Close()
+//	err = geocoder.LoadFromFile("cities15000.txt",
+//	    geodecode.WithFormat(geodecode.FormatGeoNames),
+//	    geodecode.WithAdmin1Names(codes),
+//	)
+func WithAdmin1Names(r io.Reader) LoadOption {
+	return func(cfg *loadConfig) {
+		names, err := readAdminCodes(r)
+		if err != nil {
+			cfg.setErr(err)
+			return
+		}
+		cfg.admin1Names = names
+	}
+}
+
+// WithAdmin1Code keeps the raw admin1 code in Location.Admin1Code when
+// WithAdmin1Names replaces Admin1 with the division's name.
+func WithAdmin1Code() LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.keepAdmin1Code = true
+	}
+}
+
+// WithAdmin2Names fills in Location.Admin2Name with the county or district
+// name of each loaded location, using a GeoNames admin2Codes.txt table read
+// from r. The raw admin2 code stays in Admin2. The table is read when the
+// option is applied; locations without a matching entry get an empty name.
+func WithAdmin2Names(r io.Reader) LoadOption {
+	return func(cfg *loadConfig) {
+		names, err := readAdminCodes(r)
+		if err != nil {
+			cfg.setErr(err)
+			return
+		}
+		cfg.admin2Names = names
+	}
+}
+
+// WithAlternateNames loads localized city names from a GeoNames
+// alternateNamesV2.txt table read from r, keeping only names in the given
+// ISO language codes (all languages if none are given; the full table is
+// large, so restricting the languages is recommended). Combine it with the
+// WithLanguage option to return localized names from queries.
+// Localized names are matched by geonameid, so they are only available for
+// datasets that carry one, such as FormatGeoNames files.
+//
+// Example usage:
+//
+//	names, err := os.Open("alternateNamesV2.txt")
+//	if err != nil {
+//	    return err
+//	}
+//	defer names.Close()
+//	geocoder, err := geodecode.New(
+//	    geodecode.WithDataset("cities15000.txt",
+//	        geodecode.WithFormat(geodecode.FormatGeoNames),
+//	        geodecode.WithAlternateNames(names, "de"),
+//	    ),
+//	    geodecode.WithLanguage("de"),
+//	)
+func WithAlternateNames(r io.Reader, languages ...string) LoadOption {
+	return func(cfg *loadConfig) {
+		names, err := readAlternateNames(r, languages)
+		if err != nil {
+			cfg.setErr(err)
+			return
+		}
+		cfg.altNames = names
+	}
+}
+
+// Columns maps the columns of a CSV or Parquet dataset to the names used in
+// the file, for loading extracts whose header differs from the package's own
+// format. Empty fields keep the default column name. Nested Parquet columns
+// are named by their dotted path, such as "geo.lat".
+type Columns struct {
+	Lat        string // Default "lat".
+	Lon        string // Default "lon".
+	City       string // Default "city".
+	Admin1     string // Default "admin1".
+	Admin2     string // Default "admin2".
+	CC         string // Default "cc".
+	GeonameID  string // Default "geonameid".
+	Timezone   string // Default "timezone".
+	Elevation  string // Default "elevation".
+	Population string // Default "population".
+}
+
+// WithColumns reads a CSV or Parquet dataset using the column names given
+// in cols instead of the default ones.
+//
+// Example usage, loading a semicolon-separated extract:
+//
+//	err := geocoder.LoadFromFile("sites.csv",
+//	    geodecode.WithColumns(geodecode.Columns{Lat: "latitude", Lon: "longitude", City: "name", CC: "country"}),
+//	    geodecode.WithDelimiter(';'),
+//	)
+func WithColumns(cols Columns) LoadOption {
+	return func(cfg *loadConfig) {
+		if cfg.columns == nil {
+			cfg.columns = make(map[string]string)
+		}
+		for name, mapped := range map[string]string{
+			"lat":        cols.Lat,
+			"lon":        cols.Lon,
+			"city":       cols.City,
+			"admin1":     cols.Admin1,
+			"admin2":     cols.Admin2,
+			"cc":         cols.CC,
+			"geonameid":  cols.GeonameID,
+			"timezone":   cols.Timezone,
+			"elevation":  cols.Elevation,
+			"population": cols.Population,
+		} {
+			if mapped != "" {
+				cfg.columns[name] = mapped
+			}
+		}
+	}
+}
+
+// WithDelimiter sets the field delimiter of a CSV dataset, for example '\t'
+// or ';'. The default is a comma.
+func WithDelimiter(delimiter rune) LoadOption {
+	return func(cfg *loadConfig) {
+		if delimiter == '\r' || delimiter == '\n' || delimiter == '"' || delimiter == 0 || delimiter == utf8.RuneError {
+			cfg.setErr(fmt.Errorf("geodecode: invalid CSV delimiter %q", delimiter))
+			return
+		}
+		cfg.delimiter = delimiter
+	}
+}
+
+// WithCountryFilter restricts the dataset to locations in the countries
+// identified by the given ISO 3166-1 alpha-2 codes (case-insensitive). Only
+// the selected countries are kept in memory.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithEmbeddedDataset(geodecode.WithCountryFilter("US", "CA")))
+func WithCountryFilter(codes ...string) LoadOption {
+	return func(cfg *loadConfig) {
+		if cfg.countries == nil {
+			cfg.countries = make(map[string]bool, len(codes))
+		}
+		for _, code := range codes {
+			cfg.countries[strings.ToUpper(code)] = true
+		}
+	}
+}
+
+// WithMinPopulation restricts the dataset to locations with at least n
+// inhabitants. Locations without population data, including every location
+// of datasets that lack a population column, are dropped.
+func WithMinPopulation(n int) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.minPopulation = n
+	}
+}
+
+// WithFilter restricts the dataset to locations for which keep returns true.
+// keep sees locations after administrative names have been resolved. It may
+// be given several times; a location is kept only if every filter accepts it.
+func WithFilter(keep func(Location) bool) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.filters = append(cfg.filters, keep)
+	}
+}
+
+// WithLoadProgress makes loading call progress as the dataset is read, so
+// long loads can drive progress bars and readiness probes. done is the number
+// of bytes read so far and total the size of the dataset, or -1 if it is not
+// known in advance, as for streams. For compressed datasets both count
+// compressed bytes. A final call with done equal to total is made once the
+// data has been parsed; building the KD-Tree takes a little longer.
+//
+// Example usage:
+//
+//	err := geocoder.LoadFromFile("allCountries.txt", geodecode.WithFormat(geodecode.FormatGeoNames),
+//	    geodecode.WithLoadProgress(func(done, total int) {
+//	        fmt.Printf("\rloading: %d%%", done*100/max(total, 1))
+//	    }))
+func WithLoadProgress(progress func(done, total int)) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.progress = progress
+	}
+}
+
+// WithMemoryMap makes loading map FormatBinary files into memory read-only
+// instead of reading them. The names of the locations are then used straight
+// from the mapped file, so processes on the same host that load the same file
+// share its pages in the operating system's page cache, and loading does not
+// copy the file at all. Other formats, and binary files that are compressed,
+// are read as usual. On platforms without mmap the file is read into memory.
+//
+// Mappings are never released, since the names of returned Locations may
+// still point into them; loading an unchanged file again reuses its mapping.
+// A mapped file must not be modified in place while it is in use: replace it
+// by writing a new file and renaming it over the old one.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithDataset("/var/lib/geodecode/cities.bin",
+//	    geodecode.WithFormat(geodecode.FormatBinary), geodecode.WithMemoryMap()))
+func WithMemoryMap() LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.mmap = true
+	}
+}
+
+// WithStrict makes loading fail on the first malformed row instead of
+// skipping it.
+func WithStrict() LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.strict = true
+	}
+}
+
+// setErr records err unless an earlier error was already recorded.
+func (cfg *loadConfig) setErr(err error) {
+	if cfg.err == nil {
+		cfg.err = err
+	}
+}
+
+// requiredCols lists the CSV header columns every dataset must provide.
+var requiredCols = []string{"lat", "lon", "city", "admin1", "admin2", "cc"}
+
+// optionalCols lists the CSV header columns a dataset may provide.
+var optionalCols = []string{"geonameid", "timezone", "elevation", "population"}
+
+// column returns the header name of the CSV column the package calls name.
+func (cfg *loadConfig) column(name string) string {
+	if mapped, ok := cfg.columns[name]; ok {
+		return mapped
+	}
+	return name
+}
+
+// LoadFromFile replaces the geocoder's dataset with the cities read from the
+// file at path. By default the file must be a CSV file with a header row
+// containing at least the columns lat, lon, city, admin1, admin2 and cc; use
+// WithFormat to load other formats. Gzip-compressed files are decompressed
+// transparently.
+// Once a dataset has been loaded this way, the embedded data is no longer
+// loaded lazily on the first query, and Reload reads the file again.
+func (rg *RGeocoder) LoadFromFile(path string, opts ...LoadOption) error {
+	return rg.loadFrom(source{kind: sourceFile, location: path, cfg: newLoadConfig(opts)})
+}
+
+// LoadFromReader replaces the geocoder's dataset with the cities read from r,
+// which must provide one of the formats accepted by LoadFromFile. This allows
+// loading data from HTTP responses, compressed streams, assets embedded in the
+// calling program or test fixtures.
+//
+// Example usage:
+//
+//	gz, err := gzip.NewReader(resp.Body)
+//	if err != nil {
+//	    return err
+//	}
+//	err = geocoder.LoadFromReader(gz)
+func (rg *RGeocoder) LoadFromReader(r io.Reader, opts ...LoadOption) error {
+	startTime := time.Now()
+	cfg := newLoadConfig(opts)
+	locations, err := rg.read(r, cfg)
+	if err != nil {
+		return err
+	}
+	src := source{kind: sourceReader, cfg: cfg}
+	ds := rg.newDataset(locations, cfg.altNames)
+	ds.setLoaded(src, startTime)
+	rg.install(ds, src)
+	return nil
+}
+
+// ReadLocations parses a dataset from r without building an index, accepting
+// the same formats and options as LoadFromReader. It is useful for tools that
+// convert or inspect datasets.
+func ReadLocations(r io.Reader, opts ...LoadOption) ([]Location, error) {
+	return (&RGeocoder{}).read(r, newLoadConfig(opts))
+}
+
+// readFile parses the dataset stored at path.
+func (rg *RGeocoder) readFile(path string, cfg *loadConfig) ([]Location, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: opening data file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := mapBinary(file, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		return readBlob(data, cfg)
+	}
+	return rg.read(file, cfg)
+}
+
+// mapBinary memory-maps file if WithMemoryMap is set and it holds an
+// uncompressed binary dataset. It returns nil if the file is to be read as
+// usual.
+func mapBinary(file *os.File, cfg *loadConfig) ([]byte, error) {
+	if !cfg.mmap || cfg.format != FormatBinary || cfg.err != nil {
+		return nil, nil
+	}
+	magic := make([]byte, len(binaryMagic))
+	if _, err := file.ReadAt(magic, 0); err != nil || string(magic) != binaryMagic {
+		return nil, nil
+	}
+	return mapFile(file)
+}
+
+// read parses a dataset from r in the format selected by cfg.
+func (rg *RGeocoder) read(r io.Reader, cfg *loadConfig) ([]Location, error) {
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+
+	size := sizeOf(r)
+	var pr *progressReader
+	if cfg.progress != nil {
+		pr = &progressReader{r: r, total: size, report: cfg.progress}
+		r = pr
+	}
+
+	r, err := decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.(*gzip.Reader); ok {
+		size = -1 // The size of the decompressed data is unknown
+	}
+
+	var locations []Location
+	switch cfg.format {
+	case FormatCSV:
+		locations, err = rg.readCSV(r, cfg, size)
+	case FormatGeoNames:
+		locations, err = rg.readGeoNames(r, cfg, size)
+	case FormatParquet:
+		locations, err = rg.readParquet(r, cfg)
+	case FormatBinary:
+		locations, err = readBinary(r)
+	default:
+		return nil, fmt.Errorf("geodecode: unknown dataset format %d", cfg.format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.resolveAdminNames(locations)
+	if pr != nil {
+		pr.finish()
+	}
+	return cfg.filter(locations)
+}
+
+// progressReader reports the number of bytes read through it.
+type progressReader struct {
+	r      io.Reader
+	done   int
+	total  int // -1 if unknown
+	report func(done, total int)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.done += n
+		p.report(p.done, p.total)
+	}
+	return n, err
+}
+
+// finish reports that the whole dataset has been read.
+func (p *progressReader) finish() {
+	if p.total < 0 {
+		p.total = p.done
+	}
+	p.report(p.total, p.total)
+}
+
+// sizeOf returns the number of bytes left in r, or -1 if it cannot be
+// determined without reading.
+func sizeOf(r io.Reader) int {
+	switch r := r.(type) {
+	case interface{ Len() int }: // bytes.Reader, strings.Reader
+		return r.Len()
+	case interface{ Size() int64 }: // sizedReader
+		return int(r.Size())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return int(info.Size() - offset)
+	}
+	return -1
+}
+
+// sizedReader is a reader whose size is known in advance, such as an HTTP
+// response body with a Content-Length.
+type sizedReader struct {
+	io.Reader
+	size int64
+}
+
+func (r sizedReader) Size() int64 { return r.size }
+
+// filter returns the locations accepted by the filters configured with
+// WithCountryFilter, WithMinPopulation and WithFilter. The result does not
+// share memory with locations, so rejected rows can be garbage collected.
+func (cfg *loadConfig) filter(locations []Location) ([]Location, error) {
+	if len(cfg.countries) == 0 && cfg.minPopulation <= 0 && len(cfg.filters) == 0 {
+		return locations, nil
+	}
+
+	var kept []Location
+	for _, loc := range locations {
+		if cfg.keep(loc) {
+			kept = append(kept, loc)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, errors.New("geodecode: no locations left after filtering")
+	}
+	return slices.Clip(kept), nil
+}
+
+// keep reports whether loc passes every configured filter.
+func (cfg *loadConfig) keep(loc Location) bool {
+	if len(cfg.countries) > 0 && !cfg.countries[strings.ToUpper(loc.CC)] {
+		return false
+	}
+	if cfg.minPopulation > 0 && loc.Population < cfg.minPopulation {
+		return false
+	}
+	for _, keep := range cfg.filters {
+		if !keep(loc) {
+			return false
+		}
+	}
+	return true
+}
+
+// gzipMagic is the header that starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompress returns a reader yielding the decompressed contents of r if r
+// holds a gzip stream, and the contents of r unchanged otherwise.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil || !bytes.Equal(magic, gzipMagic) {
+		return br, nil // Too short or not compressed; let the parser report problems
+	}
+	gz, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: reading gzip header: %w", err)
+	}
+	return gz, nil
+}
+
+// readCSV parses a CSV dataset of size bytes, or -1 if unknown, from r. Rows
+// with unreadable records or invalid coordinates are skipped unless
+// cfg.strict is set. The optional columns geonameid, timezone, elevation and
+// population fill the corresponding Location fields. It returns an error if
+// the header is missing a required column or no valid row was found.
+func (rg *RGeocoder) readCSV(r io.Reader, cfg *loadConfig, size int) ([]Location, error) {
+	reader := newCSVReader(r, cfg)
+	reader.ReuseRecord = true // Fields are copied into the locations
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: reading CSV header: %w", err)
+	}
+
+	colMap, missing := csvColumns(header, cfg)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("geodecode: CSV file missing required column: %s", missing[0])
+	}
+
+	colLat, colLon, colCity := colMap["lat"], colMap["lon"], colMap["city"]
+	colAdmin1, colAdmin2, colCC := colMap["admin1"], colMap["admin2"], colMap["cc"]
+	colGeonameID, hasGeonameID := colMap["geonameid"]
+	colTimezone, hasTimezone := colMap["timezone"]
+	colElevation, hasElevation := colMap["elevation"]
+	colPopulation, hasPopulation := colMap["population"]
+
+	loadedLocations := make([]Location, 0, sizeSampleRows)
+	strs := make(stringInterner)
+
+	for i := 0; ; i++ { // Start from 0 for index, CSV row number starts at 1 (after header)
+		if i == sizeSampleRows {
+			loadedLocations = growToEstimate(loadedLocations, int(reader.InputOffset()), size)
+		}
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if cfg.strict {
+				return nil, fmt.Errorf("geodecode: reading CSV row %d: %w", i+1, err)
+			}
+			rg.log(slog.LevelWarn, "geodecode: skipping row with read error", "row", i+1, "error", err)
+			continue
+		}
+
+		latStr := record[colLat]
+		lonStr := record[colLon]
+
+		lat, errLat := strconv.ParseFloat(latStr, 64)
+		lon, errLon := strconv.ParseFloat(lonStr, 64)
+
+		if errLat != nil || errLon != nil || !validCoordinate(lat, lon) {
+			if cfg.strict {
+				return nil, fmt.Errorf("geodecode: CSV row %d has invalid coordinates: lat='%s', lon='%s'", i+1, latStr, lonStr)
+			}
+			if rg.verbose {
+				rg.log(slog.LevelWarn, "geodecode: skipping row with invalid coordinates",
+					"row", i+1, "lat", latStr, "lon", lonStr, "error", errors.Join(errLat, errLon))
+			}
+			continue
+		}
+
+		loc := Location{
+			Lat:    lat,
+			Lon:    lon,
+			City:   record[colCity],
+			Admin1: record[colAdmin1],
+			Admin2: record[colAdmin2],
+			CC:     record[colCC],
+		}
+		if hasGeonameID {
+			loc.GeonameID, _ = strconv.Atoi(record[colGeonameID])
+		}
+		if hasTimezone {
+			loc.Timezone = record[colTimezone]
+		}
+		if hasElevation {
+			loc.Elevation, _ = strconv.Atoi(record[colElevation])
+		}
+		if hasPopulation {
+			loc.Population, _ = strconv.Atoi(record[colPopulation])
+		}
+		loadedLocations = append(loadedLocations, strs.location(loc))
+	}
+
+	if len(loadedLocations) == 0 {
+		return nil, errors.New("geodecode: no valid coordinates loaded")
+	}
+	if rg.verbose {
+		rg.log(slog.LevelInfo, "geodecode: parsed CSV data", "locations", len(loadedLocations))
+	}
+	return loadedLocations, nil
+}
+
+// sizeSampleRows is the number of rows after which readCSV and readGeoNames
+// estimate how many locations the whole dataset holds, from the number of
+// bytes those rows took, and allocate room for them at once.
+const sizeSampleRows = 1024
+
+// growToEstimate returns locations with room for as many locations as a
+// dataset of size bytes is expected to yield, given that its first offset
+// bytes yielded locations. It returns locations unchanged if size is
+// unknown.
+func growToEstimate(locations []Location, offset, size int) []Location {
+	if size <= 0 || offset <= 0 || offset >= size {
+		return locations
+	}
+	// Allow for rows being somewhat shorter further on.
+	n := int(float64(len(locations)) * float64(size) / float64(offset) * 1.05)
+	if n <= cap(locations) {
+		return locations
+	}
+	return slices.Grow(locations, n-len(locations))
+}
+
+// newCSVReader returns a CSV reader for r using the delimiter set in cfg.
+func newCSVReader(r io.Reader, cfg *loadConfig) *csv.Reader {
+	reader := csv.NewReader(r)
+	if cfg.delimiter != 0 {
+		reader.Comma = cfg.delimiter
+	}
+	return reader
+}
+
+// csvColumns maps the package's column names to their position in header,
+// honoring the names set with WithColumns. It also returns the header names
+// of required columns that are missing.
+func csvColumns(header []string, cfg *loadConfig) (colMap map[string]int, missing []string) {
+	headerIndex := make(map[string]int, len(header))
+	for i, col := range header {
+		headerIndex[col] = i
+	}
+
+	colMap = make(map[string]int)
+	for _, col := range append(requiredCols, optionalCols...) {
+		if i, ok := headerIndex[cfg.column(col)]; ok {
+			colMap[col] = i
+		}
+	}
+	for _, col := range requiredCols {
+		if _, ok := colMap[col]; !ok {
+			missing = append(missing, cfg.column(col))
+		}
+	}
+	return colMap, missing
+}
+
+// newDataset builds the spatial index described by spec over locations and
+// returns the resulting dataset. No index is built for a single location. If
+// spec.single is set, coordinates are stored as float32, and the index is
+// built over a copy of locations with the coordinates rounded to float32, so
+// that it agrees with the stored locations. locations itself is not
+// modified; it may be shared, as the generated dataset is.
+func newDataset(locations []Location, names map[nameKey]string, spec indexSpec) *dataset {
+	if spec.single {
+		locations = slices.Clone(locations)
+		for i := range locations {
+			locations[i].Lat = float64(float32(locations[i].Lat))
+			locations[i].Lon = float64(float32(locations[i].Lon))
+		}
+	}
+	ds := &dataset{locations: newLocationTable(locations, spec.single), names: names, indexSpec: spec}
+	if len(locations) == 1 {
+		return ds
+	}
+
+	switch spec.index {
+	case GridIndex:
+		ds.grid = newGridIndex(locations, spec.metric)
+		return ds
+	case VPTreeIndex:
+		ds.vp = newVPTree(locations, spec.metric)
+		return ds
+	}
+
+	if spec.single {
+		ds.tree32 = newKDTree[float32](locations, spec.metric, spec.leaf())
+		return ds
+	}
+	ds.tree = newKDTree[float64](locations, spec.metric, spec.leaf())
+	return ds
+}
@@ -0,0 +1,31 @@
+package geodecode
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestNewDatasetFloat32KeepsLocations(t *testing.T) {
+	locs := []Location{
+		{Lat: 52.520008, Lon: 13.404954, City: "Berlin", CC: "DE"},
+		{Lat: 48.856613, Lon: 2.352222, City: "Paris", CC: "FR"},
+	}
+	orig := slices.Clone(locs)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ds := newDataset(locs, nil, indexSpec{single: true})
+			if got := ds.locations.at(0); got.Lat != float64(float32(orig[0].Lat)) {
+				t.Errorf("Expected the stored latitude to be rounded to float32, got %v", got.Lat)
+			}
+		}()
+	}
+	wg.Wait()
+	if !slices.Equal(locs, orig) {
+		t.Errorf("Expected the locations passed to newDataset to be unchanged, got %+v", locs)
+	}
+}
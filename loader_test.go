@@ -0,0 +1,252 @@
+package geodecode_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestLoadFromFile(t *testing.T) {
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromFile(filepath.Join("testdata", "cities.csv")); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+
+	results := geocoder.Query([2]float64{48.2, 11.6}, [2]float64{0, 0})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].City != "Munich" {
+		t.Errorf("Expected Munich, got %q", results[0].City)
+	}
+	// Only the custom dataset is loaded, so (0,0) resolves to Paris.
+	if results[1].City != "Paris" {
+		t.Errorf("Expected Paris for (0,0) in custom dataset, got %q", results[1].City)
+	}
+
+	if err := geocoder.LoadFromFile(filepath.Join("testdata", "missing.csv")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+}
+
+func TestWithDataset(t *testing.T) {
+	if _, err := geodecode.New(geodecode.WithDataset("")); err == nil {
+		t.Errorf("Expected an error for an empty dataset path")
+	}
+
+	geocoder, err := geodecode.New(geodecode.WithDataset(filepath.Join("testdata", "cities.csv")))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	results := geocoder.Query([2]float64{40.0, -75.0})
+	if len(results) != 1 || results[0].City != "New York City" {
+		t.Errorf("Expected New York City from custom dataset, got %+v", results)
+	}
+}
+
+func TestLoadFromReader(t *testing.T) {
+	data := "lat,lon,city,admin1,admin2,cc\n" +
+		"35.6895,139.69171,Tokyo,Tokyo,,JP\n" +
+		"34.69374,135.50218,Osaka,Osaka,,JP\n"
+
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	results := geocoder.Query([2]float64{34.7, 135.5})
+	if len(results) != 1 || results[0].City != "Osaka" {
+		t.Errorf("Expected Osaka, got %+v", results)
+	}
+
+	if err := geocoder.LoadFromReader(strings.NewReader("city,cc\nTokyo,JP\n")); err == nil {
+		t.Errorf("Expected an error for missing columns")
+	}
+}
+
+func TestLoadFromReaderStrict(t *testing.T) {
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	path := filepath.Join("testdata", "cities.csv")
+	if err := geocoder.LoadFromFile(path, geodecode.WithStrict()); err == nil {
+		t.Errorf("Expected strict loading of %s to fail on the malformed row", path)
+	}
+	if err := geocoder.LoadFromFile(path); err != nil {
+		t.Errorf("Expected lenient loading of %s to succeed, got %v", path, err)
+	}
+}
+
+func TestLoadFromReaderOptionalColumns(t *testing.T) {
+	data := "lat,lon,city,admin1,admin2,cc,geonameid,timezone\n" +
+		"35.6895,139.69171,Tokyo,Tokyo,,JP,1850147,Asia/Tokyo\n"
+
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	got := geocoder.Query([2]float64{35.7, 139.7})[0]
+	if got.GeonameID != 1850147 || got.Timezone != "Asia/Tokyo" {
+		t.Errorf("Expected geonameid 1850147 and timezone Asia/Tokyo, got %+v", got)
+	}
+}
+
+func TestLoadFromReaderGzip(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("lat,lon,city,admin1,admin2,cc\n-33.92584,18.42322,Cape Town,Western Cape,,ZA\n"))
+	gz.Close()
+
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(&buf); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	if results := geocoder.Query([2]float64{-34, 18.4}); len(results) != 1 || results[0].City != "Cape Town" {
+		t.Errorf("Expected Cape Town from gzip stream, got %+v", results)
+	}
+}
+
+func TestLoadFilters(t *testing.T) {
+	load := func(opts ...geodecode.LoadOption) ([]geodecode.Location, error) {
+		return geodecode.ReadLocations(openFixture(t, "cities.txt"),
+			append([]geodecode.LoadOption{geodecode.WithFormat(geodecode.FormatGeoNames)}, opts...)...)
+	}
+	cities := func(locs []geodecode.Location) []string {
+		var names []string
+		for _, loc := range locs {
+			names = append(names, loc.City)
+		}
+		return names
+	}
+
+	locs, err := load(geodecode.WithCountryFilter("fr", "US"))
+	if err != nil {
+		t.Fatalf("WithCountryFilter: %v", err)
+	}
+	if got := strings.Join(cities(locs), ","); got != "Paris,Los Angeles" {
+		t.Errorf("Expected Paris,Los Angeles, got %s", got)
+	}
+
+	locs, err = load(geodecode.WithMinPopulation(3000000), geodecode.WithFeatureClasses("P"))
+	if err != nil {
+		t.Fatalf("WithMinPopulation: %v", err)
+	}
+	if got := strings.Join(cities(locs), ","); got != "Berlin,Los Angeles" {
+		t.Errorf("Expected Berlin,Los Angeles, got %s", got)
+	}
+
+	locs, err = load(
+		geodecode.WithCountryFilter("DE"),
+		geodecode.WithFilter(func(loc geodecode.Location) bool { return strings.HasPrefix(loc.City, "H") }),
+	)
+	if err != nil {
+		t.Fatalf("WithFilter: %v", err)
+	}
+	if got := strings.Join(cities(locs), ","); got != "Hamburg" {
+		t.Errorf("Expected Hamburg, got %s", got)
+	}
+
+	if _, err := load(geodecode.WithCountryFilter("JP")); err == nil {
+		t.Errorf("Expected an error when the filters reject every location")
+	}
+}
+
+func TestLoadCustomColumns(t *testing.T) {
+	data := "name;country;latitude;longitude;state;county\n" +
+		"Warehouse North;DE;53.55;9.99;Hamburg;\n" +
+		"Warehouse South;DE;48.14;11.58;Bavaria;Upper Bavaria\n"
+
+	locs, err := geodecode.ReadLocations(strings.NewReader(data),
+		geodecode.WithColumns(geodecode.Columns{
+			Lat: "latitude", Lon: "longitude", City: "name", Admin1: "state", Admin2: "county", CC: "country",
+		}),
+		geodecode.WithDelimiter(';'),
+	)
+	if err != nil {
+		t.Fatalf("ReadLocations: %v", err)
+	}
+	if len(locs) != 2 || locs[1].City != "Warehouse South" || locs[1].Admin2 != "Upper Bavaria" || locs[1].Lat != 48.14 {
+		t.Errorf("Unexpected locations: %+v", locs)
+	}
+
+	_, err = geodecode.ReadLocations(strings.NewReader(data), geodecode.WithDelimiter(';'))
+	if err == nil || !strings.Contains(err.Error(), "lat") {
+		t.Errorf("Expected a missing column error, got %v", err)
+	}
+	if _, err := geodecode.ReadLocations(strings.NewReader(data), geodecode.WithDelimiter('\n')); err == nil {
+		t.Errorf("Expected an error for an invalid delimiter")
+	}
+}
+
+func TestLoadProgress(t *testing.T) {
+	path := filepath.Join("testdata", "cities.csv")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls [][2]int
+	progress := geodecode.WithLoadProgress(func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	})
+	geocoder, _ := geodecode.New()
+	if err := geocoder.LoadFromFile(path, progress); err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	size := int(info.Size())
+	if len(calls) < 2 || calls[len(calls)-1] != [2]int{size, size} {
+		t.Fatalf("Expected progress ending at %d of %d bytes, got %v", size, size, calls)
+	}
+	for i, c := range calls[:len(calls)-1] {
+		if c[1] != size || c[0] > size || (i > 0 && c[0] < calls[i-1][0]) {
+			t.Errorf("Unexpected progress %v in %v", c, calls)
+		}
+	}
+
+	// The size of a stream is unknown until it has been read.
+	calls = nil
+	if err := geocoder.LoadFromReader(io.MultiReader(openFixture(t, "cities.csv")), progress); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	if len(calls) < 2 || calls[0][1] != -1 || calls[len(calls)-1] != [2]int{size, size} {
+		t.Errorf("Expected an unknown total, then %d of %d bytes, got %v", size, size, calls)
+	}
+}
+
+func BenchmarkReadCSV(b *testing.B) {
+	geocoder, _ := geodecode.New()
+	var locs []geodecode.Location
+	for loc := range geocoder.Locations() {
+		locs = append(locs, loc)
+	}
+	var data bytes.Buffer
+	if err := geodecode.WriteCSV(&data, locs); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := geodecode.ReadLocations(bytes.NewReader(data.Bytes())); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
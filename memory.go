@@ -0,0 +1,40 @@
+package geodecode
+
+import "unsafe"
+
+// Footprint estimates the memory held by a geocoder's dataset, in bytes.
+// The estimate counts the data structures the package allocates; allocator
+// overhead and memory held by the garbage collector are not included, so
+// actual usage is somewhat higher.
+type Footprint struct {
+	Locations int64 // The location records: coordinates, numbers and string indexes.
+	Strings   int64 // String contents; strings shared by several locations count once.
+	Tree      int64 // KD-Tree or VP-Tree nodes and the points they hold, or the grid cells with GridIndex.
+	Names     int64 // Localized names loaded with WithAlternateNames.
+	Total     int64 // Sum of the above.
+}
+
+// MemoryFootprint estimates the memory used by the geocoder's dataset,
+// loading it first if necessary, for capacity planning. It walks the whole
+// dataset, so it is not meant to be called per query.
+func (rg *RGeocoder) MemoryFootprint() Footprint {
+	ds := rg.current()
+
+	var fp Footprint
+	fp.Locations = ds.locations.columnBytes()
+	fp.Strings, _ = ds.locations.stringBytes()
+
+	if sp := ds.spatial(); sp != nil {
+		fp.Tree = sp.bytes()
+	}
+
+	// A map entry holds the key, the string header of the value and roughly
+	// one byte of bucket metadata.
+	entry := int64(unsafe.Sizeof(nameKey{}) + unsafe.Sizeof("") + 1)
+	for _, name := range ds.names {
+		fp.Names += entry + int64(len(name))
+	}
+
+	fp.Total = fp.Locations + fp.Strings + fp.Tree + fp.Names
+	return fp
+}
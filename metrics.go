@@ -0,0 +1,158 @@
+package geodecode
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/sdwillbrand/GeoDecode/internal/prom"
+)
+
+// queryLatencyBuckets are the upper bounds, in seconds, of the buckets of
+// the query latency histogram, from a microsecond for cached results to ten
+// milliseconds for cold lookups.
+var queryLatencyBuckets = []float64{1e-6, 2.5e-6, 5e-6, 1e-5, 2.5e-5, 5e-5, 1e-4, 2.5e-4, 5e-4, 1e-3, 2.5e-3, 5e-3, 1e-2}
+
+// Outcomes of queries, counted by Collector.
+const (
+	outcomeFound = iota
+	outcomeNotFound
+	outcomeInvalid
+	outcomeUnavailable
+	numOutcomes
+)
+
+var outcomeNames = [numOutcomes]string{"found", "not_found", "invalid", "unavailable"}
+
+// Collector records metrics of the geocoder it is registered with using
+// WithCollector, and writes them in the Prometheus text exposition format:
+//
+//   - geodecode_queries_total, the coordinates resolved, by result: found,
+//     not_found, invalid or unavailable
+//   - geodecode_query_duration_seconds, a histogram of the time lookups took
+//   - geodecode_cache_hits_total, geodecode_cache_misses_total and
+//     geodecode_cache_entries, with WithCache or WithApproximate
+//   - geodecode_dataset_locations, geodecode_dataset_load_duration_seconds,
+//     geodecode_dataset_loaded_timestamp_seconds and geodecode_dataset_info,
+//     with the labels source and hash, once the dataset is loaded
+//
+// A Collector is an http.Handler, so it can serve /metrics directly. It is
+// safe for concurrent use.
+//
+// Example usage:
+//
+//	metrics := geodecode.NewCollector()
+//	geocoder, err := geodecode.New(geodecode.WithCollector(metrics))
+//	...
+//	http.Handle("GET /metrics", metrics)
+type Collector struct {
+	rg      atomic.Pointer[RGeocoder]
+	queries [numOutcomes]atomic.Uint64
+	latency *prom.Hist
+}
+
+// NewCollector returns a Collector, to be registered with WithCollector.
+func NewCollector() *Collector {
+	return &Collector{latency: prom.NewHist(queryLatencyBuckets...)}
+}
+
+// WithCollector records metrics of the geocoder's queries, cache and dataset
+// in c. A Collector can be registered with one geocoder only.
+func WithCollector(c *Collector) Option {
+	return func(rg *RGeocoder) error {
+		if c == nil {
+			return errors.New("geodecode: nil collector")
+		}
+		if !c.rg.CompareAndSwap(nil, rg) {
+			return errors.New("geodecode: collector registered with another geocoder")
+		}
+		rg.hooks = append(rg.hooks, c.observe)
+		return nil
+	}
+}
+
+// observe is the QueryHook of the collector.
+func (c *Collector) observe(coord [2]float64, loc Location, err error, latency time.Duration) {
+	outcome := outcomeFound
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrNoResult):
+		outcome = outcomeNotFound
+	case errors.Is(err, ErrInvalidCoordinate):
+		outcome = outcomeInvalid
+	default:
+		outcome = outcomeUnavailable
+	}
+	c.queries[outcome].Add(1)
+	if outcome <= outcomeNotFound {
+		// Only lookups that searched the dataset are timed.
+		c.latency.Observe(latency.Seconds())
+	}
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+// It does not load the dataset: the dataset metrics are left out until it
+// is loaded.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	pw := prom.NewWriter(cw)
+
+	pw.Header("geodecode_queries_total", "Coordinates resolved, by result.", prom.Counter)
+	for i, name := range outcomeNames {
+		pw.Sample("geodecode_queries_total", float64(c.queries[i].Load()), "result", name)
+	}
+	pw.Header("geodecode_query_duration_seconds", "Time taken to resolve a coordinate.", prom.Histogram)
+	c.latency.Write(pw, "geodecode_query_duration_seconds")
+
+	rg := c.rg.Load()
+	if rg == nil {
+		pw.Flush()
+		return cw.n, cw.err
+	}
+	if rg.cache != nil {
+		stats := rg.CacheStats()
+		pw.Header("geodecode_cache_hits_total", "Lookups answered from the cache.", prom.Counter)
+		pw.Sample("geodecode_cache_hits_total", float64(stats.Hits))
+		pw.Header("geodecode_cache_misses_total", "Lookups that searched the dataset.", prom.Counter)
+		pw.Sample("geodecode_cache_misses_total", float64(stats.Misses))
+		pw.Header("geodecode_cache_entries", "Coordinates currently cached.", prom.Gauge)
+		pw.Sample("geodecode_cache_entries", float64(stats.Entries))
+	}
+	if ds := rg.shared().data.Load(); ds != nil && ds.loadErr == nil {
+		pw.Header("geodecode_dataset_locations", "Locations of the loaded dataset.", prom.Gauge)
+		pw.Sample("geodecode_dataset_locations", float64(ds.locations.len()))
+		pw.Header("geodecode_dataset_load_duration_seconds", "Time taken to load the dataset.", prom.Gauge)
+		pw.Sample("geodecode_dataset_load_duration_seconds", ds.loadDuration.Seconds())
+		pw.Header("geodecode_dataset_loaded_timestamp_seconds", "Unix time the dataset was loaded at.", prom.Gauge)
+		pw.Sample("geodecode_dataset_loaded_timestamp_seconds", float64(ds.loadedAt.UnixMilli())/1e3)
+		pw.Header("geodecode_dataset_info", "The loaded dataset, by source and content hash.", prom.Gauge)
+		pw.Sample("geodecode_dataset_info", 1, "source", ds.src.kind.String(), "hash", ds.contentHash())
+	}
+	pw.Flush()
+	return cw.n, cw.err
+}
+
+// ServeHTTP writes the metrics as the response, for Prometheus to scrape.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", prom.ContentType)
+	c.WriteTo(w)
+}
+
+// countingWriter counts the bytes written to w and keeps the first error.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
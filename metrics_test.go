@@ -0,0 +1,61 @@
+package geodecode_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestCollector(t *testing.T) {
+	metrics := geodecode.NewCollector()
+	geocoder, err := geodecode.New(geodecode.WithCollector(metrics), geodecode.WithCache(10), geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := geodecode.New(geodecode.WithCollector(metrics)); err == nil {
+		t.Errorf("Expected an error registering a collector twice")
+	}
+
+	var out strings.Builder
+	metrics.WriteTo(&out)
+	if strings.Contains(out.String(), "geodecode_dataset_locations") {
+		t.Errorf("Expected no dataset metrics before loading, got\n%s", out.String())
+	}
+
+	data := "lat,lon,city,admin1,admin2,cc\n52.52,13.405,Berlin,,,DE\n48.8566,2.3522,Paris,,,FR\n"
+	if err := geocoder.LoadFromReader(strings.NewReader(data)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	geocoder.Resolve([2]float64{52.52, 13.4}, [2]float64{52.52, 13.4}, [2]float64{0, 0})
+	geocoder.Resolve([2]float64{91, 0})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Expected the text exposition format, got %q", ct)
+	}
+	body := rec.Body.String()
+	hash := geocoder.DatasetInfo().Hash
+	for _, want := range []string{
+		"# TYPE geodecode_queries_total counter\n",
+		`geodecode_queries_total{result="found"} 2` + "\n",
+		`geodecode_queries_total{result="not_found"} 1` + "\n",
+		`geodecode_queries_total{result="invalid"} 1` + "\n",
+		`geodecode_queries_total{result="unavailable"} 0` + "\n",
+		"# TYPE geodecode_query_duration_seconds histogram\n",
+		`geodecode_query_duration_seconds_bucket{le="+Inf"} 3` + "\n",
+		"geodecode_query_duration_seconds_count 3\n",
+		"geodecode_cache_hits_total 1\n",
+		"geodecode_cache_misses_total 2\n",
+		"geodecode_cache_entries 2\n",
+		"geodecode_dataset_locations 2\n",
+		"geodecode_dataset_load_duration_seconds ",
+		`geodecode_dataset_info{source="reader",hash="` + hash + `"} 1` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package geodecode
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// mappings holds the files mapped with WithMemoryMap, so that loading an
+// unchanged file again reuses its mapping.
+var mappings struct {
+	sync.Mutex
+	files map[mappingKey][]byte
+}
+
+// mappingKey identifies a version of a mapped file.
+type mappingKey struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// mapFile maps the contents of file into memory read-only. The mapping is
+// never released, because strings handed out to callers may point into it.
+func mapFile(file *os.File) ([]byte, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: mapping data file: %w", err)
+	}
+	path, err := filepath.Abs(file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: mapping data file: %w", err)
+	}
+	key := mappingKey{path: path, size: info.Size(), modTime: info.ModTime()}
+
+	mappings.Lock()
+	defer mappings.Unlock()
+	if data, ok := mappings.files[key]; ok {
+		return data, nil
+	}
+	data, err := mmapFile(file, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: mapping data file: %w", err)
+	}
+	if mappings.files == nil {
+		mappings.files = make(map[mappingKey][]byte)
+	}
+	mappings.files[key] = data
+	return data, nil
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package geodecode
+
+import (
+	"io"
+	"os"
+)
+
+// mmapFile reads file into memory on platforms without mmap.
+func mmapFile(file *os.File, size int64) ([]byte, error) {
+	return io.ReadAll(io.NewSectionReader(file, 0, size))
+}
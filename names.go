@@ -0,0 +1,95 @@
+package geodecode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Column indices of the GeoNames alternateNamesV2.txt table.
+const (
+	anAlternateNameID = iota
+	anGeonameID
+	anLanguage
+	anName
+	anIsPreferred
+	anIsShort
+	anIsColloquial
+	anIsHistoric
+	anColumns = 8 // Minimum number of columns; "from" and "to" may follow
+)
+
+// nameKey identifies a localized name in the alternate name index.
+type nameKey struct {
+	id   int
+	lang string
+}
+
+// altName is a candidate localized name collected while reading the
+// alternate names table.
+type altName struct {
+	name      string
+	preferred bool
+}
+
+// readAlternateNames parses a GeoNames alternateNamesV2.txt table, keeping only
+// names in the given languages (all languages if none are given). Colloquial
+// and historic names are ignored; preferred names win over other names.
+func readAlternateNames(r io.Reader, languages []string) (map[nameKey]string, error) {
+	candidates := make(map[nameKey]altName)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxGeoNamesLine)
+	for row := 1; scanner.Scan(); row++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < anColumns {
+			return nil, fmt.Errorf("geodecode: alternate names row %d has %d columns, want at least %d", row, len(fields), anColumns)
+		}
+
+		lang := fields[anLanguage]
+		if len(languages) > 0 && !slices.Contains(languages, lang) {
+			continue
+		}
+		if fields[anIsColloquial] == "1" || fields[anIsHistoric] == "1" {
+			continue
+		}
+		id, err := strconv.Atoi(fields[anGeonameID])
+		if err != nil {
+			return nil, fmt.Errorf("geodecode: alternate names row %d has invalid geonameid %q", row, fields[anGeonameID])
+		}
+
+		key := nameKey{id: id, lang: lang}
+		preferred := fields[anIsPreferred] == "1"
+		if prev, ok := candidates[key]; ok && (prev.preferred || !preferred) {
+			continue
+		}
+		candidates[key] = altName{name: fields[anName], preferred: preferred}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("geodecode: reading alternate names: %w", err)
+	}
+
+	names := make(map[nameKey]string, len(candidates))
+	for key, candidate := range candidates {
+		names[key] = candidate.name
+	}
+	return names, nil
+}
+
+// localize returns loc with its City replaced by the name in the geocoder's
+// configured language, if ds knows one.
+func (rg *RGeocoder) localize(ds *dataset, loc Location) Location {
+	if rg.language == "" || loc.GeonameID == 0 {
+		return loc
+	}
+	if name, ok := ds.names[nameKey{id: loc.GeonameID, lang: rg.language}]; ok {
+		loc.City = name
+	}
+	return loc
+}
@@ -0,0 +1,45 @@
+package geodecode_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestWithLanguage(t *testing.T) {
+	newGeocoder := func(lang string) *geodecode.RGeocoder {
+		t.Helper()
+		geocoder, err := geodecode.New(
+			geodecode.WithDataset(filepath.Join("testdata", "cities.txt"),
+				geodecode.WithFormat(geodecode.FormatGeoNames),
+				geodecode.WithAlternateNames(openFixture(t, "alternateNamesV2.txt"), "de", "it"),
+			),
+			geodecode.WithLanguage(lang),
+		)
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		return geocoder
+	}
+
+	tests := []struct {
+		lang  string
+		coord [2]float64
+		want  string
+	}{
+		{"de", [2]float64{48.1, 11.6}, "München"},
+		{"it", [2]float64{48.1, 11.6}, "Monaco di Baviera"},
+		{"de", [2]float64{52.5, 13.4}, "Berlin"},        // historic name ignored
+		{"de", [2]float64{53.6, 10.0}, "Hamburg"},       // no German name, default kept
+		{"", [2]float64{48.1, 11.6}, "Munich"},          // no language configured
+		{"fr", [2]float64{48.1, 11.6}, "Munich"},        // language not loaded
+		{"it", [2]float64{34.0, -118.2}, "Los Angeles"}, // no name at all
+	}
+	for _, tt := range tests {
+		got := newGeocoder(tt.lang).Query(tt.coord)[0].City
+		if got != tt.want {
+			t.Errorf("Query(%v) with language %q = %q, want %q", tt.coord, tt.lang, got, tt.want)
+		}
+	}
+}
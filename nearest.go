@@ -0,0 +1,105 @@
+package geodecode
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// neighbor is a location found by a k nearest neighbor search.
+type neighbor struct {
+	index int     // Index of the location
+	dist  float64 // Squared distance as compared by the spatial indexes
+}
+
+// neighbors collects the k nearest of the locations offered to it. Of
+// several locations at the same distance, those loaded first are kept.
+type neighbors struct {
+	k    int
+	list []neighbor // Nearest first
+}
+
+// offer adds the location index at the squared distance d if it is among the
+// k nearest offered so far.
+func (n *neighbors) offer(index int, d float64) {
+	nb := neighbor{index, d}
+	if len(n.list) == n.k {
+		if compareNeighbors(nb, n.list[n.k-1]) >= 0 {
+			return
+		}
+		n.list = n.list[:n.k-1]
+	}
+	i, _ := slices.BinarySearchFunc(n.list, nb, compareNeighbors)
+	n.list = slices.Insert(n.list, i, nb)
+}
+
+// bound returns the squared distance up to which a location may still be
+// among the k nearest: that of the k-th nearest, or +Inf until k are known.
+func (n *neighbors) bound() float64 {
+	if len(n.list) < n.k {
+		return math.Inf(1)
+	}
+	return n.list[n.k-1].dist
+}
+
+// compareNeighbors orders neighbors by distance, then by index.
+func compareNeighbors(a, b neighbor) int {
+	return cmp.Or(cmp.Compare(a.dist, b.dist), cmp.Compare(a.index, b.index))
+}
+
+// nearestK returns the k locations in ds nearest to coord, as compared by the
+// spatial indexes, nearest first. The KD-Tree is searched; the grid and the
+// VP-Tree only find single matches, so with them every location is scanned.
+func (ds *dataset) nearestK(coord [2]float64, k int) []neighbor {
+	switch {
+	case ds.tree != nil:
+		return ds.tree.nearestK(coord, k)
+	case ds.tree32 != nil:
+		return ds.tree32.nearestK(coord, k)
+	}
+	knn := &neighbors{k: k}
+	q, w := indexPoint(ds.metric, coord[0], coord[1]), indexWeights(ds.metric, coord[0])
+	for i := range ds.locations.len() {
+		lat, lon := ds.locations.coord(i)
+		knn.offer(i, indexDist(w, q, indexPoint(ds.metric, lat, lon)))
+	}
+	return knn.list
+}
+
+// Nearest returns up to k locations nearest to coord, nearest first, as
+// Results like those of Resolve. Locations farther away than the distance set
+// with WithMaxDistance are left out, so fewer than k results, or none, may be
+// returned. The locations are found under the metric set with WithMetric and
+// ordered by their great-circle distance. With GridIndex and VPTreeIndex,
+// which only find single matches, every location is scanned, which is much
+// slower on large datasets.
+//
+// Example usage:
+//
+//	results, err := geocoder.Nearest([2]float64{52.52, 13.405}, 5)
+//	for _, r := range results {
+//	    fmt.Printf("%s, %.1f %s\n", r.Location.City, r.Distance, r.Unit)
+//	}
+func (rg *RGeocoder) Nearest(coord [2]float64, k int) ([]Result, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("geodecode: k must be at least 1, got %d", k)
+	}
+	ds, err := rg.prepare([][2]float64{coord})
+	if err != nil {
+		return nil, err
+	}
+	coord, _ = rg.normalize(coord)
+	ds = rg.loadRegions(ds, coord, k)
+
+	nbs := ds.nearestK(coord, k)
+	results := make([]Result, 0, len(nbs))
+	for _, nb := range nbs {
+		loc, err := rg.withinRange(coord, rg.result(ds, ds.locations.at(nb.index)))
+		if err == nil {
+			results = append(results, rg.found(coord, loc))
+		}
+	}
+	slices.SortStableFunc(results, func(a, b Result) int { return cmp.Compare(a.DistanceKM, b.DistanceKM) })
+	return results, nil
+}
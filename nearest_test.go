@@ -0,0 +1,78 @@
+package geodecode_test
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// haversine returns the great-circle distance between a and b in kilometers.
+func haversine(a [2]float64, lat, lon float64) float64 {
+	rad := math.Pi / 180
+	sinLat := math.Sin((lat - a[0]) * rad / 2)
+	sinLon := math.Sin((lon - a[1]) * rad / 2)
+	h := sinLat*sinLat + math.Cos(a[0]*rad)*math.Cos(lat*rad)*sinLon*sinLon
+	return 2 * 6371 * math.Asin(math.Sqrt(h))
+}
+
+func TestNearest(t *testing.T) {
+	plain, _ := geodecode.New()
+	var all []geodecode.Location
+	for loc := range plain.Locations() {
+		all = append(all, loc)
+	}
+
+	for _, index := range []geodecode.Index{geodecode.KDTreeIndex, geodecode.GridIndex} {
+		geocoder, err := geodecode.New(geodecode.WithMetric(geodecode.MetricHaversine), geodecode.WithIndex(index))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		for _, coord := range randomCoords(20) {
+			got, err := geocoder.Nearest(coord, 5)
+			if err != nil {
+				t.Fatalf("Nearest(%v): %v", coord, err)
+			}
+			dists := make([]float64, len(all))
+			for i, loc := range all {
+				dists[i] = haversine(coord, loc.Lat, loc.Lon)
+			}
+			slices.Sort(dists)
+			if len(got) != 5 {
+				t.Fatalf("%s: Expected 5 results for %v, got %d", index, coord, len(got))
+			}
+			// The radius of the Earth differs slightly from the package's.
+			for i, r := range got {
+				if math.Abs(r.DistanceKM-dists[i]) > 1e-5*dists[i] {
+					t.Errorf("%s: result %d for %v: Expected %v km, got %s at %v km", index, i, coord, dists[i], r.Location.City, r.DistanceKM)
+				}
+			}
+		}
+	}
+
+	// The nearest result is the one Resolve returns.
+	berlin := [2]float64{52.52, 13.405}
+	got, _ := plain.Nearest(berlin, 3)
+	want, _ := plain.Resolve(berlin)
+	if len(got) != 3 || got[0] != want[0] {
+		t.Errorf("Expected %+v first, got %+v", want[0], got)
+	}
+
+	near, _ := geodecode.New(geodecode.WithMaxDistance(2))
+	if got, err := near.Nearest(berlin, 50); err != nil || len(got) == 0 || len(got) == 50 {
+		t.Errorf("Expected only the locations within 2 km, got %d (%v)", len(got), err)
+	}
+	if _, err := plain.Nearest(berlin, 0); err == nil {
+		t.Errorf("Expected an error for k = 0")
+	}
+
+	lazy, _ := geodecode.New(geodecode.WithLazyRegions())
+	// Strasbourg lies on the border, so its neighbors are in two countries.
+	strasbourg := [2]float64{48.58, 7.75}
+	got, _ = lazy.Nearest(strasbourg, 20)
+	want, _ = plain.Nearest(strasbourg, 20)
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected the same results with WithLazyRegions, got %+v, want %+v", got, want)
+	}
+}
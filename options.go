@@ -0,0 +1,298 @@
+package geodecode
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+)
+
+// Option configures an RGeocoder created with New.
+type Option func(*RGeocoder) error
+
+// New creates a reverse geocoder configured by opts. Unlike GetRGeocoder, each
+// call returns an independent instance, so geocoders with different datasets
+// and settings can coexist in one process. The dataset is loaded lazily on
+// the first query.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithDataset("/data/cities.csv"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	locations := geocoder.Query([2]float64{52.52, 13.405})
+func New(opts ...Option) (*RGeocoder, error) {
+	rg := &RGeocoder{}
+	for _, opt := range opts {
+		if err := opt(rg); err != nil {
+			return nil, err
+		}
+	}
+	rg.cache = rg.newCache()
+	return rg, nil
+}
+
+// WithVerbose controls whether detailed loading and warning messages are logged.
+func WithVerbose(verbose bool) Option {
+	return func(rg *RGeocoder) error {
+		rg.verbose = verbose
+		return nil
+	}
+}
+
+// WithDataset makes the geocoder load its cities from the CSV file at path
+// instead of the embedded dataset. The file format is described in LoadFromFile.
+func WithDataset(path string, opts ...LoadOption) Option {
+	return func(rg *RGeocoder) error {
+		if path == "" {
+			return errors.New("geodecode: empty dataset path")
+		}
+		rg.shared().src = source{kind: sourceFile, location: path, cfg: newLoadConfig(opts)}
+		return nil
+	}
+}
+
+// WithDatasetURL makes the geocoder download its cities from url instead of
+// using the embedded dataset. The download happens lazily on the first query;
+// see LoadFromURL for caching and the supported formats.
+func WithDatasetURL(url string, opts ...LoadOption) Option {
+	return func(rg *RGeocoder) error {
+		if url == "" {
+			return errors.New("geodecode: empty dataset URL")
+		}
+		rg.shared().src = source{kind: sourceURL, location: url, cfg: newLoadConfig(opts)}
+		return nil
+	}
+}
+
+// WithEmbeddedDataset makes the geocoder use the embedded dataset, parsed
+// with opts. It is mainly useful to filter the embedded cities with options
+// such as WithCountryFilter, so only the relevant ones are kept in memory.
+func WithEmbeddedDataset(opts ...LoadOption) Option {
+	return func(rg *RGeocoder) error {
+		rg.shared().src = source{kind: sourceEmbedded, cfg: newLoadConfig(opts)}
+		return nil
+	}
+}
+
+// WithLanguage makes queries return city names in the given ISO language code
+// (e.g. "de" returns "München" instead of "Munich"). Names are taken from the
+// table loaded with WithAlternateNames; cities without a name in that language
+// keep their default name.
+func WithLanguage(lang string) Option {
+	return func(rg *RGeocoder) error {
+		rg.language = lang
+		return nil
+	}
+}
+
+// WithCountryNames controls whether query results have their Country field
+// filled in with the English name of the country identified by CC. It is
+// enabled by default; disabling it saves a lookup per result in hot loops
+// that only need the country code.
+func WithCountryNames(enabled bool) Option {
+	return func(rg *RGeocoder) error {
+		rg.noCountryNames = !enabled
+		return nil
+	}
+}
+
+// WithCountryInfo makes queries fill in the CountryInfo field of results
+// with the country's ISO3 code, continent, currency and calling code.
+func WithCountryInfo(enabled bool) Option {
+	return func(rg *RGeocoder) error {
+		rg.countryInfo = enabled
+		return nil
+	}
+}
+
+// WithCountryLocale makes queries return country names in the given locale
+// (e.g. "fr" returns "Allemagne" instead of "Germany"). Locales are matched
+// case-insensitively, and regional variants without a table of their own use
+// the base language, so "fr-CA" uses "fr". Countries without a translation
+// keep their English name. An error is returned for unsupported locales.
+//
+// The names come from the translations of the iso-codes project and are
+// available for ar, de, es, fr, hi, it, ja, ko, nl, pl, pt, pt-BR, ru, sv,
+// tr, zh-CN and zh-TW; "en" selects the default English names.
+func WithCountryLocale(locale string) Option {
+	return func(rg *RGeocoder) error {
+		if strings.EqualFold(locale, "en") {
+			rg.countryNames = nil
+			return nil
+		}
+		names, ok := localizedCountryNames(locale)
+		if !ok {
+			return fmt.Errorf("geodecode: no country names for locale %q", locale)
+		}
+		rg.countryNames = names
+		return nil
+	}
+}
+
+// WithLogger sends the geocoder's log messages to logger instead of
+// slog.Default. A nil logger discards them.
+//
+// Messages are logged at these levels: load progress at Info, skipped rows
+// and invalid query coordinates at Warn, and failures such as a dataset that
+// cannot be loaded at Error. QueryDebug logs its trace at Debug. Detailed
+// messages, including skipped rows and invalid queries, are only produced
+// when WithVerbose is set; the logger's handler decides which levels are
+// recorded.
+func WithLogger(logger *slog.Logger) Option {
+	return func(rg *RGeocoder) error {
+		rg.logger = logger
+		rg.quiet = logger == nil
+		return nil
+	}
+}
+
+// WithMaxDistance makes queries return an empty Location instead of a match
+// that is farther than max from the queried coordinate, for example for
+// coordinates in the middle of the ocean. max is given in the units set with
+// WithUnits, kilometers by default. Zero means no limit.
+func WithMaxDistance(max float64) Option {
+	return func(rg *RGeocoder) error {
+		if max < 0 || math.IsNaN(max) || math.IsInf(max, 0) {
+			return fmt.Errorf("geodecode: invalid maximum distance %v", max)
+		}
+		rg.maxDistance = max
+		return nil
+	}
+}
+
+// WithUnits selects the unit of the distances the geocoder accepts and
+// reports: the limit set with WithMaxDistance, the distance passed to IsNear
+// and Result.Distance. The default is Kilometers. The order of options does
+// not matter.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithUnits(geodecode.Miles), geodecode.WithMaxDistance(30))
+func WithUnits(unit Unit) Option {
+	return func(rg *RGeocoder) error {
+		if unit < Kilometers || unit > NauticalMiles {
+			return fmt.Errorf("geodecode: unknown unit %d", unit)
+		}
+		rg.units = unit
+		return nil
+	}
+}
+
+// WithCoordinatePolicy selects how queries treat coordinates outside the
+// valid range: RejectInvalid (the default) rejects them, ClampInvalid clamps
+// them to the valid range and WrapLongitude wraps longitudes such as 190.5
+// into [-180, 180]. Locations loaded from a dataset are always validated.
+func WithCoordinatePolicy(policy CoordinatePolicy) Option {
+	return func(rg *RGeocoder) error {
+		if policy < RejectInvalid || policy > WrapLongitude {
+			return fmt.Errorf("geodecode: unknown coordinate policy %d", policy)
+		}
+		rg.coordPolicy = policy
+		return nil
+	}
+}
+
+// QueryHook is called by the geocoder after resolving a coordinate, with the
+// coordinate, the resulting location, the error (nil, or wrapping
+// ErrInvalidCoordinate, ErrDataNotLoaded or ErrNoResult) and the time the
+// lookup took. Hooks run synchronously on the goroutine resolving the
+// coordinate, which is the caller's unless WithAdaptiveParallelism splits a
+// batch, so they must be fast and safe for concurrent use.
+type QueryHook func(coord [2]float64, loc Location, err error, latency time.Duration)
+
+// WithQueryHook registers hook to be called for every coordinate resolved by
+// Query, Lookup, Resolve and the batch helpers, for example to record metrics
+// or audit logs. It may be given several times; hooks run in the order they
+// were registered. A batch rejected because one coordinate is invalid calls
+// the hooks for that coordinate only.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithQueryHook(
+//	    func(coord [2]float64, loc geodecode.Location, err error, latency time.Duration) {
+//	        queryLatency.Observe(latency.Seconds())
+//	    }))
+func WithQueryHook(hook QueryHook) Option {
+	return func(rg *RGeocoder) error {
+		if hook == nil {
+			return errors.New("geodecode: nil query hook")
+		}
+		rg.hooks = append(rg.hooks, hook)
+		return nil
+	}
+}
+
+// WithIndex selects the spatial index used to search for the nearest
+// location. The default is KDTreeIndex; GridIndex is faster for typical city
+// datasets, and VPTreeIndex compares great-circle distances directly. All
+// return the same locations, except possibly among locations at exactly the
+// same distance from the query.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithIndex(geodecode.GridIndex))
+func WithIndex(index Index) Option {
+	return func(rg *RGeocoder) error {
+		if index != KDTreeIndex && index != GridIndex && index != VPTreeIndex {
+			return fmt.Errorf("geodecode: unknown index %d", index)
+		}
+		rg.index = index
+		return nil
+	}
+}
+
+// WithLeafSize sets the maximum number of locations, at least 1, in a leaf of
+// the KD-Tree. Leaves are not split further but scanned linearly, which is
+// faster than descending to single locations because their points lie next
+// to each other in memory. The default is 8; 1 splits the tree fully. It does
+// not affect the other indexes.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithLeafSize(16))
+func WithLeafSize(n int) Option {
+	return func(rg *RGeocoder) error {
+		if n < 1 {
+			return fmt.Errorf("geodecode: invalid leaf size %d", n)
+		}
+		rg.leafSize = n
+		return nil
+	}
+}
+
+// WithFloat32Coordinates stores the coordinates of the dataset, and the points
+// of the KD-Tree, as float32 instead of float64, which halves the memory they
+// take for very large datasets. Stored coordinates, and so those of results,
+// move by up to 2 meters; queries still take float64 coordinates, and
+// distances are computed in float64. The grid and VP-Tree indexes keep their
+// points in float64.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithFloat32Coordinates())
+func WithFloat32Coordinates() Option {
+	return func(rg *RGeocoder) error {
+		rg.single = true
+		return nil
+	}
+}
+
+// WithMetric selects how distances are measured when searching for the
+// nearest location. The default is MetricEuclidean.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithMetric(geodecode.MetricHaversine))
+func WithMetric(metric Metric) Option {
+	return func(rg *RGeocoder) error {
+		if metric != MetricEuclidean && metric != MetricHaversine && metric != MetricEquirectangular {
+			return fmt.Errorf("geodecode: unknown metric %d", metric)
+		}
+		rg.metric = metric
+		return nil
+	}
+}
@@ -0,0 +1,312 @@
+package geodecode_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+// antimeridianData holds two towns on either side of the antimeridian and one
+// farther west, which is nearer in degrees but not on the globe.
+const antimeridianData = "lat,lon,city,admin1,admin2,cc\n" +
+	"0,-179.9,East,,,KI\n" +
+	"0,178,West,,,FJ\n" +
+	"60,0,North,,,NO\n"
+
+func TestWithMetric(t *testing.T) {
+	coord := [2]float64{0, 179.9}
+	for _, tc := range []struct {
+		metric geodecode.Metric
+		want   string
+	}{
+		{geodecode.MetricEuclidean, "West"},
+		{geodecode.MetricHaversine, "East"},
+		{geodecode.MetricEquirectangular, "West"},
+	} {
+		geocoder, err := geodecode.New(geodecode.WithMetric(tc.metric))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+			t.Fatalf("LoadFromReader: %v", err)
+		}
+		if got := geocoder.Query(coord)[0].City; got != tc.want {
+			t.Errorf("%s: expected %s, got %s", tc.metric, tc.want, got)
+		}
+		if loc, trace := geocoder.QueryDebug(coord); loc.City != tc.want || trace.NodesVisited == 0 {
+			t.Errorf("%s: expected QueryDebug to match %s, got %s with %+v", tc.metric, tc.want, loc.City, trace)
+		}
+
+		// The metric survives saving and loading an index.
+		path := filepath.Join(t.TempDir(), "cities.idx")
+		if err := geocoder.SaveIndex(path); err != nil {
+			t.Fatalf("SaveIndex: %v", err)
+		}
+		loaded, _ := geodecode.New(geodecode.WithMetric(tc.metric))
+		if err := loaded.LoadIndex(path); err != nil {
+			t.Fatalf("LoadIndex: %v", err)
+		}
+		if got := loaded.Query(coord)[0].City; got != tc.want {
+			t.Errorf("%s: expected %s from index, got %s", tc.metric, tc.want, got)
+		}
+	}
+
+	if _, err := geodecode.New(geodecode.WithMetric(geodecode.Metric(42))); err == nil {
+		t.Errorf("Expected an error for an unknown metric")
+	}
+}
+
+func TestMetricEquirectangular(t *testing.T) {
+	// At 60 degrees north a degree of longitude is half as long as one of
+	// latitude, so East is nearer to the query than North.
+	const data = "lat,lon,city,admin1,admin2,cc\n" +
+		"60,1.5,East,,,NO\n" +
+		"61.2,0,North,,,NO\n"
+	for _, index := range []geodecode.Index{geodecode.KDTreeIndex, geodecode.GridIndex, geodecode.VPTreeIndex} {
+		for _, tc := range []struct {
+			metric geodecode.Metric
+			want   string
+		}{
+			{geodecode.MetricEuclidean, "North"},
+			{geodecode.MetricEquirectangular, "East"},
+		} {
+			geocoder, _ := geodecode.New(geodecode.WithMetric(tc.metric), geodecode.WithIndex(index))
+			if err := geocoder.LoadFromReader(strings.NewReader(data)); err != nil {
+				t.Fatalf("LoadFromReader: %v", err)
+			}
+			if got := geocoder.Query([2]float64{60, 0})[0].City; got != tc.want {
+				t.Errorf("%s/%s: expected %s, got %s", index, tc.metric, tc.want, got)
+			}
+		}
+	}
+}
+
+func TestWithMaxDistance(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	results := geocoder.Query([2]float64{60.5, 0.5}, [2]float64{30, 0})
+	if len(results) != 2 || results[0].City != "North" || results[1] != (geodecode.Location{}) {
+		t.Errorf("Expected North and an empty location, got %+v", results)
+	}
+
+	if _, err := geodecode.New(geodecode.WithMaxDistance(-1)); err == nil {
+		t.Errorf("Expected an error for a negative distance")
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	geocoder, err := geodecode.New(geodecode.WithVerbose(true), geodecode.WithLogger(logger))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData + "abc,10,Nowhere,,,XX\n")); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	geocoder.Query([2]float64{999, 999})
+
+	out := buf.String()
+	if strings.Contains(out, "level=INFO") {
+		t.Errorf("Expected Info messages to be filtered by the handler, got %q", out)
+	}
+	if !strings.Contains(out, `level=WARN msg="geodecode: skipping row with invalid coordinates" row=4 lat=abc`) {
+		t.Errorf("Expected the skipped row to be logged as a warning, got %q", out)
+	}
+	if !strings.Contains(out, `msg="geodecode: invalid query coordinate, returning empty location" lat=999 lon=999`) {
+		t.Errorf("Expected the invalid query to be logged, got %q", out)
+	}
+}
+
+func TestWithCountryNames(t *testing.T) {
+	for _, tc := range []struct {
+		enabled bool
+		want    []string
+	}{
+		{true, []string{"Fiji", "Norway"}},
+		{false, []string{"", ""}},
+	} {
+		geocoder, err := geodecode.New(geodecode.WithCountryNames(tc.enabled))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+			t.Fatalf("LoadFromReader: %v", err)
+		}
+		results := geocoder.Query([2]float64{0, 178}, [2]float64{60, 0})
+		for i, want := range tc.want {
+			if results[i].Country != want {
+				t.Errorf("WithCountryNames(%v): Expected country %q for %s, got %q", tc.enabled, want, results[i].City, results[i].Country)
+			}
+			if got := geodecode.GetCountryByCode(results[i].CC); tc.enabled && got != want {
+				t.Errorf("Expected GetCountryByCode(%q) to match Location.Country %q, got %q", results[i].CC, want, got)
+			}
+		}
+	}
+}
+
+func TestWithCountryInfo(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithCountryInfo(true))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	got := geocoder.Query([2]float64{60, 0})[0].CountryInfo
+	want := geodecode.CountryInfo{ISO3: "NOR", Continent: "Europe", Currency: "NOK", CallingCode: "+47"}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestWithCountryLocale(t *testing.T) {
+	for _, tc := range []struct {
+		locale string
+		want   string
+	}{
+		{"fr", "Norvège"},
+		{"de-AT", "Norwegen"},
+		{"en", "Norway"},
+	} {
+		geocoder, err := geodecode.New(geodecode.WithCountryLocale(tc.locale))
+		if err != nil {
+			t.Fatalf("New(WithCountryLocale(%q)): %v", tc.locale, err)
+		}
+		if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+			t.Fatalf("LoadFromReader: %v", err)
+		}
+		if got := geocoder.Query([2]float64{60, 0})[0].Country; got != tc.want {
+			t.Errorf("WithCountryLocale(%q): Expected %q, got %q", tc.locale, tc.want, got)
+		}
+	}
+
+	if _, err := geodecode.New(geodecode.WithCountryLocale("tlh")); err == nil {
+		t.Errorf("Expected an error for an unsupported locale")
+	}
+}
+
+func TestWithCoordinatePolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy geodecode.CoordinatePolicy
+		coord  [2]float64
+		want   string // Empty if the coordinate is rejected
+	}{
+		{geodecode.RejectInvalid, [2]float64{0, 190.5}, ""},
+		{geodecode.ClampInvalid, [2]float64{0, 190.5}, "West"},   // Clamped to 180
+		{geodecode.WrapLongitude, [2]float64{0, 190.5}, "East"},  // Wrapped to -169.5
+		{geodecode.WrapLongitude, [2]float64{0, -541.9}, "West"}, // Wrapped to 178.1
+		{geodecode.ClampInvalid, [2]float64{95, 0}, "North"},     // Clamped to 90
+		{geodecode.WrapLongitude, [2]float64{95, 0}, ""},         // Latitudes are not wrapped
+		{geodecode.ClampInvalid, [2]float64{math.NaN(), 0}, ""},  // NaN is always rejected
+		{geodecode.ClampInvalid, [2]float64{0, math.Inf(1)}, ""}, // So is infinity
+	} {
+		geocoder, err := geodecode.New(geodecode.WithCoordinatePolicy(tc.policy))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+			t.Fatalf("LoadFromReader: %v", err)
+		}
+		locations, err := geocoder.Lookup(tc.coord)
+		if tc.want == "" {
+			if !errors.Is(err, geodecode.ErrInvalidCoordinate) {
+				t.Errorf("Policy %d, %v: Expected ErrInvalidCoordinate, got %+v, %v", tc.policy, tc.coord, locations, err)
+			}
+			continue
+		}
+		if err != nil || locations[0].City != tc.want {
+			t.Errorf("Policy %d, %v: Expected %s, got %+v, %v", tc.policy, tc.coord, tc.want, locations, err)
+		}
+	}
+}
+
+func TestWithQueryHook(t *testing.T) {
+	type call struct {
+		coord [2]float64
+		city  string
+		err   error
+	}
+	var calls []call
+	var order []int
+	geocoder, err := geodecode.New(
+		geodecode.WithMaxDistance(1000),
+		geodecode.WithQueryHook(func(coord [2]float64, loc geodecode.Location, err error, latency time.Duration) {
+			calls = append(calls, call{coord, loc.City, err})
+			order = append(order, 1)
+		}),
+		geodecode.WithQueryHook(func([2]float64, geodecode.Location, error, time.Duration) {
+			order = append(order, 2)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+
+	geocoder.Query([2]float64{60, 0}, [2]float64{-60, 0})
+	if len(calls) != 2 || calls[0].city != "North" || calls[0].err != nil || !errors.Is(calls[1].err, geodecode.ErrNoResult) {
+		t.Errorf("Expected a hit and a miss, got %+v", calls)
+	}
+	if len(order) != 4 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("Expected hooks to run in registration order, got %v", order)
+	}
+
+	calls = nil
+	geocoder.Resolve([2]float64{60, 0}, [2]float64{100, 0})
+	if len(calls) != 1 || !errors.Is(calls[0].err, geodecode.ErrInvalidCoordinate) {
+		t.Errorf("Expected one call for the invalid coordinate, got %+v", calls)
+	}
+
+	if _, err := geodecode.New(geodecode.WithQueryHook(nil)); err == nil {
+		t.Errorf("Expected an error for a nil hook")
+	}
+}
+
+func TestWithFloat32Coordinates(t *testing.T) {
+	full, _ := geodecode.New()
+	single, err := geodecode.New(geodecode.WithFloat32Coordinates())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	fullFP, singleFP := full.MemoryFootprint(), single.MemoryFootprint()
+	if singleFP.Locations >= fullFP.Locations || singleFP.Tree >= fullFP.Tree {
+		t.Errorf("Expected smaller columns and tree, got %+v, want less than %+v", singleFP, fullFP)
+	}
+
+	coords := [][2]float64{{52.5200, 13.4050}, {40.7128, -74.0060}, {-33.8688, 151.2093}, {0, 0}}
+	want, got := full.Query(coords...), single.Query(coords...)
+	for i := range coords {
+		if got[i].City != want[i].City || math.Abs(got[i].Lat-want[i].Lat) > 1e-5 || math.Abs(got[i].Lon-want[i].Lon) > 1e-5 {
+			t.Errorf("Query(%v) = %+v, want about %+v", coords[i], got[i], want[i])
+		}
+	}
+
+	// Saved indexes remember the precision.
+	path := filepath.Join(t.TempDir(), "cities.idx")
+	if err := single.SaveIndex(path); err != nil {
+		t.Fatalf("SaveIndex: %v", err)
+	}
+	loaded, _ := geodecode.New(geodecode.WithFloat32Coordinates())
+	if err := loaded.LoadIndex(path); err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	if again := loaded.Query(coords...); again[0] != got[0] {
+		t.Errorf("Query from index = %+v, want %+v", again[0], got[0])
+	}
+}
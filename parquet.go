@@ -0,0 +1,104 @@
+package geodecode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/sdwillbrand/GeoDecode/internal/parquet"
+)
+
+// readParquet parses a Parquet dataset from r. The file is read into memory,
+// since Parquet metadata is stored at its end. Rows with invalid coordinates
+// are skipped unless cfg.strict is set; null values leave the corresponding
+// Location field empty.
+func (rg *RGeocoder) readParquet(r io.Reader, cfg *loadConfig) ([]Location, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: reading Parquet data: %w", err)
+	}
+	file, err := parquet.ReadAll(data)
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: %w", err)
+	}
+
+	available := make(map[string]bool)
+	for _, name := range file.Columns() {
+		available[name] = true
+	}
+	cols := make(map[string]*parquet.Column)
+	for _, col := range append(requiredCols, optionalCols...) {
+		name := cfg.column(col)
+		if !available[name] {
+			continue
+		}
+		if cols[col], err = file.ReadColumn(name); err != nil {
+			return nil, fmt.Errorf("geodecode: %w", err)
+		}
+	}
+	for _, col := range requiredCols {
+		if cols[col] == nil {
+			return nil, fmt.Errorf("geodecode: Parquet file missing required column: %s", cfg.column(col))
+		}
+	}
+
+	rows := cols["lat"].Len()
+	for col, c := range cols {
+		if c.Len() != rows {
+			return nil, fmt.Errorf("geodecode: Parquet column %s has %d rows, want %d", cfg.column(col), c.Len(), rows)
+		}
+	}
+
+	str := func(col string, i int) string {
+		if c := cols[col]; c != nil {
+			return c.String(i)
+		}
+		return ""
+	}
+	num := func(col string, i int) int {
+		if c := cols[col]; c != nil {
+			v, _ := c.Int(i)
+			return int(v)
+		}
+		return 0
+	}
+
+	var loadedLocations []Location
+	strs := make(stringInterner)
+	for i := 0; i < rows; i++ {
+		lat, okLat := cols["lat"].Float(i)
+		lon, okLon := cols["lon"].Float(i)
+		if !okLat || !okLon || !validCoordinate(lat, lon) {
+			if cfg.strict {
+				return nil, fmt.Errorf("geodecode: Parquet row %d has invalid coordinates: lat='%s', lon='%s'", i+1, str("lat", i), str("lon", i))
+			}
+			if rg.verbose {
+				rg.log(slog.LevelWarn, "geodecode: skipping row with invalid coordinates",
+					"row", i+1, "lat", str("lat", i), "lon", str("lon", i))
+			}
+			continue
+		}
+
+		loadedLocations = append(loadedLocations, strs.location(Location{
+			GeonameID:  num("geonameid", i),
+			Lat:        lat,
+			Lon:        lon,
+			City:       str("city", i),
+			Admin1:     str("admin1", i),
+			Admin2:     str("admin2", i),
+			CC:         str("cc", i),
+			Timezone:   str("timezone", i),
+			Elevation:  num("elevation", i),
+			Population: num("population", i),
+		}))
+	}
+
+	if len(loadedLocations) == 0 {
+		return nil, errors.New("geodecode: no valid coordinates loaded")
+	}
+	if rg.verbose {
+		rg.log(slog.LevelInfo, "geodecode: parsed Parquet data", "locations", len(loadedLocations))
+	}
+	return loadedLocations, nil
+}
@@ -0,0 +1,79 @@
+package geodecode_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+	"github.com/sdwillbrand/GeoDecode/internal/parquet"
+)
+
+func TestLoadParquet(t *testing.T) {
+	var buf bytes.Buffer
+	err := parquet.Write(&buf, []parquet.WriteColumn{
+		{Name: "latitude", Type: parquet.Double, Values: []any{52.52437, 48.13743, 95.0}},
+		{Name: "longitude", Type: parquet.Double, Values: []any{13.41053, 11.57549, 0.0}},
+		{Name: "name", Type: parquet.ByteArray, Values: []any{"Berlin", "Munich", "Invalid"}},
+		{Name: "admin1", Type: parquet.ByteArray, Dictionary: true, Values: []any{"Berlin", "Bavaria", ""}},
+		{Name: "admin2", Type: parquet.ByteArray, Optional: true, Values: []any{nil, "Upper Bavaria", nil}},
+		{Name: "cc", Type: parquet.ByteArray, Dictionary: true, Values: []any{"DE", "DE", "DE"}},
+		{Name: "population", Type: parquet.Int64, Optional: true, Values: []any{int64(3426354), nil, nil}},
+	}, parquet.WriteOptions{Codec: parquet.Snappy})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	columns := geodecode.WithColumns(geodecode.Columns{Lat: "latitude", Lon: "longitude", City: "name"})
+
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	err = geocoder.LoadFromReader(bytes.NewReader(buf.Bytes()), geodecode.WithFormat(geodecode.FormatParquet), columns)
+	if err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	results := geocoder.Query([2]float64{52.5, 13.4}, [2]float64{48.1, 11.6})
+	if results[0].City != "Berlin" || results[0].Population != 3426354 || results[0].Admin2 != "" {
+		t.Errorf("Expected Berlin with population, got %+v", results[0])
+	}
+	if results[1].City != "Munich" || results[1].Admin2 != "Upper Bavaria" {
+		t.Errorf("Expected Munich in Upper Bavaria, got %+v", results[1])
+	}
+
+	_, err = geodecode.ReadLocations(bytes.NewReader(buf.Bytes()), geodecode.WithFormat(geodecode.FormatParquet), columns, geodecode.WithStrict())
+	if err == nil {
+		t.Errorf("Expected strict loading to reject the invalid row")
+	}
+	_, err = geodecode.ReadLocations(bytes.NewReader(buf.Bytes()), geodecode.WithFormat(geodecode.FormatParquet))
+	if err == nil {
+		t.Errorf("Expected an error for missing columns without a mapping")
+	}
+}
+
+func TestLoadParquetFixtures(t *testing.T) {
+	columns := geodecode.WithColumns(geodecode.Columns{
+		Lat: "latitude", Lon: "longitude", City: "name", CC: "country_code", GeonameID: "id",
+	})
+	for _, name := range []string{"snappy_dictionary.parquet", "snappy_v2.parquet", "gzip_plain_dictionary.parquet"} {
+		geocoder, err := geodecode.New()
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		err = geocoder.LoadFromFile(filepath.Join("internal", "parquet", "testdata", name), geodecode.WithFormat(geodecode.FormatParquet), columns)
+		if err != nil {
+			t.Fatalf("LoadFromFile(%s): %v", name, err)
+		}
+		location := geocoder.Query([2]float64{42.58, 1.65})[0]
+		want := geodecode.Location{
+			GeonameID: 100000, Lat: 42.57952, Lon: 1.65362, City: "El Tarter", Admin1: "Canillo",
+			CC: "AD", Country: location.Country, Timezone: "Europe/Andorra", Population: 1000,
+		}
+		if location != want {
+			t.Errorf("%s: expected %+v, got %+v", name, want, location)
+		}
+		if info := geocoder.DatasetInfo(); info.Records != 300 {
+			t.Errorf("%s: expected 300 locations, got %d", name, info.Records)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package geodecode
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WithLazyRegions makes the geocoder load its dataset one country at a time,
+// when a query first needs it, so that services which only ever see
+// coordinates of one region do not pay memory for the whole planet. A query
+// loads the country it falls into, and any neighboring country that may hold
+// a closer location; results are the same as with the whole dataset loaded.
+//
+// Regions are loaded lazily from the embedded dataset and from binary files
+// loaded with WithMemoryMap, which can be read in parts without decoding
+// them. Other sources are loaded completely. Loading a region rebuilds the
+// index over the regions loaded so far while holding the geocoder's lock, so
+// the first queries in a new region are slower, and reloads and queries that
+// need other missing regions wait for the rebuild meanwhile. IsNear loads the regions of the country it is given, and
+// Locations and SaveIndex load every region. DatasetInfo, DatasetHash, Stats,
+// MemoryFootprint and the metrics describe the regions loaded so far, so the
+// hash changes as regions are loaded.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithLazyRegions())
+func WithLazyRegions() Option {
+	return func(rg *RGeocoder) error {
+		rg.lazyRegions = true
+		return nil
+	}
+}
+
+// regionSet partitions a binary dataset into regions, one per country code,
+// that are decoded on demand.
+type regionSet struct {
+	view    *binaryView
+	cfg     *loadConfig
+	regions []region
+}
+
+// region is a part of a regionSet.
+type region struct {
+	cc     string
+	rows   []uint32   // Records of the region in the binary dataset
+	lo, hi [3]float64 // Bounds of the records as returned by indexPoint
+}
+
+// newRegionSet partitions the binary dataset in data by country code. The
+// bounds of the regions are computed for metric.
+func newRegionSet(data []byte, cfg *loadConfig, metric Metric) (*regionSet, error) {
+	if cfg.err != nil {
+		return nil, cfg.err
+	}
+	view, err := parseBinary(data)
+	if err != nil {
+		return nil, err
+	}
+	rs := &regionSet{view: view, cfg: cfg}
+	byRef := make(map[uint32]int)
+	for i := range view.count {
+		ref := view.ccRef(i)
+		k, ok := byRef[ref]
+		if !ok {
+			cc, err := view.str(ref)
+			if err != nil {
+				return nil, err
+			}
+			k = len(rs.regions)
+			byRef[ref] = k
+			inf := math.Inf(1)
+			rs.regions = append(rs.regions, region{cc: cc, lo: [3]float64{inf, inf, inf}, hi: [3]float64{-inf, -inf, -inf}})
+		}
+
+		r := &rs.regions[k]
+		r.rows = append(r.rows, uint32(i))
+		lat, lon := view.coord(i)
+		p := indexPoint(metric, lat, lon)
+		for d := range p {
+			r.lo[d] = min(r.lo[d], p[d])
+			r.hi[d] = max(r.hi[d], p[d])
+		}
+	}
+	if len(rs.regions) == 0 {
+		return nil, errors.New("geodecode: no valid coordinates loaded")
+	}
+	return rs, nil
+}
+
+// load decodes the locations of region k that pass the filters of the set's
+// loadConfig.
+func (rs *regionSet) load(k int) ([]Location, error) {
+	r := &rs.regions[k]
+	locs := make([]Location, len(r.rows))
+	for i, row := range r.rows {
+		if err := rs.view.decode(int(row), &locs[i]); err != nil {
+			return nil, err
+		}
+	}
+	rs.cfg.resolveAdminNames(locs)
+	return slices.DeleteFunc(locs, func(loc Location) bool { return !rs.cfg.keep(loc) }), nil
+}
+
+// minDist returns a lower bound of the squared distance, as compared by the
+// spatial indexes, between q and the locations of r, weighted by w as
+// returned by indexWeights.
+func (r *region) minDist(q, w [3]float64) float64 {
+	var dist float64
+	for d := range q {
+		diff := max(r.lo[d]-q[d], q[d]-r.hi[d], 0)
+		dist += w[d] * diff * diff
+	}
+	return dist
+}
+
+// loadRegions returns a dataset that holds every region of ds that may
+// contain one of the k locations nearest to coord, loading and installing
+// the missing ones. It returns ds if no region is missing.
+func (rg *RGeocoder) loadRegions(ds *dataset, coord [2]float64, k int) *dataset {
+	for ds.regions != nil {
+		_, dist := ds.nearestIndex(coord)
+		if k > 1 {
+			dist = math.Inf(1)
+			if nbs := ds.nearestK(coord, k); len(nbs) == k {
+				dist = nbs[k-1].dist
+			}
+		}
+		q, w := indexPoint(ds.metric, coord[0], coord[1]), indexWeights(ds.metric, coord[0])
+		next, nextDist := -1, dist
+		for r := range ds.regions.regions {
+			if d := ds.regions.regions[r].minDist(q, w); !ds.loadedRegions[r] && d < nextDist {
+				next, nextDist = r, d
+			}
+		}
+		if next < 0 {
+			break
+		}
+		ds = rg.loadRegion(ds, next)
+	}
+	return ds
+}
+
+// loadRegion loads region k of ds and installs the resulting dataset. If the
+// geocoder's dataset was replaced in the meantime, the replacement is
+// returned instead.
+func (rg *RGeocoder) loadRegion(ds *dataset, k int) *dataset {
+	return rg.loadRegionsWhere(ds, func(r *region) bool { return r == &ds.regions.regions[k] })
+}
+
+// loadCountry returns a dataset that holds the regions of ds with the
+// country code cc, compared case-insensitively, loading them if necessary.
+func (rg *RGeocoder) loadCountry(ds *dataset, cc string) *dataset {
+	return rg.loadRegionsWhere(ds, func(r *region) bool { return strings.EqualFold(r.cc, cc) })
+}
+
+// loadAllRegions returns a dataset that holds every region of ds, loading
+// the missing ones, for the methods that need the complete dataset.
+func (rg *RGeocoder) loadAllRegions(ds *dataset) *dataset {
+	return rg.loadRegionsWhere(ds, func(*region) bool { return true })
+}
+
+// loadRegionsWhere loads the regions of ds for which match reports true and
+// installs the resulting dataset, rebuilding the spatial index once. It
+// returns ds if ds is loaded completely, and the geocoder's current dataset
+// if it was replaced in the meantime or already holds the regions.
+func (rg *RGeocoder) loadRegionsWhere(ds *dataset, match func(*region) bool) *dataset {
+	if ds.regions == nil {
+		return ds
+	}
+	s := rg.shared()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.data.Load()
+	if cur == nil {
+		return ds
+	}
+	if cur.regions != ds.regions {
+		return cur // Replaced
+	}
+
+	var locs []Location
+	var loaded []int
+	for k := range cur.regions.regions {
+		r := &cur.regions.regions[k]
+		if cur.loadedRegions[k] || !match(r) {
+			continue // Loaded, possibly by a concurrent query
+		}
+		regionLocs, err := cur.regions.load(k)
+		if err != nil {
+			rg.log(slog.LevelError, "geodecode: loading region failed", "country", r.cc, "error", err)
+			regionLocs = nil // Mark the region as loaded anyway, so it is not retried on every query
+		}
+		if rg.verbose {
+			rg.log(slog.LevelInfo, "geodecode: region loaded", "country", r.cc, "locations", len(regionLocs))
+		}
+		locs = append(locs, regionLocs...)
+		loaded = append(loaded, k)
+	}
+	if len(loaded) == 0 {
+		return cur
+	}
+	next := cur.withRegions(loaded, locs)
+	s.data.Store(next)
+	return next
+}
+
+// withRegions returns a copy of ds with locs, the locations of the regions
+// loaded, added, rebuilding the spatial index.
+func (ds *dataset) withRegions(loaded []int, locs []Location) *dataset {
+	merged := newDataset(concatLocations(ds.locations.slice(), locs), ds.names, ds.indexSpec)
+	merged.src = ds.src
+	merged.loadedAt = ds.loadedAt
+	merged.loadDuration = ds.loadDuration
+	merged.added = ds.added
+	merged.regions = ds.regions
+	merged.loadedRegions = slices.Clone(ds.loadedRegions)
+	for _, k := range loaded {
+		merged.loadedRegions[k] = true
+	}
+	return merged
+}
+
+// lazyBlob returns the binary dataset of src if its regions can be loaded
+// lazily, or nil if src has to be loaded completely.
+func lazyBlob(src source, cfg *loadConfig) ([]byte, error) {
+	switch src.kind {
+	case sourceEmbedded:
+		if len(generatedLocations) == 0 && len(embeddedData) > 0 {
+			return embeddedBinary()
+		}
+	case sourceFile:
+		file, err := os.Open(src.location)
+		if err != nil {
+			return nil, nil // Reported when the file is loaded completely
+		}
+		defer file.Close()
+		return mapBinary(file, cfg)
+	}
+	return nil, nil
+}
+
+// lazyDataset returns a dataset of the regions of the binary dataset in data,
+// none of which is loaded yet, with extra appended.
+func (rg *RGeocoder) lazyDataset(data []byte, cfg *loadConfig, src source, start time.Time, extra []Location) (*dataset, error) {
+	rs, err := newRegionSet(data, cfg, rg.metric)
+	if err != nil {
+		return nil, err
+	}
+	ds := &dataset{
+		indexSpec:     rg.indexSpec,
+		names:         cfg.altNames,
+		regions:       rs,
+		loadedRegions: make([]bool, len(rs.regions)),
+	}
+	ds.setLoaded(src, start)
+	if len(extra) > 0 {
+		ds = ds.withLocations(extra)
+	}
+	if rg.verbose {
+		rg.log(slog.LevelInfo, "geodecode: dataset prepared for lazy loading",
+			"source", src.String(), "regions", len(rs.regions), "duration", time.Since(start))
+	}
+	return ds, nil
+}
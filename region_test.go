@@ -0,0 +1,110 @@
+package geodecode_test
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestWithLazyRegions(t *testing.T) {
+	eager, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	lazy, err := geodecode.New(geodecode.WithLazyRegions())
+	if err != nil {
+		t.Fatalf("New with WithLazyRegions: %v", err)
+	}
+	if records := lazy.DatasetInfo().Records; records != 0 {
+		t.Errorf("Expected no records before the first query, got %d", records)
+	}
+
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		coord := [2]float64{35 + r.Float64()*25, r.Float64()*40 - 10}
+		want, got := eager.Query(coord), lazy.Query(coord)
+		if len(got) != 1 || got[0] != want[0] {
+			t.Fatalf("Query(%v): Expected %+v, got %+v", coord, want, got)
+		}
+	}
+	if records, total := lazy.DatasetInfo().Records, eager.DatasetInfo().Records; records == 0 || records >= total {
+		t.Errorf("Expected only European regions to be loaded, got %d of %d records", records, total)
+	}
+}
+
+func TestWithLazyRegionsMemoryMap(t *testing.T) {
+	locs, err := geodecode.ReadLocations(openFixture(t, "cities.txt"), geodecode.WithFormat(geodecode.FormatGeoNames))
+	if err != nil {
+		t.Fatalf("ReadLocations: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := geodecode.WriteBinary(&buf, locs); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "cities.bin")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	geocoder, err := geodecode.New(geodecode.WithLazyRegions(), geodecode.WithDataset(path,
+		geodecode.WithFormat(geodecode.FormatBinary), geodecode.WithMemoryMap()))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	// Germany is loaded first as its bounds are nearest, but Paris is nearer.
+	results := geocoder.Query([2]float64{48.5, 6.8})
+	if len(results) != 1 || results[0].City != "Paris" {
+		t.Errorf("Expected Paris, got %+v", results)
+	}
+	if records := geocoder.DatasetInfo().Records; records != 5 {
+		t.Errorf("Expected the French and German records to be loaded, got %d", records)
+	}
+
+	results = geocoder.Query([2]float64{34.1, -118.2})
+	if len(results) != 1 || results[0].City != "Los Angeles" {
+		t.Errorf("Expected Los Angeles, got %+v", results)
+	}
+}
+
+func TestWithLazyRegionsIsNear(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithLazyRegions())
+	if err != nil {
+		t.Fatalf("New with WithLazyRegions: %v", err)
+	}
+	louvre := [2]float64{48.8606, 2.3376}
+	if near, err := geocoder.IsNear(louvre, "paris", "fr", 10); err != nil || !near {
+		t.Errorf("Expected the Louvre to be near Paris before any query, got %t, %v", near, err)
+	}
+
+	// Loading the United States does not load Germany.
+	geocoder.Query([2]float64{34.1, -118.2})
+	if near, err := geocoder.IsNear(louvre, "Berlin", "DE", 10); err != nil || near {
+		t.Errorf("Expected the Louvre not to be near Berlin, got %t, %v", near, err)
+	}
+	if _, err := geocoder.IsNear(louvre, "Atlantis", "DE", 10); !errors.Is(err, geodecode.ErrCityNotFound) {
+		t.Errorf("Expected ErrCityNotFound, got %v", err)
+	}
+	if _, err := geocoder.IsNear(louvre, "Atlantis", "XX", 10); !errors.Is(err, geodecode.ErrCityNotFound) {
+		t.Errorf("Expected ErrCityNotFound for an unknown country, got %v", err)
+	}
+
+	eager, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var got, want int
+	for range geocoder.Locations() {
+		got++
+	}
+	for range eager.Locations() {
+		want++
+	}
+	if got != want {
+		t.Errorf("Expected Locations to load every region, got %d of %d locations", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package geodecode
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*RGeocoder)
+)
+
+// Register creates a geocoder configured by opts, as New does, and makes it
+// available under name through Instance. This lets applications that serve
+// several gazetteers, for example one per tenant, share them across packages
+// without passing them around. An error is returned if the options are
+// invalid or name is already registered.
+//
+// Example usage:
+//
+//	if _, err := geodecode.Register("europe", geodecode.WithDataset("/data/europe.csv")); err != nil {
+//	    log.Fatal(err)
+//	}
+//	// Elsewhere:
+//	locations := geodecode.Instance("europe").Query([2]float64{52.52, 13.405})
+func Register(name string, opts ...Option) (*RGeocoder, error) {
+	rg, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		return nil, fmt.Errorf("geodecode: geocoder %q is already registered", name)
+	}
+	registry[name] = rg
+	return rg, nil
+}
+
+// Instance returns the geocoder registered under name with Register, or nil
+// if there is none.
+func Instance(name string) *RGeocoder {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[name]
+}
+
+// Unregister removes the geocoder registered under name and releases its
+// dataset with Close. It does nothing if no geocoder is registered under
+// name. Callers still holding the geocoder can keep using it; it loads its
+// dataset again on the next query.
+func Unregister(name string) {
+	registryMu.Lock()
+	rg, ok := registry[name]
+	delete(registry, name)
+	registryMu.Unlock()
+	if ok {
+		rg.Close()
+	}
+}
@@ -0,0 +1,37 @@
+package geodecode_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestRegistry(t *testing.T) {
+	europe, err := geodecode.Register("test-europe", geodecode.WithDataset(filepath.Join("testdata", "cities.csv")))
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	defer geodecode.Unregister("test-europe")
+
+	if got := geodecode.Instance("test-europe"); got != europe {
+		t.Errorf("Expected Instance to return the registered geocoder")
+	}
+	if got := geodecode.Instance("test-missing"); got != nil {
+		t.Errorf("Expected nil for an unregistered name, got %v", got)
+	}
+	if _, err := geodecode.Register("test-europe"); err == nil {
+		t.Errorf("Expected an error when registering a name twice")
+	}
+	if _, err := geodecode.Register("test-invalid", geodecode.WithDataset("")); err == nil {
+		t.Errorf("Expected an error for invalid options")
+	}
+	if geodecode.Instance("test-invalid") != nil {
+		t.Errorf("Expected a geocoder with invalid options not to be registered")
+	}
+
+	geodecode.Unregister("test-europe")
+	if geodecode.Instance("test-europe") != nil {
+		t.Errorf("Expected the geocoder to be gone after Unregister")
+	}
+}
@@ -0,0 +1,327 @@
+package geodecode
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// sourceKind identifies where a dataset is loaded from.
+type sourceKind int
+
+const (
+	sourceEmbedded sourceKind = iota // The dataset compiled into the package
+	sourceFile                       // A dataset file, see LoadFromFile
+	sourceURL                        // A remote dataset, see LoadFromURL
+	sourceIndex                      // An index file, see LoadIndex
+	sourceReader                     // An io.Reader, which cannot be read again
+)
+
+// source describes where the geocoder's dataset comes from, so that it can
+// be loaded lazily and reloaded later.
+type source struct {
+	kind     sourceKind
+	location string      // File path or URL
+	cfg      *loadConfig // Parse settings; nil means the defaults
+}
+
+// String returns a human-readable description of s for log messages.
+func (s source) String() string {
+	switch s.kind {
+	case sourceFile:
+		return "file " + s.location
+	case sourceURL:
+		return "URL " + s.location
+	case sourceIndex:
+		return "index " + s.location
+	case sourceReader:
+		return "reader"
+	default:
+		return "embedded " + embeddedDataset
+	}
+}
+
+// errNoEmbeddedData is reported when the package was built without an
+// embedded dataset and no other dataset was configured.
+var errNoEmbeddedData = errors.New("geodecode: no dataset configured and none embedded (built with geodecode_noembed); use WithDataset, LoadFromFile or LoadFromReader")
+
+// current returns the geocoder's dataset, loading it from the configured
+// source on first use. If loading fails the error is logged and an empty
+// dataset is returned; it is not retried until the data is replaced.
+func (rg *RGeocoder) current() *dataset {
+	s := rg.shared()
+	if ds := s.data.Load(); ds != nil {
+		return ds
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return rg.currentLocked(s)
+}
+
+// currentLocked is current for callers holding s.mu.
+func (rg *RGeocoder) currentLocked(s *store) *dataset {
+	if ds := s.data.Load(); ds != nil {
+		return ds // Already loaded, perhaps by a concurrent caller
+	}
+	ds, err := rg.loadSource(s.src, s.added)
+	if err != nil {
+		rg.log(slog.LevelError, "geodecode: loading dataset failed", "source", s.src.String(), "error", err)
+		ds = &dataset{}
+		if len(s.added) > 0 {
+			ds = rg.newDataset(s.added, nil)
+		}
+		ds.src, ds.added, ds.loadErr = s.src, len(s.added), err
+	}
+	s.data.Store(ds)
+	return ds
+}
+
+// Load loads the geocoder's dataset from the configured source, so that
+// applications can load it at startup and fail fast if it is missing or
+// corrupt instead of getting empty query results. Without Load the dataset is
+// loaded lazily on the first query.
+//
+// Load does nothing if the dataset is already loaded. If an earlier lazy load
+// failed, Load tries again and returns the error.
+//
+// Example usage:
+//
+//	geocoder, err := geodecode.New(geodecode.WithDataset("/data/cities.csv"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if err := geocoder.Load(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (rg *RGeocoder) Load() error {
+	s := rg.shared()
+	if ds := s.data.Load(); ds != nil && ds.loadErr == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ds := s.data.Load(); ds != nil && ds.loadErr == nil {
+		return nil // Loaded by a concurrent caller
+	}
+	ds, err := rg.loadSource(s.src, s.added)
+	if err != nil {
+		return err
+	}
+	s.data.Store(ds)
+	return nil
+}
+
+// Close releases the geocoder's dataset, including locations added with
+// AddLocations, so its memory can be reclaimed once queries in flight have
+// finished. The geocoder remains usable: the next query loads the dataset
+// again from the source it was last loaded from, as if the geocoder were new.
+// Datasets loaded with LoadFromReader cannot be loaded again, so queries fail
+// with ErrDataNotLoaded after Close. Stop any Watch on the geocoder first, or
+// it may reload the dataset. Close always returns nil.
+func (rg *RGeocoder) Close() error {
+	s := rg.shared()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.added = nil
+	s.data.Store(nil)
+	return nil
+}
+
+// loadFrom loads the dataset from src and installs it.
+func (rg *RGeocoder) loadFrom(src source) error {
+	ds, err := rg.loadSource(src, nil)
+	if err != nil {
+		return err
+	}
+	rg.install(ds, src)
+	return nil
+}
+
+// install makes ds the geocoder's dataset and src the source it is reloaded
+// from. Locations added with AddLocations are discarded. Queries already
+// running continue with the previous dataset.
+func (rg *RGeocoder) install(ds *dataset, src source) {
+	s := rg.shared()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src = src
+	s.added = nil
+	s.data.Store(ds)
+}
+
+// Reload rebuilds the geocoder's dataset from the source it was last loaded
+// from: the configured dataset file or URL, the last file, URL or index passed
+// to a Load method, or the embedded dataset. The new dataset replaces the old
+// one atomically once it is fully built; queries running in the meantime
+// continue to use the old dataset. If reloading fails, the old dataset stays
+// in place and the error is returned. Locations added with AddLocations are
+// kept.
+//
+// Datasets loaded with LoadFromReader cannot be reloaded.
+func (rg *RGeocoder) Reload() error {
+	s := rg.shared()
+	s.mu.Lock()
+	src, added := s.src, s.added
+	s.mu.Unlock()
+
+	if src.kind == sourceReader {
+		return errors.New("geodecode: dataset was loaded from an io.Reader and cannot be reloaded")
+	}
+	ds, err := rg.loadSource(src, added)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.src != src {
+		return nil // A different dataset was loaded while reloading; keep it
+	}
+	if len(s.added) > len(added) {
+		// Locations were added while reloading.
+		ds = ds.withLocations(s.added[len(added):])
+	}
+	s.data.Store(ds)
+	return nil
+}
+
+// AddLocations adds custom places, such as warehouses or campuses, to the
+// geocoder's dataset. The dataset is loaded first if necessary. The KD-Tree is
+// rebuilt for every call, so adding many locations in one call is much
+// cheaper than adding them one at a time. Queries running in the meantime
+// continue to use the previous dataset.
+//
+// Added locations survive Reload, but are discarded when a different dataset
+// is loaded with one of the Load methods. An error is returned, and nothing
+// is added, if any location has invalid coordinates.
+func (rg *RGeocoder) AddLocations(locs ...Location) error {
+	for i, loc := range locs {
+		if !validCoordinate(loc.Lat, loc.Lon) {
+			return fmt.Errorf("geodecode: location %d (%q) has invalid coordinates: lat=%v, lon=%v", i, loc.City, loc.Lat, loc.Lon)
+		}
+	}
+	if len(locs) == 0 {
+		return nil
+	}
+
+	s := rg.shared()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Load the base dataset under the lock, so a concurrent Close cannot
+	// release it before the locations are added.
+	base := rg.currentLocked(s)
+	s.added = concatLocations(s.added, locs)
+	s.data.Store(base.withLocations(locs))
+	return nil
+}
+
+// withLocations returns a copy of ds with locs appended, rebuilding the
+// KD-Tree. The copy keeps the load metadata of ds.
+func (ds *dataset) withLocations(locs []Location) *dataset {
+	merged := newDataset(concatLocations(ds.locations.slice(), locs), ds.names, ds.indexSpec)
+	merged.src = ds.src
+	merged.loadedAt = ds.loadedAt
+	merged.loadDuration = ds.loadDuration
+	merged.added = ds.added + len(locs)
+	merged.regions = ds.regions
+	merged.loadedRegions = ds.loadedRegions
+	return merged
+}
+
+// newDataset builds a dataset for the geocoder's index settings.
+func (rg *RGeocoder) newDataset(locations []Location, names map[nameKey]string) *dataset {
+	if len(locations) == 1 {
+		rg.log(slog.LevelWarn, "geodecode: only one valid coordinate loaded, KDTree will not be built")
+	}
+	return newDataset(locations, names, rg.indexSpec)
+}
+
+// concatLocations returns a new slice holding a followed by b, leaving both
+// inputs untouched.
+func concatLocations(a, b []Location) []Location {
+	merged := make([]Location, 0, len(a)+len(b))
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
+
+// loadSource reads the dataset described by src, appends extra and builds
+// the KD-Tree.
+func (rg *RGeocoder) loadSource(src source, extra []Location) (*dataset, error) {
+	if rg.verbose {
+		rg.log(slog.LevelInfo, "geodecode: loading dataset", "source", src.String())
+	}
+	startTime := time.Now()
+
+	cfg := src.cfg
+	if cfg == nil {
+		cfg = &loadConfig{}
+	}
+
+	if rg.lazyRegions {
+		data, err := lazyBlob(src, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			return rg.lazyDataset(data, cfg, src, startTime, extra)
+		}
+	}
+
+	var (
+		locations []Location
+		err       error
+	)
+	switch src.kind {
+	case sourceFile:
+		locations, err = rg.readFile(src.location, cfg)
+	case sourceURL:
+		locations, err = rg.readURL(src.location, cfg)
+	case sourceIndex:
+		ds, err := readIndexFile(src.location)
+		if err != nil {
+			return nil, err
+		}
+		if ds.indexSpec.resolved() != rg.indexSpec.resolved() {
+			// The index was saved with different settings; rebuild it.
+			ds = rg.newDataset(ds.locations.slice(), ds.names)
+		}
+		ds.setLoaded(src, startTime)
+		if len(extra) > 0 {
+			ds = ds.withLocations(extra)
+		}
+		return ds, nil
+	case sourceEmbedded:
+		switch {
+		case len(generatedLocations) > 0:
+			locations, err = cfg.filter(generatedLocations)
+		case len(embeddedData) > 0:
+			var data []byte
+			if data, err = embeddedBinary(); err == nil {
+				locations, err = readBlob(data, cfg)
+			}
+		default:
+			err = errNoEmbeddedData
+		}
+	default:
+		err = fmt.Errorf("geodecode: cannot load dataset from %s", src)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(extra) > 0 {
+		locations = concatLocations(locations, extra)
+	}
+	ds := rg.newDataset(locations, cfg.altNames)
+	ds.setLoaded(src, startTime)
+	ds.added = len(extra)
+
+	if rg.verbose {
+		rg.log(slog.LevelInfo, "geodecode: dataset loaded",
+			"source", src.String(), "locations", ds.locations.len(), "duration", time.Since(startTime))
+	}
+	return ds, nil
+}
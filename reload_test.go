@@ -0,0 +1,302 @@
+package geodecode_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+	"weak"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+const (
+	reloadDataV1 = "lat,lon,city,admin1,admin2,cc\n52.52437,13.41053,Berlin,Berlin,,DE\n"
+	reloadDataV2 = "lat,lon,city,admin1,admin2,cc\n52.39886,13.06566,Potsdam,Brandenburg,,DE\n"
+)
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	if err := os.WriteFile(path, []byte(reloadDataV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	coord := [2]float64{52.5, 13.4}
+	if got := geocoder.Query(coord)[0].City; got != "Berlin" {
+		t.Fatalf("Expected Berlin before reload, got %q", got)
+	}
+
+	if err := os.WriteFile(path, []byte(reloadDataV2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := geocoder.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := geocoder.Query(coord)[0].City; got != "Potsdam" {
+		t.Errorf("Expected Potsdam after reload, got %q", got)
+	}
+
+	// A failed reload keeps the current dataset.
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := geocoder.Reload(); err == nil {
+		t.Errorf("Expected reload of a corrupt file to fail")
+	}
+	if got := geocoder.Query(coord)[0].City; got != "Potsdam" {
+		t.Errorf("Expected Potsdam to survive a failed reload, got %q", got)
+	}
+
+	if err := geocoder.LoadFromReader(strings.NewReader(reloadDataV1)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	if err := geocoder.Reload(); err == nil {
+		t.Errorf("Expected an error reloading a dataset loaded from a reader")
+	}
+}
+
+func TestReloadConcurrentQueries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	if err := os.WriteFile(path, []byte(reloadDataV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				results := geocoder.Query([2]float64{52.5, 13.4})
+				if len(results) != 1 || (results[0].City != "Berlin" && results[0].City != "Potsdam") {
+					t.Errorf("Unexpected result during reload: %+v", results)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		data := reloadDataV1
+		if i%2 == 0 {
+			data = reloadDataV2
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := geocoder.Reload(); err != nil {
+			t.Errorf("Reload: %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestAddLocations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	if err := os.WriteFile(path, []byte(reloadDataV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	warehouse := geodecode.Location{Lat: 52.40, Lon: 13.07, City: "Warehouse 7", CC: "DE"}
+	if err := geocoder.AddLocations(warehouse); err != nil {
+		t.Fatalf("AddLocations: %v", err)
+	}
+	results := geocoder.Query([2]float64{52.4, 13.1}, [2]float64{52.5, 13.4})
+	if results[0].City != "Warehouse 7" || results[1].City != "Berlin" {
+		t.Errorf("Expected Warehouse 7 and Berlin, got %q and %q", results[0].City, results[1].City)
+	}
+
+	if err := geocoder.AddLocations(geodecode.Location{Lat: 91, Lon: 0, City: "Nowhere"}); err == nil {
+		t.Errorf("Expected an error for invalid coordinates")
+	}
+
+	// Added locations survive a reload of the underlying dataset.
+	if err := os.WriteFile(path, []byte(reloadDataV2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := geocoder.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := geocoder.Query([2]float64{52.4, 13.1})[0].City; got != "Warehouse 7" {
+		t.Errorf("Expected Warehouse 7 after reload, got %q", got)
+	}
+
+	// Loading a different dataset discards them.
+	if err := geocoder.LoadFromReader(strings.NewReader(reloadDataV1)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+	if got := geocoder.Query([2]float64{52.4, 13.1})[0].City; got != "Berlin" {
+		t.Errorf("Expected Berlin after loading a new dataset, got %q", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	geocoder, err := geodecode.New(geodecode.WithDataset(path), geodecode.WithLogger(nil))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.Load(); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected Load to report the missing file, got %v", err)
+	}
+	if got := geocoder.Query([2]float64{52.5, 13.4}); len(got) != 0 {
+		t.Errorf("Expected no results while the file is missing, got %+v", got)
+	}
+
+	if err := os.WriteFile(path, []byte(reloadDataV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := geocoder.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := geocoder.Query([2]float64{52.5, 13.4}); len(got) != 1 || got[0].City != "Berlin" {
+		t.Errorf("Expected Berlin after Load, got %+v", got)
+	}
+}
+
+func TestConcurrentMutation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	if err := os.WriteFile(path, []byte(reloadDataV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	const adds = 20
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if results := geocoder.Query([2]float64{52.5, 13.4}); len(results) != 1 || results[0].City == "" {
+					t.Errorf("Unexpected result during mutation: %+v", results)
+					return
+				}
+				geocoder.DatasetInfo()
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < adds; i++ {
+			loc := geodecode.Location{Lat: float64(i), Lon: float64(i), City: "Site", CC: "XX"}
+			if err := geocoder.AddLocations(loc); err != nil {
+				t.Errorf("AddLocations: %v", err)
+			}
+		}
+	}()
+	for i := 0; i < adds; i++ {
+		if err := geocoder.Reload(); err != nil {
+			t.Errorf("Reload: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if info := geocoder.DatasetInfo(); info.Added != adds || info.Records != adds+1 {
+		t.Errorf("Expected %d added locations out of %d records, got %d out of %d", adds, adds+1, info.Added, info.Records)
+	}
+}
+
+func TestClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	if err := os.WriteFile(path, []byte(reloadDataV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.AddLocations(geodecode.Location{Lat: 52.5, Lon: 13.4, City: "Campus", CC: "DE"}); err != nil {
+		t.Fatalf("AddLocations: %v", err)
+	}
+	if err := geocoder.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(reloadDataV2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := geocoder.Query([2]float64{52.5, 13.4}); len(got) != 1 || got[0].City != "Potsdam" {
+		t.Errorf("Expected the dataset to be loaded again without added locations, got %+v", got)
+	}
+}
+
+func TestCloseReleasesEmbeddedData(t *testing.T) {
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	// The names point into the decompressed dataset.
+	city := geocoder.Query([2]float64{52.52, 13.405})[0].City
+	if city == "" {
+		t.Fatalf("Expected a location in Berlin")
+	}
+	name := weak.Make(unsafe.StringData(city))
+
+	if err := geocoder.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	runtime.GC()
+	runtime.GC()
+	if name.Value() != nil {
+		t.Errorf("Expected Close to release the decompressed embedded dataset")
+	}
+	runtime.KeepAlive(geocoder)
+}
+
+func TestCloseAddLocationsConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	if err := os.WriteFile(path, []byte(reloadDataV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 200 {
+			geocoder.Close()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 200 {
+			if err := geocoder.AddLocations(geodecode.Location{Lat: 52.5, Lon: 13.4, City: "Campus", CC: "DE"}); err != nil {
+				t.Errorf("AddLocations: %v", err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	if err := geocoder.AddLocations(geodecode.Location{Lat: 48.1, Lon: 11.6, City: "Depot", CC: "DE"}); err != nil {
+		t.Fatalf("AddLocations: %v", err)
+	}
+	if got := geocoder.Query([2]float64{48.1, 11.6}); len(got) != 1 || got[0].City != "Depot" {
+		t.Errorf("Expected the added location, got %+v", got)
+	}
+}
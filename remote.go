@@ -0,0 +1,180 @@
+package geodecode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// cacheMeta holds the HTTP validators of a cached remote dataset.
+type cacheMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// WithCacheDir makes LoadFromURL and WithDatasetURL keep a copy of the
+// downloaded dataset in dir. Subsequent loads send conditional requests using
+// the cached ETag and Last-Modified values and reuse the cached copy when the
+// server reports it unchanged or cannot be reached.
+func WithCacheDir(dir string) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.cacheDir = dir
+	}
+}
+
+// WithHTTPClient sets the client used by LoadFromURL and WithDatasetURL.
+// The default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.httpClient = client
+	}
+}
+
+// LoadFromURL replaces the geocoder's dataset with the cities downloaded from
+// url, which must serve one of the formats accepted by LoadFromFile. Use
+// WithCacheDir to avoid downloading an unchanged dataset again.
+//
+// Example usage:
+//
+//	err := geocoder.LoadFromURL("https://data.example.com/gazetteer.csv.gz",
+//	    geodecode.WithCacheDir("/var/cache/geodecode"))
+func (rg *RGeocoder) LoadFromURL(url string, opts ...LoadOption) error {
+	return rg.loadFrom(source{kind: sourceURL, location: url, cfg: newLoadConfig(opts)})
+}
+
+// readURL downloads and parses the dataset at url, going through the cache
+// directory if one is configured.
+func (rg *RGeocoder) readURL(url string, cfg *loadConfig) ([]Location, error) {
+	client := cfg.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("geodecode: creating request: %w", err)
+	}
+
+	if cfg.cacheDir == "" {
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("geodecode: downloading dataset: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("geodecode: downloading dataset: unexpected status %s", resp.Status)
+		}
+		if resp.ContentLength >= 0 {
+			return rg.read(sizedReader{resp.Body, resp.ContentLength}, cfg)
+		}
+		return rg.read(resp.Body, cfg)
+	}
+
+	sum := sha256.Sum256([]byte(url))
+	key := hex.EncodeToString(sum[:])
+	dataPath := filepath.Join(cfg.cacheDir, key+".data")
+	metaPath := filepath.Join(cfg.cacheDir, key+".json")
+
+	meta, cached := readCacheMeta(metaPath, dataPath)
+	if cached {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if cached {
+			rg.log(slog.LevelWarn, "geodecode: download failed, using cached copy", "url", url, "error", err)
+			return rg.readFile(dataPath, cfg)
+		}
+		return nil, fmt.Errorf("geodecode: downloading dataset: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		if rg.verbose {
+			rg.log(slog.LevelInfo, "geodecode: cached copy is up to date", "url", url)
+		}
+		return rg.readFile(dataPath, cfg)
+	case resp.StatusCode == http.StatusOK:
+		if err := writeCache(cfg.cacheDir, dataPath, resp.Body); err != nil {
+			return nil, err
+		}
+		meta = cacheMeta{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}
+		if err := writeCacheMeta(metaPath, meta); err != nil {
+			return nil, err
+		}
+		return rg.readFile(dataPath, cfg)
+	case resp.StatusCode >= http.StatusInternalServerError && cached:
+		rg.log(slog.LevelWarn, "geodecode: download failed, using cached copy", "url", url, "status", resp.Status)
+		return rg.readFile(dataPath, cfg)
+	default:
+		return nil, fmt.Errorf("geodecode: downloading dataset: unexpected status %s", resp.Status)
+	}
+}
+
+// readCacheMeta reads the validators of a cached dataset. It reports false if
+// no complete cache entry exists.
+func readCacheMeta(metaPath, dataPath string) (cacheMeta, bool) {
+	var meta cacheMeta
+	raw, err := os.ReadFile(metaPath)
+	if err != nil || json.Unmarshal(raw, &meta) != nil {
+		return cacheMeta{}, false
+	}
+	if _, err := os.Stat(dataPath); err != nil {
+		return cacheMeta{}, false
+	}
+	return meta, true
+}
+
+// writeCache atomically stores the contents of r at dataPath.
+func writeCache(dir, dataPath string, r io.Reader) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("geodecode: creating cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "download-*")
+	if err != nil {
+		return fmt.Errorf("geodecode: creating cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("geodecode: downloading dataset: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("geodecode: writing cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dataPath); err != nil {
+		return fmt.Errorf("geodecode: writing cache file: %w", err)
+	}
+	return nil
+}
+
+// writeCacheMeta stores the validators of a cached dataset.
+func writeCacheMeta(metaPath string, meta cacheMeta) error {
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(metaPath, raw, 0o644); err != nil {
+		return fmt.Errorf("geodecode: writing cache metadata: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package geodecode_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestLoadFromURL(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "cities.csv"))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	var full, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const etag = `"v1"`
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", etag)
+		w.Write(data)
+	}))
+
+	cacheDir := t.TempDir()
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := geocoder.LoadFromURL(server.URL, geodecode.WithCacheDir(cacheDir)); err != nil {
+			t.Fatalf("LoadFromURL #%d: %v", i+1, err)
+		}
+	}
+	if full != 1 || notModified != 1 {
+		t.Errorf("Expected one full and one conditional download, got %d full and %d not modified", full, notModified)
+	}
+	if results := geocoder.Query([2]float64{48.2, 11.6}); len(results) != 1 || results[0].City != "Munich" {
+		t.Errorf("Expected Munich from downloaded dataset, got %+v", results)
+	}
+
+	// The cached copy is used when the server is unreachable.
+	server.Close()
+	if err := geocoder.LoadFromURL(server.URL, geodecode.WithCacheDir(cacheDir)); err != nil {
+		t.Errorf("Expected cached copy to be used while offline, got %v", err)
+	}
+	if err := geocoder.LoadFromURL(server.URL); err == nil {
+		t.Errorf("Expected an error while offline without a cache")
+	}
+}
+
+func TestWithDatasetURL(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	if _, err := geodecode.New(geodecode.WithDatasetURL("")); err == nil {
+		t.Errorf("Expected an error for an empty URL")
+	}
+
+	geocoder, err := geodecode.New(geodecode.WithDatasetURL(server.URL+"/cities.txt",
+		geodecode.WithFormat(geodecode.FormatGeoNames)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if results := geocoder.Query([2]float64{34.0, -118.2}); len(results) != 1 || results[0].City != "Los Angeles" {
+		t.Errorf("Expected Los Angeles from remote GeoNames dataset, got %+v", results)
+	}
+
+	missing, err := geodecode.New(geodecode.WithDatasetURL(server.URL + "/missing.csv"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if results := missing.Query([2]float64{0, 0}); len(results) != 0 {
+		t.Errorf("Expected no results for a missing remote dataset, got %+v", results)
+	}
+}
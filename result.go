@@ -0,0 +1,95 @@
+package geodecode
+
+import (
+	"errors"
+	"math"
+)
+
+// confidenceScaleKM is the distance at which a match's confidence drops to
+// 1/e. It is in the order of the radius of a large city.
+const confidenceScaleKM = 25
+
+// Result is the outcome of resolving one coordinate with Resolve.
+type Result struct {
+	Location   Location // The nearest location; empty if Found is false.
+	Found      bool     // Whether a location was found for the coordinate.
+	Distance   float64  // Great-circle distance from the coordinate to Location, in Unit.
+	Unit       Unit     // Unit of Distance, set with WithUnits.
+	DistanceKM float64  // Distance in kilometers, regardless of Unit.
+	// Confidence is a rough measure between 0 and 1 of how well Location
+	// describes the coordinate. It is 1 for an exact match and decays
+	// exponentially with distance, reaching 0.37 at 25 km.
+	Confidence float64
+}
+
+// Resolve is like Lookup, but returns a Result for every coordinate, which
+// tells misses apart from real locations and reports how far away each match
+// is. Coordinates without a match, for example because of WithMaxDistance,
+// have Found set to false; they are not reported as errors. An error wrapping
+// ErrInvalidCoordinate or ErrDataNotLoaded is returned as by Lookup.
+//
+// Example usage:
+//
+//	results, err := geocoder.Resolve([2]float64{52.52, 13.405})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if results[0].Found {
+//	    fmt.Printf("%s, %.1f %s away\n", results[0].Location.City, results[0].Distance, results[0].Unit)
+//	}
+func (rg *RGeocoder) Resolve(coordinates ...[2]float64) ([]Result, error) {
+	ds, err := rg.prepare(coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveAll(rg, ds, make([]Result, 0, len(coordinates)), coordinates, (*RGeocoder).resolve)
+}
+
+// QueryInto is like Resolve, but appends the results to dst[:0] and returns
+// the extended slice, so that a caller reusing dst across calls does not
+// allocate. Once the dataset is loaded, a lookup without localized names,
+// country details, query hooks or a cache performs no heap allocations.
+//
+// Example usage:
+//
+//	buf := make([]geodecode.Result, 0, 1)
+//	for _, coord := range coords {
+//	    buf, err = geocoder.QueryInto(buf, coord)
+//	    ...
+//	}
+func (rg *RGeocoder) QueryInto(dst []Result, coordinates ...[2]float64) ([]Result, error) {
+	dst = dst[:0]
+	ds, err := rg.prepare(coordinates)
+	if err != nil {
+		return dst, err
+	}
+
+	return resolveAll(rg, ds, dst, coordinates, (*RGeocoder).resolve)
+}
+
+// resolve returns the Result for the normalized coord in ds. Like Resolve, it
+// reports no error for a coordinate without a match.
+func (rg *RGeocoder) resolve(ds *dataset, coord [2]float64) (Result, error) {
+	loc, err := rg.nearest(ds, coord)
+	switch {
+	case errors.Is(err, ErrNoResult):
+		return Result{Unit: rg.units}, nil
+	case err != nil:
+		return Result{Unit: rg.units}, err
+	}
+	return rg.found(coord, loc), nil
+}
+
+// found returns the Result of finding loc for coord.
+func (rg *RGeocoder) found(coord [2]float64, loc Location) Result {
+	distance := haversineKM(coord[0], coord[1], loc.Lat, loc.Lon)
+	return Result{
+		Location:   loc,
+		Found:      true,
+		Distance:   rg.units.fromKM(distance),
+		Unit:       rg.units,
+		DistanceKM: distance,
+		Confidence: math.Exp(-distance / confidenceScaleKM),
+	}
+}
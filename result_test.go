@@ -0,0 +1,130 @@
+package geodecode_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestResolve(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithMaxDistance(100))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+
+	results, err := geocoder.Resolve([2]float64{60, 0}, [2]float64{60.1, 0}, [2]float64{-45, 90})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if r := results[0]; !r.Found || r.Location.City != "North" || r.DistanceKM != 0 || r.Confidence != 1 {
+		t.Errorf("Expected an exact match on North, got %+v", r)
+	}
+	if r := results[1]; !r.Found || math.Abs(r.DistanceKM-11.12) > 0.01 || r.Confidence >= 1 || r.Confidence <= 0.5 {
+		t.Errorf("Expected North about 11 km away with reduced confidence, got %+v", r)
+	}
+	if r := results[2]; r.Found || r != (geodecode.Result{}) {
+		t.Errorf("Expected no match beyond the maximum distance, got %+v", r)
+	}
+
+	if _, err := geocoder.Resolve([2]float64{91, 0}); !errors.Is(err, geodecode.ErrInvalidCoordinate) {
+		t.Errorf("Expected ErrInvalidCoordinate, got %v", err)
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	found := geodecode.Result{
+		Location:   geodecode.Location{Lat: 60, City: "North", CC: "NO"},
+		Found:      true,
+		Distance:   1.5,
+		DistanceKM: 1.5,
+		Confidence: 0.94,
+	}
+	for _, tc := range []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"default miss", geodecode.Result{}, `{"found":false,"distance":0,"unit":"km","confidence":0,"location":{"geonameId":0,"lat":0,"lon":0,"city":"","admin1":"","admin1Code":"","admin2":"","admin2Name":"","cc":"","country":"","timezone":"","elevation":0,"population":0,"countryInfo":{"iso3":"","continent":"","currency":"","callingCode":""}}}`},
+		{"omit empty", geodecode.JSONOptions{SnakeCase: true, OmitEmpty: true}.Results([]geodecode.Result{found, {}}), `[{"found":true,"distance":1.5,"unit":"km","confidence":0.94,"location":{"lat":60,"lon":0,"city":"North","cc":"NO"}},{"found":false}]`},
+		{"omit distance", geodecode.JSONOptions{OmitEmpty: true, OmitDistance: true}.Result(found), `{"found":true,"location":{"lat":60,"lon":0,"city":"North","cc":"NO"}}`},
+	} {
+		got, err := json.Marshal(tc.v)
+		if err != nil {
+			t.Fatalf("%s: Marshal: %v", tc.name, err)
+		}
+		if string(got) != tc.want {
+			t.Errorf("%s: Expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestResolveUnits(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithMaxDistance(7), geodecode.WithUnits(geodecode.NauticalMiles))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := geocoder.LoadFromReader(strings.NewReader(antimeridianData)); err != nil {
+		t.Fatalf("LoadFromReader: %v", err)
+	}
+
+	// One arc minute of latitude is about one nautical mile.
+	results, err := geocoder.Resolve([2]float64{60.1, 0}, [2]float64{60.2, 0})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if r := results[0]; !r.Found || r.Unit != geodecode.NauticalMiles || math.Abs(r.Distance-6.0) > 0.05 || math.Abs(r.DistanceKM-11.12) > 0.01 {
+		t.Errorf("Expected North about 6 nmi away, got %+v", r)
+	}
+	if results[1].Found {
+		t.Errorf("Expected no match 12 nmi away with a 7 nmi limit, got %+v", results[1])
+	}
+
+	if near, err := geocoder.IsNear([2]float64{60.1, 0}, "North", "NO", 6.5); err != nil || !near {
+		t.Errorf("Expected IsNear within 6.5 nmi, got %v, %v", near, err)
+	}
+	if near, err := geocoder.IsNear([2]float64{60.1, 0}, "North", "NO", 5.5); err != nil || near {
+		t.Errorf("Expected IsNear to be false within 5.5 nmi, got %v, %v", near, err)
+	}
+}
+
+func TestQueryInto(t *testing.T) {
+	geocoder, err := geodecode.New(geodecode.WithCoordinatePolicy(geodecode.WrapLongitude))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	coords := [][2]float64{{52.52, 13.405}, {48.8566, 362.3522}}
+	want, err := geocoder.Resolve(coords...)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+
+	buf := make([]geodecode.Result, 0, len(coords))
+	got, err := geocoder.QueryInto(buf, coords...)
+	if err != nil {
+		t.Fatalf("QueryInto: %v", err)
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || &got[0] != &buf[:1][0] {
+		t.Errorf("Expected %+v in the caller's buffer, got %+v", want, got)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = geocoder.QueryInto(buf, coords[0])
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations per query, got %v", allocs)
+	}
+
+	if _, err := geocoder.QueryInto(buf, [2]float64{91, 0}); !errors.Is(err, geodecode.ErrInvalidCoordinate) {
+		t.Errorf("Expected ErrInvalidCoordinate, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package geodecode
+
+import (
+	"iter"
+	"unsafe"
+)
+
+// Text columns of a locationTable.
+const (
+	colCity = iota
+	colAdmin1
+	colAdmin1Code
+	colAdmin2
+	colAdmin2Name
+	colCC
+	colCountry
+	colTimezone
+	numTextCols
+)
+
+// locationTable stores a dataset's locations column by column rather than as
+// a []Location. Coordinates sit in their own slices and every text field is
+// a 32-bit index into a table of distinct strings, which takes a fraction of
+// the memory of full Location values and keeps the coordinates close
+// together. Location values are assembled on demand with at.
+//
+// Columns whose values are all empty or zero are left nil. Coordinates are
+// stored either in lat and lon or, with WithFloat32Coordinates, in lat32 and
+// lon32.
+type locationTable struct {
+	lat, lon     []float64
+	lat32, lon32 []float32
+	text         [numTextCols][]uint32 // Indexes into strs
+	strs         []string              // Distinct strings; strs[0] is ""
+	geonameID    []int
+	elevation    []int
+	population   []int
+}
+
+// textFields returns pointers to the text fields of loc, in column order.
+func textFields(loc *Location) [numTextCols]*string {
+	return [numTextCols]*string{&loc.City, &loc.Admin1, &loc.Admin1Code, &loc.Admin2, &loc.Admin2Name, &loc.CC, &loc.Country, &loc.Timezone}
+}
+
+// newLocationTable stores locs in a locationTable, with their coordinates
+// as float32 if single is set.
+func newLocationTable(locs []Location, single bool) locationTable {
+	t := locationTable{strs: []string{""}}
+	if single {
+		t.lat32, t.lon32 = make([]float32, len(locs)), make([]float32, len(locs))
+	} else {
+		t.lat, t.lon = make([]float64, len(locs)), make([]float64, len(locs))
+	}
+	refs := make(map[string]uint32, len(locs))
+	refs[""] = 0
+	for i := range locs {
+		loc := &locs[i]
+		if single {
+			t.lat32[i], t.lon32[i] = float32(loc.Lat), float32(loc.Lon)
+		} else {
+			t.lat[i], t.lon[i] = loc.Lat, loc.Lon
+		}
+		for col, s := range textFields(loc) {
+			if *s == "" {
+				continue
+			}
+			ref, ok := refs[*s]
+			if !ok {
+				ref = uint32(len(t.strs))
+				refs[*s] = ref
+				t.strs = append(t.strs, *s)
+			}
+			if t.text[col] == nil {
+				t.text[col] = make([]uint32, len(locs))
+			}
+			t.text[col][i] = ref
+		}
+		setColumn(&t.geonameID, i, loc.GeonameID, len(locs))
+		setColumn(&t.elevation, i, loc.Elevation, len(locs))
+		setColumn(&t.population, i, loc.Population, len(locs))
+	}
+	return t
+}
+
+// setColumn stores v at row i of the column, allocating it for n rows once
+// the first non-zero value is stored.
+func setColumn(column *[]int, i, v, n int) {
+	if v == 0 {
+		return
+	}
+	if *column == nil {
+		*column = make([]int, n)
+	}
+	(*column)[i] = v
+}
+
+// len returns the number of locations in t.
+func (t *locationTable) len() int {
+	return len(t.lat) + len(t.lat32)
+}
+
+// coord returns the coordinates of the i-th location of t.
+func (t *locationTable) coord(i int) (lat, lon float64) {
+	if t.lat32 != nil {
+		return float64(t.lat32[i]), float64(t.lon32[i])
+	}
+	return t.lat[i], t.lon[i]
+}
+
+// at assembles the i-th location of t.
+func (t *locationTable) at(i int) Location {
+	var loc Location
+	loc.Lat, loc.Lon = t.coord(i)
+	for col, s := range textFields(&loc) {
+		if column := t.text[col]; column != nil {
+			*s = t.strs[column[i]]
+		}
+	}
+	if t.geonameID != nil {
+		loc.GeonameID = t.geonameID[i]
+	}
+	if t.elevation != nil {
+		loc.Elevation = t.elevation[i]
+	}
+	if t.population != nil {
+		loc.Population = t.population[i]
+	}
+	return loc
+}
+
+// all returns an iterator over the locations of t and their indexes.
+func (t *locationTable) all() iter.Seq2[int, Location] {
+	return func(yield func(int, Location) bool) {
+		for i := range t.len() {
+			if !yield(i, t.at(i)) {
+				return
+			}
+		}
+	}
+}
+
+// slice returns the locations of t as a newly allocated []Location.
+func (t *locationTable) slice() []Location {
+	locs := make([]Location, t.len())
+	for i := range locs {
+		locs[i] = t.at(i)
+	}
+	return locs
+}
+
+// columnBytes returns the memory held by the columns of t, excluding the
+// string contents.
+func (t *locationTable) columnBytes() int64 {
+	size := int64(cap(t.lat)+cap(t.lon))*8 + int64(cap(t.lat32)+cap(t.lon32))*4
+	for _, column := range t.text {
+		size += int64(cap(column)) * 4
+	}
+	size += int64(cap(t.geonameID)+cap(t.elevation)+cap(t.population)) * int64(unsafe.Sizeof(0))
+	return size + int64(cap(t.strs))*int64(unsafe.Sizeof(""))
+}
+
+// stringBytes returns the bytes held by the distinct strings of t, and the
+// bytes saved because locations share them instead of holding a copy each.
+func (t *locationTable) stringBytes() (held, saved int64) {
+	for _, s := range t.strs {
+		held += int64(len(s))
+	}
+	for _, column := range t.text {
+		for _, ref := range column {
+			saved += int64(len(t.strs[ref]))
+		}
+	}
+	return held, saved - held
+}
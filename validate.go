@@ -0,0 +1,151 @@
+package geodecode
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// IssueKind classifies a problem found by ValidateDataset.
+type IssueKind string
+
+const (
+	// IssueMalformedRow marks a row that cannot be parsed as CSV, for example
+	// because it has the wrong number of fields.
+	IssueMalformedRow IssueKind = "malformed-row"
+	// IssueInvalidCoordinates marks a row whose coordinates are not numbers
+	// or lie outside the valid WGS84 range.
+	IssueInvalidCoordinates IssueKind = "invalid-coordinates"
+	// IssueDuplicatePoint marks a row with the same coordinates as an earlier
+	// row.
+	IssueDuplicatePoint IssueKind = "duplicate-point"
+	// IssueInvalidEncoding marks text that is not valid UTF-8, or a header
+	// starting with a byte order mark.
+	IssueInvalidEncoding IssueKind = "invalid-encoding"
+)
+
+// Issue describes a single problem found by ValidateDataset.
+type Issue struct {
+	Row     int       // Data row, starting at 1 after the header; 0 for the header itself.
+	Kind    IssueKind // Kind of problem.
+	Message string    // Human-readable description.
+}
+
+// String formats the issue for display.
+func (i Issue) String() string {
+	if i.Row == 0 {
+		return fmt.Sprintf("header: %s: %s", i.Kind, i.Message)
+	}
+	return fmt.Sprintf("row %d: %s: %s", i.Row, i.Kind, i.Message)
+}
+
+// Report is the result of ValidateDataset.
+type Report struct {
+	Rows           int      // Number of data rows, excluding the header.
+	ValidRows      int      // Number of rows the loader would keep.
+	MissingColumns []string // Required columns absent from the header.
+	Issues         []Issue  // Problems found, in file order.
+}
+
+// OK reports whether the dataset has all required columns, at least one
+// loadable row and no issues.
+func (r *Report) OK() bool {
+	return len(r.MissingColumns) == 0 && len(r.Issues) == 0 && r.ValidRows > 0
+}
+
+// ValidateDataset checks a CSV dataset for missing columns, malformed rows,
+// out-of-range coordinates, duplicate points and encoding problems, which the
+// loader otherwise skips with only a log line. It accepts the same gzip
+// compression and LoadOptions (such as WithColumns and WithDelimiter) as
+// LoadFromReader. Problems with the data are recorded in the report; an error
+// is only returned if r cannot be read.
+//
+// Example usage:
+//
+//	report, err := geodecode.ValidateDataset(file)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, issue := range report.Issues {
+//	    fmt.Println(issue)
+//	}
+func ValidateDataset(r io.Reader, opts ...LoadOption) (Report, error) {
+	var report Report
+
+	cfg := newLoadConfig(opts)
+	if cfg.err != nil {
+		return report, cfg.err
+	}
+	if cfg.format != FormatCSV {
+		return report, errors.New("geodecode: only CSV datasets can be validated")
+	}
+
+	r, err := decompress(r)
+	if err != nil {
+		return report, err
+	}
+	reader := newCSVReader(r, cfg)
+
+	header, err := reader.Read()
+	if err != nil {
+		return report, fmt.Errorf("geodecode: reading CSV header: %w", err)
+	}
+	if len(header) > 0 && strings.HasPrefix(header[0], "\ufeff") {
+		report.Issues = append(report.Issues, Issue{Kind: IssueInvalidEncoding, Message: "header starts with a UTF-8 byte order mark"})
+	}
+	for _, col := range header {
+		if !utf8.ValidString(col) {
+			report.Issues = append(report.Issues, Issue{Kind: IssueInvalidEncoding, Message: fmt.Sprintf("column name %q is not valid UTF-8", col)})
+		}
+	}
+	colMap, missing := csvColumns(header, cfg)
+	if len(missing) > 0 {
+		report.MissingColumns = missing
+		return report, nil // Rows cannot be checked without the required columns
+	}
+
+	seen := make(map[[2]float64]int) // First row holding each point
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		report.Rows++
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return report, fmt.Errorf("geodecode: reading CSV row %d: %w", row, err)
+			}
+			report.Issues = append(report.Issues, Issue{Row: row, Kind: IssueMalformedRow, Message: parseErr.Err.Error()})
+			continue
+		}
+
+		for _, field := range record {
+			if !utf8.ValidString(field) {
+				report.Issues = append(report.Issues, Issue{Row: row, Kind: IssueInvalidEncoding, Message: fmt.Sprintf("field %q is not valid UTF-8", field)})
+				break
+			}
+		}
+
+		latStr, lonStr := record[colMap["lat"]], record[colMap["lon"]]
+		lat, errLat := strconv.ParseFloat(latStr, 64)
+		lon, errLon := strconv.ParseFloat(lonStr, 64)
+		if errLat != nil || errLon != nil || !validCoordinate(lat, lon) {
+			report.Issues = append(report.Issues, Issue{Row: row, Kind: IssueInvalidCoordinates, Message: fmt.Sprintf("lat=%q, lon=%q", latStr, lonStr)})
+			continue
+		}
+		report.ValidRows++
+
+		point := [2]float64{lat, lon}
+		if first, ok := seen[point]; ok {
+			report.Issues = append(report.Issues, Issue{Row: row, Kind: IssueDuplicatePoint, Message: fmt.Sprintf("same coordinates as row %d", first)})
+			continue
+		}
+		seen[point] = row
+	}
+	return report, nil
+}
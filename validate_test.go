@@ -0,0 +1,63 @@
+package geodecode_test
+
+import (
+	"strings"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestValidateDataset(t *testing.T) {
+	data := "lat,lon,city,admin1,admin2,cc\n" +
+		"52.52437,13.41053,Berlin,Berlin,,DE\n" +
+		"95.0,13.0,Nowhere,,,DE\n" +
+		"52.52437,13.41053,Berlin Mitte,Berlin,,DE\n" +
+		"48.13743,11.57549,M\xfcnchen,Bavaria,,DE\n" +
+		"48.85341,2.3488,Paris\n"
+
+	report, err := geodecode.ValidateDataset(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ValidateDataset: %v", err)
+	}
+	if report.Rows != 5 || report.ValidRows != 3 {
+		t.Errorf("Expected 5 rows with 3 valid, got %d with %d valid", report.Rows, report.ValidRows)
+	}
+	want := []struct {
+		row  int
+		kind geodecode.IssueKind
+	}{
+		{2, geodecode.IssueInvalidCoordinates},
+		{3, geodecode.IssueDuplicatePoint},
+		{4, geodecode.IssueInvalidEncoding},
+		{5, geodecode.IssueMalformedRow},
+	}
+	if len(report.Issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %v", len(want), report.Issues)
+	}
+	for i, w := range want {
+		if got := report.Issues[i]; got.Row != w.row || got.Kind != w.kind {
+			t.Errorf("Expected issue %d to be %s in row %d, got %v", i, w.kind, w.row, got)
+		}
+	}
+	if report.OK() {
+		t.Errorf("Expected report with issues not to be OK")
+	}
+}
+
+func TestValidateDatasetMissingColumns(t *testing.T) {
+	report, err := geodecode.ValidateDataset(strings.NewReader("lat,lon,name,cc\n52.5,13.4,Berlin,DE\n"))
+	if err != nil {
+		t.Fatalf("ValidateDataset: %v", err)
+	}
+	if got := strings.Join(report.MissingColumns, ","); got != "city,admin1,admin2" {
+		t.Errorf("Expected missing columns city,admin1,admin2, got %s", got)
+	}
+
+	report, err = geodecode.ValidateDataset(openFixture(t, "cities.csv"))
+	if err != nil {
+		t.Fatalf("ValidateDataset: %v", err)
+	}
+	if len(report.MissingColumns) != 0 || report.ValidRows == 0 {
+		t.Errorf("Unexpected report for fixture: %+v", report)
+	}
+}
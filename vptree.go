@@ -0,0 +1,222 @@
+package geodecode
+
+import (
+	"math"
+	"math/bits"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// vpTree is the vantage-point tree selected with VPTreeIndex. Unlike the
+// KD-Tree and the grid, which compare points in a space the coordinates are
+// converted to, it partitions the locations by their distance under the
+// metric itself: the great-circle distance for MetricHaversine. Under
+// MetricEquirectangular, whose distances depend on the query, it is built
+// for MetricEuclidean and the scaled distances are bounded by those. It is
+// stored
+// implicitly in a flat slice: the vantage point of the range nodes[lo:hi] is
+// nodes[lo], followed by the range of the points within its radius and the
+// range of the points outside it. The tree is never modified once built, so
+// it can be searched concurrently.
+type vpTree struct {
+	metric Metric
+	nodes  []vpNode
+}
+
+// vpNode is a node of a vpTree.
+type vpNode struct {
+	lat, lon float64 // In radians for MetricHaversine, degrees otherwise
+	cosLat   float64 // Cosine of the latitude
+	radius   float64 // Distance from the node splitting its range in two
+	index    int32   // Index of the location
+}
+
+// newVPNode returns the node of the location at lat and lon for metric.
+func newVPNode(metric Metric, lat, lon float64, index int32) vpNode {
+	cosLat := math.Cos(lat * math.Pi / 180)
+	if metric == MetricHaversine {
+		lat, lon = lat*math.Pi/180, lon*math.Pi/180
+	}
+	return vpNode{lat: lat, lon: lon, cosLat: cosLat, index: index}
+}
+
+// vpDist returns the distance between a and b under metric. For
+// MetricHaversine it is the chord through the unit sphere, computed with the
+// haversine formula: it grows monotonically with the great-circle distance
+// and, unlike the square the other indexes compare, satisfies the triangle
+// inequality the search relies on.
+func vpDist(metric Metric, a, b *vpNode) float64 {
+	if metric != MetricHaversine {
+		dx, dy := a.lat-b.lat, a.lon-b.lon
+		return math.Sqrt(dx*dx + dy*dy)
+	}
+	sinLat := math.Sin((b.lat - a.lat) / 2)
+	sinLon := math.Sin((b.lon - a.lon) / 2)
+	h := sinLat*sinLat + a.cosLat*b.cosLat*sinLon*sinLon
+	return 2 * math.Sqrt(min(h, 1))
+}
+
+// newVPTree builds a vantage-point tree over locs for metric.
+func newVPTree(locs []Location, metric Metric) *vpTree {
+	t := &vpTree{metric: metric, nodes: make([]vpNode, len(locs))}
+	for i, loc := range locs {
+		t.nodes[i] = newVPNode(metric, loc.Lat, loc.Lon, int32(i))
+	}
+	b := vpBuild{metric: metric, workers: make(chan struct{}, runtime.GOMAXPROCS(0)-1)}
+	b.build(t.nodes, make([]float64, len(locs)))
+	b.wg.Wait()
+	return t
+}
+
+// vpBuild is the state of a vantage-point tree construction.
+type vpBuild struct {
+	metric  Metric
+	workers chan struct{} // Holds a token per goroutine building a subtree
+	wg      sync.WaitGroup
+}
+
+// build arranges nodes as a vantage-point tree, using dists, which has the
+// same length, as scratch space. The middle node of each range becomes its
+// vantage point, and the other nodes are split at the median of their
+// distances from it. The subtrees of large ranges are built concurrently, on
+// at most GOMAXPROCS goroutines in total.
+func (b *vpBuild) build(nodes []vpNode, dists []float64) {
+	for len(nodes) > 1 {
+		nodes[0], nodes[len(nodes)/2] = nodes[len(nodes)/2], nodes[0]
+		vp := &nodes[0]
+		rest, restDists := nodes[1:], dists[1:]
+		for i := range rest {
+			restDists[i] = vpDist(b.metric, vp, &rest[i])
+		}
+		mid := len(rest) / 2
+		selectVP(rest, restDists, mid)
+		vp.radius = restDists[mid]
+
+		inner, innerDists := rest[:mid], restDists[:mid]
+		nodes, dists = rest[mid:], restDists[mid:]
+		if len(inner) < parallelBuildMin {
+			b.build(inner, innerDists)
+			continue
+		}
+		select {
+		case b.workers <- struct{}{}:
+			b.wg.Add(1)
+			go func() {
+				defer b.wg.Done()
+				b.build(inner, innerDists)
+				<-b.workers
+			}()
+		default:
+			b.build(inner, innerDists)
+		}
+	}
+}
+
+// selectVP reorders nodes and their distances in dists alike, so that
+// dists[k] holds the distance that would be there if they were sorted, with
+// no distance before it greater and no distance after it smaller.
+func selectVP(nodes []vpNode, dists []float64, k int) {
+	lo, hi := 0, len(nodes)-1
+	for lo < hi {
+		pivot := dists[lo+(hi-lo)/2]
+		i, j := lo, hi
+		for i <= j {
+			for dists[i] < pivot {
+				i++
+			}
+			for dists[j] > pivot {
+				j--
+			}
+			if i <= j {
+				nodes[i], nodes[j] = nodes[j], nodes[i]
+				dists[i], dists[j] = dists[j], dists[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// nearest implements spatialIndex. trace counts the nodes entered and the
+// running best matches. The returned distance is squared like that of the
+// other indexes; for MetricHaversine, the squared chord equals theirs.
+func (t *vpTree) nearest(coord [2]float64, trace *QueryTrace) (int, float64) {
+	s := vpSearch{tree: t, q: newVPNode(t.metric, coord[0], coord[1], -1), scale: 1, best: -1, dist: math.Inf(1), trace: trace}
+	if t.metric == MetricEquirectangular {
+		s.scale = s.q.cosLat
+	}
+	s.search(0, len(t.nodes))
+	return s.best, s.dist * s.dist
+}
+
+// vpSearch is the state of a nearest neighbor search in a vpTree.
+type vpSearch struct {
+	tree  *vpTree
+	q     vpNode
+	scale float64 // Cosine of the query's latitude under MetricEquirectangular, 1 otherwise
+	best  int     // Location index of the running best match
+	dist  float64 // Distance of the running best match
+	trace *QueryTrace
+}
+
+// search searches the subtree of the range nodes[lo:hi].
+func (s *vpSearch) search(lo, hi int) {
+	for lo < hi {
+		n := &s.tree.nodes[lo]
+		if s.trace != nil {
+			s.trace.NodesVisited++
+		}
+
+		d := vpDist(s.tree.metric, &s.q, n)
+		rank := d
+		if s.scale != 1 {
+			dx, dy := s.q.lat-n.lat, s.scale*(s.q.lon-n.lon)
+			rank = math.Sqrt(dx*dx + dy*dy)
+		}
+		if rank < s.dist || (rank == s.dist && int(n.index) < s.best) {
+			if s.trace != nil && rank < s.dist {
+				s.trace.Candidates++
+			}
+			s.best, s.dist = int(n.index), rank
+		}
+
+		// Search the side of the radius the query is on first, then the
+		// other side if the triangle inequality allows it to hold a match at
+		// least as close. Scaled distances are at least scale times the
+		// distances the tree was built for.
+		mid := lo + 1 + (hi-lo-1)/2
+		if d < n.radius {
+			s.search(lo+1, mid)
+			if s.scale*(n.radius-d) > s.dist {
+				return
+			}
+			lo = mid
+		} else {
+			s.search(mid, hi)
+			if s.scale*(d-n.radius) > s.dist {
+				return
+			}
+			lo, hi = lo+1, mid
+		}
+	}
+}
+
+// depth returns the number of nodes on the longest path from the root to a
+// leaf.
+func (t *vpTree) depth() int {
+	return bits.Len(uint(len(t.nodes)))
+}
+
+// bytes implements spatialIndex.
+func (t *vpTree) bytes() int64 {
+	return int64(cap(t.nodes)) * int64(unsafe.Sizeof(vpNode{}))
+}
@@ -0,0 +1,36 @@
+package geodecode_test
+
+import (
+	"math"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestVPTreeIndex(t *testing.T) {
+	for _, metric := range []geodecode.Metric{geodecode.MetricEuclidean, geodecode.MetricHaversine, geodecode.MetricEquirectangular} {
+		tree, _ := geodecode.New(geodecode.WithMetric(metric))
+		vp, err := geodecode.New(geodecode.WithMetric(metric), geodecode.WithIndex(geodecode.VPTreeIndex))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		for _, coord := range randomCoords(2000) {
+			want, wantTrace := tree.QueryDebug(coord)
+			got, gotTrace := vp.QueryDebug(coord)
+			// Distances are computed differently, so locations at the same
+			// distance may compare differently.
+			if math.Abs(gotTrace.Distance-wantTrace.Distance) > 1e-9*wantTrace.Distance {
+				t.Errorf("%s: VP-Tree found %s (%v) for %v, KD-Tree %s (%v)",
+					metric, got.City, gotTrace.Distance, coord, want.City, wantTrace.Distance)
+			}
+		}
+	}
+
+	vp, _ := geodecode.New(geodecode.WithIndex(geodecode.VPTreeIndex))
+	if got := vp.Query([2]float64{0, 0}); len(got) != 1 || got[0].City != "Takoradi" {
+		t.Errorf("Expected Takoradi for (0,0), got %+v", got)
+	}
+	if stats := vp.Stats(); stats.TreeDepth == 0 {
+		t.Errorf("Expected the depth of the VP-Tree, got 0")
+	}
+}
@@ -0,0 +1,108 @@
+package geodecode
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Watch polls the geocoder's dataset file every interval and reloads the
+// dataset when the file's size or modification time changes. The reload
+// happens once the file has stayed unchanged for one interval. onReload, if not
+// nil, is called after every reload attempt with its result. Watch returns a
+// function that stops watching; it is safe to call more than once.
+//
+// Only datasets loaded from a file or index can be watched; Watch returns an
+// error for other sources. If a different file is loaded later, the new file
+// is watched from then on.
+//
+// Example usage:
+//
+//	stop, err := geocoder.Watch(10*time.Second, func(err error) {
+//	    if err != nil {
+//	        log.Printf("gazetteer reload failed: %v", err)
+//	    }
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer stop()
+func (rg *RGeocoder) Watch(interval time.Duration, onReload func(error)) (stop func(), err error) {
+	if interval <= 0 {
+		return nil, errors.New("geodecode: watch interval must be positive")
+	}
+	path, ok := rg.watchedPath()
+	if !ok {
+		return nil, errors.New("geodecode: only datasets loaded from a file can be watched")
+	}
+	last, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		pending := false // The file changed and is waiting to settle
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			current, ok := rg.watchedPath()
+			if !ok {
+				continue
+			}
+			info, err := os.Stat(current)
+			if err != nil {
+				continue // The file may be in the middle of being replaced
+			}
+			if current != path {
+				// A different file was loaded explicitly; start watching it.
+				path, last, pending = current, info, false
+				continue
+			}
+			if info.Size() != last.Size() || !info.ModTime().Equal(last.ModTime()) {
+				// Wait until the file stops changing before reloading, so a
+				// file that is still being written is not read halfway.
+				last, pending = info, true
+				continue
+			}
+			if !pending {
+				continue
+			}
+			pending = false
+
+			if rg.verbose {
+				rg.log(slog.LevelInfo, "geodecode: data file changed, reloading", "path", path)
+			}
+			err = rg.Reload()
+			if err != nil {
+				rg.log(slog.LevelError, "geodecode: reloading data file failed", "path", path, "error", err)
+			}
+			if onReload != nil {
+				onReload(err)
+			}
+		}
+	}()
+
+	return func() { once.Do(func() { close(done) }) }, nil
+}
+
+// watchedPath returns the path of the file the geocoder's dataset is loaded
+// from, if it is loaded from a file.
+func (rg *RGeocoder) watchedPath() (string, bool) {
+	s := rg.shared()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.src.kind != sourceFile && s.src.kind != sourceIndex {
+		return "", false
+	}
+	return s.src.location, true
+}
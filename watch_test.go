@@ -0,0 +1,56 @@
+package geodecode_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	if err := os.WriteFile(path, []byte(reloadDataV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	geocoder, err := geodecode.New(geodecode.WithDataset(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	reloaded := make(chan error, 1)
+	stop, err := geocoder.Watch(10*time.Millisecond, func(err error) { reloaded <- err })
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer stop()
+
+	if err := os.WriteFile(path, []byte(reloadDataV2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("Reload after change: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the watcher to reload")
+	}
+	if got := geocoder.Query([2]float64{52.5, 13.4})[0].City; got != "Potsdam" {
+		t.Errorf("Expected Potsdam after the file changed, got %q", got)
+	}
+
+	stop()
+	stop() // Stopping twice is harmless
+}
+
+func TestWatchEmbedded(t *testing.T) {
+	geocoder, err := geodecode.New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := geocoder.Watch(time.Second, nil); err == nil {
+		t.Errorf("Expected an error watching the embedded dataset")
+	}
+}
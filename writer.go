@@ -0,0 +1,73 @@
+package geodecode
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes locations to w in the CSV format read by LoadFromFile. The
+// optional columns geonameid, timezone, elevation and population are only
+// written if at least one location has a value for them.
+func WriteCSV(w io.Writer, locations []Location) error {
+	var withID, withTimezone, withElevation, withPopulation bool
+	for _, loc := range locations {
+		withID = withID || loc.GeonameID != 0
+		withTimezone = withTimezone || loc.Timezone != ""
+		withElevation = withElevation || loc.Elevation != 0
+		withPopulation = withPopulation || loc.Population != 0
+	}
+
+	header := append([]string(nil), requiredCols...)
+	if withID {
+		header = append(header, "geonameid")
+	}
+	if withTimezone {
+		header = append(header, "timezone")
+	}
+	if withElevation {
+		header = append(header, "elevation")
+	}
+	if withPopulation {
+		header = append(header, "population")
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("geodecode: writing CSV header: %w", err)
+	}
+
+	record := make([]string, 0, len(header))
+	for _, loc := range locations {
+		record = append(record[:0],
+			strconv.FormatFloat(loc.Lat, 'f', -1, 64),
+			strconv.FormatFloat(loc.Lon, 'f', -1, 64),
+			loc.City,
+			loc.Admin1,
+			loc.Admin2,
+			loc.CC,
+		)
+		if withID {
+			record = append(record, strconv.Itoa(loc.GeonameID))
+		}
+		if withTimezone {
+			record = append(record, loc.Timezone)
+		}
+		if withElevation {
+			record = append(record, strconv.Itoa(loc.Elevation))
+		}
+		if withPopulation {
+			record = append(record, strconv.Itoa(loc.Population))
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("geodecode: writing CSV row: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("geodecode: writing CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package geodecode_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	geodecode "github.com/sdwillbrand/GeoDecode"
+)
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "cities.txt"))
+	if err != nil {
+		t.Fatalf("opening fixture: %v", err)
+	}
+	defer f.Close()
+
+	want, err := geodecode.ReadLocations(f, geodecode.WithFormat(geodecode.FormatGeoNames))
+	if err != nil {
+		t.Fatalf("ReadLocations: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := geodecode.WriteCSV(&buf, want); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	got, err := geodecode.ReadLocations(&buf)
+	if err != nil {
+		t.Fatalf("ReadLocations of written CSV: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d locations after round trip, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Location %d after round trip = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
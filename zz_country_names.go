@@ -0,0 +1,4264 @@
+// Code generated by countrynames-gen from the iso-codes translations; DO NOT EDIT.
+
+package geodecode
+
+// countryNames maps a locale and an ISO country code to the country's name.
+var countryNames = map[string]map[string]string{
+	"ar": {
+		"AD": "أندورا",
+		"AE": "الإمارات العربيّة المتحدّة",
+		"AF": "أفغانستان",
+		"AG": "أنتيغوا و باربودا",
+		"AI": "أنغويلا",
+		"AL": "ألبانيا",
+		"AM": "أرمينيا",
+		"AO": "أنغولا",
+		"AQ": "القطب الجنوبي",
+		"AR": "الأرجنتين",
+		"AS": "صاموا الأمريكيّة",
+		"AT": "النّمسا",
+		"AU": "أستراليا",
+		"AW": "أروبا",
+		"AX": "جزر آلاند",
+		"AZ": "أذربيجان",
+		"BA": "البوسنة و الهرسك",
+		"BB": "بربادوس",
+		"BD": "بنغلادش",
+		"BE": "بلجيكا",
+		"BF": "بوركينا فاصو",
+		"BG": "بلغاريا",
+		"BH": "البحرين",
+		"BI": "بوروندي",
+		"BJ": "بنين",
+		"BL": "سان بارتليمي",
+		"BM": "برمودا",
+		"BN": "بروناي دار السّلام",
+		"BO": "بوليفيا",
+		"BQ": "بونير وسانت يوستاتيوس وسابا",
+		"BR": "البرازيل",
+		"BS": "جزر البهاما",
+		"BT": "بوتان",
+		"BV": "جزيرة بوفي",
+		"BW": "بوتسوانا",
+		"BY": "روسيا البيضاء",
+		"BZ": "بيليز",
+		"CA": "كندا",
+		"CC": "جزر الكوكوس",
+		"CD": "الكونغو، جمهوريّة الكونغو الدّيموقراطيّة",
+		"CF": "جمهورية إفريقيّا الوسطى",
+		"CG": "الكونغو",
+		"CH": "سويسرا",
+		"CI": "ساحل العاج",
+		"CK": "جزر كوك",
+		"CL": "تشيلي",
+		"CM": "الكاميرون",
+		"CN": "الصّين",
+		"CO": "كولومبيا",
+		"CR": "كوستاريكا",
+		"CU": "كوبا",
+		"CV": "الرأس الأخضر",
+		"CW": "جزر كوراكاو",
+		"CX": "جزر الكريسماس",
+		"CY": "قبرص",
+		"CZ": "التشيك",
+		"DE": "ألمانيا",
+		"DJ": "جيبوتي",
+		"DK": "الدّنمارك",
+		"DM": "دومينيكا",
+		"DO": "جمهوريّة الدّومينيكان",
+		"DZ": "الجزائر",
+		"EC": "الإكوادور",
+		"EE": "إستونيا",
+		"EG": "مصر",
+		"EH": "الصّحراء الغربيّة",
+		"ER": "إريتريا",
+		"ES": "إسبانيا",
+		"ET": "إثيوبيا",
+		"FI": "فنلندا",
+		"FJ": "فيجي",
+		"FK": "جزر فولكلاند (مالفيناس)",
+		"FM": "ميكرونيزيا، ولايات ميكرونيزيا الموحّدة",
+		"FO": "جزر الفارو",
+		"FR": "فرنسا",
+		"GA": "الغابون",
+		"GB": "المملكة المتّحدة",
+		"GD": "غرينادا",
+		"GE": "جورجيا",
+		"GF": "غيانا الفرنسيّة",
+		"GG": "جزيرة جويرزني",
+		"GH": "غانا",
+		"GI": "جبل طارق",
+		"GL": "غرينلاند",
+		"GM": "غامبيا",
+		"GN": "غينيا",
+		"GP": "جوادالوبّي",
+		"GQ": "غينيا الاستوائيّة",
+		"GR": "اليونان",
+		"GS": "جورجيا الجنوبيّة و جزر ساندويتش الجنوبيّة",
+		"GT": "غواتيمالا",
+		"GU": "جوام",
+		"GW": "غينيا بيساو",
+		"GY": "غويانا",
+		"HK": "هونغ كونغ",
+		"HM": "جزيرة هيرد وجزر مَكْدونالد",
+		"HN": "هندوراس",
+		"HR": "كرواتيا",
+		"HT": "هايتي",
+		"HU": "المجر (هنغاريا)",
+		"ID": "إندونيسيا",
+		"IE": "أيرلندا",
+		"IL": "إسرائيل",
+		"IM": "آيزل أف مان",
+		"IN": "الهند",
+		"IO": "مقاطعة المحيط الهندي البريطانيّة",
+		"IQ": "العراق",
+		"IR": "إيران، الجمهوريّة الإسلاميّة الإيرانيّة",
+		"IS": "آيسلندا",
+		"IT": "إيطاليا",
+		"JE": "جيرسي",
+		"JM": "جامايكا",
+		"JO": "الأردن",
+		"JP": "اليابان",
+		"KE": "كينيا",
+		"KG": "قيرغزستان",
+		"KH": "كمبوديا",
+		"KI": "كيريباتي",
+		"KM": "جزر القمر",
+		"KN": "سانت كيتس و نيفس",
+		"KP": "كوريا، جمهورية كوريا الشّعبيّة الدّيموقراطيّة",
+		"KR": "كوريا، جمهوريّة كوريا",
+		"KW": "الكويت",
+		"KY": "جزر الكيمان",
+		"KZ": "كازاخستان",
+		"LA": "جمهوريّة لاو الدّيموقراطيّة الشّعبيّة",
+		"LB": "لبنان",
+		"LC": "سانت لوسيا",
+		"LI": "ليشتنشتاين",
+		"LK": "سريلانكا",
+		"LR": "ليبيريا",
+		"LS": "ليسوتو",
+		"LT": "لثوانيا",
+		"LU": "لوكسمبورغ",
+		"LV": "لاتفيا",
+		"LY": "ليبيا",
+		"MA": "المغرب",
+		"MC": "موناكو",
+		"MD": "المالديف",
+		"ME": "المنتنيغرو",
+		"MF": "سانت مارتين (القطاع الفرنسي)",
+		"MG": "مدغشقر",
+		"MH": "جزر المارشال",
+		"MK": "مقدونيا الشمالية",
+		"ML": "مالي",
+		"MM": "ميانمار",
+		"MN": "منغوليا",
+		"MO": "مكّاو",
+		"MP": "جزر ماريانا الشّماليّة",
+		"MQ": "مارتينيك",
+		"MR": "موريتانيا",
+		"MS": "مونتسيرات",
+		"MT": "مالطة",
+		"MU": "موريشيوس",
+		"MV": "جزر المالديف",
+		"MW": "ملاوي",
+		"MX": "المكسيك",
+		"MY": "ماليزيا",
+		"MZ": "موزمبيق",
+		"NA": "ناميبيا",
+		"NC": "نيو قلدونيا",
+		"NE": "النّيجر",
+		"NF": "جزيرة نورفولك",
+		"NG": "نيجيريا",
+		"NI": "نيكاراجوا",
+		"NL": "هولندا",
+		"NO": "النّرويج",
+		"NP": "نيبال",
+		"NR": "ناورو",
+		"NU": "نيوي",
+		"NZ": "نيوزيلاندا",
+		"OM": "عمان",
+		"PA": "بنما",
+		"PE": "البيرو",
+		"PF": "بولينيسيا الفرنسيّة",
+		"PG": "بابوا غينيا الجديدة",
+		"PH": "الفلبّين",
+		"PK": "باكستان",
+		"PL": "بولندا",
+		"PM": "سانت بيير و ميكيلون",
+		"PN": "بتكيرن",
+		"PR": "بورتوريكو",
+		"PS": "دولة فلسطين",
+		"PT": "البرتغال",
+		"PW": "بالاو",
+		"PY": "الباراغواي",
+		"QA": "قطر",
+		"RE": "ريونيون",
+		"RO": "رومانيا",
+		"RS": "صربية",
+		"RU": "الاتّحاد الرّوسي",
+		"RW": "رواندا",
+		"SA": "السّعوديّة",
+		"SB": "جزر سولومن",
+		"SC": "السّيشل",
+		"SD": "السّودان",
+		"SE": "السّويد",
+		"SG": "سنغافورة",
+		"SH": "ساينت هيلينا، تريستان دا كونا",
+		"SI": "سلوفينيا",
+		"SJ": "سفالبارد و جان ماين",
+		"SK": "سلوفاكيا",
+		"SL": "سيراليون",
+		"SM": "سان مارينو",
+		"SN": "السّنغال",
+		"SO": "الصّومال",
+		"SR": "سورينام",
+		"SS": "جنوب السّودان",
+		"ST": "ساو تومي و برنسبي",
+		"SV": "السّلفادور",
+		"SX": "سانت مارتن (الجزء الهولندي)",
+		"SY": "الجمهوريّة العربيّة السّوريّة",
+		"SZ": "إسواتيني",
+		"TC": "جزر التّرك و الكايكوس",
+		"TD": "تشاد",
+		"TF": "المقاطعات الفرنسيّة الجنوبيّة",
+		"TG": "توغو",
+		"TH": "تايلاند",
+		"TJ": "طاجيكستان",
+		"TK": "جزر توكيلو",
+		"TL": "تيمور-ليستي",
+		"TM": "تركمانستان",
+		"TN": "تونس",
+		"TO": "تونغا",
+		"TT": "ترينيداد و توباغو",
+		"TV": "توفالو",
+		"TW": "تايوان",
+		"TZ": "تنزانيا",
+		"UA": "أوكرانيا",
+		"UG": "أوغندا",
+		"UM": "جزر الولايات المتّحدة الصّغرى النّائية",
+		"US": "الولايات المتّحدة",
+		"UY": "الأوروغواي",
+		"UZ": "أوزبكستان",
+		"VA": "المقعد المقدّس (ولاية مدينة الفاتيكان)",
+		"VC": "سانت فنسنت و جزر الغرينادين",
+		"VE": "فنزويلّا",
+		"VG": "فيرجن، جزر فيرجن البريطانيّة",
+		"VI": "فيرجن، جزر فيرجن الأميركيّة",
+		"VN": "الفيتنام",
+		"VU": "فانواتو",
+		"WF": "واليس و فوتونا",
+		"WS": "صاموا",
+		"YE": "اليمن",
+		"YT": "مايوت",
+		"ZA": "جنوب إفريقيا",
+		"ZM": "زامبيا",
+		"ZW": "زمبابوي",
+	},
+	"de": {
+		"AD": "Andorra",
+		"AE": "Vereinigte Arabische Emirate",
+		"AF": "Afghanistan",
+		"AG": "Antigua und Barbuda",
+		"AI": "Anguilla",
+		"AL": "Albanien",
+		"AM": "Armenien",
+		"AO": "Angola",
+		"AQ": "Antarktis",
+		"AR": "Argentinien",
+		"AS": "Amerikanisch-Samoa",
+		"AT": "Österreich",
+		"AU": "Australien",
+		"AW": "Aruba",
+		"AX": "Åland-Inseln",
+		"AZ": "Aserbaidschan",
+		"BA": "Bosnien und Herzegowina",
+		"BB": "Barbados",
+		"BD": "Bangladesch",
+		"BE": "Belgien",
+		"BF": "Burkina Faso",
+		"BG": "Bulgarien",
+		"BH": "Bahrain",
+		"BI": "Burundi",
+		"BJ": "Benin",
+		"BL": "Saint-Barthélemy",
+		"BM": "Bermuda",
+		"BN": "Brunei Darussalam",
+		"BO": "Bolivien",
+		"BQ": "Bonaire, Sint Eustatius und Saba",
+		"BR": "Brasilien",
+		"BS": "Bahamas",
+		"BT": "Bhutan",
+		"BV": "Bouvet-Insel",
+		"BW": "Botsuana",
+		"BY": "Belarus",
+		"BZ": "Belize",
+		"CA": "Kanada",
+		"CC": "Kokos-(Keeling-)Inseln",
+		"CD": "Demokratische Republik Kongo",
+		"CF": "Zentralafrikanische Republik",
+		"CG": "Kongo",
+		"CH": "Schweiz",
+		"CI": "Côte d'Ivoire",
+		"CK": "Cookinseln",
+		"CL": "Chile",
+		"CM": "Kamerun",
+		"CN": "China",
+		"CO": "Kolumbien",
+		"CR": "Costa Rica",
+		"CU": "Kuba",
+		"CV": "Kap Verde",
+		"CW": "Curaçao",
+		"CX": "Weihnachtsinseln",
+		"CY": "Zypern",
+		"CZ": "Tschechien",
+		"DE": "Deutschland",
+		"DJ": "Dschibuti",
+		"DK": "Dänemark",
+		"DM": "Dominica",
+		"DO": "Dominikanische Republik",
+		"DZ": "Algerien",
+		"EC": "Ecuador",
+		"EE": "Estland",
+		"EG": "Ägypten",
+		"EH": "Westsahara",
+		"ER": "Eritrea",
+		"ES": "Spanien",
+		"ET": "Äthiopien",
+		"FI": "Finnland",
+		"FJ": "Fidschi",
+		"FK": "Falklandinseln (Malwinen)",
+		"FM": "Mikronesien, Föderierte Staaten von",
+		"FO": "Färöer-Inseln",
+		"FR": "Frankreich",
+		"GA": "Gabun",
+		"GB": "Vereinigtes Königreich",
+		"GD": "Grenada",
+		"GE": "Georgien",
+		"GF": "Französisch-Guyana",
+		"GG": "Guernsey",
+		"GH": "Ghana",
+		"GI": "Gibraltar",
+		"GL": "Grönland",
+		"GM": "Gambia",
+		"GN": "Guinea",
+		"GP": "Guadeloupe",
+		"GQ": "Äquatorialguinea",
+		"GR": "Griechenland",
+		"GS": "South Georgia und die Südlichen Sandwichinseln",
+		"GT": "Guatemala",
+		"GU": "Guam",
+		"GW": "Guinea-Bissau",
+		"GY": "Guyana",
+		"HK": "Hongkong",
+		"HM": "Heard und McDonaldinseln",
+		"HN": "Honduras",
+		"HR": "Kroatien",
+		"HT": "Haiti",
+		"HU": "Ungarn",
+		"ID": "Indonesien",
+		"IE": "Irland",
+		"IL": "Israel",
+		"IM": "Insel Man",
+		"IN": "Indien",
+		"IO": "Britisches Territorium im Indischen Ozean",
+		"IQ": "Irak",
+		"IR": "Iran",
+		"IS": "Island",
+		"IT": "Italien",
+		"JE": "Jersey",
+		"JM": "Jamaika",
+		"JO": "Jordanien",
+		"JP": "Japan",
+		"KE": "Kenia",
+		"KG": "Kirgisistan",
+		"KH": "Kambodscha",
+		"KI": "Kiribati",
+		"KM": "Komoren",
+		"KN": "St. Kitts und Nevis",
+		"KP": "Nordkorea",
+		"KR": "Südkorea",
+		"KW": "Kuwait",
+		"KY": "Cayman-Inseln",
+		"KZ": "Kasachstan",
+		"LA": "Laos",
+		"LB": "Libanon",
+		"LC": "St. Lucia",
+		"LI": "Liechtenstein",
+		"LK": "Sri Lanka",
+		"LR": "Liberia",
+		"LS": "Lesotho",
+		"LT": "Litauen",
+		"LU": "Luxemburg",
+		"LV": "Lettland",
+		"LY": "Libyen",
+		"MA": "Marokko",
+		"MC": "Monaco",
+		"MD": "Moldau",
+		"ME": "Montenegro",
+		"MF": "Saint Martin (Französischer Teil)",
+		"MG": "Madagaskar",
+		"MH": "Marshallinseln",
+		"MK": "Nordmazedonien",
+		"ML": "Mali",
+		"MM": "Myanmar",
+		"MN": "Mongolei",
+		"MO": "Macao",
+		"MP": "Nördliche Marianen",
+		"MQ": "Martinique",
+		"MR": "Mauretanien",
+		"MS": "Montserrat",
+		"MT": "Malta",
+		"MU": "Mauritius",
+		"MV": "Malediven",
+		"MW": "Malawi",
+		"MX": "Mexiko",
+		"MY": "Malaysia",
+		"MZ": "Mosambik",
+		"NA": "Namibia",
+		"NC": "Neukaledonien",
+		"NE": "Niger",
+		"NF": "Norfolkinsel",
+		"NG": "Nigeria",
+		"NI": "Nicaragua",
+		"NL": "Niederlande",
+		"NO": "Norwegen",
+		"NP": "Nepal",
+		"NR": "Nauru",
+		"NU": "Niue",
+		"NZ": "Neuseeland",
+		"OM": "Oman",
+		"PA": "Panama",
+		"PE": "Peru",
+		"PF": "Französisch-Polynesien",
+		"PG": "Papua-Neuguinea",
+		"PH": "Philippinen",
+		"PK": "Pakistan",
+		"PL": "Polen",
+		"PM": "St. Pierre und Miquelon",
+		"PN": "Pitcairn",
+		"PR": "Puerto Rico",
+		"PS": "Palästina, Staat",
+		"PT": "Portugal",
+		"PW": "Palau",
+		"PY": "Paraguay",
+		"QA": "Katar",
+		"RE": "Réunion",
+		"RO": "Rumänien",
+		"RS": "Serbien",
+		"RU": "Russische Föderation",
+		"RW": "Ruanda",
+		"SA": "Saudi-Arabien",
+		"SB": "Salomoninseln",
+		"SC": "Seychellen",
+		"SD": "Sudan",
+		"SE": "Schweden",
+		"SG": "Singapur",
+		"SH": "St. Helena, Ascension und Tristan da Cunha",
+		"SI": "Slowenien",
+		"SJ": "Svalbard und Jan Mayen",
+		"SK": "Slowakei",
+		"SL": "Sierra Leone",
+		"SM": "San Marino",
+		"SN": "Senegal",
+		"SO": "Somalia",
+		"SR": "Suriname",
+		"SS": "Südsudan",
+		"ST": "São Tomé und Príncipe",
+		"SV": "El Salvador",
+		"SX": "Saint-Martin (Niederländischer Teil)",
+		"SY": "Syrien",
+		"SZ": "Eswatini",
+		"TC": "Turks- und Caicosinseln",
+		"TD": "Tschad",
+		"TF": "Französische Süd- und Antarktisgebiete",
+		"TG": "Togo",
+		"TH": "Thailand",
+		"TJ": "Tadschikistan",
+		"TK": "Tokelau",
+		"TL": "Timor-Leste",
+		"TM": "Turkmenistan",
+		"TN": "Tunesien",
+		"TO": "Tonga",
+		"TR": "Türkei",
+		"TT": "Trinidad und Tobago",
+		"TV": "Tuvalu",
+		"TW": "Taiwan",
+		"TZ": "Tansania",
+		"UA": "Ukraine",
+		"UG": "Uganda",
+		"UM": "United States Minor Outlying Islands",
+		"US": "Vereinigte Staaten",
+		"UY": "Uruguay",
+		"UZ": "Usbekistan",
+		"VA": "Heiliger Stuhl (Staat Vatikanstadt)",
+		"VC": "St. Vincent und die Grenadinen",
+		"VE": "Venezuela",
+		"VG": "Britische Jungferninseln",
+		"VI": "Amerikanische Jungferninseln",
+		"VN": "Vietnam",
+		"VU": "Vanuatu",
+		"WF": "Wallis und Futuna",
+		"WS": "Samoa",
+		"YE": "Jemen",
+		"YT": "Mayotte",
+		"ZA": "Südafrika",
+		"ZM": "Sambia",
+		"ZW": "Simbabwe",
+	},
+	"es": {
+		"AD": "Andorra",
+		"AE": "Emiratos Árabes Unidos",
+		"AF": "Afganistán",
+		"AG": "Antigua y Barbuda",
+		"AI": "Anguila",
+		"AL": "Albania",
+		"AM": "Armenia",
+		"AO": "Angola",
+		"AQ": "Antártida",
+		"AR": "Argentina",
+		"AS": "Samoa Estadounidense",
+		"AT": "Austria",
+		"AU": "Australia",
+		"AW": "Aruba",
+		"AX": "Islas Äland",
+		"AZ": "Azerbaiyán",
+		"BA": "Bosnia y Herzegovina",
+		"BB": "Barbados",
+		"BD": "Bangladés",
+		"BE": "Bélgica",
+		"BF": "Burquina Faso",
+		"BG": "Bulgaria",
+		"BH": "Baréin",
+		"BI": "Burundi",
+		"BJ": "Benín",
+		"BL": "San Bartolomé",
+		"BM": "Islas Bermudas",
+		"BN": "Brunei Darussalam",
+		"BO": "Bolivia",
+		"BQ": "Islas BES (Caribe Neerlandés)",
+		"BR": "Brasil",
+		"BS": "Bahamas",
+		"BT": "Bután",
+		"BV": "Isla Bouvet",
+		"BW": "Botsuana",
+		"BY": "Bielorrusia",
+		"BZ": "Belice",
+		"CA": "Canadá",
+		"CC": "Islas Cocos (Keeling)",
+		"CD": "Congo, República Democrática del",
+		"CF": "República Centroafricana",
+		"CG": "Congo",
+		"CH": "Suiza",
+		"CI": "Costa de Marfíl",
+		"CK": "Islas Cook",
+		"CL": "Chile",
+		"CM": "Camerún",
+		"CN": "China",
+		"CO": "Colombia",
+		"CR": "Costa Rica",
+		"CU": "Cuba",
+		"CV": "Cabo Verde",
+		"CW": "Curazao",
+		"CX": "Isla de Navidad",
+		"CY": "Chipre",
+		"CZ": "Chequia",
+		"DE": "Alemania",
+		"DJ": "Yibuti",
+		"DK": "Dinamarca",
+		"DM": "Dominica",
+		"DO": "República Dominicana",
+		"DZ": "Algeria",
+		"EC": "Ecuador",
+		"EE": "Estonia",
+		"EG": "Egipto",
+		"EH": "Sahara Occidental",
+		"ER": "Eritrea",
+		"ES": "España",
+		"ET": "Etiopía",
+		"FI": "Finlandia",
+		"FJ": "Fiyi",
+		"FK": "Islas Falkland (Malvinas)",
+		"FM": "Micronesia, Estados Federados de",
+		"FO": "Islas Feroe",
+		"FR": "Francia",
+		"GA": "Gabón",
+		"GB": "Reino Unido",
+		"GD": "Granada",
+		"GE": "Georgia",
+		"GF": "Guayana Francesa",
+		"GG": "Guernsey",
+		"GH": "Ghana",
+		"GI": "Gibraltar",
+		"GL": "Groenlandia",
+		"GM": "Gambia",
+		"GN": "Guinea",
+		"GP": "Guadalupe",
+		"GQ": "Guinea Ecuatorial",
+		"GR": "Grecia",
+		"GS": "Islas Georgias del Sur y Sándwich del Sur",
+		"GT": "Guatemala",
+		"GU": "Guam",
+		"GW": "Guinea-Bisáu",
+		"GY": "Guyana",
+		"HK": "Hong Kong",
+		"HM": "Islas Heard y McDonald",
+		"HN": "Honduras",
+		"HR": "Croacia",
+		"HT": "Haití",
+		"HU": "Hungría",
+		"ID": "Indonesia",
+		"IE": "Irlanda",
+		"IL": "Israel",
+		"IM": "Isla de Man",
+		"IN": "India",
+		"IO": "Territorio Británico del Océano Índico",
+		"IQ": "Irak",
+		"IR": "Irán, República islámica de",
+		"IS": "Islandia",
+		"IT": "Italia",
+		"JE": "Jersey",
+		"JM": "Jamaica",
+		"JO": "Jordania",
+		"JP": "Japón",
+		"KE": "Kenia",
+		"KG": "Kirguistán",
+		"KH": "Camboya",
+		"KI": "Kiribati",
+		"KM": "Comores, Islas",
+		"KN": "San Cristóbal y Nieves",
+		"KP": "Corea, República Democrática Popular de",
+		"KR": "Corea, República de",
+		"KW": "Kuwait",
+		"KY": "Islas Caimán",
+		"KZ": "Kazajistán",
+		"LA": "República Democrática Popular de Lao",
+		"LB": "Líbano",
+		"LC": "Santa Lucía",
+		"LI": "Liechtenstein",
+		"LK": "Sri Lanka",
+		"LR": "Liberia",
+		"LS": "Lesoto",
+		"LT": "Lituania",
+		"LU": "Luxemburgo",
+		"LV": "Letonia",
+		"LY": "Libia",
+		"MA": "Marruecos",
+		"MC": "Mónaco",
+		"MD": "Moldavia",
+		"ME": "Montenegro",
+		"MF": "San Martín (zona francesa)",
+		"MG": "Madagascar",
+		"MH": "Islas Marshall",
+		"MK": "Macedonia del Norte",
+		"ML": "Malí",
+		"MM": "Birmania",
+		"MN": "Mongolia",
+		"MO": "Macao",
+		"MP": "Islas Marianas del Norte",
+		"MQ": "Martinica",
+		"MR": "Mauritania",
+		"MS": "Montserrat",
+		"MT": "Malta",
+		"MU": "Mauricio",
+		"MV": "Islas Maldivas",
+		"MW": "Malaui",
+		"MX": "México",
+		"MY": "Malasia",
+		"MZ": "Mozambique",
+		"NA": "Namibia",
+		"NC": "Nueva Caledonia",
+		"NE": "Niger",
+		"NF": "Isla Norfolk",
+		"NG": "Nigeria",
+		"NI": "Nicaragua",
+		"NL": "Países Bajos",
+		"NO": "Noruega",
+		"NP": "Nepal",
+		"NR": "Nauru",
+		"NU": "Niue",
+		"NZ": "Nueva Zelanda",
+		"OM": "Omán",
+		"PA": "Panamá",
+		"PE": "Perú",
+		"PF": "Polinesia Francesa",
+		"PG": "Papúa Nueva Guinea",
+		"PH": "Filipinas",
+		"PK": "Pakistán",
+		"PL": "Polonia",
+		"PM": "San Pedro y Miquelon",
+		"PN": "Pitcairn",
+		"PR": "Puerto Rico",
+		"PS": "Palestina, Estado de",
+		"PT": "Portugal",
+		"PW": "Palaos",
+		"PY": "Paraguay",
+		"QA": "Catar",
+		"RE": "Reunión",
+		"RO": "Rumanía",
+		"RS": "Serbia",
+		"RU": "Federación Rusa",
+		"RW": "Ruanda",
+		"SA": "Arabia Saudí",
+		"SB": "Islas Salomón",
+		"SC": "Seychelles",
+		"SD": "Sudán",
+		"SE": "Suecia",
+		"SG": "Singapur",
+		"SH": "Santa Elena, Ascensión y Tristán de Acuña",
+		"SI": "Eslovenia",
+		"SJ": "Svalbard y Jan Mayen",
+		"SK": "Eslovaquia",
+		"SL": "Sierra Leona",
+		"SM": "San Marino",
+		"SN": "Senegal",
+		"SO": "Somalia",
+		"SR": "Surinám",
+		"SS": "Sudán del Sur",
+		"ST": "Santo Tomé y Príncipe",
+		"SV": "El Salvador",
+		"SX": "Isla de San Martín (zona holandsea)",
+		"SY": "República árabe de Siria",
+		"SZ": "Esuatini",
+		"TC": "Islas Turcas y Caicos",
+		"TD": "Chad",
+		"TF": "Territorios Franceses del Sur",
+		"TG": "Togo",
+		"TH": "Tailandia",
+		"TJ": "Tayikistán",
+		"TK": "Tokelau",
+		"TL": "Timor Oriental",
+		"TM": "Turkmenistán",
+		"TN": "Tunez",
+		"TO": "Tonga",
+		"TT": "Trinidad y Tobago",
+		"TV": "Tuvalu",
+		"TW": "Taiwán",
+		"TZ": "Tanzania",
+		"UA": "Ucrania",
+		"UG": "Uganda",
+		"UM": "Islas Ultramarinas Menores de Estados Unidos",
+		"US": "Estados Unidos",
+		"UY": "Uruguay",
+		"UZ": "Uzbekistán",
+		"VA": "Santa Sede (Ciudad Estado del Vaticano)",
+		"VC": "San Vicente y las Granadinas",
+		"VE": "Venezuela",
+		"VG": "Islas Vírgenes, Británicas",
+		"VI": "Islas Vírgenes, de EEUU",
+		"VN": "Vietnam",
+		"VU": "Vanuatu",
+		"WF": "Wallis y Futuna",
+		"WS": "Samoa",
+		"YE": "Yemen",
+		"YT": "Mayotte",
+		"ZA": "Sudáfrica",
+		"ZM": "Zambia",
+		"ZW": "Zimbabue",
+	},
+	"fr": {
+		"AD": "Andorre",
+		"AE": "Émirats arabes unis",
+		"AF": "Afghanistan",
+		"AG": "Antigua-et-Barbuda",
+		"AI": "Anguilla",
+		"AL": "Albanie",
+		"AM": "Arménie",
+		"AO": "Angola",
+		"AQ": "Antarctique",
+		"AR": "Argentine",
+		"AS": "Samoa américaines",
+		"AT": "Autriche",
+		"AU": "Australie",
+		"AW": "Aruba",
+		"AX": "Åland, Îles",
+		"AZ": "Azerbaïdjan",
+		"BA": "Bosnie-Herzégovine",
+		"BB": "Barbade",
+		"BD": "Bangladesh",
+		"BE": "Belgique",
+		"BF": "Burkina Faso",
+		"BG": "Bulgarie",
+		"BH": "Bahreïn",
+		"BI": "Burundi",
+		"BJ": "Bénin",
+		"BL": "Saint-Barthélemy",
+		"BM": "Bermudes",
+		"BN": "Brunéi Darussalam",
+		"BO": "Bolivie",
+		"BQ": "Bonaire, Saint-Eustache et Saba",
+		"BR": "Brésil",
+		"BS": "Bahamas",
+		"BT": "Bhoutan",
+		"BV": "île Bouvet",
+		"BW": "Botswana",
+		"BY": "Bélarus",
+		"BZ": "Belize",
+		"CA": "Canada",
+		"CC": "Cocos (Keeling), Îles",
+		"CD": "République démocratique du Congo",
+		"CF": "République centrafricaine",
+		"CG": "République du Congo",
+		"CH": "Suisse",
+		"CI": "Côte d'Ivoire",
+		"CK": "îles Cook",
+		"CL": "Chili",
+		"CM": "Cameroun",
+		"CN": "Chine",
+		"CO": "Colombie",
+		"CR": "Costa Rica",
+		"CU": "Cuba",
+		"CV": "Cap-Vert",
+		"CW": "Curaçao",
+		"CX": "Christmas, Île",
+		"CY": "Chypre",
+		"CZ": "Tchéquie",
+		"DE": "Allemagne",
+		"DJ": "Djibouti",
+		"DK": "Danemark",
+		"DM": "Dominique",
+		"DO": "République dominicaine",
+		"DZ": "Algérie",
+		"EC": "Équateur",
+		"EE": "Estonie",
+		"EG": "Égypte",
+		"EH": "Sahara occidental",
+		"ER": "Érythrée",
+		"ES": "Espagne",
+		"ET": "Éthiopie",
+		"FI": "Finlande",
+		"FJ": "Fidji",
+		"FK": "Malouines, Îles (Falkland)",
+		"FM": "Micronésie, États fédérés de",
+		"FO": "îles Féroé",
+		"FR": "France",
+		"GA": "Gabon",
+		"GB": "Royaume-Uni",
+		"GD": "Grenade",
+		"GE": "Géorgie",
+		"GF": "Guyane française",
+		"GG": "Guernesey",
+		"GH": "Ghana",
+		"GI": "Gibraltar",
+		"GL": "Groënland",
+		"GM": "Gambie",
+		"GN": "Guinée",
+		"GP": "Guadeloupe",
+		"GQ": "Guinée Équatoriale",
+		"GR": "Grèce",
+		"GS": "Géorgie du Sud et les îles Sandwich du Sud",
+		"GT": "Guatemala",
+		"GU": "Guam",
+		"GW": "Guinée-Bissau",
+		"GY": "Guyana",
+		"HK": "Hong Kong",
+		"HM": "îles Heard-et-MacDonald",
+		"HN": "Honduras",
+		"HR": "Croatie",
+		"HT": "Haïti",
+		"HU": "Hongrie",
+		"ID": "Indonésie",
+		"IE": "Irlande",
+		"IL": "Israël",
+		"IM": "Île de Man",
+		"IN": "Inde",
+		"IO": "Territoire britannique de l'océan Indien",
+		"IQ": "Irak",
+		"IR": "Iran, République islamique d'",
+		"IS": "Islande",
+		"IT": "Italie",
+		"JE": "Jersey",
+		"JM": "Jamaïque",
+		"JO": "Jordanie",
+		"JP": "Japon",
+		"KE": "Kenya",
+		"KG": "Kirghizistan",
+		"KH": "Cambodge",
+		"KI": "Kiribati",
+		"KM": "Comores",
+		"KN": "Saint-Christophe-et-Niévès",
+		"KP": "Corée du Nord",
+		"KR": "Corée du Sud",
+		"KW": "Koweït",
+		"KY": "îles Caïmans",
+		"KZ": "Kazakhstan",
+		"LA": "Lao, République démocratique populaire",
+		"LB": "Liban",
+		"LC": "Sainte-Lucie",
+		"LI": "Liechtenstein",
+		"LK": "Sri Lanka",
+		"LR": "Libéria",
+		"LS": "Lesotho",
+		"LT": "Lituanie",
+		"LU": "Luxembourg",
+		"LV": "Lettonie",
+		"LY": "Libye",
+		"MA": "Maroc",
+		"MC": "Monaco",
+		"MD": "Moldavie",
+		"ME": "Monténégro",
+		"MF": "Saint-Martin (partie française)",
+		"MG": "Madagascar",
+		"MH": "Îles Marshall",
+		"MK": "Macédoine du Nord",
+		"ML": "Mali",
+		"MM": "Birmanie",
+		"MN": "Mongolie",
+		"MO": "Macau",
+		"MP": "Îles Mariannes du Nord",
+		"MQ": "Martinique",
+		"MR": "Mauritanie",
+		"MS": "Montserrat",
+		"MT": "Malte",
+		"MU": "Maurice",
+		"MV": "Maldives",
+		"MW": "Malawi",
+		"MX": "Mexique",
+		"MY": "Malaisie",
+		"MZ": "Mozambique",
+		"NA": "Namibie",
+		"NC": "Nouvelle-Calédonie",
+		"NE": "Niger",
+		"NF": "île Norfolk",
+		"NG": "Nigeria",
+		"NI": "Nicaragua",
+		"NL": "Pays-Bas",
+		"NO": "Norvège",
+		"NP": "Népal",
+		"NR": "Nauru",
+		"NU": "Nioue",
+		"NZ": "Nouvelle-Zélande",
+		"OM": "Oman",
+		"PA": "Panama",
+		"PE": "Pérou",
+		"PF": "Polynésie française",
+		"PG": "Papouasie-Nouvelle-Guinée",
+		"PH": "Philippines",
+		"PK": "Pakistan",
+		"PL": "Pologne",
+		"PM": "Saint-Pierre-et-Miquelon",
+		"PN": "Îles Pitcairn",
+		"PR": "Porto Rico",
+		"PS": "Palestine, État de",
+		"PT": "Portugal",
+		"PW": "Palaos",
+		"PY": "Paraguay",
+		"QA": "Qatar",
+		"RE": "Réunion, Île de la",
+		"RO": "Roumanie",
+		"RS": "Serbie",
+		"RU": "Russie, Fédération de",
+		"RW": "Rwanda",
+		"SA": "Arabie saoudite",
+		"SB": "Salomon, Îles",
+		"SC": "Seychelles",
+		"SD": "Soudan",
+		"SE": "Suède",
+		"SG": "Singapour",
+		"SH": "Sainte-Hélène, Ascension et Tristan da Cunha",
+		"SI": "Slovénie",
+		"SJ": "Svalbard et île Jan Mayen",
+		"SK": "Slovaquie",
+		"SL": "Sierra Leone",
+		"SM": "Saint-Marin",
+		"SN": "Sénégal",
+		"SO": "Somalie",
+		"SR": "Surinam",
+		"SS": "Soudan du Sud",
+		"ST": "Sao Tomé-et-Principe",
+		"SV": "Salvador",
+		"SX": "Saint-Martin (partie néerlandaise)",
+		"SY": "Syrienne, République arabe",
+		"SZ": "Eswatini",
+		"TC": "îles Turques-et-Caïques",
+		"TD": "Tchad",
+		"TF": "Terres australes françaises",
+		"TG": "Togo",
+		"TH": "Thaïlande",
+		"TJ": "Tadjikistan",
+		"TK": "Tokelau",
+		"TL": "Timor oriental",
+		"TM": "Turkménistan",
+		"TN": "Tunisie",
+		"TO": "Tonga",
+		"TT": "Trinité-et-Tobago",
+		"TV": "Tuvalu",
+		"TW": "Taïwan",
+		"TZ": "Tanzanie",
+		"UA": "Ukraine",
+		"UG": "Ouganda",
+		"UM": "Îles mineures éloignées des États-Unis",
+		"US": "États-Unis",
+		"UY": "Uruguay",
+		"UZ": "Ouzbékistan",
+		"VA": "Saint-Siège (état de la cité du Vatican)",
+		"VC": "Saint-Vincent-et-les-Grenadines",
+		"VE": "Vénézuela",
+		"VG": "Îles Vierges britanniques",
+		"VI": "Îles Vierges, États-Unis",
+		"VN": "Viêt Nam",
+		"VU": "Vanuatu",
+		"WF": "Wallis et Futuna",
+		"WS": "Samoa",
+		"YE": "Yémen",
+		"YT": "Mayotte",
+		"ZA": "Afrique du Sud",
+		"ZM": "Zambie",
+		"ZW": "Zimbabwe",
+	},
+	"hi": {
+		"AD": "अण्डोरा",
+		"AE": "संयुक्त अरब अमीरात",
+		"AF": "अफ़्गानिस्तान",
+		"AG": "अण्टीगुआ और बारबूडा",
+		"AI": "अंगुइला",
+		"AL": "अल्बानिया",
+		"AM": "आर्मीनिया",
+		"AO": "अंगोला",
+		"AQ": "अंटार्कटिका",
+		"AR": "अर्जेण्टीना",
+		"AS": "अमेरिकी समोआ",
+		"AT": "ऑस्ट्रिया",
+		"AU": "ऑस्ट्रेलिया",
+		"AW": "अरूबा",
+		"AX": "ऑलैण्ड द्वीपसमूह",
+		"AZ": "अज़रबैजान",
+		"BA": "बॉस्निया और हर्ज़ेगोविना",
+		"BB": "बारबाडोस",
+		"BD": "बांग्लादेश",
+		"BE": "बेल्जियम",
+		"BF": "बुर्किना फासो",
+		"BG": "बुल्गारिया",
+		"BH": "बहरीन",
+		"BI": "बुरुण्डी",
+		"BJ": "बेनिन",
+		"BL": "सेंट बार्थेलेमी",
+		"BM": "बरमूडा",
+		"BN": "ब्रुनेई दरउस्सलाम",
+		"BO": "बोलिविया",
+		"BQ": "बोनैर, सिंट यूस्टेटीयस एंड साबा",
+		"BR": "ब्राज़ील",
+		"BS": "बहामास",
+		"BT": "भूटान",
+		"BV": "बोउवेट आइलैंड",
+		"BW": "बोत्सवाना",
+		"BY": "बेलारूस",
+		"BZ": "बेलीज़",
+		"CA": "कनाडा",
+		"CC": "कोकोस (कीलिंग) द्वीपसमूह",
+		"CD": "कांगो, द डेमोक्रेटिक रिपब्लिक ऑफ द",
+		"CF": "मध्य अफ़्रीकी गणराज्य",
+		"CG": "कॉंगो",
+		"CH": "स्विट्ज़रलैण्ड",
+		"CI": "कोयटे डी वोयरे",
+		"CK": "कुक द्वीपसमूह",
+		"CL": "चिली",
+		"CM": "कैमरुन",
+		"CN": "चीन",
+		"CO": "कोलम्बिया",
+		"CR": "कोस्टा रीका",
+		"CU": "क्यूबा",
+		"CV": "काबो वर्डे",
+		"CW": "कुराकाओ",
+		"CX": "क्रिसमस आइलैन्ड",
+		"CY": "साइप्रस",
+		"CZ": "चेकिया",
+		"DE": "जर्मनी",
+		"DJ": "जिबूती",
+		"DK": "डेनमार्क",
+		"DM": "डोमिनिका",
+		"DO": "डोमिनिकन गणराज्य",
+		"DZ": "अल्जीरिया",
+		"EC": "ईक्वाडोर",
+		"EE": "एस्टोनिया",
+		"EG": "मिस्र",
+		"EH": "पश्चिमी सहारा",
+		"ER": "इरित्रिया",
+		"ES": "स्पेन",
+		"ET": "इथियोपिया",
+		"FI": "फ़िनलैण्ड",
+		"FJ": "फ़िजी",
+		"FK": "फॉकलैंड आइलैंड्स (मालविनास)",
+		"FM": "माइक्रोनीसिया, फेडेरेटड स्टेट्स ऑफ",
+		"FO": "फ़रो द्वीपसमूह",
+		"FR": "फ़्रान्स",
+		"GA": "गबॉन",
+		"GB": "यूनाइटेड किंगडम",
+		"GD": "ग्रेनाडा",
+		"GE": "जॉर्जिया",
+		"GF": "फ़्रान्सीसी गुयाना",
+		"GG": "ग्वेर्नसे",
+		"GH": "घाना",
+		"GI": "जिब्राल्टर",
+		"GL": "ग्रीनलैण्ड",
+		"GM": "गाम्बिया",
+		"GN": "गिनी",
+		"GP": "गुआदेलूप",
+		"GQ": "भूमध्यरेखीय गिनी",
+		"GR": "यूनान",
+		"GS": "दक्षिण जॉर्जिया एवं दक्षिण सैंडविच द्वीप समूह",
+		"GT": "ग्वाटेमाला",
+		"GU": "गुआम",
+		"GW": "गिनी-बिसाऊ",
+		"GY": "गयाना",
+		"HK": "हांगकांग",
+		"HM": "हर्ड द्वीप और मैकडोनाल्ड द्वीप",
+		"HN": "हौण्डुरस",
+		"HR": "क्रोएशिया",
+		"HT": "हैती",
+		"HU": "हंगरी",
+		"ID": "इंडोनेशिया",
+		"IE": "आयरलैण्ड",
+		"IL": "इज़राइल",
+		"IM": "मनुष्य का टापू",
+		"IN": "भारत",
+		"IO": "ब्रिटिश हिंद महासागर क्षेत्र",
+		"IQ": "इराक़",
+		"IR": "ईरान, इस्लामिक रिपब्लिक ऑफ",
+		"IS": "आइसलैण्ड",
+		"IT": "इटली",
+		"JE": "जर्सी",
+		"JM": "जमैका",
+		"JO": "जॉर्डन",
+		"JP": "जापान",
+		"KE": "कीनिया",
+		"KG": "किर्गिज़स्तान",
+		"KH": "कम्बोडिया",
+		"KI": "किरिबाती",
+		"KM": "कोमोरोस",
+		"KN": "सन्त किट्स और नेविस",
+		"KP": "उत्तर कोरिया",
+		"KR": "दक्षिण कोरिया",
+		"KW": "कुवैत",
+		"KY": "केमन द्वीपसमूह",
+		"KZ": "कज़ाख़िस्तान",
+		"LA": "लाओ पीपल्स डेमोक्रेटिक रिपब्लिक",
+		"LB": "लेबनान",
+		"LC": "सेंट लूसिया",
+		"LI": "लिक्टेन्स्टाइन",
+		"LK": "श्रीलंका",
+		"LR": "लाइबेरिया",
+		"LS": "लेसोथो",
+		"LT": "लिथुआनिया",
+		"LU": "लक्ज़मबर्ग",
+		"LV": "लातविया",
+		"LY": "लीबिया",
+		"MA": "मोरक्को",
+		"MC": "मोनैको",
+		"MD": "मॉल्डोवा",
+		"ME": "मॉन्टेनीग्रो",
+		"MF": "सेंट मार्टिन (फ्रेंच भाग)",
+		"MG": "मेडागास्कर",
+		"MH": "मार्शल आइलैंड्स",
+		"MK": "उत्तर मैसेडोनिया",
+		"ML": "माली",
+		"MM": "म्यान्मार",
+		"MN": "मंगोलिया",
+		"MO": "मकाउ",
+		"MP": "उत्तरी मारियाना द्वीप",
+		"MQ": "मार्टीनिक",
+		"MR": "मॉरीतानिया",
+		"MS": "मॉण्टसेराट",
+		"MT": "माल्टा",
+		"MU": "मॉरिशस",
+		"MV": "मालदीव",
+		"MW": "मलावी",
+		"MX": "मेक्सिको",
+		"MY": "मलेशिया",
+		"MZ": "मोज़ाम्बीक",
+		"NA": "नामीबिया",
+		"NC": "नया कैलेडोनिया",
+		"NE": "नाइजर",
+		"NF": "नॉर्फ़ोक द्वीप",
+		"NG": "नाईजीरिया",
+		"NI": "निकारागुआ",
+		"NL": "नीदरलैण्ड",
+		"NO": "नॉर्वे",
+		"NP": "नेपाल",
+		"NR": "नौरु",
+		"NU": "निउए",
+		"NZ": "न्यूज़ीलैण्ड",
+		"OM": "ओमान",
+		"PA": "पनामा",
+		"PE": "पेरू",
+		"PF": "फ़्रान्सी पॉलिनेशिया",
+		"PG": "पापुआ न्यू गिनी",
+		"PH": "फ़िलीपीन्स",
+		"PK": "पाकिस्तान",
+		"PL": "पोलैंड",
+		"PM": "साँ-प्येर और मीकेलों",
+		"PN": "पिटकायर्न",
+		"PR": "प्युर्तो रिको",
+		"PS": "पैलेस्टाइन, स्टेट ऑफ़",
+		"PT": "पुर्तगाल",
+		"PW": "पलाउ",
+		"PY": "पैराग्वे",
+		"QA": "क़तर",
+		"RE": "रेयूनियों",
+		"RO": "रोमानिया",
+		"RS": "सर्बिया",
+		"RU": "रशियन फेडेरशन",
+		"RW": "रवाण्डा",
+		"SA": "सउदी अरब",
+		"SB": "सोलोमन द्वीपसमूह",
+		"SC": "सेशेल्स",
+		"SD": "सूडान",
+		"SE": "स्वीडन",
+		"SG": "सिंगापुर",
+		"SH": "सेंट हेलेना, असेंशन और त्रिस्तान दा कुन्हा",
+		"SI": "स्लोवेनिया",
+		"SJ": "स्वालबार्ड एन्ड जैन माएन",
+		"SK": "स्लोवाकिया",
+		"SL": "सिएरा लियोन",
+		"SM": "सान मारिनो",
+		"SN": "सेनेगल",
+		"SO": "सोमालिया",
+		"SR": "सूरीनाम",
+		"SS": "दक्षिण सूडान",
+		"ST": "साओ तोमे और प्रिन्सिपी",
+		"SV": "अल साल्वाडोर",
+		"SX": "सेंट मार्टिन (डच भाग)",
+		"SY": "सीरियन अरब रिपब्लिक",
+		"SZ": "एस्वाटिनी",
+		"TC": "तुर्क और केकोस द्वीपसमूह",
+		"TD": "चाड",
+		"TF": "फ्रेंच साउदर्न टेरीटरीज़",
+		"TG": "टोगो",
+		"TH": "थाईलैण्ड",
+		"TJ": "ताजिकिस्तान",
+		"TK": "टोकेलाऊ",
+		"TL": "तिमोर-लेस्टे",
+		"TM": "तुर्कमेनिस्तान",
+		"TN": "ट्यूनिशिया",
+		"TO": "टोंगा",
+		"TT": "त्रिनिदाद और टोबैगो",
+		"TV": "तुवालू",
+		"TW": "ताइवान",
+		"TZ": "तंज़ानिया",
+		"UA": "युक्रेन",
+		"UG": "युगाण्डा",
+		"UM": "संयुक्त राज्य अमेरिका के छोटे दूरस्थ द्वीपसमूह",
+		"US": "संयुक्त राज्य",
+		"UY": "उरुग्वे",
+		"UZ": "उज़्बेकिस्तान",
+		"VA": "होली सी (वैटिकन सिटी स्टेट)",
+		"VC": "सन्त विन्सेण्ट और ग्रेनाडाइन्स",
+		"VE": "वेनेज़ुएला",
+		"VG": "वर्जिन आइलैंड्स, ब्रिटिश",
+		"VI": "वर्जिन आइलैंड्स, यू.एस.",
+		"VN": "वियतनाम",
+		"VU": "वानूआटू",
+		"WF": "वालिस और फ्यूटुना",
+		"WS": "समोआ",
+		"YE": "यमन",
+		"YT": "मेयोट",
+		"ZA": "दक्षिण अफ़्रीका",
+		"ZM": "ज़ाम्बिया",
+		"ZW": "ज़िम्बाब्वे",
+	},
+	"it": {
+		"AD": "Andorra",
+		"AE": "Emirati Arabi Uniti",
+		"AF": "Afghanistan",
+		"AG": "Antigua e Barbuda",
+		"AI": "Anguilla",
+		"AL": "Albania",
+		"AM": "Armenia",
+		"AO": "Angola",
+		"AQ": "Antartide",
+		"AR": "Argentina",
+		"AS": "Samoa americane",
+		"AT": "Austria",
+		"AU": "Australia",
+		"AW": "Aruba",
+		"AX": "Isole Åland",
+		"AZ": "Azerbaigian",
+		"BA": "Bosnia-Erzegovina",
+		"BB": "Barbados",
+		"BD": "Bangladesh",
+		"BE": "Belgio",
+		"BF": "Burkina Faso",
+		"BG": "Bulgaria",
+		"BH": "Bahrein",
+		"BI": "Burundi",
+		"BJ": "Benin",
+		"BL": "Saint-Barthélemy",
+		"BM": "Bermuda",
+		"BN": "Brunei",
+		"BO": "Bolivia",
+		"BQ": "Paesi Bassi caraibici",
+		"BR": "Brasile",
+		"BS": "Bahamas",
+		"BT": "Bhutan",
+		"BV": "Isola Bouvet",
+		"BW": "Botswana",
+		"BY": "Bielorussia",
+		"BZ": "Belize",
+		"CA": "Canada",
+		"CC": "Isole Cocos (Keeling)",
+		"CD": "Repubblica democratica del Congo",
+		"CF": "Repubblica Centrafricana",
+		"CG": "Congo",
+		"CH": "Svizzera",
+		"CI": "Costa d'Avorio",
+		"CK": "Isole Cook",
+		"CL": "Cile",
+		"CM": "Camerun",
+		"CN": "Cina",
+		"CO": "Colombia",
+		"CR": "Costa Rica",
+		"CU": "Cuba",
+		"CV": "Capo Verde",
+		"CW": "Curaçao",
+		"CX": "Isola di Natale",
+		"CY": "Cipro",
+		"CZ": "Cechia",
+		"DE": "Germania",
+		"DJ": "Gibuti",
+		"DK": "Danimarca",
+		"DM": "Dominica",
+		"DO": "Repubblica Dominicana",
+		"DZ": "Algeria",
+		"EC": "Ecuador",
+		"EE": "Estonia",
+		"EG": "Egitto",
+		"EH": "Sahara occidentale",
+		"ER": "Eritrea",
+		"ES": "Spagna",
+		"ET": "Etiopia",
+		"FI": "Finlandia",
+		"FJ": "Figi",
+		"FK": "Isole Falkland (Malvine)",
+		"FM": "Micronesia",
+		"FO": "Isole Fær Øer",
+		"FR": "Francia",
+		"GA": "Gabon",
+		"GB": "Regno Unito",
+		"GD": "Grenada",
+		"GE": "Georgia",
+		"GF": "Guyana francese",
+		"GG": "Guernsey",
+		"GH": "Ghana",
+		"GI": "Gibilterra",
+		"GL": "Groenlandia",
+		"GM": "Gambia",
+		"GN": "Guinea",
+		"GP": "Guadalupa",
+		"GQ": "Guinea equatoriale",
+		"GR": "Grecia",
+		"GS": "Georgia del Sud e Isole Sandwich Australi",
+		"GT": "Guatemala",
+		"GU": "Guam",
+		"GW": "Guinea-Bissau",
+		"GY": "Guyana",
+		"HK": "Hong Kong",
+		"HM": "Isole Heard e McDonald",
+		"HN": "Honduras",
+		"HR": "Croazia",
+		"HT": "Haiti",
+		"HU": "Ungheria",
+		"ID": "Indonesia",
+		"IE": "Irlanda",
+		"IL": "Israele",
+		"IM": "Isola di Man",
+		"IN": "India",
+		"IO": "Territorio britannico dell'Oceano Indiano",
+		"IQ": "Iraq",
+		"IR": "Iran",
+		"IS": "Islanda",
+		"IT": "Italia",
+		"JE": "Jersey",
+		"JM": "Giamaica",
+		"JO": "Giordania",
+		"JP": "Giappone",
+		"KE": "Kenya",
+		"KG": "Kirghizistan",
+		"KH": "Cambogia",
+		"KI": "Kiribati",
+		"KM": "Comore",
+		"KN": "Saint Kitts e Nevis",
+		"KP": "Corea del Nord",
+		"KR": "Corea del Sud",
+		"KW": "Kuwait",
+		"KY": "Isole Cayman",
+		"KZ": "Kazakistan",
+		"LA": "Laos",
+		"LB": "Libano",
+		"LC": "Saint Lucia",
+		"LI": "Liechtenstein",
+		"LK": "Sri Lanka",
+		"LR": "Liberia",
+		"LS": "Lesotho",
+		"LT": "Lituania",
+		"LU": "Lussemburgo",
+		"LV": "Lettonia",
+		"LY": "Libia",
+		"MA": "Marocco",
+		"MC": "Monaco",
+		"MD": "Moldavia",
+		"ME": "Montenegro",
+		"MF": "Saint-Martin (Francia)",
+		"MG": "Madagascar",
+		"MH": "Isole Marshall",
+		"MK": "Macedonia del Nord",
+		"ML": "Mali",
+		"MM": "Birmania",
+		"MN": "Mongolia",
+		"MO": "Macao",
+		"MP": "Isole Marianne Settentrionali",
+		"MQ": "Martinica",
+		"MR": "Mauritania",
+		"MS": "Montserrat",
+		"MT": "Malta",
+		"MU": "Maurizio",
+		"MV": "Maldive",
+		"MW": "Malawi",
+		"MX": "Messico",
+		"MY": "Malaysia",
+		"MZ": "Mozambico",
+		"NA": "Namibia",
+		"NC": "Nuova Caledonia",
+		"NE": "Niger",
+		"NF": "Isola Norfolk",
+		"NG": "Nigeria",
+		"NI": "Nicaragua",
+		"NL": "Paesi Bassi",
+		"NO": "Norvegia",
+		"NP": "Nepal",
+		"NR": "Nauru",
+		"NU": "Niue",
+		"NZ": "Nuova Zelanda",
+		"OM": "Oman",
+		"PA": "Panama",
+		"PE": "Perù",
+		"PF": "Polinesia francese",
+		"PG": "Papua Nuova Guinea",
+		"PH": "Filippine",
+		"PK": "Pakistan",
+		"PL": "Polonia",
+		"PM": "Saint-Pierre e Miquelon",
+		"PN": "Pitcairn",
+		"PR": "Portorico",
+		"PS": "Palestina, Stato di",
+		"PT": "Portogallo",
+		"PW": "Palau",
+		"PY": "Paraguay",
+		"QA": "Qatar",
+		"RE": "Riunione",
+		"RO": "Romania",
+		"RS": "Serbia",
+		"RU": "Russia",
+		"RW": "Ruanda",
+		"SA": "Arabia Saudita",
+		"SB": "Isole Salomone",
+		"SC": "Seychelles",
+		"SD": "Sudan",
+		"SE": "Svezia",
+		"SG": "Singapore",
+		"SH": "Sant'Elena, Ascensione e Tristan da Cunha",
+		"SI": "Slovenia",
+		"SJ": "Svalbard e Jan Mayen",
+		"SK": "Slovacchia",
+		"SL": "Sierra Leone",
+		"SM": "San Marino",
+		"SN": "Senegal",
+		"SO": "Somalia",
+		"SR": "Suriname",
+		"SS": "Sudan del sud",
+		"ST": "São Tomé e Príncipe",
+		"SV": "El Salvador",
+		"SX": "Sint Maarten (Olanda)",
+		"SY": "Siria",
+		"SZ": "Eswatini",
+		"TC": "Isole Turks e Caicos",
+		"TD": "Ciad",
+		"TF": "Territori francesi meridionali",
+		"TG": "Togo",
+		"TH": "Thailandia",
+		"TJ": "Tagikistan",
+		"TK": "Tokelau",
+		"TL": "Timor Est",
+		"TM": "Turkmenistan",
+		"TN": "Tunisia",
+		"TO": "Tonga",
+		"TT": "Trinidad e Tobago",
+		"TV": "Tuvalu",
+		"TW": "Taiwan",
+		"TZ": "Tanzania",
+		"UA": "Ucraina",
+		"UG": "Uganda",
+		"UM": "Isole minori esterne degli Stati Uniti d'America",
+		"US": "Stati Uniti",
+		"UY": "Uruguay",
+		"UZ": "Uzbekistan",
+		"VA": "Santa Sede (Stato della Città del Vaticano)",
+		"VC": "Saint Vincent e Grenadine",
+		"VE": "Venezuela",
+		"VG": "Isole Vergini, Regno Unito",
+		"VI": "Isole Vergini, U.S.A.",
+		"VN": "Vietnam",
+		"VU": "Vanuatu",
+		"WF": "Wallis e Futuna",
+		"WS": "Samoa",
+		"YE": "Yemen",
+		"YT": "Mayotte",
+		"ZA": "Sudafrica",
+		"ZM": "Zambia",
+		"ZW": "Zimbabwe",
+	},
+	"ja": {
+		"AD": "アンドラ",
+		"AE": "アラブ首長国連邦",
+		"AF": "アフガニスタン",
+		"AG": "アンティグア・バーブーダ",
+		"AI": "アングイラ",
+		"AL": "アルバニア",
+		"AM": "アルメニア",
+		"AO": "アンゴラ",
+		"AQ": "南極大陸",
+		"AR": "アルゼンチン",
+		"AS": "米領サモア",
+		"AT": "オーストリア",
+		"AU": "オーストラリア連邦",
+		"AW": "アルーバ",
+		"AX": "オーランド諸島",
+		"AZ": "アゼルバイジャン",
+		"BA": "ボスニア・ヘルツェゴビナ",
+		"BB": "バルバドス",
+		"BD": "バングラデシュ",
+		"BE": "ベルギー",
+		"BF": "ブルキナファソ",
+		"BG": "ブルガリア",
+		"BH": "バーレーン",
+		"BI": "ブルンジ",
+		"BJ": "ベナン",
+		"BL": "サンバルテルミ",
+		"BM": "バーミューダ",
+		"BN": "ブルネイ・ダルサラーム国",
+		"BO": "ボリビア",
+		"BQ": "ボネール、シントユースタティウス及びサバ",
+		"BR": "ブラジル",
+		"BS": "バハマ",
+		"BT": "ブータン",
+		"BV": "ブーベ島",
+		"BW": "ボツワナ",
+		"BY": "ベラルーシ",
+		"BZ": "ベリーズ",
+		"CA": "カナダ",
+		"CC": "ココス (キーリング) 諸島",
+		"CD": "コンゴ民主共和国",
+		"CF": "中央アフリカ共和国",
+		"CG": "コンゴ",
+		"CH": "スイス",
+		"CI": "コートジボワール",
+		"CK": "クック諸島",
+		"CL": "チリ",
+		"CM": "カメルーン",
+		"CN": "中国",
+		"CO": "コロンビア",
+		"CR": "コスタリカ",
+		"CU": "キューバ",
+		"CV": "カーボヴェルデ",
+		"CW": "キュラソー",
+		"CX": "クリスマス島",
+		"CY": "キプロス",
+		"DE": "ドイツ",
+		"DJ": "ジブチ",
+		"DK": "デンマーク",
+		"DM": "ドミニカ",
+		"DO": "ドミニカ共和国",
+		"DZ": "アルジェリア",
+		"EC": "エクアドル",
+		"EE": "エストニア",
+		"EG": "エジプト",
+		"EH": "西サハラ",
+		"ER": "エリトリア国",
+		"ES": "スペイン",
+		"ET": "エチオピア",
+		"FI": "フィンランド",
+		"FJ": "フィジー",
+		"FK": "フォークランド諸島 (マルビナス)",
+		"FM": "ミクロネシア連邦",
+		"FO": "フェロー諸島",
+		"FR": "フランス",
+		"GA": "ガボン",
+		"GB": "英国",
+		"GD": "グレナダ",
+		"GE": "グルジア",
+		"GF": "仏領ギアナ",
+		"GG": "ガーンジー",
+		"GH": "ガーナ",
+		"GI": "ジブラルタル",
+		"GL": "グリーンランド",
+		"GM": "ガンビア",
+		"GN": "ギニア",
+		"GP": "グアドループ",
+		"GQ": "赤道ギニア",
+		"GR": "ギリシャ",
+		"GS": "サウスジョージア及びサウスサンドウィッチ諸島",
+		"GT": "グアテマラ",
+		"GU": "グアム",
+		"GW": "ギニアビサウ",
+		"GY": "ガイアナ",
+		"HK": "香港",
+		"HM": "ハード島及びマクドナルド諸島",
+		"HN": "ホンジュラス",
+		"HR": "クロアチア",
+		"HT": "ハイチ",
+		"HU": "ハンガリー",
+		"ID": "インドネシア",
+		"IE": "アイルランド",
+		"IL": "イスラエル",
+		"IM": "マン島",
+		"IN": "インド",
+		"IO": "英国インド洋領土",
+		"IQ": "イラク",
+		"IR": "イラン・イスラム共和国",
+		"IS": "アイスランド",
+		"IT": "イタリア",
+		"JE": "ジャージー",
+		"JM": "ジャマイカ",
+		"JO": "ヨルダン",
+		"JP": "日本",
+		"KE": "ケニア",
+		"KG": "キルギスタン",
+		"KH": "カンボジア",
+		"KI": "キリバス",
+		"KM": "コモロ",
+		"KN": "セントクリストファー・ネーヴィス",
+		"KP": "朝鮮民主主義人民共和国",
+		"KR": "大韓民国 (韓国)",
+		"KW": "クウェート",
+		"KY": "ケイマン諸島",
+		"KZ": "カザフスタン",
+		"LA": "ラオス人民民主共和国",
+		"LB": "レバノン",
+		"LC": "セントルシア",
+		"LI": "リヒテンシュタイン",
+		"LK": "スリランカ",
+		"LR": "リベリア",
+		"LS": "レソト",
+		"LT": "リトアニア",
+		"LU": "ルクセンブルク",
+		"LV": "ラトビア",
+		"LY": "リビア",
+		"MA": "モロッコ",
+		"MC": "モナコ",
+		"MD": "モルドバ",
+		"ME": "モンテネグロ",
+		"MF": "サンマルタン (仏領)",
+		"MG": "マダガスカル",
+		"MH": "マーシャル諸島",
+		"ML": "マリ",
+		"MM": "ミャンマー",
+		"MN": "モンゴル国",
+		"MO": "マカオ",
+		"MP": "北マリアナ諸島",
+		"MQ": "マルティニーク",
+		"MR": "モーリタニア",
+		"MS": "モントセラト",
+		"MT": "マルタ",
+		"MU": "モーリシャス",
+		"MV": "モルディブ",
+		"MW": "マラウイ",
+		"MX": "メキシコ",
+		"MY": "マレーシア",
+		"MZ": "モザンビーク",
+		"NA": "ナミビア",
+		"NC": "ニューカレドニア",
+		"NE": "ニジェール",
+		"NF": "ノーフォーク島",
+		"NG": "ナイジェリア",
+		"NI": "ニカラグア",
+		"NL": "オランダ",
+		"NO": "ノルウェー",
+		"NP": "ネパール",
+		"NR": "ナウル",
+		"NU": "ニウエ",
+		"NZ": "ニュージーランド",
+		"OM": "オマーン",
+		"PA": "パナマ",
+		"PE": "ペルー",
+		"PF": "仏領ポリネシア",
+		"PG": "パプアニューギニア",
+		"PH": "フィリピン",
+		"PK": "パキスタン",
+		"PL": "ポーランド",
+		"PM": "サンピエール及びミクロン",
+		"PN": "ピトケアン",
+		"PR": "プエルトリコ",
+		"PS": "パレスチナ",
+		"PT": "ポルトガル",
+		"PW": "パラオ",
+		"PY": "パラグアイ",
+		"QA": "カタール",
+		"RE": "レユニオン",
+		"RO": "ルーマニア",
+		"RS": "セルビア",
+		"RU": "ロシア連邦",
+		"RW": "ルワンダ",
+		"SA": "サウジアラビア",
+		"SB": "ソロモン諸島",
+		"SC": "セーシェル",
+		"SD": "スーダン",
+		"SE": "スウェーデン",
+		"SG": "シンガポール",
+		"SH": "セントヘレナ、アセンション及びトリスタン・ダ・クーニャ",
+		"SI": "スロベニア",
+		"SJ": "スヴァールバル及びヤンマイエン",
+		"SK": "スロバキア",
+		"SL": "シエラレオネ",
+		"SM": "サンマリノ",
+		"SN": "セネガル",
+		"SO": "ソマリア",
+		"SR": "スリナム",
+		"SS": "南スーダン",
+		"ST": "サントメ・プリンシペ",
+		"SV": "エルサルバドル",
+		"SX": "サンマルタン (オランダ領)",
+		"SY": "シリア・アラブ共和国",
+		"TC": "タークス及びカイコス諸島",
+		"TD": "チャド",
+		"TF": "フランス南方領土",
+		"TG": "トーゴ",
+		"TH": "タイ",
+		"TJ": "タジキスタン",
+		"TK": "トケラウ",
+		"TL": "東ティモール",
+		"TM": "トルクメニスタン",
+		"TN": "チュニジア",
+		"TO": "トンガ",
+		"TT": "トリニダード・トバゴ",
+		"TV": "ツバル",
+		"TW": "台湾",
+		"TZ": "タンザニア",
+		"UA": "ウクライナ",
+		"UG": "ウガンダ",
+		"UM": "アメリカ合衆国外諸島",
+		"US": "米国",
+		"UY": "ウルグアイ",
+		"UZ": "ウズベキスタン",
+		"VA": "聖庁 (バチカン市国)",
+		"VC": "セントビンセント及びグレナディーン諸島",
+		"VE": "ベネズエラ",
+		"VG": "英領ヴァージン諸島",
+		"VI": "米領ヴァージン諸島",
+		"VN": "ベトナム",
+		"VU": "バヌアツ",
+		"WF": "ワリー及びフテュナ",
+		"WS": "サモア",
+		"YE": "イエメン",
+		"YT": "マヨット",
+		"ZA": "南アフリカ",
+		"ZM": "ザンビア",
+		"ZW": "ジンバブエ",
+	},
+	"ko": {
+		"AD": "안도라",
+		"AE": "아랍에미리트",
+		"AF": "아프가니스탄",
+		"AG": "앤티가 바부다",
+		"AI": "앵귈라",
+		"AL": "알바니아",
+		"AM": "아르메니아",
+		"AO": "앙골라",
+		"AQ": "남극",
+		"AR": "아르헨티나",
+		"AS": "아메리칸사모아",
+		"AT": "오스트리아",
+		"AU": "오스트레일리아",
+		"AW": "아루바",
+		"AX": "올란드 제도",
+		"AZ": "아제르바이잔",
+		"BA": "보스니아 헤르체고비나",
+		"BB": "바베이도스",
+		"BD": "방글라데시",
+		"BE": "벨기에",
+		"BF": "부르키나파소",
+		"BG": "불가리아",
+		"BH": "바레인",
+		"BI": "부룬디",
+		"BJ": "베냉",
+		"BL": "생바르텔레미",
+		"BM": "버뮤다",
+		"BN": "브루나이 다루살람",
+		"BO": "볼리비아",
+		"BQ": "보네르, 신트외스타티위스, 사바 섬",
+		"BR": "브라질",
+		"BS": "바하마",
+		"BT": "부탄",
+		"BV": "부베 섬",
+		"BW": "보츠와나",
+		"BY": "벨라루스",
+		"BZ": "벨리즈",
+		"CA": "캐나다",
+		"CC": "코코스 제도",
+		"CD": "콩고 민주 공화국",
+		"CF": "중앙아프리카 공화국",
+		"CG": "콩고",
+		"CH": "스위스",
+		"CI": "코트디부아르",
+		"CK": "쿡 제도",
+		"CL": "칠레",
+		"CM": "카메룬",
+		"CN": "중국",
+		"CO": "콜롬비아",
+		"CR": "코스타리카",
+		"CU": "쿠바",
+		"CV": "카보베르데",
+		"CW": "퀴라소",
+		"CX": "크리스마스 섬",
+		"CY": "키프로스",
+		"CZ": "체코",
+		"DE": "독일",
+		"DJ": "지부티",
+		"DK": "덴마크",
+		"DM": "도미니카 연방",
+		"DO": "도미니카 공화국",
+		"DZ": "알제리",
+		"EC": "에콰도르",
+		"EE": "에스토니아",
+		"EG": "이집트",
+		"EH": "서사하라",
+		"ER": "에리트레아",
+		"ES": "스페인",
+		"ET": "에티오피아",
+		"FI": "핀란드",
+		"FJ": "피지",
+		"FK": "포클랜드 제도 (말비나스)",
+		"FM": "미크로네시아 연방",
+		"FO": "페로 제도",
+		"FR": "프랑스",
+		"GA": "가봉",
+		"GB": "영국",
+		"GD": "그레나다",
+		"GE": "조지아",
+		"GF": "프랑스령 기아나",
+		"GG": "건지 섬",
+		"GH": "가나",
+		"GI": "지브롤터",
+		"GL": "그린란드",
+		"GM": "감비아",
+		"GN": "기니",
+		"GP": "과들루프",
+		"GQ": "적도 기니",
+		"GR": "그리스",
+		"GS": "사우스조지아 사우스샌드위치 제도",
+		"GT": "과테말라",
+		"GU": "괌",
+		"GW": "기니비사우",
+		"GY": "가이아나",
+		"HK": "홍콩",
+		"HM": "허드 맥도널드 제도",
+		"HN": "온두라스",
+		"HR": "크로아티아",
+		"HT": "아이티",
+		"HU": "헝가리",
+		"ID": "인도네시아",
+		"IE": "아일랜드",
+		"IL": "이스라엘",
+		"IM": "맨 섬",
+		"IN": "인도",
+		"IO": "영국령 인도양 지역",
+		"IQ": "이라크",
+		"IR": "이란 이슬람 공화국",
+		"IS": "아이슬란드",
+		"IT": "이탈리아",
+		"JE": "저지 섬",
+		"JM": "자메이카",
+		"JO": "요르단",
+		"JP": "일본",
+		"KE": "케냐",
+		"KG": "키르기스스탄",
+		"KH": "캄보디아",
+		"KI": "키리바시",
+		"KM": "코모로",
+		"KN": "세인트키츠 네비스",
+		"KP": "조선민주주의인민공화국",
+		"KR": "대한민국",
+		"KW": "쿠웨이트",
+		"KY": "케이맨 제도",
+		"KZ": "카자흐스탄",
+		"LA": "라오 인민 민주주의 공화국",
+		"LB": "레바논",
+		"LC": "세인트루시아",
+		"LI": "리히텐슈타인",
+		"LK": "스리랑카",
+		"LR": "라이베리아",
+		"LS": "레소토",
+		"LT": "리투아니아",
+		"LU": "룩셈부르크",
+		"LV": "라트비아",
+		"LY": "리비아",
+		"MA": "모로코",
+		"MC": "모나코",
+		"MD": "몰도바",
+		"ME": "몬테네그로",
+		"MF": "생마르탱 (프랑스령)",
+		"MG": "마다가스카르",
+		"MH": "마셜 제도",
+		"MK": "북마케도니아",
+		"ML": "말리",
+		"MM": "미얀마",
+		"MN": "몽골",
+		"MO": "마카오",
+		"MP": "북마리아나 제도",
+		"MQ": "마르티니크",
+		"MR": "모리타니",
+		"MS": "몬트세랫",
+		"MT": "몰타",
+		"MU": "모리셔스",
+		"MV": "몰디브",
+		"MW": "말라위",
+		"MX": "멕시코",
+		"MY": "말레이시아",
+		"MZ": "모잠비크",
+		"NA": "나미비아",
+		"NC": "누벨칼레도니",
+		"NE": "니제르",
+		"NF": "노퍽 섬",
+		"NG": "나이지리아",
+		"NI": "니카라과",
+		"NL": "네덜란드",
+		"NO": "노르웨이",
+		"NP": "네팔",
+		"NR": "나우루",
+		"NU": "니우에",
+		"NZ": "뉴질랜드",
+		"OM": "오만",
+		"PA": "파나마",
+		"PE": "페루",
+		"PF": "프랑스령 폴리네시아",
+		"PG": "파푸아뉴기니",
+		"PH": "필리핀",
+		"PK": "파키스탄",
+		"PL": "폴란드",
+		"PM": "생피에르 미클롱",
+		"PN": "핏케언 제도",
+		"PR": "푸에르토리코",
+		"PS": "팔레스타인",
+		"PT": "포르투갈",
+		"PW": "팔라우",
+		"PY": "파라과이",
+		"QA": "카타르",
+		"RE": "레위니옹",
+		"RO": "루마니아",
+		"RS": "세르비아",
+		"RU": "러시아 연방",
+		"RW": "르완다",
+		"SA": "사우디아라비아",
+		"SB": "솔로몬 제도",
+		"SC": "세이셸",
+		"SD": "수단",
+		"SE": "스웨덴",
+		"SG": "싱가포르",
+		"SH": "세인트헬레나 어센션 트리스탄다쿠냐",
+		"SI": "슬로베니아",
+		"SJ": "스발바르 얀마옌 제도",
+		"SK": "슬로바키아",
+		"SL": "시에라리온",
+		"SM": "산마리노",
+		"SN": "세네갈",
+		"SO": "소말리아",
+		"SR": "수리남",
+		"SS": "남수단",
+		"ST": "상투메 프린시페",
+		"SV": "엘살바도르",
+		"SX": "신트마르턴 (네덜란드령)",
+		"SY": "시리아 아랍 공화국",
+		"SZ": "에스와티니",
+		"TC": "터크스 케이커스 제도",
+		"TD": "차드",
+		"TF": "프랑스령 남 자치구역",
+		"TG": "토고",
+		"TH": "태국",
+		"TJ": "타지키스탄",
+		"TK": "토켈라우",
+		"TL": "동티모르",
+		"TM": "투르크메니스탄",
+		"TN": "튀니지",
+		"TO": "통가",
+		"TR": "튀르키예",
+		"TT": "트리니다드 토바고",
+		"TV": "투발루",
+		"TW": "타이완",
+		"TZ": "탄자니아",
+		"UA": "우크라이나",
+		"UG": "우간다",
+		"UM": "미국령 군소 제도",
+		"US": "미국",
+		"UY": "우루과이",
+		"UZ": "우즈베키스탄",
+		"VA": "바티칸 시티 (Holy See)",
+		"VC": "세인트빈센트 그레나딘",
+		"VE": "베네수엘라",
+		"VG": "버진 제도, 영국령",
+		"VI": "버진 제도, 미국령",
+		"VN": "베트남",
+		"VU": "바누아투",
+		"WF": "왈리스 퓌튀나",
+		"WS": "사모아",
+		"YE": "예멘",
+		"YT": "마요트",
+		"ZA": "남아프리카 공화국",
+		"ZM": "잠비아",
+		"ZW": "짐바브웨",
+	},
+	"nl": {
+		"AD": "Andorra",
+		"AE": "Verenigde Arabische Emiraten",
+		"AF": "Afghanistan",
+		"AG": "Antigua en Barbuda",
+		"AI": "Anguilla",
+		"AL": "Albanië",
+		"AM": "Armenië",
+		"AO": "Angola",
+		"AQ": "Antarctica",
+		"AR": "Argentinië",
+		"AS": "Amerikaans-Samoa",
+		"AT": "Oostenrijk",
+		"AU": "Australië",
+		"AW": "Aruba",
+		"AX": "Ålandseilanden",
+		"AZ": "Azerbeidzjan",
+		"BA": "Bosnië en Herzegovina",
+		"BB": "Barbados",
+		"BD": "Bangladesh",
+		"BE": "België",
+		"BF": "Burkina Faso",
+		"BG": "Bulgarije",
+		"BH": "Bahrein",
+		"BI": "Burundi",
+		"BJ": "Benin",
+		"BL": "Saint-Barthélemy",
+		"BM": "Bermuda",
+		"BN": "Brunei",
+		"BO": "Bolivia",
+		"BQ": "Bonaire, Sint Eustatius en Saba",
+		"BR": "Brazilië",
+		"BS": "Bahama's",
+		"BT": "Bhutan",
+		"BV": "Bouveteiland",
+		"BW": "Botswana",
+		"BY": "Wit-Rusland",
+		"BZ": "Belize",
+		"CA": "Canada",
+		"CC": "Cocoseilanden (Keelingeilanden)",
+		"CD": "Congo, Democratische Republiek",
+		"CF": "Centraal-Afrikaanse Republiek",
+		"CG": "Congo",
+		"CH": "Zwitserland",
+		"CI": "Ivoorkust",
+		"CK": "Cookeilanden",
+		"CL": "Chili",
+		"CM": "Kameroen",
+		"CN": "China",
+		"CO": "Colombia",
+		"CR": "Costa Rica",
+		"CU": "Cuba",
+		"CV": "Kaapverdië",
+		"CW": "Curaçao",
+		"CX": "Christmaseiland",
+		"CY": "Cyprus",
+		"CZ": "Tsjechië",
+		"DE": "Duitsland",
+		"DJ": "Djibouti",
+		"DK": "Denemarken",
+		"DM": "Dominica",
+		"DO": "Dominicaanse Republiek",
+		"DZ": "Algerije",
+		"EC": "Ecuador",
+		"EE": "Estland",
+		"EG": "Egypte",
+		"EH": "Westelijke Sahara",
+		"ER": "Eritrea",
+		"ES": "Spanje",
+		"ET": "Ethiopië",
+		"FI": "Finland",
+		"FJ": "Fiji",
+		"FK": "Falklandeilanden (Malvinas)",
+		"FM": "Micronesia",
+		"FO": "Faeröer",
+		"FR": "Frankrijk",
+		"GA": "Gabon",
+		"GB": "Verenigd Koninkrijk",
+		"GD": "Grenada",
+		"GE": "Georgia",
+		"GF": "Frans-Guyana",
+		"GG": "Guernsey",
+		"GH": "Ghana",
+		"GI": "Gibraltar",
+		"GL": "Groenland",
+		"GM": "Gambia",
+		"GN": "Guinee",
+		"GP": "Guadeloupe",
+		"GQ": "Equatoriaal-Guinea",
+		"GR": "Griekenland",
+		"GS": "Zuid-Georgia en de Zuidelijke Sandwicheilanden",
+		"GT": "Guatemala",
+		"GU": "Guam",
+		"GW": "Guinee-Bissau",
+		"GY": "Guyana",
+		"HK": "Hongkong",
+		"HM": "Heardeiland en McDonaldeilanden",
+		"HN": "Honduras",
+		"HR": "Kroatië",
+		"HT": "Haïti",
+		"HU": "Hongarije",
+		"ID": "Indonesië",
+		"IE": "Ierland",
+		"IL": "Israël",
+		"IM": "Eiland Man",
+		"IN": "India",
+		"IO": "Brits Indische Oceaanterritorium",
+		"IQ": "Irak",
+		"IR": "Iran",
+		"IS": "IJsland",
+		"IT": "Italië",
+		"JE": "Jersey",
+		"JM": "Jamaica",
+		"JO": "Jordanië",
+		"JP": "Japan",
+		"KE": "Kenia",
+		"KG": "Kirgizië",
+		"KH": "Cambodja",
+		"KI": "Kiribati",
+		"KM": "Comoren",
+		"KN": "Saint Kitts en Nevis",
+		"KP": "Noord-Korea",
+		"KR": "Zuid-Korea",
+		"KW": "Koeweit",
+		"KY": "Kaaimaneilanden",
+		"KZ": "Kazachstan",
+		"LA": "Laos",
+		"LB": "Libanon",
+		"LC": "Saint Lucia",
+		"LI": "Liechtenstein",
+		"LK": "Sri Lanka",
+		"LR": "Liberia",
+		"LS": "Lesotho",
+		"LT": "Litouwen",
+		"LU": "Luxemburg",
+		"LV": "Letland",
+		"LY": "Libië",
+		"MA": "Marokko",
+		"MC": "Monaco",
+		"MD": "Moldavië",
+		"ME": "Montenegro",
+		"MF": "Sint-Maarten (Frans deel)",
+		"MG": "Madagaskar",
+		"MH": "Marshalleilanden",
+		"MK": "Noord-Macedonië",
+		"ML": "Mali",
+		"MM": "Myanmar",
+		"MN": "Mongolië",
+		"MO": "Macau",
+		"MP": "Noordelijke Marianen",
+		"MQ": "Martinique",
+		"MR": "Mauritanië",
+		"MS": "Montserrat",
+		"MT": "Malta",
+		"MU": "Mauritius",
+		"MV": "Maldiven",
+		"MW": "Malawi",
+		"MX": "Mexico",
+		"MY": "Maleisië",
+		"MZ": "Mozambique",
+		"NA": "Namibië",
+		"NC": "Nieuw-Caledonië",
+		"NE": "Niger",
+		"NF": "Norfolk",
+		"NG": "Nigeria",
+		"NI": "Nicaragua",
+		"NL": "Nederland",
+		"NO": "Noorwegen",
+		"NP": "Nepal",
+		"NR": "Nauru",
+		"NU": "Niue",
+		"NZ": "Nieuw-Zeeland",
+		"OM": "Oman",
+		"PA": "Panama",
+		"PE": "Peru",
+		"PF": "Frans-Polynesië",
+		"PG": "Papoea-Nieuw-Guinea",
+		"PH": "Filipijnen",
+		"PK": "Pakistan",
+		"PL": "Polen",
+		"PM": "Saint-Pierre en Miquelon",
+		"PN": "Pitcairneilanden",
+		"PR": "Puerto Rico",
+		"PS": "Palestina, Staat",
+		"PT": "Portugal",
+		"PW": "Palau",
+		"PY": "Paraguay",
+		"QA": "Qatar",
+		"RE": "Réunion",
+		"RO": "Roemenië",
+		"RS": "Servië",
+		"RU": "Rusland",
+		"RW": "Rwanda",
+		"SA": "Saoedi-Arabië",
+		"SB": "Salomonseilanden",
+		"SC": "Seychellen",
+		"SD": "Soedan",
+		"SE": "Zweden",
+		"SG": "Singapore",
+		"SH": "Sint-Helena, Ascension en Tristan da Cunha",
+		"SI": "Slovenië",
+		"SJ": "Spitsbergen en Jan Mayen",
+		"SK": "Slowakije",
+		"SL": "Sierra Leone",
+		"SM": "San Marino",
+		"SN": "Senegal",
+		"SO": "Somalië",
+		"SR": "Suriname",
+		"SS": "Zuid-Soedan",
+		"ST": "Sao Tomé en Principe",
+		"SV": "El Salvador",
+		"SX": "Sint Maarten (Nederlands deel)",
+		"SY": "Syrië",
+		"SZ": "Eswatini",
+		"TC": "Turks- en Caicoseilanden",
+		"TD": "Tsjaad",
+		"TF": "Franse Zuidelijke Gebieden",
+		"TG": "Togo",
+		"TH": "Thailand",
+		"TJ": "Tadzjikistan",
+		"TK": "Tokelau",
+		"TL": "Oost-Timor",
+		"TM": "Turkmenistan",
+		"TN": "Tunesië",
+		"TO": "Tonga",
+		"TR": "Turkije",
+		"TT": "Trinidad en Tobago",
+		"TV": "Tuvalu",
+		"TW": "Taiwan",
+		"TZ": "Tanzania",
+		"UA": "Oekraïne",
+		"UG": "Oeganda",
+		"UM": "Kleine afgelegen eilanden van de Verenigde Staten",
+		"US": "Verenigde Staten",
+		"UY": "Uruguay",
+		"UZ": "Oezbekistan",
+		"VA": "Vaticaanstad, Staat",
+		"VC": "Saint Vincent en de Grenadines",
+		"VE": "Venezuela",
+		"VG": "Maagdeneilanden, Britse",
+		"VI": "Maagdeneilanden, Amerikaanse",
+		"VN": "Vietnam",
+		"VU": "Vanuatu",
+		"WF": "Wallis en Futuna",
+		"WS": "Samoa",
+		"YE": "Jemen",
+		"YT": "Mayotte",
+		"ZA": "Zuid-Afrika",
+		"ZM": "Zambia",
+		"ZW": "Zimbabwe",
+	},
+	"pl": {
+		"AD": "Andora",
+		"AE": "Zjednoczone Emiraty Arabskie",
+		"AF": "Afganistan",
+		"AG": "Antigua i Barbuda",
+		"AI": "Anguilla",
+		"AL": "Albania",
+		"AM": "Armenia",
+		"AO": "Angola",
+		"AQ": "Antarktyka",
+		"AR": "Argentyna",
+		"AS": "Samoa Amerykańskie",
+		"AT": "Austria",
+		"AU": "Australia",
+		"AW": "Aruba",
+		"AX": "Wyspy Alandzkie",
+		"AZ": "Azerbejdżan",
+		"BA": "Bośnia i Hercegowina",
+		"BB": "Barbados",
+		"BD": "Bangladesz",
+		"BE": "Belgia",
+		"BF": "Burkina Faso",
+		"BG": "Bułgaria",
+		"BH": "Bahrajn",
+		"BI": "Burundi",
+		"BJ": "Benin",
+		"BL": "Saint-Barthélemy",
+		"BM": "Bermudy",
+		"BN": "Państwo Brunei",
+		"BO": "Boliwia",
+		"BQ": "Bonaire, Sint Eustatius i Saba",
+		"BR": "Brazylia",
+		"BS": "Bahamy",
+		"BT": "Bhutan",
+		"BV": "Wyspa Bouveta",
+		"BW": "Botswana",
+		"BY": "Białoruś",
+		"BZ": "Belize",
+		"CA": "Kanada",
+		"CC": "Wyspy Kokosowe (Wyspy Keelinga)",
+		"CD": "Kongo, Demokratyczna Republika Konga",
+		"CF": "Republika Środkowoafrykańska",
+		"CG": "Kongo",
+		"CH": "Szwajcaria",
+		"CI": "Wybrzeże Kości Słoniowej",
+		"CK": "Wyspy Cooka",
+		"CL": "Chile",
+		"CM": "Kamerun",
+		"CN": "Chiny",
+		"CO": "Kolumbia",
+		"CR": "Kostaryka",
+		"CU": "Kuba",
+		"CV": "Republika Zielonego Przylądka",
+		"CW": "Curaçao",
+		"CX": "Wyspa Bożego Narodzenia",
+		"CY": "Cypr",
+		"CZ": "Czechy",
+		"DE": "Niemcy",
+		"DJ": "Dżibuti",
+		"DK": "Dania",
+		"DM": "Dominika",
+		"DO": "Republika Dominikańska",
+		"DZ": "Algieria",
+		"EC": "Ekwador",
+		"EE": "Estonia",
+		"EG": "Egipt",
+		"EH": "Sahara Zachodnia",
+		"ER": "Erytrea",
+		"ES": "Hiszpania",
+		"ET": "Etiopia",
+		"FI": "Finlandia",
+		"FJ": "Fidżi",
+		"FK": "Falklandy (Malwiny)",
+		"FM": "Mikronezja",
+		"FO": "Wyspy Owcze",
+		"FR": "Francja",
+		"GA": "Gabon",
+		"GB": "Wielka Brytania",
+		"GD": "Grenada",
+		"GE": "Gruzja",
+		"GF": "Gujana Francuska",
+		"GG": "Guernsey",
+		"GH": "Ghana",
+		"GI": "Gibraltar",
+		"GL": "Grenlandia",
+		"GM": "Gambia",
+		"GN": "Gwinea",
+		"GP": "Gwadelupa",
+		"GQ": "Gwinea Równikowa",
+		"GR": "Grecja",
+		"GS": "Georgia Południowa i Sandwich Południowy",
+		"GT": "Gwatemala",
+		"GU": "Guam",
+		"GW": "Gwinea Bissau",
+		"GY": "Gujana",
+		"HK": "Hongkong",
+		"HM": "Wyspy Heard i McDonalda",
+		"HN": "Honduras",
+		"HR": "Chorwacja",
+		"HT": "Haiti",
+		"HU": "Węgry",
+		"ID": "Indonezja",
+		"IE": "Irlandia",
+		"IL": "Izrael",
+		"IM": "Wyspa Man",
+		"IN": "Indie",
+		"IO": "Brytyjskie Terytorium Oceanu Indyjskiego",
+		"IQ": "Irak",
+		"IR": "Iran",
+		"IS": "Islandia",
+		"IT": "Włochy",
+		"JE": "Jersey",
+		"JM": "Jamajka",
+		"JO": "Jordania",
+		"JP": "Japonia",
+		"KE": "Kenia",
+		"KG": "Kirgistan",
+		"KH": "Kambodża",
+		"KI": "Kiribati",
+		"KM": "Komory",
+		"KN": "Saint Kitts i Nevis",
+		"KP": "Korea Północna",
+		"KR": "Korea Południowa",
+		"KW": "Kuwejt",
+		"KY": "Kajmany",
+		"KZ": "Kazachstan",
+		"LA": "Laos",
+		"LB": "Liban",
+		"LC": "Saint Lucia",
+		"LI": "Liechtenstein",
+		"LK": "Sri Lanka",
+		"LR": "Liberia",
+		"LS": "Lesotho",
+		"LT": "Litwa",
+		"LU": "Luksemburg",
+		"LV": "Łotwa",
+		"LY": "Libia",
+		"MA": "Maroko",
+		"MC": "Monako",
+		"MD": "Mołdawia",
+		"ME": "Czarnogóra",
+		"MF": "Saint-Martin (część francuska)",
+		"MG": "Madagaskar",
+		"MH": "Wyspy Marshalla",
+		"MK": "Macedonia Północna",
+		"ML": "Mali",
+		"MM": "Mjanma",
+		"MN": "Mongolia",
+		"MO": "Makau",
+		"MP": "Mariany Północne",
+		"MQ": "Martynika",
+		"MR": "Mauretania",
+		"MS": "Montserrat",
+		"MT": "Malta",
+		"MU": "Mauritius",
+		"MV": "Malediwy",
+		"MW": "Malawi",
+		"MX": "Meksyk",
+		"MY": "Malezja",
+		"MZ": "Mozambik",
+		"NA": "Namibia",
+		"NC": "Nowa Kaledonia",
+		"NE": "Niger",
+		"NF": "Wyspy Norfolk",
+		"NG": "Nigeria",
+		"NI": "Nikaragua",
+		"NL": "Holandia",
+		"NO": "Norwegia",
+		"NP": "Nepal",
+		"NR": "Nauru",
+		"NU": "Niue",
+		"NZ": "Nowa Zelandia",
+		"OM": "Oman",
+		"PA": "Panama",
+		"PE": "Peru",
+		"PF": "Polinezja Francuska",
+		"PG": "Papua-Nowa Gwinea",
+		"PH": "Filipiny",
+		"PK": "Pakistan",
+		"PL": "Polska",
+		"PM": "Saint-Pierre i Miquelon",
+		"PN": "Pitcairn",
+		"PR": "Portoryko",
+		"PS": "Palestyna (państwo)",
+		"PT": "Portugalia",
+		"PW": "Palau",
+		"PY": "Paragwaj",
+		"QA": "Katar",
+		"RE": "Reunion",
+		"RO": "Rumunia",
+		"RS": "Serbia",
+		"RU": "Federacja Rosyjska",
+		"RW": "Ruanda",
+		"SA": "Arabia Saudyjska",
+		"SB": "Wyspy Salomona",
+		"SC": "Seszele",
+		"SD": "Sudan",
+		"SE": "Szwecja",
+		"SG": "Singapur",
+		"SH": "Wyspa Świętej Heleny, Wyspa Wniebowstąpienia i Tristan da Cunha",
+		"SI": "Słowenia",
+		"SJ": "Svalbard i Jan Mayen",
+		"SK": "Słowacja",
+		"SL": "Sierra Leone",
+		"SM": "San Marino",
+		"SN": "Senegal",
+		"SO": "Somalia",
+		"SR": "Surinam",
+		"SS": "Sudan Południowy",
+		"ST": "Wyspy Świętego Tomasza i Książęca",
+		"SV": "Salwador",
+		"SX": "Sint Maarten (część holenderska)",
+		"SY": "Syria",
+		"SZ": "Eswatini",
+		"TC": "Turks i Caicos",
+		"TD": "Czad",
+		"TF": "Francuskie Terytoria Południowe",
+		"TG": "Togo",
+		"TH": "Tajlandia",
+		"TJ": "Tadżykistan",
+		"TK": "Tokelau",
+		"TL": "Timor Wschodni",
+		"TM": "Turkmenistan",
+		"TN": "Tunezja",
+		"TO": "Tonga",
+		"TR": "Turcja",
+		"TT": "Trynidad i Tobago",
+		"TV": "Tuvalu",
+		"TW": "Tajwan",
+		"TZ": "Tanzania",
+		"UA": "Ukraina",
+		"UG": "Uganda",
+		"UM": "Dalekie Wyspy Mniejsze Stanów Zjednoczonych",
+		"US": "Stany Zjednoczone",
+		"UY": "Urugwaj",
+		"UZ": "Uzbekistan",
+		"VA": "Państwo Watykańskie (Stolica Apostolska)",
+		"VC": "Saint Vincent i Grenadyny",
+		"VE": "Wenezuela",
+		"VG": "Brytyjskie Wyspy Dziewicze",
+		"VI": "Wyspy Dziewicze Stanów Zjednoczonych",
+		"VN": "Wietnam",
+		"VU": "Vanuatu",
+		"WF": "Wallis i Futuna",
+		"WS": "Samoa",
+		"YE": "Jemen",
+		"YT": "Majotta",
+		"ZA": "Południowa Afryka",
+		"ZM": "Zambia",
+		"ZW": "Zimbabwe",
+	},
+	"pt": {
+		"AD": "Andorra",
+		"AE": "Emirados Árabes Unidos",
+		"AF": "Afeganistão",
+		"AG": "Antígua e Barbuda",
+		"AI": "Anguilla",
+		"AL": "Albânia",
+		"AM": "Arménia",
+		"AO": "Angola",
+		"AQ": "Antártida",
+		"AR": "Argentina",
+		"AS": "Samoa Americana",
+		"AT": "Áustria",
+		"AU": "Austrália",
+		"AW": "Aruba",
+		"AX": "Ilhas Alanda",
+		"AZ": "Azerbaijão",
+		"BA": "Bósnia e Herzegovina",
+		"BB": "Barbados",
+		"BD": "Bangladeche",
+		"BE": "Bélgica",
+		"BF": "Burkina Faso",
+		"BG": "Bulgária",
+		"BH": "Barém",
+		"BI": "Burundi",
+		"BJ": "Benim",
+		"BL": "Saint Barthélemy",
+		"BM": "Bermudas",
+		"BN": "Brunei",
+		"BO": "Bolívia",
+		"BQ": "Bonaire, Santo Eustáquio e Saba",
+		"BR": "Brasil",
+		"BS": "Bahamas",
+		"BT": "Butão",
+		"BV": "Ilha Bouvet",
+		"BW": "Botsuana",
+		"BY": "Bielorússia",
+		"BZ": "Belize",
+		"CA": "Canadá",
+		"CC": "Ilhas Cocos",
+		"CD": "Congo, República Democrática do",
+		"CF": "República Centro-Africana",
+		"CG": "Congo",
+		"CH": "Suíça",
+		"CI": "Costa do Marfim",
+		"CK": "Ilhas Cook",
+		"CL": "Chile",
+		"CM": "Camarões",
+		"CN": "China",
+		"CO": "Colômbia",
+		"CR": "Costa Rica",
+		"CU": "Cuba",
+		"CV": "Cabo Verde",
+		"CW": "Curação",
+		"CX": "Ilha Natal",
+		"CY": "Chipre",
+		"CZ": "Chéquia",
+		"DE": "Alemanha",
+		"DJ": "Djibouti",
+		"DK": "Dinamarca",
+		"DM": "Dominica",
+		"DO": "República Dominicana",
+		"DZ": "Argélia",
+		"EC": "Equador",
+		"EE": "Estónia",
+		"EG": "Egito",
+		"EH": "Saara Ocidental",
+		"ER": "Eritreia",
+		"ES": "Espanha",
+		"ET": "Etiópia",
+		"FI": "Finlândia",
+		"FJ": "Fiji",
+		"FK": "Ilhas Falkland (Malvinas)",
+		"FM": "Micronésia, Estados Federados da",
+		"FO": "Ilhas Faroé",
+		"FR": "França",
+		"GA": "Gabão",
+		"GB": "Reino Unido",
+		"GD": "Granada",
+		"GE": "Geórgia",
+		"GF": "Guiana Francesa",
+		"GG": "Guernsey",
+		"GH": "Gana",
+		"GI": "Gibraltar",
+		"GL": "Gronelândia",
+		"GM": "Gâmbia",
+		"GN": "Guiné",
+		"GP": "Guadalupe",
+		"GQ": "Guiné Equatorial",
+		"GR": "Grécia",
+		"GS": "Ilhas Geórgia do Sul e Sandwich do Sul",
+		"GT": "Guatemala",
+		"GU": "Guam",
+		"GW": "Guiné-Bissáu",
+		"GY": "Guiana",
+		"HK": "Hong Kong",
+		"HM": "Ilha Heard e Ilhas McDonald",
+		"HN": "Honduras",
+		"HR": "Croácia",
+		"HT": "Haiti",
+		"HU": "Hungria",
+		"ID": "Indonésia",
+		"IE": "Irlanda",
+		"IL": "Israel",
+		"IM": "Ilha de Man",
+		"IN": "Índia",
+		"IO": "Território Britânico do Oceano Índico",
+		"IQ": "Iraque",
+		"IR": "Irão, República Islâmica do",
+		"IS": "Islândia",
+		"IT": "Itália",
+		"JE": "Jersey",
+		"JM": "Jamaica",
+		"JO": "Jordânia",
+		"JP": "Japão",
+		"KE": "Quénia",
+		"KG": "Quirguistão",
+		"KH": "Camboja",
+		"KI": "Kiribati",
+		"KM": "Comores",
+		"KN": "São Cristóvão e Nevis",
+		"KP": "Coreia do Norte",
+		"KR": "Coreia do Sul",
+		"KW": "Kuwait",
+		"KY": "Ilhas Caimão",
+		"KZ": "Cazaquistão",
+		"LA": "República Democrática Popular do Laos",
+		"LB": "Líbano",
+		"LC": "Santa Lúcia",
+		"LI": "Liechtenstein",
+		"LK": "Sri Lanka",
+		"LR": "Libéria",
+		"LS": "Lesoto",
+		"LT": "Lituânia",
+		"LU": "Luxemburgo",
+		"LV": "Letónia",
+		"LY": "Líbia",
+		"MA": "Marrocos",
+		"MC": "Mónaco",
+		"MD": "Moldávia",
+		"ME": "Montenegro",
+		"MF": "São Martin (Território Francês)",
+		"MG": "Madagáscar",
+		"MH": "Ilhas Marshall",
+		"MK": "Macedónia do Norte",
+		"ML": "Mali",
+		"MM": "Birmânia",
+		"MN": "Mongólia",
+		"MO": "Macau",
+		"MP": "Ilhas Marianas do Norte",
+		"MQ": "Martinica",
+		"MR": "Mauritânia",
+		"MS": "Monserrate",
+		"MT": "Malta",
+		"MU": "Maurícia",
+		"MV": "Maldivas",
+		"MW": "Malawi",
+		"MX": "México",
+		"MY": "Malásia",
+		"MZ": "Moçambique",
+		"NA": "Namíbia",
+		"NC": "Nova Caledónia",
+		"NE": "Níger",
+		"NF": "Ilha Norfolk",
+		"NG": "Nigéria",
+		"NI": "Nicarágua",
+		"NL": "Países Baixos",
+		"NO": "Noruega",
+		"NP": "Nepal",
+		"NR": "Nauru",
+		"NU": "Niue",
+		"NZ": "Nova Zelândia",
+		"OM": "Omã",
+		"PA": "Panamá",
+		"PE": "Peru",
+		"PF": "Polinésia Francesa",
+		"PG": "Papua Nova Guiné",
+		"PH": "Filipinas",
+		"PK": "Paquistão",
+		"PL": "Polónia",
+		"PM": "Saint Pierre e Miquelon",
+		"PN": "Pitcairn",
+		"PR": "Porto Rico",
+		"PS": "Palestina, Estado da",
+		"PT": "Portugal",
+		"PW": "Palau",
+		"PY": "Paraguai",
+		"QA": "Catar",
+		"RE": "Ilha Reunião",
+		"RO": "Roménia",
+		"RS": "Sérvia",
+		"RU": "Federação Russa",
+		"RW": "Ruanda",
+		"SA": "Arábia Saudita",
+		"SB": "Ilhas Salomão",
+		"SC": "Seychelles",
+		"SD": "Sudão",
+		"SE": "Suécia",
+		"SG": "Singapura",
+		"SH": "Santa Helena, Ascensão e Tristão da Cunha",
+		"SI": "Eslovénia",
+		"SJ": "Svalbard e Jan Mayen",
+		"SK": "Eslováquia",
+		"SL": "Serra Leoa",
+		"SM": "San Marino",
+		"SN": "Senegal",
+		"SO": "Somália",
+		"SR": "Suriname",
+		"SS": "Sudão do Sul",
+		"ST": "São Tomé e Príncipe",
+		"SV": "El Salvador",
+		"SX": "São Martinho (Países Baixos)",
+		"SY": "República Árabe Síria",
+		"SZ": "Suazilândia",
+		"TC": "Ilhas Turcas e Caicos",
+		"TD": "Chade",
+		"TF": "Territórios Franceses do Sul",
+		"TG": "Togo",
+		"TH": "Tailândia",
+		"TJ": "Tajiquistão",
+		"TK": "Tokelau",
+		"TL": "Timor-Leste",
+		"TM": "Turquemenistão",
+		"TN": "Tunísia",
+		"TO": "Tonga",
+		"TR": "Turquia",
+		"TT": "Trindade e Tobago",
+		"TV": "Tuvalu",
+		"TW": "Taiwan",
+		"TZ": "Tanzânia",
+		"UA": "Ucrânia",
+		"UG": "Uganda",
+		"UM": "Ilhas Menores Distantes dos Estados Unidos",
+		"US": "Estados Unidos",
+		"UY": "Uruguai",
+		"UZ": "Uzbequistão",
+		"VA": "Santa Sé (Estado da Cidade do Vaticano)",
+		"VC": "São Vicente e Granadinas",
+		"VE": "Venezuela",
+		"VG": "Ilhas Virgens, Britânicas",
+		"VI": "Ilhas Virgens, Estados Unidos",
+		"VN": "Vietname",
+		"VU": "Vanuatu",
+		"WF": "Wallis e Futuna",
+		"WS": "Samoa",
+		"YE": "Iémen",
+		"YT": "Mayotte",
+		"ZA": "África do Sul",
+		"ZM": "Zâmbia",
+		"ZW": "Zimbábue",
+	},
+	"pt-br": {
+		"AD": "Andorra",
+		"AE": "Emirados Árabes Unidos",
+		"AF": "Afeganistão",
+		"AG": "Antígua e Barbuda",
+		"AI": "Anguila",
+		"AL": "Albânia",
+		"AM": "Armênia",
+		"AO": "Angola",
+		"AQ": "Antártida",
+		"AR": "Argentina",
+		"AS": "Samoa Americana",
+		"AT": "Áustria",
+		"AU": "Austrália",
+		"AW": "Aruba",
+		"AX": "Ilhas Åland",
+		"AZ": "Azerbaidjão",
+		"BA": "Bósnia-Herzegóvina",
+		"BB": "Barbados",
+		"BD": "Bangladesh",
+		"BE": "Bélgica",
+		"BF": "Burquina",
+		"BG": "Bulgária",
+		"BH": "Barein",
+		"BI": "Burundi",
+		"BJ": "Benin",
+		"BL": "São Bartolomeu",
+		"BM": "Bermuda",
+		"BN": "Brunei",
+		"BO": "Bolívia",
+		"BQ": "Bonaire, Saba e Santo Eustáquio",
+		"BR": "Brasil",
+		"BS": "Bahamas",
+		"BT": "Butão",
+		"BV": "Ilha Bouvet",
+		"BW": "Botsuana",
+		"BY": "Bielo-Rússia",
+		"BZ": "Belize",
+		"CA": "Canadá",
+		"CC": "Ilhas Cocos",
+		"CD": "Congo, República Democrática do",
+		"CF": "República Centro-Africana",
+		"CG": "Congo",
+		"CH": "Suíça",
+		"CI": "Costa do Marfim",
+		"CK": "Ilhas Cook",
+		"CL": "Chile",
+		"CM": "Camarões",
+		"CN": "China",
+		"CO": "Colômbia",
+		"CR": "Costa Rica",
+		"CU": "Cuba",
+		"CV": "Cabo Verde",
+		"CW": "Curaçao",
+		"CX": "Ilha Christmas",
+		"CY": "Chipre",
+		"CZ": "Chéquia",
+		"DE": "Alemanha",
+		"DJ": "Djibuti",
+		"DK": "Dinamarca",
+		"DM": "Domínica",
+		"DO": "República Dominicana",
+		"DZ": "Argélia",
+		"EC": "Equador",
+		"EE": "Estônia",
+		"EG": "Egito",
+		"EH": "Saara Ocidental",
+		"ER": "Eritréia",
+		"ES": "Espanha",
+		"ET": "Etiópia",
+		"FI": "Finlândia",
+		"FJ": "Fiji",
+		"FK": "Ilhas Malvinas (Falkland)",
+		"FM": "Micronésia, Estados Federados da",
+		"FO": "Ilhas Faroe",
+		"FR": "França",
+		"GA": "Gabão",
+		"GB": "Reino Unido",
+		"GD": "Granada",
+		"GE": "Geórgia",
+		"GF": "Guiana Francesa",
+		"GG": "Guernsey",
+		"GH": "Gana",
+		"GI": "Gibraltar",
+		"GL": "Groenlândia",
+		"GM": "Gâmbia",
+		"GN": "Guiné",
+		"GP": "Guadalupe",
+		"GQ": "Guiné Equatorial",
+		"GR": "Grécia",
+		"GS": "Geórgia do Sul e Ilhas Sandwich do Sul",
+		"GT": "Guatemala",
+		"GU": "Guam",
+		"GW": "Guiné-Bissau",
+		"GY": "Guiana",
+		"HK": "Hong Kong",
+		"HM": "Ilha Heard e Ilhas McDonald",
+		"HN": "Honduras",
+		"HR": "Croácia",
+		"HT": "Haiti",
+		"HU": "Hungria",
+		"ID": "Indonésia",
+		"IE": "Irlanda",
+		"IL": "Israel",
+		"IM": "Ilha de Man",
+		"IN": "Índia",
+		"IO": "Território Britânico do Oceano Índico",
+		"IQ": "Iraque",
+		"IR": "Irã, República Islâmica do",
+		"IS": "Islândia",
+		"IT": "Itália",
+		"JE": "Jersey",
+		"JM": "Jamaica",
+		"JO": "Jordânia",
+		"JP": "Japão",
+		"KE": "Quênia",
+		"KG": "Quirguistão",
+		"KH": "Camboja",
+		"KI": "Kiribati",
+		"KM": "Comores",
+		"KN": "São Cristóvão e Névis",
+		"KP": "Coreia do Norte",
+		"KR": "Coreia do Sul",
+		"KW": "Kuwait",
+		"KY": "Ilhas Cayman",
+		"KZ": "Cazaquistão",
+		"LA": "República Popular Democrática do Laos",
+		"LB": "Líbano",
+		"LC": "Santa Lúcia",
+		"LI": "Liechtenstein",
+		"LK": "Sri Lanka",
+		"LR": "Libéria",
+		"LS": "Lesoto",
+		"LT": "Lituânia",
+		"LU": "Luxemburgo",
+		"LV": "Letônia",
+		"LY": "Líbia",
+		"MA": "Marrocos",
+		"MC": "Mônaco",
+		"MD": "Moldávia",
+		"ME": "Montenegro",
+		"MF": "São Martim (parte francesa)",
+		"MG": "Madagascar",
+		"MH": "Ilhas Marshall",
+		"MK": "Macedônia do Norte",
+		"ML": "Mali",
+		"MM": "Myanmar",
+		"MN": "Mongólia",
+		"MO": "Macau",
+		"MP": "Ilhas Marianas do Norte",
+		"MQ": "Martinica",
+		"MR": "Mauritânia",
+		"MS": "Montserrat",
+		"MT": "Malta",
+		"MU": "Maurício",
+		"MV": "Maldivas",
+		"MW": "Malaui",
+		"MX": "México",
+		"MY": "Malásia",
+		"MZ": "Moçambique",
+		"NA": "Namíbia",
+		"NC": "Nova Caledônia",
+		"NE": "Níger",
+		"NF": "Ilha Norfolk",
+		"NG": "Nigéria",
+		"NI": "Nicarágua",
+		"NL": "Países Baixos",
+		"NO": "Noruega",
+		"NP": "Nepal",
+		"NR": "Nauru",
+		"NU": "Niue",
+		"NZ": "Nova Zelândia",
+		"OM": "Omã",
+		"PA": "Panamá",
+		"PE": "Peru",
+		"PF": "Polinésia Francesa",
+		"PG": "Papua-Nova Guiné",
+		"PH": "Filipinas",
+		"PK": "Paquistão",
+		"PL": "Polônia",
+		"PM": "São Pedro e Miquelon",
+		"PN": "Pitcairn",
+		"PR": "Porto Rico",
+		"PS": "Palestina, Estado da",
+		"PT": "Portugal",
+		"PW": "Palau",
+		"PY": "Paraguai",
+		"QA": "Catar",
+		"RE": "Reunião",
+		"RO": "Romênia",
+		"RS": "Sérvia",
+		"RU": "Federação Russa",
+		"RW": "Ruanda",
+		"SA": "Arábia Saudita",
+		"SB": "Ilhas Salomão",
+		"SC": "Seychelles",
+		"SD": "Sudão",
+		"SE": "Suécia",
+		"SG": "Cingapura",
+		"SH": "Santa Helena, Ascensão e Tristão da Cunha",
+		"SI": "Eslovênia",
+		"SJ": "Svalbard e a Ilha de Jan Mayen",
+		"SK": "Eslováquia",
+		"SL": "Serra Leoa",
+		"SM": "São Marino",
+		"SN": "Senegal",
+		"SO": "Somália",
+		"SR": "Suriname",
+		"SS": "Sudão do Sul",
+		"ST": "São Tomé e Príncipe",
+		"SV": "El Salvador",
+		"SX": "São Martim (parte holandesa)",
+		"SY": "República Árabe da Síria",
+		"SZ": "Suazilândia",
+		"TC": "Ilhas Turks e Caicos",
+		"TD": "Chade",
+		"TF": "Territórios Franceses do Sul",
+		"TG": "Togo",
+		"TH": "Tailândia",
+		"TJ": "Tadjiquistão",
+		"TK": "Toquelau",
+		"TL": "Timor Leste",
+		"TM": "Turcomenistão",
+		"TN": "Tunísia",
+		"TO": "Tonga",
+		"TR": "Turquia",
+		"TT": "Trinidade e Tobago",
+		"TV": "Tuvalu",
+		"TW": "Taiwan",
+		"TZ": "Tanzânia",
+		"UA": "Ucrânia",
+		"UG": "Uganda",
+		"UM": "Ilhas Menores Distantes dos Estados Unidos",
+		"US": "Estados Unidos",
+		"UY": "Uruguai",
+		"UZ": "Uzbequistão",
+		"VA": "Santa Sé (Cidade-Estado do Vaticano)",
+		"VC": "São Vicente e Granadinas",
+		"VE": "Venezuela",
+		"VG": "Ilhas Virgens Britânicas",
+		"VI": "Ilhas Virgens dos Estados Unidos",
+		"VN": "Vietnã",
+		"VU": "Vanuatu",
+		"WF": "Wallis e Futuna",
+		"WS": "Samoa",
+		"YE": "Iêmen",
+		"YT": "Maiote",
+		"ZA": "África do Sul",
+		"ZM": "Zâmbia",
+		"ZW": "Zimbábue",
+	},
+	"ru": {
+		"AD": "Андорра",
+		"AE": "Объединённые Арабские Эмираты",
+		"AF": "Афганистан",
+		"AG": "Антигуа и Барбуда",
+		"AI": "Ангвилла",
+		"AL": "Албания",
+		"AM": "Армения",
+		"AO": "Ангола",
+		"AQ": "Антарктика",
+		"AR": "Аргентина",
+		"AS": "Американские Самоа",
+		"AT": "Австрия",
+		"AU": "Австралия",
+		"AW": "Аруба",
+		"AX": "Аландские острова",
+		"AZ": "Азербайджан",
+		"BA": "Босния и Герцеговина",
+		"BB": "Барбадос",
+		"BD": "Бангладеш",
+		"BE": "Бельгия",
+		"BF": "Буркина-Фасо",
+		"BG": "Болгария",
+		"BH": "Бахрейн",
+		"BI": "Бурунди",
+		"BJ": "Бенин",
+		"BL": "Сен-Бартельми",
+		"BM": "Бермуды",
+		"BN": "Бруней Даруссалам",
+		"BO": "Боливия",
+		"BQ": "Бонайре, Синт-Эстатиус и Саба",
+		"BR": "Бразилия",
+		"BS": "Багамы",
+		"BT": "Бутан",
+		"BV": "Остров Буве",
+		"BW": "Ботсвана",
+		"BY": "Беларусь",
+		"BZ": "Белиз",
+		"CA": "Канада",
+		"CC": "Кокосовые острова",
+		"CD": "Демократическая Республика Конго",
+		"CF": "Центрально-африканская республика",
+		"CG": "Конго",
+		"CH": "Швейцария",
+		"CI": "Кот-д'Ивуар",
+		"CK": "Острова Кука",
+		"CL": "Чили",
+		"CM": "Камерун",
+		"CN": "Китай",
+		"CO": "Колумбия",
+		"CR": "Коста-Рика",
+		"CU": "Куба",
+		"CV": "Кабо-Верде",
+		"CW": "Кюрасао",
+		"CX": "Остров Рождества",
+		"CY": "Кипр",
+		"CZ": "Чехия",
+		"DE": "Германия",
+		"DJ": "Джибути",
+		"DK": "Дания",
+		"DM": "Доминика",
+		"DO": "Доминиканская республика",
+		"DZ": "Алжир",
+		"EC": "Эквадор",
+		"EE": "Эстония",
+		"EG": "Египет",
+		"EH": "Западная Сахара",
+		"ER": "Эритрея",
+		"ES": "Испания",
+		"ET": "Эфиопия",
+		"FI": "Финляндия",
+		"FJ": "Фиджи",
+		"FK": "Фолклендские (Мальвинские) острова",
+		"FM": "Федеративные Штаты Микронезии",
+		"FO": "Фарерские острова",
+		"FR": "Франция",
+		"GA": "Габон",
+		"GB": "Соединённое Королевство",
+		"GD": "Гренада",
+		"GE": "Грузия",
+		"GF": "Французская Гвиана",
+		"GG": "Гернси",
+		"GH": "Гана",
+		"GI": "Гибралтар",
+		"GL": "Гренландия",
+		"GM": "Гамбия",
+		"GN": "Гвинея",
+		"GP": "Гваделупа",
+		"GQ": "Экваториальная Гвинея",
+		"GR": "Греция",
+		"GS": "Южная Джорджия и Южные Сандвичевы острова",
+		"GT": "Гватемала",
+		"GU": "Гуам",
+		"GW": "Гвинея-Бисау",
+		"GY": "Гайана",
+		"HK": "Гонконг",
+		"HM": "Остров Херд и острова МакДональд",
+		"HN": "Гондурас",
+		"HR": "Хорватия",
+		"HT": "Гаити",
+		"HU": "Венгрия",
+		"ID": "Индонезия",
+		"IE": "Ирландия",
+		"IL": "Израиль",
+		"IM": "Остров Мэн",
+		"IN": "Индия",
+		"IO": "Британская территория Индийского океана",
+		"IQ": "Ирак",
+		"IR": "Иран",
+		"IS": "Исландия",
+		"IT": "Италия",
+		"JE": "Джерси",
+		"JM": "Ямайка",
+		"JO": "Иордания",
+		"JP": "Япония",
+		"KE": "Кения",
+		"KG": "Киргизия",
+		"KH": "Камбоджа",
+		"KI": "Кирибати",
+		"KM": "Коморы",
+		"KN": "Сент-Китс и Невис",
+		"KP": "Северная Корея",
+		"KR": "Южная Корея",
+		"KW": "Кувейт",
+		"KY": "Каймановы острова",
+		"KZ": "Казахстан",
+		"LA": "Лаосская Народно-Демократическая Республика",
+		"LB": "Ливан",
+		"LC": "Сент-Люсия",
+		"LI": "Лихтенштейн",
+		"LK": "Шри-Ланка",
+		"LR": "Либерия",
+		"LS": "Лесото",
+		"LT": "Литва",
+		"LU": "Люксембург",
+		"LV": "Латвия",
+		"LY": "Ливия",
+		"MA": "Марокко",
+		"MC": "Монако",
+		"MD": "Молдавия",
+		"ME": "Черногория",
+		"MF": "Сен-Мартен (Франция)",
+		"MG": "Мадагаскар",
+		"MH": "Маршалловы острова",
+		"MK": "Северная Македония",
+		"ML": "Мали",
+		"MM": "Мьянма",
+		"MN": "Монголия",
+		"MO": "Макао",
+		"MP": "Острова северной Марианы",
+		"MQ": "Мартиника",
+		"MR": "Мавритания",
+		"MS": "Монтсеррат",
+		"MT": "Мальта",
+		"MU": "Маврикий",
+		"MV": "Мальдивы",
+		"MW": "Малави",
+		"MX": "Мексика",
+		"MY": "Малайзия",
+		"MZ": "Мозамбик",
+		"NA": "Намибия",
+		"NC": "Новая Каледония",
+		"NE": "Нигер",
+		"NF": "Остров Норфолк",
+		"NG": "Нигерия",
+		"NI": "Никарагуа",
+		"NL": "Нидерланды",
+		"NO": "Норвегия",
+		"NP": "Непал",
+		"NR": "Науру",
+		"NU": "Ниуэ",
+		"NZ": "Новая Зеландия",
+		"OM": "Оман",
+		"PA": "Панама",
+		"PE": "Перу",
+		"PF": "Французская Полинезия",
+		"PG": "Папуа — Новая Гвинея",
+		"PH": "Филиппины",
+		"PK": "Пакистан",
+		"PL": "Польша",
+		"PM": "Сен-Пьер и Микелон",
+		"PN": "Питкэрн",
+		"PR": "Пуэрто-Рико",
+		"PS": "Палестина",
+		"PT": "Португалия",
+		"PW": "Палау",
+		"PY": "Парагвай",
+		"QA": "Катар",
+		"RE": "Реюньон",
+		"RO": "Румыния",
+		"RS": "Сербия",
+		"RU": "Российская Федерация",
+		"RW": "Руанда",
+		"SA": "Саудовская Аравия",
+		"SB": "Соломоновы Острова",
+		"SC": "Сейшелы",
+		"SD": "Судан",
+		"SE": "Швеция",
+		"SG": "Сингапур",
+		"SH": "Остров Святой Елены, Остров Вознесения и Тристан-да-Кунья",
+		"SI": "Словения",
+		"SJ": "Шпицберген и Ян-Майен",
+		"SK": "Словакия",
+		"SL": "Сьерра-Леоне",
+		"SM": "Сан-Марино",
+		"SN": "Сенегал",
+		"SO": "Сомали",
+		"SR": "Суринам",
+		"SS": "Южный Судан",
+		"ST": "Сан-Томе и Принсипи",
+		"SV": "Сальвадор",
+		"SX": "Синт-Мартен (голландская часть)",
+		"SY": "Сирийская Арабская Республика",
+		"SZ": "Эсватини",
+		"TC": "Острова Туркс и Каикос",
+		"TD": "Чад",
+		"TF": "Французские южные территории",
+		"TG": "Того",
+		"TH": "Таиланд",
+		"TJ": "Таджикистан",
+		"TK": "Токелау",
+		"TL": "Восточный Тимор",
+		"TM": "Туркменистан",
+		"TN": "Тунис",
+		"TO": "Тонга",
+		"TT": "Тринидад и Тобаго",
+		"TV": "Тувалу",
+		"TW": "Тайвань",
+		"TZ": "Танзания",
+		"UA": "Украина",
+		"UG": "Уганда",
+		"UM": "Соединенные штаты Малых Удаленных островов",
+		"US": "Соединённые штаты",
+		"UY": "Уругвай",
+		"UZ": "Узбекистан",
+		"VA": "Государство-город Ватикан",
+		"VC": "Сент-Винсент и Гренадины",
+		"VE": "Венесуэла",
+		"VG": "Виргинские острова (Британия)",
+		"VI": "Виргинские острова (США)",
+		"VN": "Вьетнам",
+		"VU": "Вануату",
+		"WF": "Уоллес и Футана",
+		"WS": "Самоа",
+		"YE": "Йемен",
+		"YT": "Майот",
+		"ZA": "Южная Африка",
+		"ZM": "Замбия",
+		"ZW": "Зимбабве",
+	},
+	"sv": {
+		"AD": "Andorra",
+		"AE": "Förenade Arabemiraten",
+		"AF": "Afghanistan",
+		"AG": "Antigua och Barbuda",
+		"AI": "Anguilla",
+		"AL": "Albanien",
+		"AM": "Armenien",
+		"AO": "Angola",
+		"AQ": "Antarktis",
+		"AR": "Argentina",
+		"AS": "Amerikanska Samoa",
+		"AT": "Österrike",
+		"AU": "Australien",
+		"AW": "Aruba",
+		"AX": "Åland",
+		"AZ": "Azerbajdzjan",
+		"BA": "Bosnien-Hercegovina",
+		"BB": "Barbados",
+		"BD": "Bangladesh",
+		"BE": "Belgien",
+		"BF": "Burkina Faso",
+		"BG": "Bulgarien",
+		"BH": "Bahrain",
+		"BI": "Burundi",
+		"BJ": "Benin",
+		"BL": "Saint-Barthélemy",
+		"BM": "Bermuda",
+		"BN": "Brunei",
+		"BO": "Bolivia",
+		"BQ": "Bonaire, Sint Eustatius och Saba",
+		"BR": "Brasilien",
+		"BS": "Bahamas",
+		"BT": "Bhutan",
+		"BV": "Bouvetön",
+		"BW": "Botswana",
+		"BY": "Vitryssland",
+		"BZ": "Belize",
+		"CA": "Kanada",
+		"CC": "Kokosöarna",
+		"CD": "Kongo, demokratiska republiken",
+		"CF": "Centralafrikanska republiken",
+		"CG": "Kongo",
+		"CH": "Schweiz",
+		"CI": "Elfenbenskusten",
+		"CK": "Cooköarna",
+		"CL": "Chile",
+		"CM": "Kamerun",
+		"CN": "Kina",
+		"CO": "Colombia",
+		"CR": "Costa Rica",
+		"CU": "Kuba",
+		"CV": "Kap Verde",
+		"CW": "Curaçao",
+		"CX": "Julön",
+		"CY": "Cypern",
+		"CZ": "Tjeckien",
+		"DE": "Tyskland",
+		"DJ": "Djibouti",
+		"DK": "Danmark",
+		"DM": "Dominica",
+		"DO": "Dominikanska republiken",
+		"DZ": "Algeriet",
+		"EC": "Ecuador",
+		"EE": "Estland",
+		"EG": "Egypten",
+		"EH": "Västsahara",
+		"ER": "Eritrea",
+		"ES": "Spanien",
+		"ET": "Etiopien",
+		"FI": "Finland",
+		"FJ": "Fiji",
+		"FK": "Falklandsöarna (Malvinas)",
+		"FM": "Mikronesien, federala staterna",
+		"FO": "Färöarna",
+		"FR": "Frankrike",
+		"GA": "Gabon",
+		"GB": "Förenade kungariket",
+		"GD": "Grenada",
+		"GE": "Georgien",
+		"GF": "Franska Guyana",
+		"GG": "Guernsey",
+		"GH": "Ghana",
+		"GI": "Gibraltar",
+		"GL": "Grönland",
+		"GM": "Gambia",
+		"GN": "Guinea",
+		"GP": "Guadeloupe",
+		"GQ": "Ekvatorialguinea",
+		"GR": "Grekland",
+		"GS": "Sydgeorgien och södra Sandwichöarna",
+		"GT": "Guatemala",
+		"GU": "Guam",
+		"GW": "Guinea-Bissau",
+		"GY": "Guyana",
+		"HK": "Hongkong",
+		"HM": "Heardön och McDonaldöarna",
+		"HN": "Honduras",
+		"HR": "Kroatien",
+		"HT": "Haiti",
+		"HU": "Ungern",
+		"ID": "Indonesien",
+		"IE": "Irland",
+		"IL": "Israel",
+		"IM": "Isle of Man",
+		"IN": "Indien",
+		"IO": "Brittiskt territorium i Indiska Oceanen",
+		"IQ": "Irak",
+		"IR": "Iran",
+		"IS": "Island",
+		"IT": "Italien",
+		"JE": "Jersey",
+		"JM": "Jamaica",
+		"JO": "Jordanien",
+		"JP": "Japan",
+		"KE": "Kenya",
+		"KG": "Kirgizistan",
+		"KH": "Kambodja",
+		"KI": "Kiribati",
+		"KM": "Comorerna",
+		"KN": "Sankt Kitts och Nevis",
+		"KP": "Nordkorea",
+		"KR": "Sydkorea",
+		"KW": "Kuwait",
+		"KY": "Caymanöarna",
+		"KZ": "Kazakstan",
+		"LA": "Laos",
+		"LB": "Libanon",
+		"LC": "Sankt Lucia",
+		"LI": "Liechtenstein",
+		"LK": "Sri Lanka",
+		"LR": "Liberia",
+		"LS": "Lesotho",
+		"LT": "Litauen",
+		"LU": "Luxemburg",
+		"LV": "Lettland",
+		"LY": "Libyen",
+		"MA": "Marocko",
+		"MC": "Monaco",
+		"MD": "Moldavien",
+		"ME": "Montenegro",
+		"MF": "Saint Martin (franska delen)",
+		"MG": "Madagaskar",
+		"MH": "Marshallöarna",
+		"MK": "Nordmakedonien",
+		"ML": "Mali",
+		"MM": "Myanmar",
+		"MN": "Mongoliet",
+		"MO": "Macao",
+		"MP": "Nordmarianerna",
+		"MQ": "Martinique",
+		"MR": "Mauretanien",
+		"MS": "Montserrat",
+		"MT": "Malta",
+		"MU": "Mauritius",
+		"MV": "Maldiverna",
+		"MW": "Malawi",
+		"MX": "Mexiko",
+		"MY": "Malaysia",
+		"MZ": "Moçambique",
+		"NA": "Namibia",
+		"NC": "Nya Kaledonien",
+		"NE": "Niger",
+		"NF": "Norfolköarna",
+		"NG": "Nigeria",
+		"NI": "Nicaragua",
+		"NL": "Nederländerna",
+		"NO": "Norge",
+		"NP": "Nepal",
+		"NR": "Nauru",
+		"NU": "Niue",
+		"NZ": "Nya Zeeland",
+		"OM": "Oman",
+		"PA": "Panama",
+		"PE": "Peru",
+		"PF": "Franska Polynesien",
+		"PG": "Papua Nya Guinea",
+		"PH": "Filippinerna",
+		"PK": "Pakistan",
+		"PL": "Polen",
+		"PM": "Sankt Pierre och Miquelon",
+		"PN": "Pitcairn",
+		"PR": "Puerto Rico",
+		"PS": "Staten Palestina",
+		"PT": "Portugal",
+		"PW": "Palau",
+		"PY": "Paraguay",
+		"QA": "Qatar",
+		"RE": "Réunion",
+		"RO": "Rumänien",
+		"RS": "Serbien",
+		"RU": "Ryska federationen",
+		"RW": "Rwanda",
+		"SA": "Saudiarabien",
+		"SB": "Salomonöarna",
+		"SC": "Seychellerna",
+		"SD": "Sudan",
+		"SE": "Sverige",
+		"SG": "Singapore",
+		"SH": "Saint Helena, Ascension och Tristan da Cunha",
+		"SI": "Slovenien",
+		"SJ": "Svalbard och Jan Mayen",
+		"SK": "Slovakien",
+		"SL": "Sierra Leone",
+		"SM": "San Marino",
+		"SN": "Senegal",
+		"SO": "Somalia",
+		"SR": "Surinam",
+		"SS": "Sydsudan",
+		"ST": "São Tomé och Príncipe",
+		"SV": "El Salvador",
+		"SX": "Sint Maarten (nederländska delen)",
+		"SY": "Syrien",
+		"SZ": "Swaziland",
+		"TC": "Turks- och Caicosöarna",
+		"TD": "Tchad",
+		"TF": "Franska sydterritorierna",
+		"TG": "Togo",
+		"TH": "Thailand",
+		"TJ": "Tadzjikistan",
+		"TK": "Tokelau",
+		"TL": "Östtimor",
+		"TM": "Turkmenistan",
+		"TN": "Tunisien",
+		"TO": "Tonga",
+		"TR": "Turkiet",
+		"TT": "Trinidad och Tobago",
+		"TV": "Tuvalu",
+		"TW": "Taiwan",
+		"TZ": "Tanzania",
+		"UA": "Ukraina",
+		"UG": "Uganda",
+		"UM": "Förenta staternas mindre öar i Oceanien och Västindien",
+		"US": "USA",
+		"UY": "Uruguay",
+		"UZ": "Uzbekistan",
+		"VA": "Vatikanstaten",
+		"VC": "Sankt Vincent och Grenadinerna",
+		"VE": "Venezuela",
+		"VG": "Jungfruöarna, brittiska",
+		"VI": "Jungfruöarna, amerikanska",
+		"VN": "Vietnam",
+		"VU": "Vanuatu",
+		"WF": "Wallis och Futuna",
+		"WS": "Samoa",
+		"YE": "Yemen",
+		"YT": "Mayotte",
+		"ZA": "Sydafrika",
+		"ZM": "Zambia",
+		"ZW": "Zimbabwe",
+	},
+	"tr": {
+		"AD": "Andorra",
+		"AE": "Birleşik Arap Emirlikleri",
+		"AF": "Afganistan",
+		"AG": "Antigua ve Barbuda",
+		"AI": "Anguilla",
+		"AL": "Arnavutluk",
+		"AM": "Ermenistan",
+		"AO": "Angola",
+		"AQ": "Antarktika",
+		"AR": "Arjantin",
+		"AS": "Amerikan Samoası",
+		"AT": "Avusturya",
+		"AU": "Avustralya",
+		"AW": "Aruba",
+		"AX": "Åland Adaları",
+		"AZ": "Azerbaycan",
+		"BA": "Bosna-Hersek",
+		"BB": "Barbados",
+		"BD": "Bangladeş",
+		"BE": "Belçika",
+		"BF": "Burkina Faso",
+		"BG": "Bulgaristan",
+		"BH": "Bahreyn",
+		"BI": "Burundi",
+		"BJ": "Benin",
+		"BL": "Saint Barthélemy",
+		"BM": "Bermuda",
+		"BN": "Brunei Krallığı",
+		"BO": "Bolivya",
+		"BQ": "Bonaire, Sint Eustatius ve Saba",
+		"BR": "Brezilya",
+		"BS": "Bahamalar",
+		"BT": "Bhutan",
+		"BV": "Bouvet Adası",
+		"BW": "Botsvana",
+		"BY": "Belarus",
+		"BZ": "Belize",
+		"CA": "Kanada",
+		"CC": "Cocos (Keeling) Adaları",
+		"CD": "Kongo Demokratik Cumhuriyeti",
+		"CF": "Orta Afrika Cumhuriyeti",
+		"CG": "Kongo",
+		"CH": "İsviçre",
+		"CI": "Fildişi Sahili",
+		"CK": "Cook Adaları",
+		"CL": "Şili",
+		"CM": "Kamerun",
+		"CN": "Çin",
+		"CO": "Kolombiya",
+		"CR": "Kosta Rika",
+		"CU": "Küba",
+		"CV": "Yeşil Burun Adaları",
+		"CW": "Curaçao",
+		"CX": "Christmas Adası",
+		"CY": "Kıbrıs",
+		"CZ": "Çekya",
+		"DE": "Almanya",
+		"DJ": "Cibuti",
+		"DK": "Danimarka",
+		"DM": "Dominika",
+		"DO": "Dominik Cumhuriyeti",
+		"DZ": "Cezayir",
+		"EC": "Ekvador",
+		"EE": "Estonya",
+		"EG": "Mısır",
+		"EH": "Batı Sahra",
+		"ER": "Eritre",
+		"ES": "İspanya",
+		"ET": "Etiyopya",
+		"FI": "Finlandiya",
+		"FJ": "Fiji",
+		"FK": "Falkland Adaları (Malvinas)",
+		"FM": "Mikronezya Federe Devletleri",
+		"FO": "Faroe Adaları",
+		"FR": "Fransa",
+		"GA": "Gabon",
+		"GB": "Birleşik Krallık",
+		"GD": "Grenada",
+		"GE": "Gürcistan",
+		"GF": "Fransız Guyanası",
+		"GG": "Guernsey",
+		"GH": "Gana",
+		"GI": "Cebelitarık",
+		"GL": "Grönland",
+		"GM": "Gambiya",
+		"GN": "Gine",
+		"GP": "Guadeloupe",
+		"GQ": "Ekvator Ginesi",
+		"GR": "Yunanistan",
+		"GS": "Güney Georgia ve Güney Sandwich Adaları",
+		"GT": "Guatemala",
+		"GU": "Guam",
+		"GW": "Gine-Bissau",
+		"GY": "Guyana",
+		"HK": "Hong Kong",
+		"HM": "Heard Adası ve McDonald Adaları",
+		"HN": "Honduras",
+		"HR": "Hırvatistan",
+		"HT": "Haiti",
+		"HU": "Macaristan",
+		"ID": "Endonezya",
+		"IE": "İrlanda",
+		"IL": "İsrail",
+		"IM": "Man Adası",
+		"IN": "Hindistan",
+		"IO": "Britanya Hint Okyanusu Toprakları",
+		"IQ": "Irak",
+		"IR": "İran",
+		"IS": "İzlanda",
+		"IT": "İtalya",
+		"JE": "Jersey",
+		"JM": "Jamaika",
+		"JO": "Ürdün",
+		"JP": "Japonya",
+		"KE": "Kenya",
+		"KG": "Kırgızistan",
+		"KH": "Kamboçya",
+		"KI": "Kiribati",
+		"KM": "Komorlar",
+		"KN": "Saint Kitts ve Nevis",
+		"KP": "Kuzey Kore",
+		"KR": "Güney Kore",
+		"KW": "Kuveyt",
+		"KY": "Cayman Adaları",
+		"KZ": "Kazakistan",
+		"LA": "Laos",
+		"LB": "Lübnan",
+		"LC": "Saint Lucia",
+		"LI": "Lihtenştayn",
+		"LK": "Sri Lanka",
+		"LR": "Liberya",
+		"LS": "Lesoto",
+		"LT": "Litvanya",
+		"LU": "Lüksemburg",
+		"LV": "Letonya",
+		"LY": "Libya",
+		"MA": "Fas",
+		"MC": "Monako",
+		"MD": "Moldova",
+		"ME": "Karadağ",
+		"MF": "Saint Martin (Fransız kısmı)",
+		"MG": "Madagaskar",
+		"MH": "Marşal Adaları",
+		"MK": "Kuzey Makedonya",
+		"ML": "Mali",
+		"MM": "Myanmar",
+		"MN": "Moğolistan",
+		"MO": "Makao",
+		"MP": "Kuzey Mariana Adaları",
+		"MQ": "Martinique",
+		"MR": "Moritanya",
+		"MS": "Montserrat",
+		"MT": "Malta",
+		"MU": "Mauritius",
+		"MV": "Maldivler",
+		"MW": "Malavi",
+		"MX": "Meksika",
+		"MY": "Malezya",
+		"MZ": "Mozambik",
+		"NA": "Namibya",
+		"NC": "Yeni Kaledonya",
+		"NE": "Nijer",
+		"NF": "Norfolk Adası",
+		"NG": "Nijerya",
+		"NI": "Nikaragua",
+		"NL": "Hollanda",
+		"NO": "Norveç",
+		"NP": "Nepal",
+		"NR": "Nauru",
+		"NU": "Niue",
+		"NZ": "Yeni Zelanda",
+		"OM": "Umman",
+		"PA": "Panama",
+		"PE": "Peru",
+		"PF": "Fransız Polinezyası",
+		"PG": "Papua Yeni Gine",
+		"PH": "Filipinler",
+		"PK": "Pakistan",
+		"PL": "Polonya",
+		"PM": "Saint Pierre ve Miquelon",
+		"PN": "Pitcairn",
+		"PR": "Porto Riko",
+		"PS": "Filistin Devleti",
+		"PT": "Portekiz",
+		"PW": "Palau",
+		"PY": "Paraguay",
+		"QA": "Katar",
+		"RE": "Réunion",
+		"RO": "Romanya",
+		"RS": "Sırbistan",
+		"RU": "Rusya Federasyonu",
+		"RW": "Ruanda",
+		"SA": "Suudi Arabistan",
+		"SB": "Solomon Adaları",
+		"SC": "Seyşeller",
+		"SD": "Sudan",
+		"SE": "İsveç",
+		"SG": "Singapur",
+		"SH": "Saint Helena, Ascension ve Tristan da Cunha",
+		"SI": "Slovenya",
+		"SJ": "Svalbard ve Jan Mayen",
+		"SK": "Slovakya",
+		"SL": "Sierra Leone",
+		"SM": "San Marino",
+		"SN": "Senegal",
+		"SO": "Somali",
+		"SR": "Surinam",
+		"SS": "Güney Sudan",
+		"ST": "Sao Tome ve Principe",
+		"SV": "El Salvador",
+		"SX": "Sint Maarten (Hollanda kısmı)",
+		"SY": "Suriye",
+		"SZ": "Eswatini",
+		"TC": "Turks ve Caicos Adaları",
+		"TD": "Çad",
+		"TF": "Fransız Güney Bölgeleri",
+		"TG": "Togo",
+		"TH": "Tayland",
+		"TJ": "Tacikistan",
+		"TK": "Tokelau",
+		"TL": "Timor-Leste",
+		"TM": "Türkmenistan",
+		"TN": "Tunus",
+		"TO": "Tonga",
+		"TR": "Türkiye",
+		"TT": "Trinidad ve Tobago",
+		"TV": "Tuvalu",
+		"TW": "Tayvan",
+		"TZ": "Tanzanya",
+		"UA": "Ukrayna",
+		"UG": "Uganda",
+		"UM": "Amerika Birleşik Devletleri Küçük Dış Adaları",
+		"US": "Amerika Birleşik Devletleri",
+		"UY": "Uruguay",
+		"UZ": "Özbekistan",
+		"VA": "Holy See (Vatikan Şehir Devleti)",
+		"VC": "Saint Vincent ve Grenadinler",
+		"VE": "Venezuela",
+		"VG": "İngiliz Virgin Adaları",
+		"VI": "Virgin Adaları, A.B.D.",
+		"VN": "Vietnam",
+		"VU": "Vanuatu",
+		"WF": "Wallis ve Futuna Adaları",
+		"WS": "Samoa",
+		"YE": "Yemen",
+		"YT": "Mayotte",
+		"ZA": "Güney Afrika",
+		"ZM": "Zambiya",
+		"ZW": "Zimbabve",
+	},
+	"zh-cn": {
+		"AD": "安道尔",
+		"AE": "阿联酋",
+		"AF": "阿富汗",
+		"AG": "安提瓜和巴布达",
+		"AI": "安圭拉",
+		"AL": "阿尔巴尼亚",
+		"AM": "亚美尼亚",
+		"AO": "安哥拉",
+		"AQ": "南极洲",
+		"AR": "阿根廷",
+		"AS": "美属萨摩亚",
+		"AT": "奥地利",
+		"AU": "澳大利亚",
+		"AW": "阿鲁巴",
+		"AX": "奥兰群岛",
+		"AZ": "阿塞拜疆",
+		"BA": "波斯尼亚和黑塞哥维那",
+		"BB": "巴巴多斯",
+		"BD": "孟加拉",
+		"BE": "比利时",
+		"BF": "布基纳法索",
+		"BG": "保加利亚",
+		"BH": "巴林",
+		"BI": "布隆迪",
+		"BJ": "贝宁",
+		"BL": "圣巴泰勒米岛",
+		"BM": "百慕大",
+		"BN": "文莱",
+		"BO": "波利维亚",
+		"BQ": "博奈尔、圣尤斯特歇斯岛和萨巴",
+		"BR": "巴西",
+		"BS": "巴哈马",
+		"BT": "不丹",
+		"BV": "布维群岛",
+		"BW": "博兹瓦那",
+		"BY": "白俄罗斯",
+		"BZ": "伯利兹",
+		"CA": "加拿大",
+		"CC": "科科斯群岛",
+		"CD": "刚果民主共和国",
+		"CF": "中非",
+		"CG": "刚果",
+		"CH": "瑞士",
+		"CI": "科特迪瓦",
+		"CK": "库克群岛",
+		"CL": "智利",
+		"CM": "喀麦隆",
+		"CN": "中国",
+		"CO": "哥伦比亚",
+		"CR": "哥斯达黎加",
+		"CU": "古巴",
+		"CV": "佛得角",
+		"CW": "库拉索",
+		"CX": "圣诞岛",
+		"CY": "塞浦路斯",
+		"CZ": "捷克",
+		"DE": "德国",
+		"DJ": "吉布提",
+		"DK": "丹麦",
+		"DM": "多米尼克",
+		"DO": "多米尼加共和国",
+		"DZ": "阿尔及利亚",
+		"EC": "厄瓜多尔",
+		"EE": "爱沙尼亚",
+		"EG": "埃及",
+		"EH": "西撒哈拉",
+		"ER": "厄立特里亚",
+		"ES": "西班牙",
+		"ET": "埃塞俄比亚",
+		"FI": "芬兰",
+		"FJ": "斐济",
+		"FK": "福克兰群岛(马尔维纳斯)",
+		"FM": "密克罗尼西亚",
+		"FO": "法罗群岛",
+		"FR": "法国",
+		"GA": "加蓬",
+		"GB": "英国",
+		"GD": "格林纳达",
+		"GE": "格鲁吉亚",
+		"GF": "法属圭亚那",
+		"GG": "根西岛",
+		"GH": "加纳",
+		"GI": "直布罗陀",
+		"GL": "格陵兰",
+		"GM": "冈比亚",
+		"GN": "几内亚",
+		"GP": "瓜德罗普",
+		"GQ": "赤道几内亚",
+		"GR": "希腊",
+		"GS": "南乔治亚岛和南桑德韦奇岛",
+		"GT": "瓜地马拉",
+		"GU": "关岛",
+		"GW": "几内亚比绍",
+		"GY": "圭亚那",
+		"HK": "香港",
+		"HM": "赫德岛与麦克唐纳群岛",
+		"HN": "洪都拉斯",
+		"HR": "克罗地亚",
+		"HT": "海地",
+		"HU": "匈牙利",
+		"ID": "印度尼西亚",
+		"IE": "爱尔兰",
+		"IL": "以色列",
+		"IM": "曼岛",
+		"IN": "印度",
+		"IO": "英属印度洋领地",
+		"IQ": "伊拉克",
+		"IR": "伊朗",
+		"IS": "冰岛",
+		"IT": "意大利",
+		"JE": "泽西岛",
+		"JM": "牙买加",
+		"JO": "约旦",
+		"JP": "日本",
+		"KE": "肯尼亚",
+		"KG": "吉尔吉斯坦",
+		"KH": "柬埔塞",
+		"KI": "基里巴斯",
+		"KM": "科摩罗",
+		"KN": "圣基茨和尼维斯",
+		"KP": "朝鲜",
+		"KR": "韩国",
+		"KW": "科威特",
+		"KY": "开曼群岛",
+		"KZ": "哈萨克斯坦",
+		"LA": "老挝",
+		"LB": "黎巴嫩",
+		"LC": "圣路西亚",
+		"LI": "列支敦士登",
+		"LK": "斯里兰卡",
+		"LR": "利比里亚",
+		"LS": "莱索托",
+		"LT": "立陶宛",
+		"LU": "卢森堡",
+		"LV": "拉脱维亚",
+		"LY": "利比亚",
+		"MA": "摩洛哥",
+		"MC": "摩纳哥",
+		"MD": "摩尔多瓦",
+		"ME": "黑山",
+		"MF": "法属圣马丁",
+		"MG": "马达加斯加",
+		"MH": "马绍尔群岛",
+		"MK": "北马其顿",
+		"ML": "马里",
+		"MM": "缅甸",
+		"MN": "蒙古",
+		"MO": "澳门",
+		"MP": "北马里亚纳群岛",
+		"MQ": "马提尼克",
+		"MR": "毛里塔尼亚",
+		"MS": "蒙塞拉特岛",
+		"MT": "马尔他",
+		"MU": "毛里求斯",
+		"MV": "马尔代夫",
+		"MW": "马拉维",
+		"MX": "墨西哥",
+		"MY": "马来西亚",
+		"MZ": "莫桑比克",
+		"NA": "纳米比亚",
+		"NC": "新喀里多尼亚",
+		"NE": "尼日尔",
+		"NF": "诺福克岛",
+		"NG": "尼日利亚",
+		"NI": "尼加拉瓜",
+		"NL": "荷兰",
+		"NO": "挪威",
+		"NP": "尼泊尔",
+		"NR": "瑙鲁",
+		"NU": "纽埃",
+		"NZ": "新西兰",
+		"OM": "阿曼",
+		"PA": "巴拿马",
+		"PE": "秘鲁",
+		"PF": "法属玻利尼西亚",
+		"PG": "巴布亚新几内亚",
+		"PH": "菲律宾",
+		"PK": "巴基斯坦",
+		"PL": "波兰",
+		"PM": "圣皮埃尔和密克隆",
+		"PN": "皮特克恩",
+		"PR": "波多黎各",
+		"PS": "巴勒斯坦",
+		"PT": "葡萄牙",
+		"PW": "帕劳",
+		"PY": "巴拉圭",
+		"QA": "卡塔尔",
+		"RE": "留尼汪",
+		"RO": "罗马尼亚",
+		"RS": "塞尔维亚",
+		"RU": "俄罗斯",
+		"RW": "卢旺达",
+		"SA": "沙特阿拉伯",
+		"SB": "所罗门群岛",
+		"SC": "塞舌尔",
+		"SD": "苏丹",
+		"SE": "瑞典",
+		"SG": "新加坡",
+		"SH": "圣赫勒拿-阿森松-特里斯坦达库尼亚",
+		"SI": "斯洛文尼亚",
+		"SJ": "斯瓦尔巴特和扬马延岛",
+		"SK": "斯洛伐克",
+		"SL": "塞拉利昂",
+		"SM": "圣马力诺市",
+		"SN": "塞内加尔",
+		"SO": "索马里",
+		"SR": "苏里南",
+		"SS": "南苏丹",
+		"ST": "圣多美和普林西比",
+		"SV": "萨尔瓦多",
+		"SX": "荷属圣马丁",
+		"SY": "叙利亚",
+		"SZ": "斯威士兰",
+		"TC": "特克斯和凯科斯群岛",
+		"TD": "乍得",
+		"TF": "法属南半球领地",
+		"TG": "多哥",
+		"TH": "泰国",
+		"TJ": "塔吉克斯坦",
+		"TK": "托克劳",
+		"TL": "东帝汶",
+		"TM": "土库曼斯坦",
+		"TN": "突尼斯",
+		"TO": "汤加",
+		"TR": "土耳其",
+		"TT": "特里尼达和多巴哥",
+		"TV": "图瓦卢",
+		"TW": "台湾",
+		"TZ": "坦桑尼亚",
+		"UA": "乌克兰",
+		"UG": "乌干达",
+		"UM": "美国本土外小岛屿",
+		"US": "美国",
+		"UY": "乌拉圭",
+		"UZ": "乌兹别克斯坦",
+		"VA": "梵地冈",
+		"VC": "圣文森特和格林纳丁斯",
+		"VE": "委内瑞拉",
+		"VG": "英属维尔京群岛",
+		"VI": "美属维尔京群岛",
+		"VN": "越南",
+		"VU": "瓦努阿图",
+		"WF": "瓦利斯和富图纳",
+		"WS": "萨摩亚",
+		"YE": "也门",
+		"YT": "马约特",
+		"ZA": "南非",
+		"ZM": "赞比亚",
+		"ZW": "津巴布韦",
+	},
+	"zh-tw": {
+		"AD": "安道爾",
+		"AE": "阿拉伯聯合大公國",
+		"AF": "阿富汗",
+		"AG": "安地卡及巴布達",
+		"AI": "安圭拉",
+		"AL": "阿爾巴尼亞",
+		"AM": "亞美尼亞",
+		"AO": "安哥拉",
+		"AQ": "南極洲",
+		"AR": "阿根廷",
+		"AS": "美屬薩摩亞",
+		"AT": "奧地利",
+		"AU": "澳大利亞",
+		"AW": "阿路巴",
+		"AX": "奧蘭群島",
+		"AZ": "亞塞拜然",
+		"BA": "波士尼亞及赫塞哥維納",
+		"BB": "巴貝多",
+		"BD": "孟加拉",
+		"BE": "比利時",
+		"BF": "布吉納法索",
+		"BG": "保加利亞",
+		"BH": "巴林",
+		"BI": "蒲隆地",
+		"BJ": "貝南",
+		"BL": "聖巴瑟米",
+		"BM": "百慕達",
+		"BN": "汶萊",
+		"BO": "玻利維亞",
+		"BQ": "波內赫、聖尤斯特歇斯及薩巴",
+		"BR": "巴西",
+		"BS": "巴哈馬",
+		"BT": "不丹",
+		"BV": "布威島",
+		"BW": "波札那",
+		"BY": "白俄羅斯",
+		"BZ": "貝里斯",
+		"CA": "加拿大",
+		"CC": "科科斯 (基林) 群島",
+		"CD": "剛果民主共和國",
+		"CF": "中非共和國",
+		"CG": "剛果",
+		"CH": "瑞士",
+		"CI": "象牙海岸",
+		"CK": "庫克群島",
+		"CL": "智利",
+		"CM": "喀麥隆",
+		"CN": "中國",
+		"CO": "哥倫比亞",
+		"CR": "哥斯大黎加",
+		"CU": "古巴",
+		"CV": "維德角",
+		"CW": "古拉索",
+		"CX": "聖誕島",
+		"CY": "賽普勒斯",
+		"CZ": "捷克",
+		"DE": "德國",
+		"DJ": "吉布地",
+		"DK": "丹麥",
+		"DM": "多米尼克",
+		"DO": "多明尼加共和國",
+		"DZ": "阿爾及利亞",
+		"EC": "厄瓜多",
+		"EE": "愛沙尼亞",
+		"EG": "埃及",
+		"EH": "西撒哈拉",
+		"ER": "厄利垂亞",
+		"ES": "西班牙",
+		"ET": "衣索比亞",
+		"FI": "芬蘭",
+		"FJ": "斐濟",
+		"FK": "福克蘭群島 (馬維娜斯)",
+		"FM": "密克羅尼西亞聯邦",
+		"FO": "法羅群島",
+		"FR": "法國",
+		"GA": "加彭",
+		"GB": "英國",
+		"GD": "格瑞那達",
+		"GE": "喬治亞",
+		"GF": "法屬蓋亞那",
+		"GG": "根息島",
+		"GH": "迦納",
+		"GI": "直布羅陀",
+		"GL": "格陵蘭",
+		"GM": "甘比亞",
+		"GN": "幾內亞",
+		"GP": "瓜地洛普",
+		"GQ": "赤道幾內亞",
+		"GR": "希臘",
+		"GS": "南喬治亞及南三明治群島",
+		"GT": "瓜地馬拉",
+		"GU": "關島",
+		"GW": "幾內亞比索",
+		"GY": "蓋亞那",
+		"HK": "香港",
+		"HM": "赫德島及麥當勞群島",
+		"HN": "宏都拉斯",
+		"HR": "克羅埃西亞",
+		"HT": "海地",
+		"HU": "匈牙利",
+		"ID": "印度尼西亞",
+		"IE": "愛爾蘭",
+		"IL": "以色列",
+		"IM": "曼島",
+		"IN": "印度",
+		"IO": "英屬印度洋領地",
+		"IQ": "伊拉克",
+		"IR": "伊朗",
+		"IS": "冰島",
+		"IT": "義大利",
+		"JE": "澤西島",
+		"JM": "牙買加",
+		"JO": "約旦",
+		"JP": "日本",
+		"KE": "肯亞",
+		"KG": "吉爾吉斯",
+		"KH": "柬埔寨",
+		"KI": "吉里巴斯",
+		"KM": "葛摩",
+		"KN": "聖克里斯多福及尼維斯",
+		"KP": "北韓",
+		"KR": "南韓",
+		"KW": "科威特",
+		"KY": "開曼群島",
+		"KZ": "哈薩克",
+		"LA": "寮國",
+		"LB": "黎巴嫩",
+		"LC": "聖露西亞",
+		"LI": "列支敦斯登",
+		"LK": "斯里蘭卡",
+		"LR": "賴比瑞亞",
+		"LS": "賴索托",
+		"LT": "立陶宛",
+		"LU": "盧森堡",
+		"LV": "拉脫維亞",
+		"LY": "利比亞",
+		"MA": "摩洛哥",
+		"MC": "摩納哥",
+		"MD": "摩爾多瓦",
+		"ME": "蒙特內哥羅",
+		"MF": "聖馬丁 (法屬)",
+		"MG": "馬達加斯加",
+		"MH": "馬紹爾群島",
+		"MK": "北馬其頓",
+		"ML": "馬利",
+		"MM": "緬甸",
+		"MN": "蒙古",
+		"MO": "澳門",
+		"MP": "北馬里亞納群島",
+		"MQ": "馬丁尼克",
+		"MR": "茅利塔尼亞",
+		"MS": "蒙塞拉特島",
+		"MT": "馬爾他",
+		"MU": "模里西斯",
+		"MV": "馬爾地夫",
+		"MW": "馬拉威",
+		"MX": "墨西哥",
+		"MY": "馬來西亞",
+		"MZ": "莫三比克",
+		"NA": "納米比亞",
+		"NC": "新喀里多尼亞",
+		"NE": "尼日",
+		"NF": "諾福克島",
+		"NG": "奈及利亞",
+		"NI": "尼加拉瓜",
+		"NL": "荷蘭",
+		"NO": "挪威",
+		"NP": "尼泊爾",
+		"NR": "諾魯",
+		"NU": "紐埃",
+		"NZ": "紐西蘭",
+		"OM": "阿曼",
+		"PA": "巴拿馬",
+		"PE": "祕魯",
+		"PF": "法屬玻里尼西亞",
+		"PG": "巴布亞紐幾內亞",
+		"PH": "菲律賓",
+		"PK": "巴基斯坦",
+		"PL": "波蘭",
+		"PM": "聖皮耶及密克隆群島",
+		"PN": "皮特肯島",
+		"PR": "波多黎各",
+		"PS": "巴勒斯坦",
+		"PT": "葡萄牙",
+		"PW": "帛琉",
+		"PY": "巴拉圭",
+		"QA": "卡達",
+		"RE": "留尼旺島",
+		"RO": "羅馬尼亞",
+		"RS": "塞爾維亞",
+		"RU": "俄羅斯聯邦",
+		"RW": "盧安達",
+		"SA": "沙烏地阿拉伯",
+		"SB": "索羅門群島",
+		"SC": "塞席爾",
+		"SD": "蘇丹",
+		"SE": "瑞典",
+		"SG": "新加坡",
+		"SH": "聖赫倫那島、阿森松島及崔斯坦達庫尼亞群島",
+		"SI": "斯洛維尼亞",
+		"SJ": "冷岸群島及央棉",
+		"SK": "斯洛伐克",
+		"SL": "獅子山",
+		"SM": "聖馬利諾",
+		"SN": "塞內加爾",
+		"SO": "索馬利亞",
+		"SR": "蘇利南",
+		"SS": "南蘇丹",
+		"ST": "聖多美及普林西比",
+		"SV": "薩爾瓦多",
+		"SX": "聖馬丁 (荷屬)",
+		"SY": "敘利亞",
+		"SZ": "史瓦帝尼",
+		"TC": "土克凱可群島",
+		"TD": "查德",
+		"TF": "法屬南部領地",
+		"TG": "多哥",
+		"TH": "泰國",
+		"TJ": "塔吉克",
+		"TK": "托克勞",
+		"TL": "東帝汶",
+		"TM": "土庫曼",
+		"TN": "突尼西亞",
+		"TO": "東加",
+		"TR": "土耳其",
+		"TT": "千里達及托巴哥",
+		"TV": "吐瓦魯",
+		"TW": "臺灣",
+		"TZ": "坦尚尼亞",
+		"UA": "烏克蘭",
+		"UG": "烏干達",
+		"UM": "美屬邊疆群島",
+		"US": "美國",
+		"UY": "烏拉圭",
+		"UZ": "烏茲別克",
+		"VA": "教廷 (梵蒂岡城市國)",
+		"VC": "聖文森及格瑞納丁",
+		"VE": "委內瑞拉",
+		"VG": "英屬維京群島",
+		"VI": "美屬維京群島",
+		"VN": "越南",
+		"VU": "萬那杜",
+		"WF": "沃里斯及伏塔那群島",
+		"WS": "薩摩亞",
+		"YE": "葉門",
+		"YT": "馬約特",
+		"ZA": "南非",
+		"ZM": "尚比亞",
+		"ZW": "辛巴威",
+	},
+}